@@ -0,0 +1,378 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AlertSeverity ... severity of an alert after the Common Alerting Protocol (CAP)
+type AlertSeverity string
+
+const (
+	AlertUnknown  AlertSeverity = "unknown"
+	AlertMinor    AlertSeverity = "minor"
+	AlertModerate AlertSeverity = "moderate"
+	AlertSevere   AlertSeverity = "severe"
+	AlertExtreme  AlertSeverity = "extreme"
+)
+
+// alertSeverities ... from the least to the most severe
+var alertSeverities = []AlertSeverity{AlertUnknown, AlertMinor, AlertModerate, AlertSevere, AlertExtreme}
+
+// Rank ... 0 for unknown up to 4 for extreme
+func (s AlertSeverity) Rank() int {
+	for i, severity := range alertSeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return 0
+}
+
+// label ... level of the alert in the terms of its sender, the German name of its severity without it
+func (a Alert) label() string {
+	if a.Level != "" {
+		return a.Level
+	}
+	return a.Severity.Label()
+}
+
+// ParseAlertSeverity ... severity of its CAP name like moderate or its German label like mäßig
+func ParseAlertSeverity(s string) (AlertSeverity, error) {
+	for _, severity := range alertSeverities[1:] {
+		if strings.EqualFold(s, string(severity)) || strings.EqualFold(s, severity.Label()) {
+			return severity, nil
+		}
+	}
+	return "", fmt.Errorf("invalid severity %q, want minor, moderate, severe or extreme", s)
+}
+
+// Label ... German name of the severity, empty if it is unknown
+func (s AlertSeverity) Label() string {
+	switch s {
+	case AlertMinor:
+		return "gering"
+	case AlertModerate:
+		return "mäßig"
+	case AlertSevere:
+		return "schwer"
+	case AlertExtreme:
+		return "extrem"
+	}
+	return ""
+}
+
+// severityKeywords and futureKeywords ... words of the event of an alert hinting at a CAP value, checked in their order,
+// so the more severe words win
+var (
+	severityKeywords = []struct {
+		severity AlertSeverity
+		words    []string
+	}{
+		{AlertExtreme, []string{"extrem", "hurricane", "tornado"}},
+		{AlertSevere, []string{"unwetter", "severe", "orkan", "heavy"}},
+		{AlertModerate, []string{"warning", "warnung", "markant"}},
+		{AlertMinor, []string{"advisory", "statement", "vorabinformation", "hinweis", "minor"}},
+	}
+	futureKeywords = []string{"watch", "outlook", "vorabinformation"}
+)
+
+// containsAny ... true if s contains any of the words
+func containsAny(s string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyAlert ... alert with the severity, urgency, certainty and category it lacks derived from its event and
+// tags, as One Call alerts carry none of them. The values are those of CAP in lower case, urgency and certainty
+// stay empty without a hint, the category is met for any weather
+func ClassifyAlert(a Alert) Alert {
+	event := strings.ToLower(a.Name)
+	if a.Severity == "" {
+		a.Severity = AlertUnknown
+		for _, k := range severityKeywords {
+			if containsAny(event, k.words) {
+				a.Severity = k.severity
+				break
+			}
+		}
+	}
+	future := containsAny(event, futureKeywords)
+	if a.Urgency == "" && future {
+		a.Urgency = "future"
+	}
+	if a.Certainty == "" {
+		switch {
+		case future:
+			a.Certainty = "possible"
+		case containsAny(event, []string{"warning", "warnung"}):
+			a.Certainty = "likely"
+		}
+	}
+	if a.Category == "" {
+		a.Category = "met"
+		tags := strings.ToLower(strings.Join(a.Tags, ","))
+		switch {
+		case strings.Contains(tags, "fire"):
+			a.Category = "fire"
+		case strings.Contains(tags, "air quality"):
+			a.Category = "env"
+		case strings.Contains(tags, "avalanche"):
+			a.Category = "geo"
+		}
+	}
+	return a
+}
+
+// SortAlerts ... copy of the alerts, the most severe first, alerts of the same severity keep their order
+func SortAlerts(alerts []Alert) []Alert {
+	sorted := append([]Alert{}, alerts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Severity.Rank() > sorted[j].Severity.Rank()
+	})
+	return sorted
+}
+
+// Period ... start and end of the alert
+func (a Alert) Period() (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("02.01.2006, 15:04", a.Start, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := time.ParseInLocation("02.01.2006, 15:04", a.End, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, end, nil
+}
+
+// AlertFilter ... which alerts to keep, the zero value keeps all of them
+type AlertFilter struct {
+	// MinSeverity drops less severe alerts, those of unknown severity are kept, as they might be severe
+	MinSeverity AlertSeverity
+	// Events keeps the alerts whose event or tags contain one of the words, regardless of case
+	Events []string
+	// Within keeps the alerts in force at some time from now till the duration later
+	Within time.Duration
+}
+
+// Match ... true if the filter keeps the alert
+func (flt AlertFilter) Match(a Alert, now time.Time) bool {
+	if flt.MinSeverity != "" && a.Severity != AlertUnknown && a.Severity.Rank() < flt.MinSeverity.Rank() {
+		return false
+	}
+	if len(flt.Events) > 0 {
+		text := strings.ToLower(a.Name + "," + strings.Join(a.Tags, ","))
+		found := false
+		for _, event := range flt.Events {
+			if event = strings.ToLower(strings.TrimSpace(event)); event != "" && strings.Contains(text, event) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if flt.Within > 0 {
+		start, end, err := a.Period()
+		// an alert without a readable period is kept rather than missed
+		if err == nil && (!start.Before(now.Add(flt.Within)) || !end.After(now)) {
+			return false
+		}
+	}
+	return true
+}
+
+// filters ... true if the filter drops any alerts at all
+func (flt AlertFilter) filters() bool {
+	return flt.MinSeverity != "" || len(flt.Events) > 0 || flt.Within > 0
+}
+
+// FilterAlerts ... copy of the forecast with the alerts of its days the filter keeps
+func FilterAlerts(f Forecast, filter AlertFilter, now time.Time) Forecast {
+	daily := make([]ForecastDaily, len(f.Daily))
+	for i, day := range f.Daily {
+		alerts := []Alert{}
+		for _, a := range day.Alerts {
+			if filter.Match(a, now) {
+				alerts = append(alerts, a)
+			}
+		}
+		day.Alerts = alerts
+		daily[i] = day
+	}
+	f.Daily = daily
+	return f
+}
+
+// alertFilterFlags ... --min-severity, --only and --within filling opts.Alerts, within is the help of --within
+func alertFilterFlags(fs *flag.FlagSet, opts *Options, within string) {
+	fs.Func("min-severity", "only alerts at least as severe: minor, moderate, severe or extreme", func(s string) (err error) {
+		opts.Alerts.MinSeverity, err = ParseAlertSeverity(s)
+		return err
+	})
+	fs.Func("only", "only alerts whose event or tags contain one of the comma separated words, e.g. storm,flood", func(s string) error {
+		opts.Alerts.Events = strings.Split(s, ",")
+		return nil
+	})
+	fs.DurationVar(&opts.Alerts.Within, "within", 0, within)
+}
+
+// alertFlags ... flags of the alert command
+func alertFlags(fs *flag.FlagSet, opts *Options) {
+	fs.BoolVar(&opts.ExitCode, "exit-code", false, fmt.Sprintf("exit with %d if there are alerts, %d on failures", ExitAlerts, ExitFailure))
+	alertFilterFlags(fs, opts, "only alerts in force within the duration from now, e.g. 24h")
+	fs.BoolVar(&opts.ICS, "ics", false, "write the alerts in the iCalendar format")
+	fs.DurationVar(&opts.Remind, "remind", 0, "remind of each alert the duration before its start, e.g. 30m, implies --ics")
+}
+
+// validateAlert ... --remind implies --ics
+func validateAlert(fs *flag.FlagSet, opts *Options) error {
+	if opts.Remind != 0 {
+		if opts.Remind < time.Minute {
+			return fmt.Errorf("invalid reminder %s, want at least 1m", opts.Remind)
+		}
+		opts.ICS = true
+	}
+	return nil
+}
+
+// alertID ... identifies an alert by its event and time range, regardless of the day and the sender it comes with
+func alertID(a Alert) string {
+	return strings.ToLower(strings.TrimSpace(a.event())) + "|" + a.Start + "|" + a.End
+}
+
+// event ... name of the alert given by the sender, before any translation
+func (a Alert) event() string {
+	if a.Event != "" {
+		return a.Event
+	}
+	return a.Name
+}
+
+// mergeAlert ... the alert completed by the same alert of another day or sender, with the senders and tags of both
+// and the higher severity
+func mergeAlert(a, other Alert) Alert {
+	senders := []string{}
+	if a.Sender != "" {
+		senders = strings.Split(a.Sender, ", ")
+	}
+	if other.Sender != "" {
+		senders = appendMissing(senders, strings.Split(other.Sender, ", ")...)
+	}
+	a.Sender = strings.Join(senders, ", ")
+	if len(other.Tags) > 0 {
+		a.Tags = appendMissing(append([]string{}, a.Tags...), other.Tags...)
+	}
+	if other.Severity.Rank() > a.Severity.Rank() {
+		a.Severity = other.Severity
+	}
+	if a.Description == "" {
+		a.Description = other.Description
+	}
+	if a.Urgency == "" {
+		a.Urgency = other.Urgency
+	}
+	if a.Certainty == "" {
+		a.Certainty = other.Certainty
+	}
+	if a.Category == "" {
+		a.Category = other.Category
+	}
+	return a
+}
+
+// appendMissing ... the values appended to the list unless it already has them
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, l := range list {
+			if l == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// MergeAlerts ... the alerts with each warning once, alerts of the same event and time range are merged into the
+// first of them, see mergeAlert
+func MergeAlerts(alerts []Alert) []Alert {
+	merged := []Alert{}
+	index := map[string]int{}
+	for _, a := range alerts {
+		id := alertID(a)
+		if i, ok := index[id]; ok {
+			merged[i] = mergeAlert(merged[i], a)
+			continue
+		}
+		index[id] = len(merged)
+		merged = append(merged, a)
+	}
+	return merged
+}
+
+// Alerts ... alerts of all days of the forecast, each once
+func (f Forecast) Alerts() []Alert {
+	alerts := []Alert{}
+	for _, day := range f.Daily {
+		alerts = append(alerts, day.Alerts...)
+	}
+	return MergeAlerts(alerts)
+}
+
+// AddAlerts ... the alerts added to every day of the forecast they are in force on, merged with the alerts the day
+// already has
+func AddAlerts(f *Forecast, alerts []Alert) {
+	for i, day := range f.Daily {
+		dayStart, err := time.ParseInLocation("02.01.2006", day.Day, time.Local)
+		if err != nil {
+			continue
+		}
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		added := append([]Alert{}, day.Alerts...)
+		for _, a := range alerts {
+			start, end, err := a.Period()
+			if err != nil || !start.Before(dayEnd) || !end.After(dayStart) {
+				continue
+			}
+			added = append(added, a)
+		}
+		f.Daily[i].Alerts = MergeAlerts(added)
+	}
+}
+
+// addAlerts ... alerts of the alert sources of the config file added to the forecast, failing sources only warn
+// as the weather was fetched
+func (env *cliEnv) addAlerts(c *Client, coordinates Coordinates, place Place, f *Forecast) {
+	for _, source := range env.cfg.AlertSources {
+		var alerts []Alert
+		var err error
+		switch source {
+		case AlertSourceMeteoAlarm:
+			alerts, err = c.GetMeteoAlarm(coordinates, place)
+		case AlertSourceDWD:
+			alerts, err = c.GetDWD(env.cfg.DWDWarncell, place)
+		default:
+			err = fmt.Errorf("unknown alert source %q", source)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", source, err)
+			continue
+		}
+		AddAlerts(f, alerts)
+	}
+}
@@ -0,0 +1,388 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+// alertServer ... fake API with the alerts added to the second day of the canned forecast, nil for none
+func alertServer(t *testing.T, alerts []map[string]any) *httptest.Server {
+	t.Helper()
+	var resp map[string]any
+	err := json.Unmarshal(weathertest.WeatherResponse, &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp["daily"].([]any)[1].(map[string]any)["alerts"] = alerts
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", weathertest.Handler())
+	mux.HandleFunc("/data/3.0/onecall", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	})
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// runCLI ... runs the CLI against the server with a config of its own, returning stdout
+func runCLI(t *testing.T, ts *httptest.Server, args ...string) ([]byte, error) {
+	t.Helper()
+	return runCLIWithConfig(t, `{"base_url": "`+ts.URL+`"}`, args...)
+}
+
+// runCLIWithConfig ... runs the CLI with the config file content, returning the output
+func runCLIWithConfig(t *testing.T, config string, args ...string) ([]byte, error) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "config.json")
+	err := os.WriteFile(cfg, []byte(config), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WEATHER_CONFIG", cfg)
+	t.Setenv("OPENWEATHERMAP_API_KEY", "dummyAPIKey")
+	stdout := os.Stdout
+	out, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = out
+	runErr := weather.Run(append([]string{"weather"}, args...))
+	os.Stdout = stdout
+	out.Close()
+	data, err := os.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data, runErr
+}
+
+func TestAlertExitCode(t *testing.T) {
+	heat := []map[string]any{{"start": 1655460000, "end": 1655496000, "name": "Hitze", "description": "Starke Hitze"}}
+	tests := map[string]struct {
+		alerts   []map[string]any
+		args     []string
+		wantCode int
+	}{
+		"no alerts":    {nil, []string{"--exit-code"}, 0},
+		"alerts":       {heat, []string{"--exit-code"}, weather.ExitAlerts},
+		"without flag": {heat, nil, 0},
+		"text":         {heat, []string{"--exit-code", "--format", "text"}, weather.ExitAlerts},
+	}
+	for name, tc := range tests {
+		_, err := runCLI(t, alertServer(t, tc.alerts), append([]string{"alert", "Bonn,DE"}, tc.args...)...)
+		code := 0
+		var exit *weather.ExitError
+		if errors.As(err, &exit) {
+			code = exit.Code
+		} else if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if tc.wantCode != code {
+			t.Errorf("%s: want exit code %d, got %d", name, tc.wantCode, code)
+		}
+	}
+}
+
+func TestAlertExitCodeFailure(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(ts.Close)
+	_, err := runCLI(t, ts, "alert", "Bonn,DE", "--exit-code")
+	var exit *weather.ExitError
+	if !errors.As(err, &exit) || exit.Code != weather.ExitFailure || exit.Err == nil {
+		t.Errorf("want failure exit code %d with the error, got %v", weather.ExitFailure, err)
+	}
+}
+
+func TestAlertJSON(t *testing.T) {
+	heat := []map[string]any{{"start": 1655460000, "end": 1655496000, "name": "Hitze", "description": "Starke Hitze"}}
+	out, err := runCLI(t, alertServer(t, heat), "alert", "Bonn,DE", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Count  int
+		Alerts []struct {
+			Day         string
+			Name        string
+			Description string
+		}
+	}
+	err = json.Unmarshal(out, &got)
+	if err != nil {
+		t.Fatalf("invalid output %s: %v", out, err)
+	}
+	if got.Count != 1 || len(got.Alerts) != 1 {
+		t.Fatalf("want one alert, got %s", out)
+	}
+	if got.Alerts[0].Name != "Hitze" || got.Alerts[0].Description != "Starke Hitze" || got.Alerts[0].Day == "" {
+		t.Errorf("unexpected alert %+v", got.Alerts[0])
+	}
+}
+
+func TestParseWeatherResponseTopLevelAlerts(t *testing.T) {
+	t.Parallel()
+	var resp map[string]any
+	err := json.Unmarshal(weathertest.WeatherResponse, &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// from 18.06. 18:00 till 19.06. 06:00
+	resp["alerts"] = []map[string]any{{
+		"sender_name": "Deutscher Wetterdienst",
+		"event":       "Gewitter",
+		"start":       1655568000,
+		"end":         1655611200,
+		"description": "Schwere Gewitter",
+		"tags":        []string{"Thunderstorm"},
+	}}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, f, err := weather.ParseWeatherResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := weather.Alert{
+		Start:       "18.06.2022, 18:00",
+		End:         "19.06.2022, 06:00",
+		Name:        "Gewitter",
+		Description: "Schwere Gewitter",
+		Sender:      "Deutscher Wetterdienst",
+		Tags:        []string{"Thunderstorm"},
+		Severity:    weather.AlertUnknown,
+		Category:    "met",
+	}
+	for i, day := range f.Daily[:4] {
+		inForce := i == 1 || i == 2
+		if !inForce {
+			if len(day.Alerts) != 0 {
+				t.Errorf("want no alert on %s, got %+v", day.Day, day.Alerts)
+			}
+			continue
+		}
+		if len(day.Alerts) != 1 || !cmp.Equal(want, day.Alerts[0]) {
+			t.Errorf("want the alert on %s, got %+v", day.Day, day.Alerts)
+		}
+	}
+}
+
+func TestClassifyAlert(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		alert weather.Alert
+		want  weather.Alert
+	}{
+		"extreme": {
+			weather.Alert{Name: "Amtliche Warnung vor EXTREMEM UNWETTER"},
+			weather.Alert{Severity: weather.AlertExtreme, Certainty: "likely", Category: "met"},
+		},
+		"severe": {
+			weather.Alert{Name: "Severe Thunderstorm Warning", Tags: []string{"Thunderstorm"}},
+			weather.Alert{Severity: weather.AlertSevere, Certainty: "likely", Category: "met"},
+		},
+		"advisory": {
+			weather.Alert{Name: "Heat Advisory", Tags: []string{"Extreme temperature value"}},
+			weather.Alert{Severity: weather.AlertMinor, Category: "met"},
+		},
+		"watch": {
+			weather.Alert{Name: "Fire Weather Watch", Tags: []string{"Fire warning"}},
+			weather.Alert{Severity: weather.AlertUnknown, Urgency: "future", Certainty: "possible", Category: "fire"},
+		},
+		"given": {
+			weather.Alert{Name: "Frost", Severity: weather.AlertModerate, Category: "env"},
+			weather.Alert{Severity: weather.AlertModerate, Category: "env"},
+		},
+	}
+	for name, tc := range tests {
+		got := weather.ClassifyAlert(tc.alert)
+		tc.want.Name, tc.want.Tags = tc.alert.Name, tc.alert.Tags
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("%s: %s", name, cmp.Diff(tc.want, got))
+		}
+	}
+}
+
+func TestSortAlerts(t *testing.T) {
+	t.Parallel()
+	alerts := []weather.Alert{
+		{Name: "Nebel", Severity: weather.AlertMinor},
+		{Name: "Hitze", Severity: weather.AlertUnknown},
+		{Name: "Orkan", Severity: weather.AlertExtreme},
+		{Name: "Frost", Severity: weather.AlertMinor},
+	}
+	got := []string{}
+	for _, a := range weather.SortAlerts(alerts) {
+		got = append(got, a.Name)
+	}
+	want := []string{"Orkan", "Nebel", "Frost", "Hitze"}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	if alerts[0].Name != "Nebel" {
+		t.Error("want the alerts unchanged")
+	}
+}
+
+func TestFilterAlerts(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 6, 17, 12, 0, 0, 0, time.Local)
+	f := weather.Forecast{Daily: []weather.ForecastDaily{
+		{Day: "17.06.2022", Alerts: []weather.Alert{
+			{Name: "Hitze", Start: "17.06.2022, 10:00", End: "17.06.2022, 20:00", Severity: weather.AlertMinor},
+			{Name: "Gewitter", Start: "17.06.2022, 18:00", End: "18.06.2022, 02:00", Severity: weather.AlertSevere,
+				Tags: []string{"Thunderstorm"}},
+		}},
+		{Day: "18.06.2022", Alerts: []weather.Alert{
+			{Name: "Sturmböen", Start: "18.06.2022, 14:00", End: "18.06.2022, 22:00", Severity: weather.AlertModerate,
+				Tags: []string{"Wind"}},
+			{Name: "Hochwasser", Start: "18.06.2022, 06:00", End: "20.06.2022, 00:00", Severity: weather.AlertUnknown,
+				Tags: []string{"Flood"}},
+		}},
+	}}
+	tests := map[string]struct {
+		filter weather.AlertFilter
+		want   []string
+	}{
+		"all":          {weather.AlertFilter{}, []string{"Hitze", "Gewitter", "Sturmböen", "Hochwasser"}},
+		"min severity": {weather.AlertFilter{MinSeverity: weather.AlertModerate}, []string{"Gewitter", "Sturmböen", "Hochwasser"}},
+		"only":         {weather.AlertFilter{Events: []string{"wind", " FLOOD"}}, []string{"Sturmböen", "Hochwasser"}},
+		"within":       {weather.AlertFilter{Within: 12 * time.Hour}, []string{"Hitze", "Gewitter"}},
+		"combined": {
+			weather.AlertFilter{MinSeverity: weather.AlertSevere, Events: []string{"gewitter", "wind"}, Within: 24 * time.Hour},
+			[]string{"Gewitter"},
+		},
+	}
+	for name, tc := range tests {
+		got := []string{}
+		for _, day := range weather.FilterAlerts(f, tc.filter, now).Daily {
+			for _, a := range day.Alerts {
+				got = append(got, a.Name)
+			}
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("%s: %s", name, cmp.Diff(tc.want, got))
+		}
+	}
+	if len(f.Daily[0].Alerts) != 2 {
+		t.Error("want the forecast unchanged")
+	}
+}
+
+func TestParseAlertSeverity(t *testing.T) {
+	t.Parallel()
+	for s, want := range map[string]weather.AlertSeverity{"moderate": weather.AlertModerate, "Schwer": weather.AlertSevere} {
+		got, err := weather.ParseAlertSeverity(s)
+		if err != nil || got != want {
+			t.Errorf("%s: want %s, got %s, %v", s, want, got, err)
+		}
+	}
+	for _, s := range []string{"unknown", "heavy", ""} {
+		if _, err := weather.ParseAlertSeverity(s); err == nil {
+			t.Errorf("%s: want an error", s)
+		}
+	}
+}
+
+func TestAlertFilterFlags(t *testing.T) {
+	alerts := []map[string]any{
+		{"start": 1655460000, "end": 1655496000, "name": "Heat Advisory", "description": "Hitze"},
+		{"start": 1655460000, "end": 1655496000, "name": "Severe Thunderstorm Warning", "description": "Gewitter"},
+	}
+	ts := alertServer(t, alerts)
+	tests := map[string]struct {
+		args     []string
+		wantCode int
+	}{
+		"severe":        {[]string{"--min-severity", "severe"}, weather.ExitAlerts},
+		"extreme":       {[]string{"--min-severity", "extreme"}, 0},
+		"only heat":     {[]string{"--only", "heat"}, weather.ExitAlerts},
+		"only flood":    {[]string{"--only", "flood,wind"}, 0},
+		"severe or not": {[]string{"--min-severity", "moderate", "--only", "heat"}, 0},
+	}
+	for name, tc := range tests {
+		_, err := runCLI(t, ts, append([]string{"alert", "Bonn,DE", "--exit-code"}, tc.args...)...)
+		code := 0
+		var exit *weather.ExitError
+		if errors.As(err, &exit) {
+			code = exit.Code
+		} else if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if tc.wantCode != code {
+			t.Errorf("%s: want exit code %d, got %d", name, tc.wantCode, code)
+		}
+	}
+	_, err := runCLI(t, ts, "alert", "Bonn,DE", "--min-severity", "heavy")
+	if err == nil {
+		t.Error("want an error for an invalid severity")
+	}
+}
+
+func TestMergeAlerts(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Daily: []weather.ForecastDaily{
+		{Day: "17.06.2022", Alerts: []weather.Alert{
+			{Name: "Gewitter", Start: "17.06.2022, 18:00", End: "18.06.2022, 02:00", Sender: "DWD",
+				Tags: []string{"Thunderstorm"}, Severity: weather.AlertModerate},
+			{Name: "gewitter ", Start: "17.06.2022, 18:00", End: "18.06.2022, 02:00", Sender: "MeteoAlarm",
+				Tags: []string{"Thunderstorm", "Rain"}, Severity: weather.AlertSevere, Description: "Starkregen"},
+			{Name: "Hitze", Start: "17.06.2022, 10:00", End: "17.06.2022, 20:00", Sender: "DWD"},
+		}},
+		{Day: "18.06.2022", Alerts: []weather.Alert{
+			{Name: "Gewitter", Start: "17.06.2022, 18:00", End: "18.06.2022, 02:00", Sender: "DWD",
+				Tags: []string{"Thunderstorm"}, Severity: weather.AlertModerate},
+			{Name: "Gewitter", Start: "18.06.2022, 18:00", End: "19.06.2022, 02:00", Sender: "DWD"},
+		}},
+	}}
+	want := []weather.Alert{
+		{Name: "Gewitter", Start: "17.06.2022, 18:00", End: "18.06.2022, 02:00", Sender: "DWD, MeteoAlarm",
+			Tags: []string{"Thunderstorm", "Rain"}, Severity: weather.AlertSevere, Description: "Starkregen"},
+		{Name: "Hitze", Start: "17.06.2022, 10:00", End: "17.06.2022, 20:00", Sender: "DWD"},
+		{Name: "Gewitter", Start: "18.06.2022, 18:00", End: "19.06.2022, 02:00", Sender: "DWD"},
+	}
+	got := f.Alerts()
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	if current := f.CurrentAlerts(); len(current) != 2 {
+		t.Errorf("want 2 current alerts, got %+v", current)
+	}
+	if f.Daily[0].Alerts[0].Sender != "DWD" || len(f.Daily[0].Alerts[0].Tags) != 1 {
+		t.Error("want the forecast unchanged")
+	}
+}
+
+func TestPrintAlertsOnce(t *testing.T) {
+	heat := map[string]any{"start": 1655460000, "end": 1655496000, "name": "Hitze", "description": "Starke Hitze"}
+	out, err := runCLI(t, alertServer(t, []map[string]any{heat, heat}), "alert", "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(out), "Hitze von") != 1 {
+		t.Errorf("want the alert once, got %s", out)
+	}
+	out, err = runCLI(t, alertServer(t, []map[string]any{heat, heat}), "alert", "Bonn,DE", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"count": 1`) {
+		t.Errorf("want one alert, got %s", out)
+	}
+}
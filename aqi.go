@@ -0,0 +1,135 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const FunctionAQI = "aqi"
+
+type (
+	// AirQuality ... air quality index and pollutant concentrations in μg/m³
+	AirQuality struct {
+		Timestamp string  `json:"timestamp"`
+		AQI       AQI     `json:"aqi"`
+		PM25      float64 `json:"pm2_5"`
+		PM10      float64 `json:"pm10"`
+		O3        float64 `json:"o3"`
+		NO2       float64 `json:"no2"`
+	}
+
+	// AQI ... air quality index of OpenWeatherMap, 1 (good) to 5 (very poor)
+	AQI int
+
+	AirPollutionResponse struct {
+		List []struct {
+			DT   int64
+			Main struct {
+				AQI AQI
+			}
+			Components struct {
+				PM2_5 float64
+				PM10  float64
+				O3    float64
+				NO2   float64
+			}
+		}
+	}
+)
+
+func runAQI(env *cliEnv, opts Options) error {
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return err
+	}
+	aq, err := c.GetAirQuality(coordinates)
+	if err != nil {
+		return err
+	}
+	place := placeName(c, coordinates)
+	if opts.Format == FormatJSON {
+		return printJSON(os.Stdout, struct {
+			Place string `json:"place,omitempty"`
+			AirQuality
+			Category string `json:"category"`
+		}{place, aq, aq.AQI.Category()})
+	}
+	PrintAirQuality(aq, Forecast{Place: place})
+	return nil
+}
+
+// Category ... health category of the air quality index
+func (a AQI) Category() string {
+	switch a {
+	case 1:
+		return "gut"
+	case 2:
+		return "angemessen"
+	case 3:
+		return "mäßig"
+	case 4:
+		return "schlecht"
+	case 5:
+		return "sehr schlecht"
+	}
+	return "UNBEKANNT"
+}
+
+func ParseAirPollutionResponse(data []byte) (AirQuality, error) {
+	var resp AirPollutionResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return AirQuality{}, fmt.Errorf("invalid API response %s: %w", data, err)
+	}
+	if len(resp.List) < 1 {
+		return AirQuality{}, fmt.Errorf("invalid API response %s: want at least one List element", data)
+	}
+	entry := resp.List[0]
+	aq := AirQuality{
+		Timestamp: time.Unix(entry.DT, 0).Format("02.01.2006 15:04 MST"),
+		AQI:       entry.Main.AQI,
+		PM25:      entry.Components.PM2_5,
+		PM10:      entry.Components.PM10,
+		O3:        entry.Components.O3,
+		NO2:       entry.Components.NO2,
+	}
+	return aq, nil
+}
+
+func (c *Client) FormatAirPollutionURL(coordinates Coordinates) string {
+	return fmt.Sprintf("%s/data/2.5/air_pollution?lat=%g&lon=%g&appid=%s", c.BaseURL, coordinates.Lat, coordinates.Lon, c.APIKey)
+}
+
+// GetAirQuality ... current air pollution at the coordinates
+func (c *Client) GetAirQuality(coordinates Coordinates) (AirQuality, error) {
+	URL := c.FormatAirPollutionURL(coordinates)
+	resp, err := c.HTTPClient.Get(URL)
+	if err != nil {
+		return AirQuality{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return AirQuality{}, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return AirQuality{}, err
+	}
+	return ParseAirPollutionResponse(data)
+}
+
+// PrintAirQuality ... air quality index with category and the main pollutants
+func PrintAirQuality(aq AirQuality, f Forecast) {
+	fmt.Println()
+	printHeader("Luftqualität vom "+aq.Timestamp, f)
+	fmt.Printf("Index: %d (%s)\n", aq.AQI, aq.AQI.Category())
+	fmt.Printf("Feinstaub PM2.5: %.1f μg/m³\n", aq.PM25)
+	fmt.Printf("Feinstaub PM10: %.1f μg/m³\n", aq.PM10)
+	fmt.Printf("Ozon O3: %.1f μg/m³\n", aq.O3)
+	fmt.Printf("Stickstoffdioxid NO2: %.1f μg/m³\n", aq.NO2)
+	fmt.Println()
+}
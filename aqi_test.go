@@ -0,0 +1,74 @@
+package weather_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseAirPollutionResponse(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/air_pollution.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := weather.AirQuality{
+		Timestamp: "17.06.2022 17:23 CEST",
+		AQI:       2,
+		PM25:      4.57,
+		PM10:      6.12,
+		O3:        89.41,
+		NO2:       6.77,
+	}
+	got, err := weather.ParseAirPollutionResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseAirPollutionResponseEmpty(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/air_pollution_invalid.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = weather.ParseAirPollutionResponse(data)
+	if err == nil {
+		t.Fatal("want error parsing invalid response, but got nil")
+	}
+}
+
+func TestGetAirQuality(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/data/2.5/air_pollution" {
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+			f, err := os.Open("testdata/air_pollution.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			io.Copy(w, f)
+		}))
+	defer ts.Close()
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.HTTPClient = ts.Client()
+	got, err := c.GetAirQuality(weather.Coordinates{Lat: 1.0, Lon: 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AQI.Category() != "angemessen" {
+		t.Errorf("want category angemessen, got %s", got.AQI.Category())
+	}
+}
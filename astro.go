@@ -0,0 +1,122 @@
+package weather
+
+import (
+	"math"
+	"time"
+)
+
+// SunPosition ... position of the sun in degrees, Azimuth clockwise from north, Elevation above the horizon,
+// corrected by the refraction of the atmosphere
+type SunPosition struct {
+	Time      time.Time `json:"time"`
+	Azimuth   float64   `json:"azimuth"`
+	Elevation float64   `json:"elevation"`
+}
+
+// solarCoordinates ... declination of the sun in radians and the equation of time in minutes at the Julian Day,
+// after the solar calculator of NOAA, accurate to a minute between 1800 and 2100
+func solarCoordinates(jd float64) (float64, float64) {
+	rad := math.Pi / 180
+	jc := (jd - 2451545) / 36525
+	meanLong := math.Mod(280.46646+jc*(36000.76983+jc*0.0003032), 360) * rad
+	meanAnomaly := (357.52911 + jc*(35999.05029-0.0001537*jc)) * rad
+	eccentricity := 0.016708634 - jc*(0.000042037+0.0000001267*jc)
+	center := math.Sin(meanAnomaly)*(1.914602-jc*(0.004817+0.000014*jc)) +
+		math.Sin(2*meanAnomaly)*(0.019993-0.000101*jc) + math.Sin(3*meanAnomaly)*0.000289
+	omega := (125.04 - 1934.136*jc) * rad
+	apparentLong := meanLong + (center-0.00569-0.00478*math.Sin(omega))*rad
+	meanObliquity := 23 + (26+(21.448-jc*(46.815+jc*(0.00059-jc*0.001813)))/60)/60
+	obliquity := (meanObliquity + 0.00256*math.Cos(omega)) * rad
+	declination := math.Asin(math.Sin(obliquity) * math.Sin(apparentLong))
+
+	y := math.Pow(math.Tan(obliquity/2), 2)
+	equation := y*math.Sin(2*meanLong) - 2*eccentricity*math.Sin(meanAnomaly) +
+		4*eccentricity*y*math.Sin(meanAnomaly)*math.Cos(2*meanLong) -
+		0.5*y*y*math.Sin(4*meanLong) - 1.25*eccentricity*eccentricity*math.Sin(2*meanAnomaly)
+	return declination, 4 * equation / rad
+}
+
+// refraction ... lift of the sun in degrees by the refraction of the atmosphere at the true elevation,
+// after Sæmundsson
+func refraction(elevation float64) float64 {
+	if elevation < -1 {
+		return 0
+	}
+	return 1.02 / math.Tan((elevation+10.3/(elevation+5.11))*math.Pi/180) / 60
+}
+
+// GetSunPosition ... position of the sun at the time seen from the coordinates
+func GetSunPosition(t time.Time, coordinates Coordinates) SunPosition {
+	rad := math.Pi / 180
+	declination, equation := solarCoordinates(julianDay(t))
+	utc := t.UTC()
+	minutes := float64(utc.Hour()*60+utc.Minute()) + float64(utc.Second())/60
+	trueSolarTime := math.Mod(minutes+equation+4*coordinates.Lon+2*1440, 1440)
+	hourAngle := (trueSolarTime/4 - 180) * rad
+	lat := coordinates.Lat * rad
+
+	zenith := math.Acos(math.Max(-1, math.Min(1,
+		math.Sin(lat)*math.Sin(declination)+math.Cos(lat)*math.Cos(declination)*math.Cos(hourAngle))))
+	elevation := 90 - zenith/rad
+	azimuth := math.Atan2(math.Sin(hourAngle), math.Cos(hourAngle)*math.Sin(lat)-math.Tan(declination)*math.Cos(lat))
+	return SunPosition{
+		Time:      t,
+		Azimuth:   math.Mod(azimuth/rad+540, 360),
+		Elevation: elevation + refraction(elevation),
+	}
+}
+
+// SunCrossing ... time of the day of the date the sun passes the elevation in degrees, rising in the morning
+// or setting in the evening, false if it stays above or below all day
+func SunCrossing(date time.Time, coordinates Coordinates, elevation float64, rising bool) (time.Time, bool) {
+	rad := math.Pi / 180
+	y, m, d := date.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	lat := coordinates.Lat * rad
+	// the second pass takes the declination and the equation of time at the crossing of the first one
+	t := midnight.Add(12 * time.Hour)
+	for i := 0; i < 2; i++ {
+		declination, equation := solarCoordinates(julianDay(t))
+		cosH := (math.Sin(elevation*rad) - math.Sin(lat)*math.Sin(declination)) / (math.Cos(lat) * math.Cos(declination))
+		if cosH < -1 || cosH > 1 {
+			return time.Time{}, false
+		}
+		hourAngle := math.Acos(cosH) / rad
+		if rising {
+			hourAngle = -hourAngle
+		}
+		minutes := 720 - 4*coordinates.Lon - equation + 4*hourAngle
+		t = midnight.Add(time.Duration(minutes * float64(time.Minute)))
+	}
+	return t.In(date.Location()), true
+}
+
+// SunForecast ... forecast of the days from the date on with sunrise, sunset and day length computed from the
+// coordinates, without the weather API. At polar day and night sunrise and sunset are "--:--" and the day lasts
+// 24 or 0 hours
+func SunForecast(date time.Time, coordinates Coordinates, days int) Forecast {
+	f := Forecast{Coordinates: &coordinates, Daily: []ForecastDaily{}}
+	for i := 0; i < days; i++ {
+		d := date.AddDate(0, 0, i)
+		day := ForecastDaily{Day: d.Format("02.01.2006"), Sunrise: "--:--", Sunset: "--:--", Alerts: []Alert{}}
+		sunrise, okRise := SunCrossing(d, coordinates, sunriseElevation, true)
+		sunset, okSet := SunCrossing(d, coordinates, sunriseElevation, false)
+		switch {
+		case okRise && okSet:
+			day.Sunrise = sunrise.Round(time.Minute).Format("15:04")
+			day.Sunset = sunset.Round(time.Minute).Format("15:04")
+			day.DayLength = sunset.Sub(sunrise).Round(time.Second)
+		case sunElevationAtNoon(d, coordinates) > 0:
+			day.DayLength = 24 * time.Hour
+		}
+		f.Daily = append(f.Daily, day)
+	}
+	return f
+}
+
+// sunElevationAtNoon ... elevation of the sun at the mean solar noon of the date
+func sunElevationAtNoon(date time.Time, coordinates Coordinates) float64 {
+	y, m, d := date.Date()
+	noon := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Add(time.Duration((720 - 4*coordinates.Lon) * float64(time.Minute)))
+	return GetSunPosition(noon, coordinates).Elevation
+}
@@ -0,0 +1,96 @@
+package weather_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+)
+
+func TestGetSunPosition(t *testing.T) {
+	t.Parallel()
+	berlin := weather.Coordinates{Lat: 52.52, Lon: 13.405}
+	tests := []struct {
+		name      string
+		at        time.Time
+		azimuth   float64
+		elevation float64
+	}{
+		// at solar noon of the summer solstice the sun stands 90 - 52.52 + 23.44 degrees high in the south
+		{"noon", time.Date(2022, 6, 21, 11, 8, 0, 0, time.UTC), 180, 60.93},
+		{"morning", time.Date(2022, 6, 21, 7, 0, 0, 0, time.UTC), 97, 35.3},
+		{"night", time.Date(2022, 12, 21, 23, 0, 0, 0, time.UTC), 358, -60.9},
+	}
+	for _, tt := range tests {
+		got := weather.GetSunPosition(tt.at, berlin)
+		if math.Abs(got.Azimuth-tt.azimuth) > 2 || math.Abs(got.Elevation-tt.elevation) > 1 {
+			t.Errorf("%s: want azimuth %g and elevation %g, got %+v", tt.name, tt.azimuth, tt.elevation, got)
+		}
+	}
+}
+
+func TestFormatSunPosition(t *testing.T) {
+	t.Parallel()
+	want := "Azimut 215° (SW), Höhe 42.3°"
+	if got := weather.FormatSunPosition(weather.SunPosition{Azimuth: 215.2, Elevation: 42.27}); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestSunCrossing(t *testing.T) {
+	t.Parallel()
+	berlin := weather.Coordinates{Lat: 52.52, Lon: 13.405}
+	date := time.Date(2022, 6, 21, 0, 0, 0, 0, time.UTC)
+	// sunrise and sunset of the almanacs with the refraction at the horizon
+	for _, tt := range []struct {
+		rising bool
+		want   string
+	}{{true, "02:43"}, {false, "19:33"}} {
+		got, ok := weather.SunCrossing(date, berlin, -0.833, tt.rising)
+		if got = got.Round(time.Minute); !ok || got.Format("15:04") != tt.want {
+			t.Errorf("rising %t: want %s, got %s", tt.rising, tt.want, got.Format("15:04"))
+		}
+	}
+	// the sun doesn't set in the summer of Tromsø
+	if _, ok := weather.SunCrossing(date, weather.Coordinates{Lat: 69.65, Lon: 18.96}, -0.833, false); ok {
+		t.Error("want no sunset at midnight sun")
+	}
+}
+
+func TestGetPhotoDay(t *testing.T) {
+	t.Parallel()
+	got := weather.GetPhotoDay(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), weather.Coordinates{Lat: 52.52, Lon: 13.405})
+	if got.BlueMorning == nil || got.GoldenMorning == nil || got.GoldenEvening == nil || got.BlueEvening == nil {
+		t.Fatalf("want all windows in autumn, got %s", got)
+	}
+	if got.BlueMorning.To != got.GoldenMorning.From || got.GoldenEvening.To != got.BlueEvening.From {
+		t.Errorf("want the golden hours to border the blue ones, got %s", got)
+	}
+	if got.GoldenMorning.From >= got.GoldenMorning.To || got.BlueEvening.From >= got.BlueEvening.To {
+		t.Errorf("want the windows in order, got %s", got)
+	}
+}
+
+func TestSunForecast(t *testing.T) {
+	t.Parallel()
+	tromso := weather.Coordinates{Lat: 69.65, Lon: 18.96}
+	tests := []struct {
+		date    time.Time
+		sunrise string
+		length  time.Duration
+	}{
+		{time.Date(2022, 6, 21, 0, 0, 0, 0, time.UTC), "--:--", 24 * time.Hour},
+		{time.Date(2022, 12, 21, 0, 0, 0, 0, time.UTC), "--:--", 0},
+	}
+	for _, tt := range tests {
+		f := weather.SunForecast(tt.date, tromso, 1)
+		if len(f.Daily) != 1 || f.Daily[0].Sunrise != tt.sunrise || f.Daily[0].DayLength != tt.length {
+			t.Errorf("%s: want %s and %s, got %+v", tt.date.Format("02.01.2006"), tt.sunrise, tt.length, f.Daily)
+		}
+	}
+	f := weather.SunForecast(time.Date(2022, 6, 21, 0, 0, 0, 0, time.UTC), weather.Coordinates{Lat: 52.52, Lon: 13.405}, 3)
+	if len(f.Daily) != 3 || f.Daily[0].Day != "21.06.2022" || f.Daily[0].Sunrise != "02:43" || f.Daily[0].Sunset != "19:33" {
+		t.Errorf("want sunrise and sunset of Berlin, got %+v", f.Daily)
+	}
+}
@@ -0,0 +1,118 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+const FunctionAt = "at"
+
+// ParseTimeOfDay ... the next time of day like 18:30 from now on, in local time.
+// A time within the last hour is still today, as its hour is part of the forecast,
+// a point in time like "2023-07-01 14:00" is taken as it is.
+func ParseTimeOfDay(s string, now time.Time) (time.Time, error) {
+	if t, err := ParseHistoricalTime(s, time.Local); err == nil {
+		return t, nil
+	}
+	clock, err := time.ParseInLocation("15:04", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, want e.g. 18:30 or 2023-07-01 18:30", s)
+	}
+	now = now.In(time.Local)
+	t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+	if t.Before(now.Add(-time.Hour)) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// validateAt ... validate of the commands taking a point in time in front of the location, parsed by parse
+func validateAt(name string, parse func(s string, now time.Time) (time.Time, error)) func(fs *flag.FlagSet, opts *Options) error {
+	return func(fs *flag.FlagSet, opts *Options) (err error) {
+		if len(opts.Args) == 0 {
+			return fmt.Errorf("%s needs a point in time in front of the location", name)
+		}
+		now := time.Now()
+		// an unquoted "2023-07-01 14:00" comes as two words
+		if len(opts.Args) > 1 {
+			if t, err := parse(opts.Args[0]+" "+opts.Args[1], now); err == nil {
+				opts.At = t
+				opts.Args = opts.Args[2:]
+				return nil
+			}
+		}
+		opts.At, err = parse(opts.Args[0], now)
+		if err != nil {
+			return err
+		}
+		opts.Args = opts.Args[1:]
+		return nil
+	}
+}
+
+// GetHourlyAt ... hourly slot nearest to t, false if t is more than an hour before the first or after the last slot
+func GetHourlyAt(f Forecast, t time.Time) (ForecastHourly, bool) {
+	if len(f.Hourly) == 0 {
+		return ForecastHourly{}, false
+	}
+	if t.Before(f.Hourly[0].Time.Add(-time.Hour)) || t.After(f.Hourly[len(f.Hourly)-1].Time.Add(time.Hour)) {
+		return ForecastHourly{}, false
+	}
+	nearest := f.Hourly[0]
+	for _, slot := range f.Hourly[1:] {
+		if absDuration(slot.Time.Sub(t)) < absDuration(nearest.Time.Sub(t)) {
+			nearest = slot
+		}
+	}
+	return nearest, true
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// PrintHourAt ... output of the hourly forecast nearest to t
+func PrintHourAt(f Forecast, t time.Time) error {
+	slot, ok := GetHourlyAt(f, t)
+	if !ok {
+		return fmt.Errorf("%s is beyond the hourly forecast", t.Format("02.01.2006 15:04"))
+	}
+	fmt.Println()
+	printHeader(fmt.Sprintf("Vorhersage für %s %s", slot.Day, slot.Hour), f)
+	fmt.Printf("Beschreibung: %s\n", slot.Description)
+	fmt.Printf("Temperatur: %.1f %s, gefühlt %.1f %[2]s\n", slot.Temperature, f.TemperatureUnit(), slot.FeelsLike)
+	fmt.Printf("Regenwahrscheinlichkeit: %.0f %%\n", slot.RainChance)
+	if slot.Rain > 0 || slot.Snow > 0 {
+		fmt.Printf("Niederschlag: %s\n", FormatPrecipitation(slot.Rain, slot.Snow))
+	}
+	fmt.Printf("Wind: %s aus %s, in Böen %s\n", f.FormatSpeed(slot.WindSpeed), slot.WindDirection.Direction(), f.FormatSpeed(slot.WindGust))
+	fmt.Printf("Luftfeuchtigkeit: %d %%\n", slot.Humidity)
+	fmt.Printf("Luftdruck: %d hPa\n", slot.Pressure)
+	fmt.Printf("Bewölkung: %d %%\n", slot.Clouds)
+	fmt.Println()
+	return nil
+}
+
+func runAt(env *cliEnv, opts Options) error {
+	_, forecast, err := env.fetch(opts)
+	if err != nil {
+		return err
+	}
+	if opts.Format != FormatJSON {
+		return PrintHourAt(forecast, opts.At)
+	}
+	slot, ok := GetHourlyAt(forecast, opts.At)
+	if !ok {
+		return fmt.Errorf("%s is beyond the hourly forecast", opts.At.Format("02.01.2006 15:04"))
+	}
+	return printJSON(os.Stdout, struct {
+		Place  string         `json:"place,omitempty"`
+		Time   time.Time      `json:"time"`
+		Hourly ForecastHourly `json:"hourly"`
+	}{forecast.Place, opts.At, slot})
+}
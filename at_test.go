@@ -0,0 +1,90 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestParseTimeOfDay(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 6, 17, 18, 10, 0, 0, time.Local)
+	tests := map[string]time.Time{
+		"18:30":            time.Date(2022, 6, 17, 18, 30, 0, 0, time.Local),
+		"17:30":            time.Date(2022, 6, 17, 17, 30, 0, 0, time.Local),
+		"9:00":             time.Date(2022, 6, 18, 9, 0, 0, 0, time.Local),
+		"2022-06-19 07:00": time.Date(2022, 6, 19, 7, 0, 0, 0, time.Local),
+	}
+	for input, want := range tests {
+		got, err := weather.ParseTimeOfDay(input, now)
+		if err != nil {
+			t.Fatalf("%s: %v", input, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("%s: want %s, got %s", input, want, got)
+		}
+	}
+	for _, invalid := range []string{"", "evening", "25:00"} {
+		_, err := weather.ParseTimeOfDay(invalid, now)
+		if err == nil {
+			t.Errorf("%q: want error, but got nil", invalid)
+		}
+	}
+}
+
+func TestGetHourlyAt(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2022, 6, 17, 15, 0, 0, 0, time.UTC)
+	f := weather.Forecast{}
+	for i := 0; i < 3; i++ {
+		f.Hourly = append(f.Hourly, weather.ForecastHourly{Time: start.Add(time.Duration(i) * time.Hour), Temperature: float64(i)})
+	}
+	tests := map[time.Duration]float64{
+		-40 * time.Minute: 0,
+		29 * time.Minute:  0,
+		31 * time.Minute:  1,
+		2 * time.Hour:     2,
+		170 * time.Minute: 2,
+	}
+	for offset, want := range tests {
+		got, ok := weather.GetHourlyAt(f, start.Add(offset))
+		if !ok || got.Temperature != want {
+			t.Errorf("%s: want slot %.0f, got %v %v", offset, want, got.Hour, ok)
+		}
+	}
+	for _, offset := range []time.Duration{-2 * time.Hour, 4 * time.Hour} {
+		_, ok := weather.GetHourlyAt(f, start.Add(offset))
+		if ok {
+			t.Errorf("%s: want no slot beyond the forecast", offset)
+		}
+	}
+}
+
+func TestAtCommand(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	// 20 minutes after the second hour of the canned forecast
+	at := time.Unix(1655478000+80*60, 0).Format("2006-01-02 15:04")
+	out, err := runCLI(t, ts, "at", at, "Bonn,DE", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Hourly weather.ForecastHourly `json:"hourly"`
+	}
+	err = json.Unmarshal(out, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Unix(1655478000+3600, 0); !got.Hourly.Time.Equal(want) {
+		t.Errorf("want the slot of %s, got %s", want, got.Hourly.Time)
+	}
+	_, err = runCLI(t, ts, "at", "2030-01-01 12:00", "Bonn,DE")
+	if err == nil {
+		t.Error("want error beyond the hourly forecast, but got nil")
+	}
+}
@@ -0,0 +1,242 @@
+package weather
+
+import (
+	"crypto/sha1"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+const FunctionAstro = "astro"
+
+type (
+	// AstroDay ... sun and moon of a day of the astronomical calendar
+	AstroDay struct {
+		Day          string  `json:"day"`
+		Sunrise      string  `json:"sunrise"`
+		Sunset       string  `json:"sunset"`
+		DayLength    int64   `json:"day_length_seconds"`
+		Moonphase    Phase   `json:"moonphase"`
+		Illumination float64 `json:"moon_illumination"`
+	}
+
+	// MoonEvent ... full or new moon
+	MoonEvent struct {
+		Time time.Time `json:"time"`
+		Full bool      `json:"full"`
+	}
+)
+
+// GetAstroMonth ... sunrise, sunset and moon phase of the days of the month computed at the coordinates
+func GetAstroMonth(month time.Time, coordinates Coordinates) []AstroDay {
+	first, next := monthRange(month)
+	days := next.AddDate(0, 0, -1).Day()
+	sun := SunForecast(first, coordinates, days)
+	moon := MoonForecast(first, days)
+	astro := []AstroDay{}
+	for i, day := range sun.Daily {
+		p := moon.Daily[i].Moonphase
+		astro = append(astro, AstroDay{
+			Day:          day.Day,
+			Sunrise:      day.Sunrise,
+			Sunset:       day.Sunset,
+			DayLength:    int64(day.DayLength / time.Second),
+			Moonphase:    p,
+			Illumination: math.Round(p.Illumination()*100) / 100,
+		})
+	}
+	return astro
+}
+
+// GetMoonEvents ... full and new moons from one time till the other, in their order
+func GetMoonEvents(from, to time.Time) []MoonEvent {
+	events := []MoonEvent{}
+	full, newMoon := NextMoonPhase(from, true), NextMoonPhase(from, false)
+	for full.Before(to) || newMoon.Before(to) {
+		if full.Before(newMoon) {
+			events = append(events, MoonEvent{Time: full, Full: true})
+			full = NextMoonPhase(full.Add(time.Hour), true)
+		} else {
+			events = append(events, MoonEvent{Time: newMoon})
+			newMoon = NextMoonPhase(newMoon.Add(time.Hour), false)
+		}
+	}
+	return events
+}
+
+// String ... Vollmond or Neumond
+func (e MoonEvent) String() string {
+	if e.Full {
+		return "Vollmond"
+	}
+	return "Neumond"
+}
+
+// Glyph ... 🌕 or 🌑
+func (e MoonEvent) Glyph() string {
+	if e.Full {
+		return Phase(0.5).Glyph()
+	}
+	return Phase(0).Glyph()
+}
+
+// monthRange ... start of the month and of the next one
+func monthRange(month time.Time) (time.Time, time.Time) {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	return first, first.AddDate(0, 1, 0)
+}
+
+// PrintAstro ... table of the days of the month followed by its full and new moons
+func PrintAstro(month time.Time, days []AstroDay, events []MoonEvent, f Forecast) {
+	fmt.Println()
+	printHeader("Sonne und Mond im "+month.Format("01/2006"), f)
+	fmt.Printf("%-10s  %-7s  %-9s  %-12s  %s\n", "Tag", "Aufgang", "Untergang", "Tageslänge", "Mond")
+	for _, d := range days {
+		fmt.Printf("%-10s  %-7s  %-9s  %-11s  %s %3.0f %%\n", d.Day, d.Sunrise, d.Sunset,
+			FormatDayLength(time.Duration(d.DayLength)*time.Second), d.Moonphase.Glyph(), d.Illumination*100)
+	}
+	fmt.Println()
+	for _, e := range events {
+		fmt.Printf("%s: %s Uhr\n", e, e.Time.Format("02.01.2006, 15:04"))
+	}
+	fmt.Println()
+}
+
+// icsText ... text escaped for iCalendar
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icsTime ... time in UTC in the format of iCalendar
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// writeICS ... calendar of the events in the iCalendar format, each event are its lines between BEGIN:VEVENT
+// and END:VEVENT
+func writeICS(w io.Writer, events [][]string) error {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//cntzr//weather//DE", "CALSCALE:GREGORIAN"}
+	for _, event := range events {
+		lines = append(lines, "BEGIN:VEVENT")
+		lines = append(lines, event...)
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+	_, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
+
+// WriteAstroICS ... calendar of the month in the iCalendar format, an all-day event per day with sunrise, sunset and
+// day length and an event at every full and new moon
+func WriteAstroICS(w io.Writer, days []AstroDay, events []MoonEvent, coordinates Coordinates, now time.Time) error {
+	stamp := icsTime(now)
+	location := fmt.Sprintf("%.4f_%.4f", coordinates.Lat, coordinates.Lon)
+	calendar := [][]string{}
+	for _, d := range days {
+		date, err := time.ParseInLocation("02.01.2006", d.Day, time.Local)
+		if err != nil {
+			return err
+		}
+		summary := fmt.Sprintf("☀ %s - %s (%s)", d.Sunrise, d.Sunset, FormatDayLength(time.Duration(d.DayLength)*time.Second))
+		calendar = append(calendar, []string{
+			fmt.Sprintf("UID:sun-%s-%s@weather", date.Format("20060102"), location),
+			"DTSTAMP:" + stamp,
+			"DTSTART;VALUE=DATE:" + date.Format("20060102"),
+			"DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:" + icsText(summary),
+			"TRANSP:TRANSPARENT",
+		})
+	}
+	for _, e := range events {
+		at := icsTime(e.Time)
+		calendar = append(calendar, []string{
+			fmt.Sprintf("UID:moon-%s@weather", at),
+			"DTSTAMP:" + stamp,
+			"DTSTART:" + at,
+			"DTEND:" + at,
+			"SUMMARY:" + icsText(e.Glyph()+" "+e.String()),
+			"TRANSP:TRANSPARENT",
+		})
+	}
+	return writeICS(w, calendar)
+}
+
+// WriteAlertsICS ... the alerts as events of a calendar in the iCalendar format, with a reminder the duration
+// before their start unless it is 0
+func WriteAlertsICS(w io.Writer, alerts []Alert, remind time.Duration, now time.Time) error {
+	stamp := icsTime(now)
+	calendar := [][]string{}
+	for _, a := range alerts {
+		start, end, err := a.Period()
+		if err != nil {
+			return fmt.Errorf("invalid period of the alert %q: %w", a.Name, err)
+		}
+		summary := "⚠ " + a.Name
+		if label := a.label(); label != "" {
+			summary += " (" + label + ")"
+		}
+		description := a.Description
+		if a.Sender != "" {
+			description += "\n" + a.Sender
+		}
+		event := []string{
+			fmt.Sprintf("UID:alert-%x@weather", sha1.Sum([]byte(alertID(a)))),
+			"DTSTAMP:" + stamp,
+			"DTSTART:" + icsTime(start),
+			"DTEND:" + icsTime(end),
+			"SUMMARY:" + icsText(summary),
+			"DESCRIPTION:" + icsText(description),
+		}
+		if remind > 0 {
+			event = append(event,
+				"BEGIN:VALARM",
+				"ACTION:DISPLAY",
+				"DESCRIPTION:"+icsText(summary),
+				fmt.Sprintf("TRIGGER:-PT%dM", int(remind.Minutes())),
+				"END:VALARM")
+		}
+		calendar = append(calendar, event)
+	}
+	return writeICS(w, calendar)
+}
+
+// astroFlags ... flags of the astro command
+func astroFlags(fs *flag.FlagSet, opts *Options) {
+	fs.Func("month", "month of the calendar like 2025-08 (default this month)", func(s string) (err error) {
+		opts.Month, err = time.ParseInLocation("2006-01", s, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid month %q, want e.g. 2025-08", s)
+		}
+		return nil
+	})
+	fs.BoolVar(&opts.ICS, "ics", false, "write the calendar in the iCalendar format")
+}
+
+func runAstro(env *cliEnv, opts Options) error {
+	month := opts.Month
+	if month.IsZero() {
+		month = time.Now()
+	}
+	coordinates, place, err := env.locateOffline(opts)
+	if err != nil {
+		return err
+	}
+	days := GetAstroMonth(month, coordinates)
+	events := GetMoonEvents(monthRange(month))
+	switch {
+	case opts.ICS:
+		return WriteAstroICS(os.Stdout, days, events, coordinates, time.Now())
+	case opts.Format == FormatJSON:
+		return printJSON(os.Stdout, struct {
+			Month string      `json:"month"`
+			Daily []AstroDay  `json:"daily"`
+			Moons []MoonEvent `json:"moons"`
+		}{month.Format("2006-01"), days, events})
+	}
+	PrintAstro(month, days, events, Forecast{Place: place})
+	return nil
+}
@@ -0,0 +1,99 @@
+package weather_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+)
+
+func TestGetAstroMonth(t *testing.T) {
+	t.Parallel()
+	days := weather.GetAstroMonth(time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC), weather.Coordinates{Lat: 52.52, Lon: 13.405})
+	if len(days) != 28 || days[0].Day != "01.02.2025" || days[27].Day != "28.02.2025" {
+		t.Fatalf("want the 28 days of February, got %+v", days)
+	}
+	if days[0].DayLength >= days[27].DayLength || days[11].Moonphase != 0.5 {
+		t.Errorf("want longer days and the full moon on 12.02.2025, got %+v and %+v", days[0], days[11])
+	}
+}
+
+func TestGetMoonEvents(t *testing.T) {
+	t.Parallel()
+	got := weather.GetMoonEvents(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	if len(got) != 2 || !got[0].Full || got[0].Time.Day() != 9 || got[1].Full || got[1].Time.Day() != 23 {
+		t.Errorf("want the full moon of 9 and the new moon of 23 August, got %+v", got)
+	}
+}
+
+func TestWriteAstroICS(t *testing.T) {
+	t.Parallel()
+	days := []weather.AstroDay{{Day: "09.08.2025", Sunrise: "05:40", Sunset: "20:41", DayLength: 54060}}
+	events := []weather.MoonEvent{{Time: time.Date(2025, 8, 9, 7, 55, 0, 0, time.UTC), Full: true}}
+	var b bytes.Buffer
+	err := weather.WriteAstroICS(&b, days, events, weather.Coordinates{Lat: 52.52, Lon: 13.405}, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART;VALUE=DATE:20250809\r\nDTEND;VALUE=DATE:20250810\r\nSUMMARY:☀ 05:40 - 20:41 (15h 01min)\r\n",
+		"DTSTART:20250809T075500Z\r\n",
+		"SUMMARY:🌕 Vollmond\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("want %q in %q", want, b.String())
+		}
+	}
+}
+
+func TestWriteAlertsICS(t *testing.T) {
+	t.Parallel()
+	alerts := []weather.Alert{
+		{Name: "Gewitter", Start: "17.06.2022, 18:00", End: "18.06.2022, 02:00", Description: "Hagel, Starkregen",
+			Sender: "DWD", Severity: weather.AlertSevere},
+		{Name: "Hitze", Start: "18.06.2022, 11:00", End: "18.06.2022, 19:00", Level: "Wetterwarnung"},
+	}
+	var b bytes.Buffer
+	err := weather.WriteAlertsICS(&b, alerts, 30*time.Minute, time.Date(2022, 6, 17, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2022, 6, 17, 18, 0, 0, 0, time.Local).UTC().Format("20060102T150405Z")
+	for _, want := range []string{
+		"DTSTART:" + start + "\r\n",
+		"SUMMARY:⚠ Gewitter (schwer)\r\nDESCRIPTION:Hagel\\, Starkregen\\nDWD\r\n",
+		"SUMMARY:⚠ Hitze (Wetterwarnung)\r\n",
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\nDESCRIPTION:⚠ Gewitter (schwer)\r\nTRIGGER:-PT30M\r\nEND:VALARM\r\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("want %q in %q", want, b.String())
+		}
+	}
+	if strings.Count(b.String(), "BEGIN:VALARM") != 2 {
+		t.Errorf("want a reminder per alert, got %q", b.String())
+	}
+
+	b.Reset()
+	err = weather.WriteAlertsICS(&b, alerts, 0, time.Now())
+	if err != nil || strings.Contains(b.String(), "VALARM") {
+		t.Errorf("want no reminders, got %q, %v", b.String(), err)
+	}
+}
+
+func TestAlertRemind(t *testing.T) {
+	heat := []map[string]any{{"start": 1655460000, "end": 1655496000, "name": "Hitze", "description": "Starke Hitze"}}
+	out, err := runCLI(t, alertServer(t, heat), "alert", "Bonn,DE", "--remind", "1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "BEGIN:VCALENDAR") || !strings.Contains(string(out), "TRIGGER:-PT60M") {
+		t.Errorf("want the alert as calendar with a reminder, got %s", out)
+	}
+	if _, err := runCLI(t, alertServer(t, heat), "alert", "Bonn,DE", "--remind", "10s"); err == nil {
+		t.Error("want an error for a reminder below a minute")
+	}
+}
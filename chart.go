@@ -0,0 +1,191 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	// ChartWidth and ChartHeight ... size of the PNG chart in pixels
+	ChartWidth  = 960
+	ChartHeight = 360
+
+	// margins of the plot for the labels of the temperatures left, the rain chances right and the hours below
+	chartLeft   = 56
+	chartRight  = 56
+	chartTop    = 16
+	chartBottom = 32
+	// fontScale ... pixels per dot of the built-in font
+	fontScale = 2
+)
+
+var (
+	chartGrid        = color.RGBA{R: 225, G: 225, B: 225, A: 255}
+	chartMidnight    = color.RGBA{R: 150, G: 150, B: 150, A: 255}
+	chartText        = color.RGBA{R: 60, G: 60, B: 60, A: 255}
+	chartRain        = color.RGBA{R: 130, G: 180, B: 235, A: 255}
+	chartTemperature = color.RGBA{R: 215, G: 50, B: 40, A: 255}
+)
+
+// chartPlot ... area of the plot within the chart, the margins take the labels
+var chartPlot = image.Rect(chartLeft, chartTop, ChartWidth-chartRight, ChartHeight-chartBottom)
+
+// chartFont ... glyphs of 3x5 dots for the labels of the chart
+var chartFont = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'-': {"...", "...", "###", "...", "..."},
+	'.': {"...", "...", "...", "...", ".#."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'%': {"#.#", "..#", ".#.", "#..", "#.#"},
+	'°': {".#.", "#.#", ".#.", "...", "..."},
+	'C': {"###", "#..", "#..", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	' ': {"...", "...", "...", "...", "..."},
+}
+
+// textWidth ... width of the text in pixels, a glyph and the space after it take 4 dots
+func textWidth(text string) int {
+	return len([]rune(text)) * 4 * fontScale
+}
+
+// drawText ... the text in the built-in font with its top left corner at x, y, unknown characters are left blank
+func drawText(img draw.Image, x, y int, text string, c color.Color) {
+	for _, r := range text {
+		for row, dots := range chartFont[r] {
+			for col, dot := range dots {
+				if dot != '#' {
+					continue
+				}
+				fill(img, image.Rect(x+col*fontScale, y+row*fontScale, x+(col+1)*fontScale, y+(row+1)*fontScale), c)
+			}
+		}
+		x += 4 * fontScale
+	}
+}
+
+func fill(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+// drawLine ... line of 2 pixels width from x0, y0 to x1, y1 by Bresenham
+func drawLine(img draw.Image, x0, y0, x1, y1 int, c color.Color) {
+	dx, dy := int(math.Abs(float64(x1-x0))), -int(math.Abs(float64(y1-y0)))
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		fill(img, image.Rect(x0, y0, x0+2, y0+2), c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// DrawChart ... chart of the hourly forecast, the rain chances as blue bars on the right scale and the temperatures
+// as red line on the left scale, midnights are marked and every sixth hour is labelled
+func DrawChart(f Forecast) (*image.RGBA, error) {
+	if len(f.Hourly) == 0 {
+		return nil, errors.New("no hourly forecast for the chart")
+	}
+	img := image.NewRGBA(image.Rect(0, 0, ChartWidth, ChartHeight))
+	fill(img, img.Bounds(), color.White)
+	plot := chartPlot
+	step := float64(plot.Dx()) / float64(len(f.Hourly))
+	min, max := temperatureRange(f.Hourly)
+	y := func(t float64) int {
+		return plot.Max.Y - int(math.Round((t-min)/(max-min)*float64(plot.Dy())))
+	}
+	unit := f.TemperatureUnit()
+	for i := 0; i <= 4; i++ {
+		t := min + (max-min)*float64(i)/4
+		fill(img, image.Rect(plot.Min.X, y(t), plot.Max.X, y(t)+1), chartGrid)
+		label := fmt.Sprintf("%.0f %s", t, unit)
+		drawText(img, plot.Min.X-textWidth(label)-4, y(t)-5*fontScale/2, label, chartText)
+		label = fmt.Sprintf("%d %%", i*25)
+		drawText(img, plot.Max.X+8, plot.Max.Y-plot.Dy()*i/4-5*fontScale/2, label, chartText)
+	}
+
+	for i, slot := range f.Hourly {
+		x := plot.Min.X + int(float64(i)*step)
+		hour := strings.SplitN(slot.Hour, ":", 2)[0]
+		if i > 0 && hour == "00" {
+			fill(img, image.Rect(x, plot.Min.Y, x+1, plot.Max.Y), chartMidnight)
+		}
+		if hour == "00" || hour == "06" || hour == "12" || hour == "18" {
+			label := hour
+			if hour == "00" && len(slot.Day) >= 6 {
+				// the day is shown at midnight, e.g. 18.06.
+				label = slot.Day[:6]
+			}
+			drawText(img, x-textWidth(label)/2, plot.Max.Y+8, label, chartText)
+		}
+		top := plot.Max.Y - int(math.Round(slot.RainChance/100*float64(plot.Dy())))
+		fill(img, image.Rect(x+1, top, x+int(step)-1, plot.Max.Y), chartRain)
+	}
+
+	for i := 1; i < len(f.Hourly); i++ {
+		x0 := plot.Min.X + int((float64(i-1)+0.5)*step)
+		x1 := plot.Min.X + int((float64(i)+0.5)*step)
+		drawLine(img, x0, y(f.Hourly[i-1].Temperature), x1, y(f.Hourly[i].Temperature), chartTemperature)
+	}
+	return img, nil
+}
+
+// temperatureRange ... scale of the temperatures of the chart, whole degrees with some room above and below the line
+func temperatureRange(hourly []ForecastHourly) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, slot := range hourly {
+		min = math.Min(min, slot.Temperature)
+		max = math.Max(max, slot.Temperature)
+	}
+	return math.Floor(min) - 1, math.Ceil(max) + 1
+}
+
+// writeChart ... the chart of the hourly forecast as PNG file
+func writeChart(f Forecast, path string) error {
+	img, err := DrawChart(f)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = png.Encode(file, img)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
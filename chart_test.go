@@ -0,0 +1,94 @@
+package weather_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestDrawChart(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Hourly: []weather.ForecastHourly{
+		{Day: "17.06.2022", Hour: "23:00", Temperature: 10, RainChance: 0},
+		{Day: "18.06.2022", Hour: "00:00", Temperature: 20, RainChance: 100},
+	}}
+	img, err := weather.DrawChart(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != weather.ChartWidth || img.Bounds().Dy() != weather.ChartHeight {
+		t.Fatalf("want a chart of %dx%d, got %v", weather.ChartWidth, weather.ChartHeight, img.Bounds())
+	}
+	// the second hour rains, the first one doesn't
+	if got := img.RGBAAt(weather.ChartWidth*3/4, weather.ChartHeight/2); got == (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Error("want a rain bar in the second half, got white")
+	}
+	if got := img.RGBAAt(weather.ChartWidth/4, weather.ChartHeight/2); got != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("want no rain bar in the first half, got %v", got)
+	}
+	_, err = weather.DrawChart(weather.Forecast{})
+	if err == nil {
+		t.Error("want error without hourly forecast, but got nil")
+	}
+}
+
+func TestGraphPNG(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	path := filepath.Join(t.TempDir(), "chart.png")
+	_, err := runCLI(t, ts, "graph", "--png", path, "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != weather.ChartWidth {
+		t.Errorf("want a chart %d pixels wide, got %v", weather.ChartWidth, img.Bounds())
+	}
+}
+
+func TestWriteChartSVG(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Place: "Bonn & Beuel", Hourly: []weather.ForecastHourly{
+		{Day: "17.06.2022", Hour: "23:00", Temperature: 10, RainChance: 0, WindSpeed: 5},
+		{Day: "18.06.2022", Hour: "00:00", Temperature: 20, RainChance: 100, WindSpeed: 10},
+	}}
+	var b bytes.Buffer
+	err := weather.WriteChartSVG(&b, f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := b.String()
+	for _, want := range []string{"<svg ", `class="temp"`, `class="rain"`, `class="wind"`, "Bonn &amp; Beuel", "18.06.", "Wind 0 - 40 km/h", "</svg>"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("want %q in the chart, got %q", want, svg)
+		}
+	}
+	b.Reset()
+	err = weather.WriteChartSVG(&b, f, []string{weather.SeriesTemp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), `class="rain"`) || strings.Contains(b.String(), `class="wind"`) {
+		t.Errorf("want the temperatures only, got %q", b.String())
+	}
+	err = weather.WriteChartSVG(&b, weather.Forecast{}, nil)
+	if err == nil {
+		t.Error("want error without hourly forecast, but got nil")
+	}
+}
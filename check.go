@@ -0,0 +1,132 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CommandCheck = "check"
+
+	// conditions of the check command
+	CheckRain  = "rain"
+	CheckTemp  = "temp"
+	CheckAlert = "alert"
+
+	// DefaultCheckWithin ... window of the rain and temperature checks from now on
+	DefaultCheckWithin = 3 * time.Hour
+
+	// ExitCheckFailed ... exit code of the check command if the condition isn't met, it exits with 0 if it is
+	// and with ExitFailure if the weather could not be fetched
+	ExitCheckFailed = 1
+)
+
+// checkConditions ... conditions of the check command, used for shell completion
+var checkConditions = []string{CheckRain, CheckTemp, CheckAlert}
+
+// WeatherCheck ... condition checked against the weather, Within is the window from now on, 0 means
+// DefaultCheckWithin for rain and temperature and now for alerts
+type WeatherCheck struct {
+	Condition string
+	Within    time.Duration
+	// Below and Above are the limits of the temperature check in the units of the forecast, one is needed
+	Below *float64
+	Above *float64
+	// Alerts selects the alerts of the alert check
+	Alerts AlertFilter
+}
+
+// Met ... true for rain if an hour within the window reaches RainThreshold, for temp if the temperature now or of an
+// hour within the window is below or above the limit, for alert if an alert of the filter is in force within it
+func (ch WeatherCheck) Met(c Conditions, f Forecast, now time.Time) (bool, error) {
+	window := ch.Within
+	if window == 0 && ch.Condition != CheckAlert {
+		window = DefaultCheckWithin
+	}
+	switch ch.Condition {
+	case CheckRain:
+		_, _, ok := rainPeriod(f.Hourly, now, window)
+		return ok, nil
+	case CheckTemp:
+		if ch.Below == nil && ch.Above == nil {
+			return false, fmt.Errorf("%s %s needs --below or --above", CommandCheck, CheckTemp)
+		}
+		temperatures := []float64{c.Temperature}
+		for _, slot := range f.Hourly {
+			if slot.Time.Add(time.Hour).After(now) && !slot.Time.After(now.Add(window)) {
+				temperatures = append(temperatures, slot.Temperature)
+			}
+		}
+		for _, t := range temperatures {
+			if (ch.Below != nil && t < *ch.Below) || (ch.Above != nil && t > *ch.Above) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case CheckAlert:
+		for _, a := range f.Alerts() {
+			if !ch.Alerts.Match(a, now) {
+				continue
+			}
+			start, end, err := a.Period()
+			// an alert without a readable period counts as in force
+			if err != nil || (!start.After(now.Add(window)) && end.After(now)) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid condition %q, want %s, %s or %s", ch.Condition, CheckRain, CheckTemp, CheckAlert)
+}
+
+// checkFlags ... flags of the check command, the alert filter is also that of the alert condition
+func checkFlags(fs *flag.FlagSet, opts *Options) {
+	alertFilterFlags(fs, opts, fmt.Sprintf("window of the condition from now on, e.g. 6h (default %s, for alerts now)", DefaultCheckWithin))
+	limit := func(limit **float64) func(string) error {
+		return func(s string) error {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return fmt.Errorf("invalid temperature %q", s)
+			}
+			*limit = &v
+			return nil
+		}
+	}
+	fs.Func("below", "temp: met if the temperature falls below it, in the units", limit(&opts.Check.Below))
+	fs.Func("above", "temp: met if the temperature rises above it, in the units", limit(&opts.Check.Above))
+}
+
+// validateCheck ... the condition is the word in front of the location
+func validateCheck(fs *flag.FlagSet, opts *Options) error {
+	if len(opts.Args) == 0 {
+		return fmt.Errorf("%s needs a condition in front of the location: %s", CommandCheck, strings.Join(checkConditions, ", "))
+	}
+	opts.Check.Condition = strings.ToLower(opts.Args[0])
+	opts.Check.Within = opts.Alerts.Within
+	opts.Check.Alerts = opts.Alerts
+	opts.Args = opts.Args[1:]
+	return nil
+}
+
+func runCheck(env *cliEnv, opts Options) error {
+	// fails early on an invalid check, before the weather is fetched
+	_, err := opts.Check.Met(Conditions{}, Forecast{}, time.Now())
+	if err != nil {
+		return err
+	}
+	conditions, forecast, err := env.fetch(opts)
+	if err != nil {
+		return err
+	}
+	met, err := opts.Check.Met(conditions, forecast, time.Now())
+	if err != nil {
+		return err
+	}
+	if !met {
+		return &ExitError{Code: ExitCheckFailed}
+	}
+	return nil
+}
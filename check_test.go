@@ -0,0 +1,84 @@
+package weather_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestWeatherCheckMet(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 6, 17, 12, 30, 0, 0, time.Local)
+	f := rainForecast(now.Truncate(time.Hour), 10, 20, 30, 60, 70)
+	for i, temp := range []float64{18, 16, 14, 12, 9} {
+		f.Hourly[i].Temperature = temp
+	}
+	f.Daily[0].Alerts = []weather.Alert{
+		{Name: "Gewitter", Start: "17.06.2022, 18:00", End: "17.06.2022, 22:00", Severity: weather.AlertSevere},
+	}
+	c := weather.Conditions{Temperature: 19}
+	ten, twenty := 10.0, 20.0
+	tests := map[string]struct {
+		check weather.WeatherCheck
+		want  bool
+	}{
+		"rain within 3h":   {weather.WeatherCheck{Condition: weather.CheckRain}, true},
+		"rain within 1h":   {weather.WeatherCheck{Condition: weather.CheckRain, Within: time.Hour}, false},
+		"below within 3h":  {weather.WeatherCheck{Condition: weather.CheckTemp, Below: &ten}, false},
+		"below within 4h":  {weather.WeatherCheck{Condition: weather.CheckTemp, Below: &ten, Within: 4 * time.Hour}, true},
+		"above now":        {weather.WeatherCheck{Condition: weather.CheckTemp, Above: &ten}, true},
+		"above":            {weather.WeatherCheck{Condition: weather.CheckTemp, Above: &twenty}, false},
+		"alert now":        {weather.WeatherCheck{Condition: weather.CheckAlert}, false},
+		"alert within 6h":  {weather.WeatherCheck{Condition: weather.CheckAlert, Within: 6 * time.Hour}, true},
+		"alert too severe": {weather.WeatherCheck{Condition: weather.CheckAlert, Within: 6 * time.Hour, Alerts: weather.AlertFilter{MinSeverity: weather.AlertExtreme}}, false},
+	}
+	for name, tc := range tests {
+		got, err := tc.check.Met(c, f, now)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if tc.want != got {
+			t.Errorf("%s: want %t, got %t", name, tc.want, got)
+		}
+	}
+	for _, check := range []weather.WeatherCheck{{Condition: "snow"}, {Condition: weather.CheckTemp}} {
+		if _, err := check.Met(c, f, now); err == nil {
+			t.Errorf("%+v: want an error", check)
+		}
+	}
+}
+
+func TestCheckExitCode(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	t.Cleanup(ts.Close)
+	tests := map[string]struct {
+		args     []string
+		wantCode int
+	}{
+		"met":       {[]string{"temp", "--above", "-50"}, 0},
+		"not met":   {[]string{"temp", "--below", "-50"}, weather.ExitCheckFailed},
+		"past rain": {[]string{"rain", "--within", "6h"}, weather.ExitCheckFailed},
+		"no limit":  {[]string{"temp"}, weather.ExitFailure},
+		"unknown":   {[]string{"snow"}, weather.ExitFailure},
+		"bad limit": {[]string{"temp", "--below", "cold"}, weather.ExitFailure},
+	}
+	for name, tc := range tests {
+		out, err := runCLI(t, ts, append([]string{"check"}, append(tc.args, "Bonn,DE")...)...)
+		code := 0
+		var exit *weather.ExitError
+		if errors.As(err, &exit) {
+			code = exit.Code
+		} else if err != nil {
+			code = -1
+		}
+		if tc.wantCode != code {
+			t.Errorf("%s: want exit code %d, got %d (%v)", name, tc.wantCode, code, err)
+		}
+		if len(out) > 0 {
+			t.Errorf("%s: want no output, got %s", name, out)
+		}
+	}
+}
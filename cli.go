@@ -0,0 +1,1564 @@
+package weather
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type (
+	// Options ... flags shared by the weather commands of the CLI
+	Options struct {
+		Location string
+		Zip      string
+		Here     bool
+		Units    string
+		Lang     string
+		Format   string
+		Days     int
+		Hours    int
+		Listen   string
+		Interval time.Duration
+		// RainWithin and Ntfy are the flags of the notifying commands
+		RainWithin time.Duration
+		Ntfy       string
+		Webhook    string
+		Desktop    bool
+		Briefing   bool
+		// Notify raises desktop notifications after the output of a weather command
+		Notify bool
+		// Icon renders the weather icon after the output with the graphics protocol, GraphicsAuto picks one
+		Icon string
+		// Color colours the text output of the Renderer, ColorAuto if stdout is a terminal
+		Color string
+		// ExitCode makes the alert command exit with ExitAlerts if there are alerts
+		ExitCode bool
+		// Alerts filters the alerts of the alert command, set by --min-severity, --only and --within
+		Alerts AlertFilter
+		// Remind is the lead time of the reminders of the alerts exported by --ics
+		Remind time.Duration
+		// Check is the condition of the check command, its window is --within and its limits --below and --above
+		Check WeatherCheck
+		// Graph is the measurement of the graph command, e.g. temp, its day is Day
+		Graph string
+		// Day is the offset of the day shown by the forecast commands, 0 is today
+		Day int
+		// At is the point in time of the historical weather or of the hourly forecast of at
+		At time.Time
+		// Exclude are the blocks of the One Call response the command doesn't need
+		Exclude []string
+		// Speed, Start and Every are the flags of the route command, Every is the distance between the samples in km
+		Speed Speed
+		Start time.Time
+		Every float64
+		// Layer, Zoom and Output are the flags of the map command, Output is also --png of the graph command
+		Layer  string
+		Zoom   int
+		Output string
+		// Base is the base temperature of the degree days in the units
+		Base float64
+		// Garden adds the evapotranspiration and the watering need to the forecast of a day
+		Garden bool
+		// Energy shows the wind power outlook instead of the wind
+		Energy bool
+		// Icons leads the moon phases with their Unicode glyphs
+		Icons bool
+		// Photo adds the blue and golden hours to the sun command
+		Photo bool
+		// Date and Offline make commands which can do without the weather API compute their output locally,
+		// Date is the first day, today if only Offline is set
+		Date    time.Time
+		Offline bool
+		// Month and ICS are the flags of the astro command, ICS writes the calendar in the iCalendar format
+		Month time.Time
+		ICS   bool
+		// Commute are the trips of the commute command, set by --leave, --return and --duration
+		Commute CommutePlan
+		// Template is the line of the oneline and statusbar commands with placeholders like %t, see FormatOneline
+		Template string
+		// Bar adds the colour codes of a status bar to --format statusbar of the statusbar command,
+		// Follow reprints its line every Interval
+		Bar    string
+		Follow bool
+		// Args are the positional arguments, which form the location of most commands
+		Args []string
+	}
+
+	// command ... one subcommand of the CLI
+	command struct {
+		name    string
+		summary string
+		// usage names the positional arguments in the help, [LOCATION] if empty
+		usage string
+		// days and hours are the defaults for --days and --hours, commands without them don't offer the flags
+		days  int
+		hours int
+		// listen and interval are the defaults for --listen and --interval of long running commands
+		listen   string
+		interval time.Duration
+		// run is used by commands which don't need weather data
+		run func(env *cliEnv, args []string) error
+		// runOpts is used by commands taking the weather flags, but doing more than printing the weather
+		runOpts func(env *cliEnv, opts Options) error
+		// flags adds the own flags of the command, with their defaults written into opts, which also
+		// become the defaults of weather flags like --format
+		flags func(fs *flag.FlagSet, opts *Options)
+		// validate checks the parsed options of the command, words it takes in front of the location
+		// are taken from opts.Args
+		validate func(fs *flag.FlagSet, opts *Options) error
+		// metric commands always use metric units, as their output is named after them, and don't offer --units
+		metric bool
+		// serve commands take the flags of serveFlags
+		serve bool
+		// formats are the output formats the command offers besides text and json
+		formats []string
+		// exclude are the blocks of the One Call response print and data don't need
+		exclude []string
+		// offline computes the forecast of opts.Days days from the date on without the weather API,
+		// commands with it offer --date and --offline
+		offline func(date time.Time, coordinates Coordinates, opts Options) Forecast
+		// words are the fixed arguments of a run command, used for shell completion
+		words []string
+		// print and data render the weather as text or JSON, render replaces print for the outputs of a Renderer
+		print  func(c Conditions, f Forecast, opts Options) error
+		render func(r Renderer, w io.Writer, c Conditions, f Forecast, opts Options) error
+		data   func(c Conditions, f Forecast, opts Options) any
+		// icon is the code of the weather icon shown by commands offering --icon
+		icon func(c Conditions, f Forecast, opts Options) string
+	}
+
+	// ServeCommand ... serve mode implemented outside of the library, like grpc-serve of the binary,
+	// so the library doesn't depend on its packages
+	ServeCommand struct {
+		Name    string
+		Summary string
+		// Listen is the default for --listen
+		Listen string
+		Serve  func(s *Server, listen string) error
+	}
+
+	// ExitError ... makes RunCLI exit with Code instead of 1, Err is printed if set
+	ExitError struct {
+		Code int
+		Err  error
+	}
+
+	// cliEnv ... state shared by all commands of one CLI call
+	cliEnv struct {
+		name    string
+		cfg     Config
+		cfgPath string
+	}
+)
+
+const (
+	// function arguments for CLI
+	FunctionCurrent       = "current"
+	FunctionToday         = "today"
+	FunctionTomorrow      = "tomorrow"
+	FunctionAfterTomorrow = "aftertomorrow"
+	FunctionForecast      = "forecast"
+	FunctionMoon          = "moon"
+	FunctionRain          = "rain"
+	FunctionAlert         = "alert"
+	FunctionWeek          = "week"
+	FunctionHourly        = "hourly"
+
+	// management commands for CLI
+	CommandLocations = "locations"
+	CommandSearch    = "search"
+	CommandHelp      = "help"
+
+	// output formats for CLI
+	FormatText = "text"
+	FormatJSON = "json"
+
+	// exit codes of --exit-code, failures are told apart from alerts
+	ExitAlerts  = 1
+	ExitFailure = 2
+)
+
+var validUnits = map[string]bool{
+	UnitsMetric:   true,
+	UnitsImperial: true,
+	UnitsStandard: true,
+}
+
+var validFormat = map[string]bool{
+	FormatText: true,
+	FormatJSON: true,
+}
+
+// weekdays ... names of the days taken by weekday commands, in English and German
+var weekdays = map[string]time.Weekday{
+	"sunday":     time.Sunday,
+	"monday":     time.Monday,
+	"tuesday":    time.Tuesday,
+	"wednesday":  time.Wednesday,
+	"thursday":   time.Thursday,
+	"friday":     time.Friday,
+	"saturday":   time.Saturday,
+	"sonntag":    time.Sunday,
+	"montag":     time.Monday,
+	"dienstag":   time.Tuesday,
+	"mittwoch":   time.Wednesday,
+	"donnerstag": time.Thursday,
+	"freitag":    time.Friday,
+	"samstag":    time.Saturday,
+}
+
+var commands = []command{
+	{
+		name:    FunctionCurrent,
+		exclude: []string{ExcludeMinutely, ExcludeHourly},
+		summary: "aktuelles Wetter",
+		flags:   moonIconsFlags,
+		render: func(r Renderer, w io.Writer, c Conditions, f Forecast, opts Options) error {
+			return r.RenderCurrent(w, c, f)
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			return currentJSON{f.Place, c, f.Daily[0].Alerts}
+		},
+		icon: func(c Conditions, f Forecast, opts Options) string {
+			return c.Icon
+		},
+	},
+	onelineCommand(FunctionOneline, "das Wetter in einer Zeile für den Prompt", onelineFlags),
+	statusbarCommand(),
+	forecastCommand(FunctionToday, "Vorhersage für heute", 0),
+	forecastCommand(FunctionTomorrow, "Vorhersage für morgen", 1),
+	forecastCommand(FunctionAfterTomorrow, "Vorhersage für übermorgen", 2),
+	weekdayCommand(FunctionForecast, "Vorhersage für einen der nächsten 8 Tage, per --day oder Wochentag"),
+	{
+		name:    FunctionWeek,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely, ExcludeHourly},
+		summary: "Übersicht der ganzen Woche",
+		days:    8,
+		render: func(r Renderer, w io.Writer, c Conditions, f Forecast, opts Options) error {
+			return r.RenderWeek(w, f)
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			return struct {
+				Place string          `json:"place,omitempty"`
+				Daily []ForecastDaily `json:"daily"`
+			}{f.Place, f.Daily}
+		},
+	},
+	{
+		name:    FunctionHourly,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely},
+		summary: "Vorhersage Stunde für Stunde",
+		hours:   24,
+		render: func(r Renderer, w io.Writer, c Conditions, f Forecast, opts Options) error {
+			return r.RenderHourly(w, f, opts.Hours)
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			hourly := f.Hourly
+			if opts.Hours < len(hourly) {
+				hourly = hourly[:opts.Hours]
+			}
+			return struct {
+				Place  string           `json:"place,omitempty"`
+				Hourly []ForecastHourly `json:"hourly"`
+			}{f.Place, hourly}
+		},
+	},
+	{
+		name:    FunctionWind,
+		exclude: []string{ExcludeMinutely},
+		summary: "Wind aktuell und stündlich",
+		hours:   24,
+		flags:   windEnergyFlags,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			if opts.Energy {
+				PrintWindEnergy(f)
+				return nil
+			}
+			PrintWind(c, f, opts.Hours)
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			if opts.Energy {
+				return struct {
+					Place     string          `json:"place,omitempty"`
+					HubHeight int             `json:"hub_height"`
+					Daily     []WindEnergyDay `json:"daily"`
+				}{f.Place, HubHeight, GetWindEnergy(f)}
+			}
+			hourly := f.Hourly
+			if opts.Hours < len(hourly) {
+				hourly = hourly[:opts.Hours]
+			}
+			windiest, _ := GetWindiestHour(f, 0)
+			return struct {
+				Place     string           `json:"place,omitempty"`
+				Speed     Speed            `json:"wind_speed"`
+				Gust      Speed            `json:"wind_gust"`
+				Direction Direction        `json:"wind_direction"`
+				Windiest  ForecastHourly   `json:"windiest_hour"`
+				Hourly    []ForecastHourly `json:"hourly"`
+			}{f.Place, c.WindSpeed, c.WindGust, c.WindDirection, windiest, hourly}
+		},
+	},
+	{
+		name:    FunctionSun,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely, ExcludeHourly},
+		summary: "Sonnenauf-/untergang und Tageslänge",
+		flags:   sunFlags,
+		days:    8,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			PrintSun(f, sunNow(f, opts), opts.Photo)
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			type sunDay struct {
+				Day       string    `json:"day"`
+				Sunrise   string    `json:"sunrise"`
+				Sunset    string    `json:"sunset"`
+				DayLength int64     `json:"day_length_seconds"`
+				Photo     *PhotoDay `json:"photo,omitempty"`
+			}
+			days := []sunDay{}
+			for i, day := range f.Daily {
+				d := sunDay{Day: day.Day, Sunrise: day.Sunrise, Sunset: day.Sunset, DayLength: int64(day.DayLength / time.Second)}
+				if p, ok := f.photoDay(i); ok && opts.Photo {
+					d.Photo = &p
+				}
+				days = append(days, d)
+			}
+			position := sunNow(f, opts)
+			return struct {
+				Place    string       `json:"place,omitempty"`
+				Position *SunPosition `json:"position,omitempty"`
+				Daily    []sunDay     `json:"daily"`
+			}{f.Place, position, days}
+		},
+		offline: func(date time.Time, coordinates Coordinates, opts Options) Forecast {
+			return SunForecast(date, coordinates, opts.Days)
+		},
+	},
+	{
+		name:    FunctionUV,
+		exclude: []string{ExcludeMinutely, ExcludeHourly},
+		summary: "UV-Index mit Schutzempfehlung",
+		days:    8,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			PrintUV(c, f)
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			type uvDay struct {
+				Day      string  `json:"day"`
+				Max      UVIndex `json:"uvi_max"`
+				Category string  `json:"category"`
+			}
+			days := []uvDay{}
+			for _, day := range f.Daily {
+				days = append(days, uvDay{day.Day, day.UVI, day.UVI.Category()})
+			}
+			return struct {
+				Place    string  `json:"place,omitempty"`
+				Current  UVIndex `json:"uvi"`
+				Category string  `json:"category"`
+				Advice   string  `json:"advice"`
+				Daily    []uvDay `json:"daily"`
+			}{f.Place, c.UVI, c.UVI.Category(), c.UVI.Advice(), days}
+		},
+	},
+	{
+		name:     FunctionDegreeDays,
+		exclude:  []string{ExcludeCurrent, ExcludeMinutely, ExcludeHourly},
+		summary:  "Heiz- und Kühlgradtage der Woche",
+		days:     8,
+		flags:    degreeDayFlags,
+		validate: validateDegreeDays,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			PrintDegreeDays(f, opts.Base)
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			days := GetDegreeDays(f, opts.Base)
+			heating, cooling := SumDegreeDays(days)
+			return struct {
+				Place   string      `json:"place,omitempty"`
+				Units   string      `json:"units"`
+				Base    float64     `json:"base"`
+				Heating float64     `json:"heating"`
+				Cooling float64     `json:"cooling"`
+				Daily   []DegreeDay `json:"daily"`
+			}{f.Place, f.Units, opts.Base, heating, cooling, days}
+		},
+	},
+	{
+		name:    FunctionStargazing,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely},
+		summary: "die besten Nächte der Woche für den Sternenhimmel",
+		days:    8,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			PrintStargazing(f)
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			nights := GetStargazing(f)
+			best := ""
+			if i := BestNight(nights); i >= 0 {
+				best = nights[i].Day
+			}
+			return struct {
+				Place  string            `json:"place,omitempty"`
+				Best   string            `json:"best,omitempty"`
+				Nights []StargazingNight `json:"nights"`
+			}{f.Place, best, nights}
+		},
+	},
+	{
+		name:    FunctionRun,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely, ExcludeDaily},
+		summary: "die besten Stunden zum Laufen und für Sport im Freien",
+		hours:   24,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			PrintSport(f, opts.Hours)
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			hours := []SportHour{}
+			end := time.Now().Add(time.Duration(opts.Hours) * time.Hour)
+			for _, h := range GetSportHours(f) {
+				if !h.Time.After(end) {
+					hours = append(hours, h)
+				}
+			}
+			return struct {
+				Place   string        `json:"place,omitempty"`
+				Windows []SportWindow `json:"windows"`
+				Hourly  []SportHour   `json:"hourly"`
+			}{f.Place, BestSportWindows(GetSportHours(f)), hours}
+		},
+	},
+	{
+		name:     FunctionCommute,
+		exclude:  []string{ExcludeCurrent, ExcludeMinutely},
+		summary:  "bleibt der Weg zur Arbeit und zurück heute und morgen trocken?",
+		flags:    commuteFlags,
+		validate: validateCommute,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			PrintCommute(f, opts.Commute, time.Now())
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			type tripJSON struct {
+				CommuteTrip
+				Advice string `json:"advice"`
+			}
+			trips := []tripJSON{}
+			for _, t := range GetCommute(f, opts.Commute, time.Now()) {
+				trips = append(trips, tripJSON{t, t.Advice()})
+			}
+			return struct {
+				Place string     `json:"place,omitempty"`
+				Trips []tripJSON `json:"trips"`
+			}{f.Place, trips}
+		},
+	},
+	{
+		name:    FunctionLaundry,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely},
+		summary: "kann die Wäsche heute draußen trocknen?",
+		print: func(c Conditions, f Forecast, opts Options) error {
+			PrintLaundry(f)
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			type dryingJSON struct {
+				DryingDay
+				Advice string `json:"advice"`
+			}
+			days := []dryingJSON{}
+			for _, d := range GetDrying(f) {
+				days = append(days, dryingJSON{d, d.Advice()})
+			}
+			return struct {
+				Place string       `json:"place,omitempty"`
+				Daily []dryingJSON `json:"daily"`
+			}{f.Place, days}
+		},
+	},
+	{
+		name:    FunctionMoon,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely, ExcludeHourly},
+		summary: "Mondauf-/untergang und Mondphasen",
+		flags:   moonIconsFlags,
+		days:    8,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			PrintMoon(f, opts.Icons)
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			type moonJSON struct {
+				ForecastDaily
+				Illumination float64 `json:"moon_illumination"`
+				Waxing       bool    `json:"moon_waxing"`
+				Age          float64 `json:"moon_age"`
+			}
+			days := []moonJSON{}
+			for _, day := range f.Daily {
+				p := day.Moonphase
+				days = append(days, moonJSON{day, math.Round(p.Illumination()*100) / 100, p.Waxing(), math.Round(p.Age()*10) / 10})
+			}
+			after := f.nextMoonsAfter(time.Now())
+			return struct {
+				Place        string     `json:"place,omitempty"`
+				Daily        []moonJSON `json:"daily"`
+				NextFullMoon time.Time  `json:"next_full_moon"`
+				NextNewMoon  time.Time  `json:"next_new_moon"`
+			}{f.Place, days, NextMoonPhase(after, true), NextMoonPhase(after, false)}
+		},
+		offline: func(date time.Time, coordinates Coordinates, opts Options) Forecast {
+			return MoonForecast(date, opts.Days)
+		},
+	},
+	{
+		name:    FunctionRain,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely},
+		summary: "Niederschlag der nächsten Tage",
+		days:    3,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			PrintRain(f)
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			type rainDay struct {
+				Day        string  `json:"day"`
+				RainChance float64 `json:"rain_chance"`
+				Rain       float64 `json:"rain"`
+				Snow       float64 `json:"snow"`
+			}
+			days := []rainDay{}
+			for _, day := range f.Daily {
+				days = append(days, rainDay{day.Day, day.RainChance, day.Rain, day.Snow})
+			}
+			return struct {
+				Place  string           `json:"place,omitempty"`
+				Hourly []ForecastHourly `json:"hourly"`
+				Daily  []rainDay        `json:"daily"`
+			}{f.Place, f.Hourly, days}
+		},
+	},
+	{
+		name:    FunctionNowcast,
+		exclude: []string{ExcludeCurrent, ExcludeHourly, ExcludeDaily},
+		summary: "Niederschlag der nächsten Stunde minutengenau",
+		print: func(c Conditions, f Forecast, opts Options) error {
+			return PrintNowcast(f)
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			// without the minutely forecast the nowcast is left out
+			n, _ := GetNowcast(f)
+			return struct {
+				Place    string             `json:"place,omitempty"`
+				Nowcast  Nowcast            `json:"nowcast"`
+				Minutely []ForecastMinutely `json:"minutely"`
+			}{f.Place, n, f.Minutely}
+		},
+	},
+	{
+		name:     FunctionGraph,
+		exclude:  []string{ExcludeCurrent, ExcludeMinutely},
+		summary:  "Tagesverlauf als Grafik, z.B. graph temp today",
+		usage:    "MEASUREMENT [today|tomorrow|aftertomorrow|WEEKDAY] [LOCATION] | --png FILE [LOCATION]",
+		flags:    graphFlags,
+		validate: validateGraph,
+		words:    append(graphNames(), FunctionToday, FunctionTomorrow, FunctionAfterTomorrow),
+		render: func(r Renderer, w io.Writer, c Conditions, f Forecast, opts Options) error {
+			if opts.Output != "" {
+				err := writeChart(f, opts.Output)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintf(w, "Grafik gespeichert in %s\n", opts.Output)
+				return err
+			}
+			g, err := GetGraph(f, opts.Graph, opts.Day)
+			if err != nil {
+				return err
+			}
+			return r.RenderGraph(w, g)
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			// without the hourly forecast of the day the graph is left empty
+			g, _ := GetGraph(f, opts.Graph, opts.Day)
+			return g
+		},
+	},
+	{
+		name:    FunctionAlert,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely, ExcludeHourly},
+		summary: "Warnungen der nächsten Tage",
+		render: func(r Renderer, w io.Writer, c Conditions, f Forecast, opts Options) error {
+			if opts.ICS {
+				return WriteAlertsICS(w, f.Alerts(), opts.Remind, time.Now())
+			}
+			return r.RenderAlerts(w, f)
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			alerts := forecastAlerts(f)
+			return alertsJSON{f.Place, len(alerts), alerts}
+		},
+		flags:    alertFlags,
+		validate: validateAlert,
+	},
+	{
+		name:     CommandCheck,
+		summary:  "prüft still eine Bedingung für Skripte: rain, temp oder alert, Exit-Code 0 wenn erfüllt, sonst 1",
+		usage:    "CONDITION [LOCATION]",
+		runOpts:  runCheck,
+		flags:    checkFlags,
+		validate: validateCheck,
+		words:    checkConditions,
+	},
+	{
+		name:     CommandNotify,
+		summary:  "neue Warnungen und Regen per ntfy melden",
+		runOpts:  runNotify,
+		flags:    notifyFlags,
+		validate: validateNotify,
+	},
+	{
+		name:    CommandWatch,
+		summary: "Wetter laufend aktualisieren und melden",
+		runOpts: runWatch,
+		// polled often, the minutely forecast isn't shown
+		exclude:  []string{ExcludeMinutely},
+		interval: 15 * time.Minute,
+		flags:    notifyFlags,
+		validate: validateNotify,
+	},
+	{
+		name:    CommandDaemon,
+		summary: "geplante Aufgaben der Konfiguration ausführen",
+		run:     runDaemon,
+	},
+	{
+		name:    FunctionAQI,
+		summary: "Luftqualität",
+		runOpts: runAQI,
+	},
+	{
+		name:    FunctionPollen,
+		summary: "Pollenflug von Gräsern, Birke, Ambrosia und mehr",
+		runOpts: runPollen,
+		days:    3,
+	},
+	{
+		name:    FunctionAstro,
+		summary: "Kalender eines Monats mit Sonnenauf- und -untergang, Mondphasen, Voll- und Neumond",
+		runOpts: runAstro,
+		flags:   astroFlags,
+	},
+	{
+		name:    FunctionGarden,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely, ExcludeHourly},
+		summary: "gießen oder nicht gießen? Wasserbilanz des Gartens",
+		runOpts: runGarden,
+		days:    8,
+	},
+	{
+		name:     FunctionCompare,
+		summary:  "Wetter mehrerer Orte vergleichen",
+		runOpts:  runCompare,
+		validate: validateCompare,
+	},
+	{
+		name:    CommandHistory,
+		summary: "Verlauf und Trends der gespeicherten Messungen",
+		runOpts: runHistory,
+		days:    30,
+	},
+	{
+		name:     FunctionHistoryAt,
+		summary:  "beobachtetes Wetter zu einem vergangenen Zeitpunkt",
+		usage:    "TIME [LOCATION]",
+		runOpts:  runHistoryAt,
+		validate: validateAt(FunctionHistoryAt, parseHistoricalAt),
+	},
+	{
+		name:    CommandConsensus,
+		summary: "Übereinstimmung mehrerer Provider",
+		runOpts: runConsensus,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely, ExcludeHourly},
+		days:    3,
+	},
+	{
+		name:     CommandMap,
+		summary:  "Wetterkarte (Niederschlag, Wolken, ...) als PNG speichern",
+		runOpts:  runMap,
+		flags:    mapFlags,
+		validate: validateMap,
+	},
+	{
+		name:     FunctionAt,
+		summary:  "stündliche Vorhersage zu einer Uhrzeit, z.B. 18:30",
+		usage:    "TIME [LOCATION]",
+		runOpts:  runAt,
+		exclude:  []string{ExcludeCurrent, ExcludeMinutely, ExcludeDaily},
+		validate: validateAt(FunctionAt, ParseTimeOfDay),
+	},
+	{
+		name:     CommandRoute,
+		summary:  "Vorhersage entlang eines GPX-Tracks zur Ankunftszeit",
+		usage:    "GPX-FILE",
+		runOpts:  runRoute,
+		exclude:  []string{ExcludeCurrent, ExcludeMinutely, ExcludeDaily},
+		flags:    routeFlags,
+		validate: validateRoute,
+	},
+	{
+		name:    CommandDiff,
+		summary: "Änderungen der Vorhersage seit dem letzten Aufruf",
+		runOpts: runDiff,
+	},
+	{
+		name:    CommandSearch,
+		summary: "Orte suchen",
+		run:     runSearch,
+	},
+	{
+		name:    CommandLocations,
+		summary: "Ortsaliase verwalten (add, list, remove)",
+		words:   []string{"add", "list", "remove"},
+		run: func(env *cliEnv, args []string) error {
+			return RunLocations(args)
+		},
+	},
+	{
+		name:    CommandSchema,
+		summary: "JSON Schema der Ausgaben (alert, alerts, conditions, current, forecast)",
+		words:   SchemaNames(),
+		run:     runSchema,
+	},
+	{
+		name:    CommandVersion,
+		summary: "Version, Provider und Basis-URL",
+		run:     runVersion,
+	},
+	{
+		name:    CommandServe,
+		summary: "HTTP-Server mit JSON-Endpunkten",
+		serve:   true,
+		run:     runServe,
+	},
+	{
+		name:    CommandExporter,
+		summary: "Prometheus-Metriken unter /metrics",
+		runOpts: runExporter,
+		// polled often, the minutely forecast isn't shown
+		exclude:  []string{ExcludeMinutely},
+		metric:   true,
+		listen:   ":9265",
+		interval: 5 * time.Minute,
+	},
+}
+
+// forecastCommand ... today, tomorrow and aftertomorrow only differ in the offset, the day of the options
+func forecastCommand(name, summary string, offset int) command {
+	return command{
+		name:    name,
+		summary: summary,
+		exclude: []string{ExcludeCurrent, ExcludeMinutely},
+		flags: func(fs *flag.FlagSet, opts *Options) {
+			opts.Day = offset
+			gardenFlags(fs, opts)
+		},
+		render: func(r Renderer, w io.Writer, c Conditions, f Forecast, opts Options) error {
+			err := r.RenderForecast(w, f, opts.Day)
+			if err == nil && opts.Garden {
+				printGarden(f, opts.Day)
+			}
+			return err
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			hourly := []ForecastHourly{}
+			for _, slot := range f.Hourly {
+				if slot.Day == f.Daily[opts.Day].Day {
+					hourly = append(hourly, slot)
+				}
+			}
+			var et0 *float64
+			if v, ok := f.ET0(opts.Day); ok && opts.Garden {
+				et0 = &v
+			}
+			return struct {
+				Place  string           `json:"place,omitempty"`
+				Day    ForecastDaily    `json:"day"`
+				Hourly []ForecastHourly `json:"hourly"`
+				ET0    *float64         `json:"et0,omitempty"`
+			}{f.Place, f.Daily[opts.Day], hourly, et0}
+		},
+		icon: func(c Conditions, f Forecast, opts Options) string {
+			return f.Daily[opts.Day].Icon
+		},
+	}
+}
+
+// onelineCommand ... the weather in the line of the template, whose default is set by flags
+func onelineCommand(name, summary string, flags func(fs *flag.FlagSet, opts *Options)) command {
+	return command{
+		name:    name,
+		exclude: []string{ExcludeMinutely, ExcludeHourly},
+		summary: summary,
+		flags:   flags,
+		print: func(c Conditions, f Forecast, opts Options) error {
+			fmt.Println(FormatOneline(opts.Template, c, f))
+			return nil
+		},
+		data: func(c Conditions, f Forecast, opts Options) any {
+			return struct {
+				Place string `json:"place,omitempty"`
+				Line  string `json:"line"`
+			}{f.Place, FormatOneline(opts.Template, c, f)}
+		},
+	}
+}
+
+// statusbarCommand ... the short line of a status bar, the only command with the formats statusbar and waybar
+func statusbarCommand() command {
+	c := onelineCommand(FunctionStatusbar, "kurze Zeile für tmux, polybar und i3blocks oder Waybar-Modul "+
+		"(--format statusbar oder waybar), mit --follow laufend", statusbarFlags)
+	// refreshed by --follow
+	c.interval = 10 * time.Minute
+	c.formats = []string{FormatStatusbar, FormatWaybar}
+	c.validate = validateStatusbar
+	return c
+}
+
+// weekdayCommand ... forecast of the day given by --day or a weekday name, e.g. "forecast saturday Berlin,DE"
+func weekdayCommand(name, summary string) command {
+	c := forecastCommand(name, summary, 0)
+	c.usage = "[WEEKDAY] [LOCATION]"
+	c.flags = func(fs *flag.FlagSet, opts *Options) {
+		gardenFlags(fs, opts)
+		fs.IntVar(&opts.Day, "day", opts.Day, "day of the forecast, 0 is today, 7 at most")
+	}
+	c.validate = validateWeekday
+	c.words = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+	return c
+}
+
+// validateWeekday ... a weekday name in front of the location replaces --day
+func validateWeekday(fs *flag.FlagSet, opts *Options) error {
+	if len(opts.Args) == 0 {
+		return nil
+	}
+	weekday, ok := weekdays[strings.ToLower(opts.Args[0])]
+	if !ok {
+		return nil
+	}
+	if isFlagSet(fs, "day") {
+		return errors.New("--day and a weekday are mutually exclusive")
+	}
+	opts.Day = weekdayOffset(weekday, time.Now().Weekday())
+	opts.Args = opts.Args[1:]
+	return nil
+}
+
+// weekdayOffset ... offset of the next day with the weekday from now on, today is 0
+func weekdayOffset(weekday, now time.Weekday) int {
+	return (int(weekday) - int(now) + 7) % 7
+}
+
+func (sc ServeCommand) command() command {
+	return command{
+		name:    sc.Name,
+		summary: sc.Summary,
+		serve:   true,
+		run: func(env *cliEnv, args []string) error {
+			s, listen, err := env.server(sc.Name, sc.Listen, args)
+			if err != nil {
+				return err
+			}
+			return sc.Serve(s, listen)
+		},
+	}
+}
+
+// takesLocation ... true for commands accepting the location and other weather flags
+func (c command) takesLocation() bool {
+	return c.run == nil
+}
+
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// RunCLI ... runs the CLI with os.Args, extra adds serve commands of the binary
+func RunCLI(extra ...ServeCommand) {
+	err := Run(os.Args, extra...)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		if exit.Err != nil {
+			fmt.Fprintln(os.Stderr, exit.Err)
+		}
+		os.Exit(exit.Code)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Run ... executes the CLI with the given arguments, args[0] is the program name
+func Run(args []string, extra ...ServeCommand) error {
+	for _, sc := range extra {
+		if _, ok := lookupCommand(sc.Name); !ok {
+			commands = append(commands, sc.command())
+		}
+	}
+	env := &cliEnv{name: "weather"}
+	if len(args) > 0 {
+		env.name = filepath.Base(args[0])
+	}
+	if len(args) < 2 || args[1] == CommandHelp || args[1] == "-h" || args[1] == "--help" {
+		usage(env.name, os.Stdout)
+		return nil
+	}
+	cmd, ok := lookupCommand(args[1])
+	if !ok {
+		usage(env.name, os.Stderr)
+		return fmt.Errorf("unknown command %q", args[1])
+	}
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	env.cfg = cfg
+	env.cfgPath = path
+	if cmd.run != nil {
+		return cmd.run(env, args[2:])
+	}
+	return env.runWeather(cmd, args[2:])
+}
+
+func usage(name string, w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s COMMAND [FLAGS] [LOCATION]\n\nCommands:\n", name)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nExample: %s current London,UK\n\n", name)
+	fmt.Fprintf(w, "Run '%s COMMAND --help' for the flags of a command.\n", name)
+	fmt.Fprintln(w, "LOCATION defaults to WEATHER_DEFAULT_LOCATION or default_location in the config file.")
+}
+
+// NewOptions ... defaults for the flags, taken from the config where possible
+func NewOptions(cfg Config) Options {
+	opts := Options{
+		Units:  UnitsMetric,
+		Lang:   "de",
+		Format: FormatText,
+	}
+	if cfg.Units != "" {
+		opts.Units = cfg.Units
+	}
+	if cfg.Lang != "" {
+		opts.Lang = cfg.Lang
+	}
+	return opts
+}
+
+// ParseOptions ... parses the flags of a weather command, positional arguments form the location
+func ParseOptions(cmd string, args []string, cfg Config) (Options, error) {
+	c, ok := lookupCommand(cmd)
+	if !ok {
+		return Options{}, fmt.Errorf("unknown command %q", cmd)
+	}
+	return parseOptions(c, args, cfg)
+}
+
+// parseOptions ... ParseOptions for a command at hand, which keeps the command list free of initialization cycles
+func parseOptions(c command, args []string, cfg Config) (Options, error) {
+	opts := NewOptions(cfg)
+	opts.Days = c.days
+	opts.Hours = c.hours
+	opts.Listen = c.listen
+	opts.Interval = c.interval
+	opts.Exclude = c.exclude
+	if c.metric {
+		opts.Units = UnitsMetric
+	}
+	fs := weatherFlags(c, &opts)
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.Args = positional
+	if c.validate != nil {
+		err = c.validate(fs, &opts)
+		if err != nil {
+			return Options{}, err
+		}
+	}
+	if opts.Location == "" {
+		opts.Location = JoinLocation(opts.Args)
+	}
+	if !validUnits[opts.Units] {
+		return Options{}, fmt.Errorf("invalid units %q, want metric, imperial or standard", opts.Units)
+	}
+	formats := append([]string{FormatText, FormatJSON}, c.formats...)
+	switch {
+	case validFormat[opts.Format] || contains(c.formats, opts.Format):
+	case opts.Format == FormatStatusbar || opts.Format == FormatWaybar:
+		return Options{}, fmt.Errorf("invalid format %q, want %s, %s is printed by the %s command", opts.Format, orList(formats), opts.Format, FunctionStatusbar)
+	default:
+		return Options{}, fmt.Errorf("invalid format %q, want %s", opts.Format, orList(formats))
+	}
+	if c.days > 0 && opts.Days < 1 {
+		return Options{}, fmt.Errorf("invalid number of days %d, want at least 1", opts.Days)
+	}
+	if c.hours > 0 && (opts.Hours < 1 || opts.Hours > 48) {
+		return Options{}, fmt.Errorf("invalid number of hours %d, want between 1 and 48", opts.Hours)
+	}
+	if c.interval > 0 && opts.Interval < time.Minute {
+		return Options{}, fmt.Errorf("invalid interval %s, want at least 1m", opts.Interval)
+	}
+	if opts.Day < 0 {
+		return Options{}, fmt.Errorf("invalid day %d, want 0 for today or later", opts.Day)
+	}
+	if opts.Icon != "" && !validGraphics[opts.Icon] {
+		return Options{}, fmt.Errorf("invalid icon graphics %q, want auto, kitty or sixel", opts.Icon)
+	}
+	if c.render != nil && !validColors[opts.Color] {
+		return Options{}, fmt.Errorf("invalid color %q, want auto, always or never", opts.Color)
+	}
+	return opts, nil
+}
+
+// weatherFlags ... own flags of the command and the flags of the weather commands, writing into opts
+func weatherFlags(c command, opts *Options) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	if c.flags != nil {
+		c.flags(fs, opts)
+	}
+	fs.StringVar(&opts.Location, "location", "", "location like London,UK, lat,lon or an alias")
+	fs.StringVar(&opts.Zip, "zip", "", "postal code with country, e.g. 10115,DE")
+	fs.BoolVar(&opts.Here, "here", false, "determine the location from the public IP via ipinfo.io")
+	if !c.metric {
+		fs.StringVar(&opts.Units, "units", opts.Units, "units: metric, imperial or standard")
+	}
+	fs.StringVar(&opts.Lang, "lang", opts.Lang, "language of the weather descriptions")
+	fs.StringVar(&opts.Format, "format", opts.Format, "output format: "+orList(append([]string{FormatText, FormatJSON}, c.formats...)))
+	if c.days > 0 {
+		fs.IntVar(&opts.Days, "days", opts.Days, "number of days to show")
+	}
+	if c.hours > 0 {
+		fs.IntVar(&opts.Hours, "hours", opts.Hours, "number of hours to show (max. 48)")
+	}
+	if c.listen != "" {
+		fs.StringVar(&opts.Listen, "listen", opts.Listen, "address to listen on")
+	}
+	if c.interval > 0 {
+		fs.DurationVar(&opts.Interval, "interval", opts.Interval, "how often the weather is refreshed")
+	}
+	if c.offline != nil {
+		fs.Func("date", "first day like 2025-12-24, computed locally without the weather API", func(s string) (err error) {
+			opts.Date, err = time.ParseInLocation("2006-01-02", s, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid date %q, want e.g. 2025-12-24", s)
+			}
+			return nil
+		})
+		fs.BoolVar(&opts.Offline, "offline", false, "compute locally from today on without the weather API")
+	}
+	if c.icon != nil {
+		fs.StringVar(&opts.Icon, "icon", "", "render the weather icon in the terminal: auto, kitty or sixel")
+	}
+	if c.render != nil {
+		fs.StringVar(&opts.Color, "color", ColorAuto, "colour the output: auto, always or never")
+	}
+	if c.print != nil || c.render != nil {
+		fs.BoolVar(&opts.Notify, "notify", false, "raise desktop notifications for new alerts and rain within the next hour")
+	}
+	usage := c.usage
+	if usage == "" {
+		usage = "[LOCATION]"
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [FLAGS] %s\n\n%s\n\nFlags:\n", c.name, usage, c.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// isFlagSet ... true if the flag was given on the command line
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// parseInterleaved ... allows flags after positional arguments, e.g. "current London,UK --units imperial"
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		// negative coordinates like -33.9,18.42 are no flags
+		for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+			if _, ok := ParseCoordinates(args[0]); !ok {
+				break
+			}
+			positional = append(positional, args[0])
+			args = args[1:]
+		}
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// NewClientFromConfig ... client with the settings of the config file, key is the API key
+func NewClientFromConfig(key string, cfg Config) *Client {
+	c := NewClient(key)
+	if cfg.BaseURL != "" {
+		c.BaseURL = cfg.BaseURL
+	}
+	if cfg.TileURL != "" {
+		c.TileURL = cfg.TileURL
+	}
+	if cfg.IconURL != "" {
+		c.IconURL = cfg.IconURL
+	}
+	if cfg.PollenURL != "" {
+		c.PollenURL = cfg.PollenURL
+	}
+	if cfg.MeteoAlarmURL != "" {
+		c.MeteoAlarmURL = cfg.MeteoAlarmURL
+	}
+	if cfg.DWDURL != "" {
+		c.DWDURL = cfg.DWDURL
+	}
+	if cfg.GeoLimit > 0 {
+		c.GeoLimit = cfg.GeoLimit
+	}
+	c.API = cfg.API
+	return c
+}
+
+func (env *cliEnv) client() (*Client, error) {
+	key := os.Getenv("OPENWEATHERMAP_API_KEY")
+	if key == "" {
+		return nil, errors.New("please set the env variable OPENWEATHERMAP_API_KEY")
+	}
+	return NewClientFromConfig(key, env.cfg), nil
+}
+
+// locate ... coordinates for the location given by the options, falling back to the default location
+func (env *cliEnv) locate(c *Client, opts Options) (Coordinates, error) {
+	switch {
+	case opts.Here:
+		return DefaultGeolocator.Locate()
+	case opts.Zip != "":
+		return c.GetCoordinatesByZip(opts.Zip)
+	}
+	location := opts.Location
+	if location == "" {
+		location = GetDefaultLocation(env.cfg)
+	}
+	if location == "" {
+		return Coordinates{}, errors.New("no location given and no default location configured")
+	}
+	location = env.cfg.ResolveLocation(location)
+	if isTerminal(os.Stdin) {
+		return c.LocateInteractive(location, os.Stdin, os.Stderr)
+	}
+	return c.Locate(location)
+}
+
+// fetch ... weather for the location given by the options, including the resolved place name
+func (env *cliEnv) fetch(opts Options) (Conditions, Forecast, error) {
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	// the store and the notifications need the whole weather
+	if env.cfg.Store == nil && !opts.Notify {
+		c.Exclude = opts.Exclude
+	}
+	conditions, forecast, err := env.provider(c, opts).GetWeather(coordinates)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	env.record(Observation{
+		Location:   notifyLocation(opts, forecast),
+		Time:       time.Now(),
+		Conditions: conditions,
+		Forecast:   forecast,
+	})
+	return conditions, forecast, nil
+}
+
+// configuredProvider ... client whose weather is completed by the config file for every command, watch and the daemon:
+// the place name, the alerts of the alert sources translated into the language, the pressure trend and the fire
+// danger from the stored observations and the frost
+type configuredProvider struct {
+	*Client
+	env  *cliEnv
+	opts Options
+}
+
+// provider ... the client completing its weather like the weather commands, for the options of the command
+func (env *cliEnv) provider(c *Client, opts Options) Provider {
+	return &configuredProvider{Client: c, env: env, opts: opts}
+}
+
+func (p *configuredProvider) Get(location string) (Conditions, Forecast, error) {
+	coordinates, err := p.Locate(location)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	return p.GetWeather(coordinates)
+}
+
+func (p *configuredProvider) GetWeather(coordinates Coordinates) (Conditions, Forecast, error) {
+	conditions, forecast, err := p.Client.GetWeather(coordinates)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	// the place name is only informative, so a failing lookup results in an empty name
+	place, err := p.ReverseGeocode(coordinates)
+	if err == nil {
+		forecast.Place = place.String()
+	}
+	if !p.excludes(ExcludeAlerts) {
+		p.env.addAlerts(p.Client, coordinates, place, &forecast)
+		p.env.translateAlerts(&forecast, p.opts.Lang)
+	}
+	// the stored observations lack this one, which the pressure trend starts from
+	o := Observation{
+		Location:   notifyLocation(p.opts, forecast),
+		Time:       time.Now(),
+		Conditions: conditions,
+		Forecast:   forecast,
+	}
+	recent := append(p.env.recent(o.Location, o.Time.Add(-recentPeriod), o.Time), o)
+	if trend, ok := GetPressureTrend(recent); ok {
+		conditions.PressureTrend = &trend
+	}
+	SetFireDanger(&forecast, RecentRain(recent, o.Time.Format("02.01.2006")), p.env.cfg.FireThreshold(forecast.Place))
+	SetFrost(&forecast, p.env.cfg.FrostLimit())
+	return conditions, forecast, nil
+}
+
+// offline ... forecast computed by the offline function of the command without the weather API
+func (env *cliEnv) offline(cmd command, opts Options) (Forecast, error) {
+	coordinates, place, err := env.locateOffline(opts)
+	if err != nil {
+		return Forecast{}, err
+	}
+	date := opts.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	forecast := cmd.offline(date, coordinates, opts)
+	forecast.Units = opts.Units
+	forecast.Place = place
+	return forecast, nil
+}
+
+// locateOffline ... coordinates and place name of the location for commands computing locally, the API is only asked
+// if the API key is set, without it the location must be coordinates or an alias of them and has no place name
+func (env *cliEnv) locateOffline(opts Options) (Coordinates, string, error) {
+	key := os.Getenv("OPENWEATHERMAP_API_KEY")
+	c := NewClientFromConfig(key, env.cfg)
+	c.Lang = opts.Lang
+	coordinates, err := env.locate(c, opts)
+	if err != nil {
+		return Coordinates{}, "", err
+	}
+	if key == "" {
+		return coordinates, "", nil
+	}
+	return coordinates, placeName(c, coordinates), nil
+}
+
+// resolve ... client configured by the options and the coordinates of the requested location
+func (env *cliEnv) resolve(opts Options) (*Client, Coordinates, error) {
+	c, err := env.client()
+	if err != nil {
+		return nil, Coordinates{}, err
+	}
+	c.Units = opts.Units
+	c.Lang = opts.Lang
+	coordinates, err := env.locate(c, opts)
+	if err != nil {
+		return nil, Coordinates{}, err
+	}
+	return c, coordinates, nil
+}
+
+// placeName ... the place name is only informative, so a failing lookup results in an empty name
+func placeName(c *Client, coordinates Coordinates) string {
+	place, err := c.ReverseGeocode(coordinates)
+	if err != nil {
+		return ""
+	}
+	return place.String()
+}
+
+func (env *cliEnv) runWeather(cmd command, args []string) (err error) {
+	// failures exit with ExitFailure, where the exit code tells something else
+	failure := func() {
+		var exit *ExitError
+		if err != nil && !errors.As(err, &exit) {
+			err = &ExitError{Code: ExitFailure, Err: err}
+		}
+	}
+	if cmd.name == CommandCheck {
+		defer failure()
+	}
+	opts, err := parseOptions(cmd, args, env.cfg)
+	if err != nil {
+		return err
+	}
+	if opts.ExitCode {
+		defer failure()
+	}
+	if cmd.runOpts != nil {
+		return cmd.runOpts(env, opts)
+	}
+	if opts.Follow {
+		return env.runFollow(opts)
+	}
+	var conditions Conditions
+	var forecast Forecast
+	if cmd.offline != nil && (opts.Offline || !opts.Date.IsZero()) {
+		forecast, err = env.offline(cmd, opts)
+	} else {
+		conditions, forecast, err = env.fetch(opts)
+	}
+	if err != nil {
+		return err
+	}
+	if opts.Notify {
+		err = env.notifyDesktop(opts, forecast)
+		if err != nil {
+			return err
+		}
+	}
+	if opts.Alerts.filters() {
+		forecast = FilterAlerts(forecast, opts.Alerts, time.Now())
+	}
+	if opts.Days > 0 && opts.Days < len(forecast.Daily) {
+		forecast.Daily = forecast.Daily[:opts.Days]
+	}
+	if opts.Day > 0 && opts.Day >= len(forecast.Daily) {
+		return fmt.Errorf("day %d is out of range, the forecast has %d days", opts.Day, len(forecast.Daily))
+	}
+	switch opts.Format {
+	case FormatJSON:
+		err = printJSON(os.Stdout, cmd.data(conditions, forecast, opts))
+	case FormatStatusbar:
+		_, err = fmt.Println(StatusLine(opts.Bar, opts.Template, conditions, forecast))
+	case FormatWaybar:
+		err = writeWaybar(os.Stdout, GetWaybar(opts.Template, conditions, forecast))
+	default:
+		if cmd.render != nil {
+			err = cmd.render(env.renderer(opts), os.Stdout, conditions, forecast, opts)
+		} else {
+			err = cmd.print(conditions, forecast, opts)
+		}
+		if err == nil && opts.Icon != "" {
+			env.printIcon(cmd.icon(conditions, forecast, opts), opts.Icon)
+		}
+	}
+	if err == nil && opts.ExitCode && len(forecastAlerts(forecast)) > 0 {
+		return &ExitError{Code: ExitAlerts}
+	}
+	return err
+}
+
+// renderer ... renderer of the text output of the options, coloured as --color says and laid out for the terminal
+func (env *cliEnv) renderer(opts Options) Renderer {
+	return TextRenderer{
+		Icons: opts.Icons,
+		Color: UseColor(opts.Color, os.Stdout, os.Getenv),
+		Width: TerminalWidth(os.Stdout, os.Getenv),
+	}
+}
+
+// printIcon ... the icon is only decoration, so a failing download or an unknown terminal shows nothing
+func (env *cliEnv) printIcon(icon, graphics string) {
+	if graphics == GraphicsAuto {
+		if !isTerminal(os.Stdout) {
+			return
+		}
+		graphics = DetectGraphics(os.Getenv)
+	}
+	c, err := env.client()
+	if graphics == "" || icon == "" || err != nil {
+		return
+	}
+	data, err := c.GetIcon(icon)
+	if err != nil {
+		return
+	}
+	WriteImage(os.Stdout, data, graphics)
+}
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func searchFlags(opts *Options, limit *int) *flag.FlagSet {
+	fs := flag.NewFlagSet(CommandSearch, flag.ContinueOnError)
+	fs.StringVar(&opts.Format, "format", opts.Format, "output format: text or json")
+	fs.IntVar(limit, "limit", 0, "maximum number of matches (1-5)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [FLAGS] QUERY\n\nFlags:\n", CommandSearch)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func runSearch(env *cliEnv, args []string) error {
+	opts := NewOptions(env.cfg)
+	limit := 0
+	fs := searchFlags(&opts, &limit)
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: %s %s QUERY", env.name, CommandSearch)
+	}
+	c, err := env.client()
+	if err != nil {
+		return err
+	}
+	if limit > 0 {
+		c.GeoLimit = limit
+	}
+	results, err := c.GetCoordinatesAll(strings.Join(positional, "+"))
+	if err != nil {
+		return err
+	}
+	if opts.Format == FormatJSON {
+		return printJSON(os.Stdout, results)
+	}
+	PrintSearchResults(strings.Join(positional, " "), results)
+	return nil
+}
+
+// RunLocations ... manages the location aliases in the config file
+func RunLocations(args []string) error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("usage: %s locations add ALIAS LOCATION | list | remove ALIAS", os.Args[0])
+	}
+	switch args[0] {
+	case "add":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: %s locations add ALIAS LOCATION", os.Args[0])
+		}
+		err = cfg.AddLocation(args[1], strings.Join(args[2:], " "))
+		if err != nil {
+			return err
+		}
+		return SaveConfig(path, cfg)
+	case "remove":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: %s locations remove ALIAS", os.Args[0])
+		}
+		err = cfg.RemoveLocation(args[1])
+		if err != nil {
+			return err
+		}
+		return SaveConfig(path, cfg)
+	case "list":
+		for _, alias := range cfg.LocationAliases() {
+			fmt.Printf("%s: %s\n", alias, cfg.Locations[alias])
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown locations subcommand %q, want add, list or remove", args[0])
+}
+
+// isTerminal ... true if f is an interactive terminal and not a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// GetLocation ... location from the raw arguments, args[0] is the program and args[1] the function
+func GetLocation(args []string) string {
+	if len(args) < 3 {
+		return ""
+	}
+	return JoinLocation(args[2:])
+}
+
+// JoinLocation ... location formed by positional words, e.g. "New", "York" becomes "New+York"
+func JoinLocation(words []string) string {
+	return strings.Join(words, "+")
+}
+
+// orList ... words of the help and error messages like "text, json or statusbar"
+func orList(words []string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " or " + words[len(words)-1]
+}
+
+func GetFunction(args []string) string {
+	return strings.Join(args[1:2], "")
+}
@@ -0,0 +1,267 @@
+package weather_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseOptionsPositionalLocation(t *testing.T) {
+	t.Parallel()
+	want := weather.Options{
+		Location: "What+a+long+Place",
+		Units:    weather.UnitsMetric,
+		Lang:     "de",
+		Format:   weather.FormatText,
+		Color:    weather.ColorAuto,
+		Exclude:  []string{weather.ExcludeMinutely, weather.ExcludeHourly},
+		Args:     []string{"What", "a", "long", "Place"},
+	}
+	got, err := weather.ParseOptions("current", []string{"What", "a", "long", "Place"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseOptionsFlagsAfterLocation(t *testing.T) {
+	t.Parallel()
+	want := weather.Options{
+		Location: "London,UK",
+		Units:    weather.UnitsImperial,
+		Lang:     "en",
+		Format:   weather.FormatJSON,
+		Days:     5,
+		Exclude:  []string{weather.ExcludeCurrent, weather.ExcludeMinutely, weather.ExcludeHourly},
+		Args:     []string{"London,UK"},
+	}
+	args := []string{"London,UK", "--units", "imperial", "--lang=en", "--format", "json", "--days", "5"}
+	got, err := weather.ParseOptions("moon", args, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseOptionsZipAndHere(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParseOptions("current", []string{"--zip", "10115,DE"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Zip != "10115,DE" || got.Location != "" {
+		t.Errorf("want zip 10115,DE without location, got %+v", got)
+	}
+	got, err = weather.ParseOptions("current", []string{"--here"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Here {
+		t.Error("want --here to be set")
+	}
+}
+
+func TestParseOptionsNegativeCoordinates(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParseOptions("current", []string{"-33.9,18.42"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Location != "-33.9,18.42" {
+		t.Errorf("want location -33.9,18.42, got %s", got.Location)
+	}
+}
+
+func TestParseOptionsDefaultsFromConfig(t *testing.T) {
+	t.Parallel()
+	cfg := weather.Config{Units: weather.UnitsImperial, Lang: "en"}
+	got, err := weather.ParseOptions("today", nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Units != weather.UnitsImperial || got.Lang != "en" {
+		t.Errorf("want units and lang from config, got %+v", got)
+	}
+}
+
+func TestParseOptionsInvalid(t *testing.T) {
+	t.Parallel()
+	tests := map[string][]string{
+		"units":   {"--units", "kelvin"},
+		"format":  {"--format", "xml"},
+		"days":    {"--days", "0"},
+		"unknown": {"--foo"},
+	}
+	for name, args := range tests {
+		_, err := weather.ParseOptions("rain", args, weather.Config{})
+		if err == nil {
+			t.Errorf("%s: want error, but got nil", name)
+		}
+	}
+}
+
+func TestParseOptionsUnknownCommand(t *testing.T) {
+	t.Parallel()
+	_, err := weather.ParseOptions("yesterday", nil, weather.Config{})
+	if err == nil {
+		t.Fatal("want error for unknown command, but got nil")
+	}
+}
+
+func TestTemperatureUnit(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"":                    "°C",
+		weather.UnitsMetric:   "°C",
+		weather.UnitsImperial: "°F",
+		weather.UnitsStandard: "K",
+	}
+	for units, want := range tests {
+		got := weather.Forecast{Units: units}.TemperatureUnit()
+		if want != got {
+			t.Errorf("%s: want %s, got %s", units, want, got)
+		}
+	}
+}
+
+func TestFormatSpeed(t *testing.T) {
+	t.Parallel()
+	if got := (weather.Forecast{Units: weather.UnitsMetric}).FormatSpeed(10); got != "36 km/h" {
+		t.Errorf("want 36 km/h, got %s", got)
+	}
+	if got := (weather.Forecast{Units: weather.UnitsImperial}).FormatSpeed(10); got != "10 mph" {
+		t.Errorf("want 10 mph, got %s", got)
+	}
+}
+
+func TestParseOptionsHours(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParseOptions("hourly", []string{"Bonn", "--hours", "12"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Hours != 12 {
+		t.Errorf("want 12 hours, got %d", got.Hours)
+	}
+	_, err = weather.ParseOptions("hourly", []string{"--hours", "72"}, weather.Config{})
+	if err == nil {
+		t.Error("want error for more than 48 hours, but got nil")
+	}
+	_, err = weather.ParseOptions("current", []string{"--hours", "12"}, weather.Config{})
+	if err == nil {
+		t.Error("want error for --hours on current, but got nil")
+	}
+}
+
+func TestParseOptionsCompareLocations(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParseOptions("compare", []string{"Berlin,DE", "--units", "metric", "Hamburg,DE"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Berlin,DE", "Hamburg,DE"}
+	if !cmp.Equal(want, got.Args) {
+		t.Error(cmp.Diff(want, got.Args))
+	}
+}
+
+func TestParseOptionsCompareRejectsLocationFlags(t *testing.T) {
+	t.Parallel()
+	tests := map[string][]string{
+		"location": {"Berlin,DE", "Hamburg,DE", "--location", "Bonn,DE"},
+		"zip":      {"Berlin,DE", "Hamburg,DE", "--zip", "10115,DE"},
+		"here":     {"Berlin,DE", "--here"},
+	}
+	for name, args := range tests {
+		_, err := weather.ParseOptions("compare", args, weather.Config{})
+		if err == nil {
+			t.Errorf("%s: want error, but got nil", name)
+		}
+	}
+}
+
+func TestParseOptionsDay(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParseOptions("forecast", []string{"Berlin,DE", "--day", "5"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Day != 5 || got.Location != "Berlin,DE" {
+		t.Errorf("want day 5 of Berlin,DE, got %d of %s", got.Day, got.Location)
+	}
+	got, err = weather.ParseOptions("tomorrow", []string{"Berlin,DE"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Day != 1 {
+		t.Errorf("want day 1 for tomorrow, got %d", got.Day)
+	}
+	tests := map[string][]string{
+		"negative":  {"forecast", "--day", "-1"},
+		"today":     {"today", "--day", "3"},
+		"exclusive": {"forecast", "--day", "3", "saturday"},
+	}
+	for name, args := range tests {
+		_, err := weather.ParseOptions(args[0], args[1:], weather.Config{})
+		if err == nil {
+			t.Errorf("%s: want error, but got nil", name)
+		}
+	}
+}
+
+func TestParseOptionsWeekday(t *testing.T) {
+	t.Parallel()
+	afterTomorrow := (time.Now().Weekday() + 2) % 7
+	for _, name := range []string{strings.ToLower(afterTomorrow.String()), strings.ToUpper(afterTomorrow.String())} {
+		got, err := weather.ParseOptions("forecast", []string{name, "Berlin,DE"}, weather.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"Berlin,DE"}
+		if got.Day != 2 || !cmp.Equal(want, got.Args) {
+			t.Errorf("%s: want day 2 of %v, got day %d of %v", name, want, got.Day, got.Args)
+		}
+	}
+	got, err := weather.ParseOptions("forecast", []string{"Freitag"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (int(time.Friday) - int(time.Now().Weekday()) + 7) % 7; got.Day != want {
+		t.Errorf("want day %d for Freitag, got %d", want, got.Day)
+	}
+}
+
+func TestWeatherCommandExcludesBlocks(t *testing.T) {
+	tests := map[string]string{
+		"moon":    "current,minutely,hourly",
+		"nowcast": "current,hourly,daily",
+	}
+	for cmd, want := range tests {
+		var got string
+		mux := http.NewServeMux()
+		mux.Handle("/", weathertest.Handler())
+		mux.HandleFunc("/data/3.0/onecall", func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL.Query().Get("exclude")
+			w.Write(weathertest.WeatherResponse)
+		})
+		ts := httptest.NewServer(mux)
+		_, err := runCLI(t, ts, cmd, "Bonn,DE")
+		ts.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+		if want != got {
+			t.Errorf("%s: want exclude %s, got %s", cmd, want, got)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Package grpcserver ... gRPC frontend of weather.Server, kept out of the library so only the binary depends on gRPC
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/cmd/weatherpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const CommandGRPCServe = "grpc-serve"
+
+// Command ... grpc-serve for weather.RunCLI
+var Command = weather.ServeCommand{
+	Name:    CommandGRPCServe,
+	Summary: "gRPC-Server mit Streaming-Updates",
+	Listen:  ":9090",
+	Serve:   Serve,
+}
+
+// GRPCServer ... gRPC frontend of the Server, answering from its cache
+type GRPCServer struct {
+	weatherpb.UnimplementedWeatherServiceServer
+	Server *weather.Server
+}
+
+// NewGRPCServer ... gRPC server with the API key check of s, the service is registered already
+func NewGRPCServer(s *weather.Server) *grpc.Server {
+	gs := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			err := authorized(s, ctx)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := authorized(s, ss.Context())
+			if err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	weatherpb.RegisterWeatherServiceServer(gs, &GRPCServer{Server: s})
+	return gs
+}
+
+// authorized ... checks the x-api-key or authorization metadata, if keys are configured
+func authorized(s *weather.Server, ctx context.Context) error {
+	if len(s.APIKeys) == 0 {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range md.Get("x-api-key") {
+		if s.ValidKey(key) {
+			return nil
+		}
+	}
+	for _, key := range md.Get("authorization") {
+		if s.ValidKey(strings.TrimPrefix(key, "Bearer ")) {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid API key")
+}
+
+func (g *GRPCServer) GetConditions(ctx context.Context, req *weatherpb.LocationRequest) (*weatherpb.ConditionsResponse, error) {
+	conditions, forecast, err := g.lookup(req.GetLocation())
+	if err != nil {
+		return nil, err
+	}
+	return conditionsResponse(conditions, forecast), nil
+}
+
+func (g *GRPCServer) GetForecast(ctx context.Context, req *weatherpb.LocationRequest) (*weatherpb.Forecast, error) {
+	_, forecast, err := g.lookup(req.GetLocation())
+	if err != nil {
+		return nil, err
+	}
+	return forecastProto(forecast), nil
+}
+
+func (g *GRPCServer) GetAlerts(ctx context.Context, req *weatherpb.LocationRequest) (*weatherpb.AlertsResponse, error) {
+	_, forecast, err := g.lookup(req.GetLocation())
+	if err != nil {
+		return nil, err
+	}
+	return &weatherpb.AlertsResponse{Alerts: alertsProto(forecast.CurrentAlerts())}, nil
+}
+
+// WatchConditions ... sends the conditions whenever they differ from the last ones sent
+func (g *GRPCServer) WatchConditions(req *weatherpb.WatchRequest, stream weatherpb.WeatherService_WatchConditionsServer) error {
+	interval := time.Duration(req.GetIntervalSeconds()) * time.Second
+	if interval <= 0 {
+		interval = g.Server.TTL
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last weather.Conditions
+	lastAlerts := -1
+	for {
+		conditions, forecast, err := g.lookup(req.GetLocation())
+		switch {
+		case status.Code(err) == codes.InvalidArgument:
+			return err
+		case err != nil:
+			// a failing update keeps the stream open, the next tick may succeed, lookup logged the cause
+		default:
+			alerts := len(forecast.CurrentAlerts())
+			if conditions != last || alerts != lastAlerts {
+				err = stream.Send(conditionsResponse(conditions, forecast))
+				if err != nil {
+					return err
+				}
+				last, lastAlerts = conditions, alerts
+			}
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lookup ... weather from the server, with errors mapped to gRPC status codes
+//
+// Upstream errors may contain the request URL including the OpenWeatherMap key,
+// so clients only get a fixed message and the cause is logged.
+func (g *GRPCServer) lookup(location string) (weather.Conditions, weather.Forecast, error) {
+	conditions, forecast, err := g.Server.Lookup(location)
+	switch {
+	case errors.Is(err, weather.ErrBadRequest):
+		return weather.Conditions{}, weather.Forecast{}, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		log.Printf("lookup %s: %v", location, err)
+		return weather.Conditions{}, weather.Forecast{}, status.Error(codes.Unavailable, "upstream unavailable")
+	}
+	return conditions, forecast, nil
+}
+
+func conditionsResponse(c weather.Conditions, f weather.Forecast) *weatherpb.ConditionsResponse {
+	return &weatherpb.ConditionsResponse{
+		Conditions: conditionsProto(c),
+		Alerts:     alertsProto(f.CurrentAlerts()),
+	}
+}
+
+func conditionsProto(c weather.Conditions) *weatherpb.Conditions {
+	return &weatherpb.Conditions{
+		Timestamp:     c.Timestamp,
+		Sunrise:       c.Sunrise,
+		Sunset:        c.Sunset,
+		Summary:       c.Summary,
+		Temperature:   c.Temperature,
+		FeelsLike:     c.FeelsLike,
+		DewPoint:      c.DewPoint,
+		Pressure:      int32(c.Pressure),
+		Humidity:      int32(c.Humidity),
+		WindSpeed:     float64(c.WindSpeed),
+		WindGust:      float64(c.WindGust),
+		WindDirection: float64(c.WindDirection),
+		Uvi:           float64(c.UVI),
+	}
+}
+
+func alertsProto(alerts []weather.Alert) []*weatherpb.Alert {
+	result := []*weatherpb.Alert{}
+	for _, a := range alerts {
+		result = append(result, &weatherpb.Alert{
+			Start:       a.Start,
+			End:         a.End,
+			Name:        a.Name,
+			Description: a.Description,
+		})
+	}
+	return result
+}
+
+func forecastProto(f weather.Forecast) *weatherpb.Forecast {
+	result := &weatherpb.Forecast{
+		Place: f.Place,
+		Units: f.Units,
+	}
+	for _, h := range f.Hourly {
+		result.Hourly = append(result.Hourly, &weatherpb.HourlyForecast{
+			Day:           h.Day,
+			Hour:          h.Hour,
+			Temperature:   h.Temperature,
+			FeelsLike:     h.FeelsLike,
+			RainChance:    h.RainChance,
+			WindSpeed:     float64(h.WindSpeed),
+			WindGust:      float64(h.WindGust),
+			WindDirection: float64(h.WindDirection),
+		})
+	}
+	for _, d := range f.Daily {
+		result.Daily = append(result.Daily, &weatherpb.DailyForecast{
+			Day:              d.Day,
+			Description:      d.Description,
+			Sunrise:          d.Sunrise,
+			Sunset:           d.Sunset,
+			DayLengthSeconds: int64(d.DayLength / time.Second),
+			Moonrise:         d.Moonrise,
+			Moonset:          d.Moonset,
+			Moonphase:        float64(d.Moonphase),
+			Temp: &weatherpb.DailyTemperatures{
+				Max:     d.Temp.Max,
+				Min:     d.Temp.Min,
+				Morning: d.Temp.Morning,
+				Day:     d.Temp.Day,
+				Evening: d.Temp.Evening,
+				Night:   d.Temp.Night,
+			},
+			RainChance:    d.RainChance,
+			WindSpeed:     float64(d.WindSpeed),
+			WindDirection: float64(d.WindDirection),
+			Uvi:           float64(d.UVI),
+			Alerts:        alertsProto(d.Alerts),
+		})
+	}
+	return result
+}
+
+// Serve ... serves s over gRPC on the listen address until the listener fails
+func Serve(s *weather.Server, listen string) error {
+	lis, err := net.Listen("tcp", listen)
+	if err != nil {
+		return err
+	}
+	log.Printf("listening on %s", listen)
+	return NewGRPCServer(s).Serve(lis)
+}
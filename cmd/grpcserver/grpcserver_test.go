@@ -0,0 +1,132 @@
+package grpcserver_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/cmd/grpcserver"
+	"github.com/cntzr/weather/cmd/weatherpb"
+	"github.com/cntzr/weather/weathertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newMock() *weathertest.Mock {
+	return &weathertest.Mock{
+		Conditions: weather.Conditions{Summary: "Clear", Temperature: 21.5},
+		Forecast: weather.Forecast{
+			Units: weather.UnitsMetric,
+			Daily: []weather.ForecastDaily{{Day: "Fr, 17.06.", Alerts: []weather.Alert{{Name: "Hitze"}}}},
+		},
+	}
+}
+
+func newGRPCClient(t *testing.T, s *weather.Server) weatherpb.WeatherServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := grpcserver.NewGRPCServer(s)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return weatherpb.NewWeatherServiceClient(conn)
+}
+
+func TestGRPCGetConditions(t *testing.T) {
+	t.Parallel()
+	client := newGRPCClient(t, weather.NewServer(newMock(), time.Minute))
+	resp, err := client.GetConditions(context.Background(), &weatherpb.LocationRequest{Location: "Bonn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetConditions().GetTemperature() != 21.5 {
+		t.Errorf("want temperature 21.5, got %v", resp.GetConditions().GetTemperature())
+	}
+	if len(resp.GetAlerts()) != 1 || resp.GetAlerts()[0].GetName() != "Hitze" {
+		t.Errorf("want alert Hitze, got %v", resp.GetAlerts())
+	}
+}
+
+func TestGRPCGetForecast(t *testing.T) {
+	t.Parallel()
+	client := newGRPCClient(t, weather.NewServer(newMock(), time.Minute))
+	resp, err := client.GetForecast(context.Background(), &weatherpb.LocationRequest{Location: "Bonn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetDaily()) != 1 || resp.GetDaily()[0].GetDay() != "Fr, 17.06." {
+		t.Errorf("want one day Fr, 17.06., got %v", resp.GetDaily())
+	}
+	if resp.GetUnits() != weather.UnitsMetric {
+		t.Errorf("want units %q, got %q", weather.UnitsMetric, resp.GetUnits())
+	}
+}
+
+func TestGRPCErrors(t *testing.T) {
+	t.Parallel()
+	s := weather.NewServer(newMock(), time.Minute)
+	s.APIKeys = []string{"secret"}
+	client := newGRPCClient(t, s)
+	_, err := client.GetAlerts(context.Background(), &weatherpb.LocationRequest{Location: "Bonn"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("want Unauthenticated without key, got %v", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret")
+	_, err = client.GetAlerts(ctx, &weatherpb.LocationRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want InvalidArgument without location, got %v", err)
+	}
+	_, err = client.GetAlerts(ctx, &weatherpb.LocationRequest{Location: "Bonn"})
+	if err != nil {
+		t.Errorf("want no error with key, got %v", err)
+	}
+}
+
+func TestGRPCHidesUpstreamErrors(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	m.Err = errors.New(`Get "https://api.openweathermap.org/data/3.0/onecall?appid=SECRET-OWM-KEY": connection refused`)
+	client := newGRPCClient(t, weather.NewServer(m, time.Minute))
+	_, err := client.GetConditions(context.Background(), &weatherpb.LocationRequest{Location: "Bonn"})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("want Unavailable, got %v", err)
+	}
+	if strings.Contains(err.Error(), "SECRET-OWM-KEY") {
+		t.Errorf("want upstream error hidden from the client, got %v", err)
+	}
+}
+
+func TestGRPCWatchConditions(t *testing.T) {
+	t.Parallel()
+	client := newGRPCClient(t, weather.NewServer(newMock(), time.Minute))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchConditions(ctx, &weatherpb.WatchRequest{Location: "Bonn", IntervalSeconds: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetConditions().GetSummary() != "Clear" {
+		t.Errorf("want summary Clear, got %q", resp.GetConditions().GetSummary())
+	}
+}
@@ -2,8 +2,18 @@ package main
 
 import (
 	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/cmd/grpcserver"
+	_ "github.com/cntzr/weather/cmd/sqldrivers"
+)
+
+// set by goreleaser via -ldflags
+var (
+	version string
+	commit  string
+	date    string
 )
 
 func main() {
-	weather.RunCLI()
+	weather.SetBuildInfo(version, commit, date)
+	weather.RunCLI(grpcserver.Command)
 }
@@ -0,0 +1,10 @@
+// Package sqldrivers registers the database/sql drivers of the weather stores,
+// so only the binary depends on them and the library stays free of cgo and third-party modules.
+package sqldrivers
+
+import (
+	// PostgreSQL and TimescaleDB, registered as "pgx"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	// pure Go SQLite, registered as "sqlite"
+	_ "modernc.org/sqlite"
+)
@@ -0,0 +1,85 @@
+package sqldrivers_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	_ "github.com/cntzr/weather/cmd/sqldrivers"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	t.Parallel()
+	testStore(t, weather.DriverSQLite, filepath.Join(t.TempDir(), "history.db"), "Bonn")
+}
+
+// TestPostgresStore ... runs against the database of WEATHER_TEST_POSTGRES, e.g. postgres://localhost/weather_test
+func TestPostgresStore(t *testing.T) {
+	t.Parallel()
+	dsn := os.Getenv("WEATHER_TEST_POSTGRES")
+	if dsn == "" {
+		t.Skip("WEATHER_TEST_POSTGRES not set")
+	}
+	// the table outlives the test, so every run uses a location of its own
+	testStore(t, weather.DriverPostgres, dsn, fmt.Sprintf("Bonn %d", time.Now().UnixNano()))
+}
+
+// testStore ... round trip of observations through a store, which is reopened in between
+func testStore(t *testing.T, driver, dsn, location string) {
+	t.Helper()
+	s, err := weather.OpenSQLStore(driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC)
+	for i, temperature := range []float64{21.5, 23, 19} {
+		err := s.Write(weather.Observation{
+			Location:   location,
+			Time:       start.Add(time.Duration(i) * time.Hour),
+			Conditions: weather.Conditions{Summary: "Clear", Temperature: temperature, Pressure: 1015},
+			Forecast:   weather.Forecast{Units: weather.UnitsMetric, Daily: []weather.ForecastDaily{{Alerts: []weather.Alert{{Name: "Hitze"}}}}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = s.Write(weather.Observation{Location: location + " Beuel", Time: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reopening runs the migrations only once
+	s, err = weather.OpenSQLStore(driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	got, err := s.Observations(location, start.Add(time.Hour), start.Add(3*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 observations, got %d", len(got))
+	}
+	if got[0].Conditions.Temperature != 23 || got[1].Conditions.Temperature != 19 || !got[0].Time.Equal(start.Add(time.Hour)) {
+		t.Errorf("unexpected observations %+v", got)
+	}
+	if got[0].Forecast.CurrentAlerts()[0].Name != "Hitze" || got[0].Location != location {
+		t.Errorf("want forecast and location restored, got %+v", got[0])
+	}
+}
+
+func TestOpenSQLStoreUnknownDriver(t *testing.T) {
+	t.Parallel()
+	_, err := weather.OpenSQLStore("oracle", "")
+	if err == nil {
+		t.Error("want error for unsupported driver, but got nil")
+	}
+}
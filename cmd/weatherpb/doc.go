@@ -0,0 +1,4 @@
+// Package weatherpb ... protobuf messages and gRPC service of weather grpc-serve, generated from weather.proto
+package weatherpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative weather.proto
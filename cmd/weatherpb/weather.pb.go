@@ -0,0 +1,987 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: weather.proto
+
+// Weather data of the weather serve modes, mirroring the Go types of github.com/cntzr/weather.
+
+package weatherpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LocationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// location like London,UK, lat,lon or an alias of the server config
+	Location      string `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationRequest) Reset() {
+	*x = LocationRequest{}
+	mi := &file_weather_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationRequest) ProtoMessage() {}
+
+func (x *LocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationRequest.ProtoReflect.Descriptor instead.
+func (*LocationRequest) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LocationRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+type WatchRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Location string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// how often the server checks for updates, the cache TTL if unset
+	IntervalSeconds int32 `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_weather_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WatchRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+type Conditions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     string                 `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Sunrise       string                 `protobuf:"bytes,2,opt,name=sunrise,proto3" json:"sunrise,omitempty"`
+	Sunset        string                 `protobuf:"bytes,3,opt,name=sunset,proto3" json:"sunset,omitempty"`
+	Summary       string                 `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	Temperature   float64                `protobuf:"fixed64,5,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	FeelsLike     float64                `protobuf:"fixed64,6,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
+	DewPoint      float64                `protobuf:"fixed64,7,opt,name=dew_point,json=dewPoint,proto3" json:"dew_point,omitempty"`
+	Pressure      int32                  `protobuf:"varint,8,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	Humidity      int32                  `protobuf:"varint,9,opt,name=humidity,proto3" json:"humidity,omitempty"`
+	WindSpeed     float64                `protobuf:"fixed64,10,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	WindGust      float64                `protobuf:"fixed64,11,opt,name=wind_gust,json=windGust,proto3" json:"wind_gust,omitempty"`
+	WindDirection float64                `protobuf:"fixed64,12,opt,name=wind_direction,json=windDirection,proto3" json:"wind_direction,omitempty"`
+	Uvi           float64                `protobuf:"fixed64,13,opt,name=uvi,proto3" json:"uvi,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Conditions) Reset() {
+	*x = Conditions{}
+	mi := &file_weather_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Conditions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Conditions) ProtoMessage() {}
+
+func (x *Conditions) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Conditions.ProtoReflect.Descriptor instead.
+func (*Conditions) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Conditions) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *Conditions) GetSunrise() string {
+	if x != nil {
+		return x.Sunrise
+	}
+	return ""
+}
+
+func (x *Conditions) GetSunset() string {
+	if x != nil {
+		return x.Sunset
+	}
+	return ""
+}
+
+func (x *Conditions) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Conditions) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *Conditions) GetFeelsLike() float64 {
+	if x != nil {
+		return x.FeelsLike
+	}
+	return 0
+}
+
+func (x *Conditions) GetDewPoint() float64 {
+	if x != nil {
+		return x.DewPoint
+	}
+	return 0
+}
+
+func (x *Conditions) GetPressure() int32 {
+	if x != nil {
+		return x.Pressure
+	}
+	return 0
+}
+
+func (x *Conditions) GetHumidity() int32 {
+	if x != nil {
+		return x.Humidity
+	}
+	return 0
+}
+
+func (x *Conditions) GetWindSpeed() float64 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *Conditions) GetWindGust() float64 {
+	if x != nil {
+		return x.WindGust
+	}
+	return 0
+}
+
+func (x *Conditions) GetWindDirection() float64 {
+	if x != nil {
+		return x.WindDirection
+	}
+	return 0
+}
+
+func (x *Conditions) GetUvi() float64 {
+	if x != nil {
+		return x.Uvi
+	}
+	return 0
+}
+
+type ConditionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conditions    *Conditions            `protobuf:"bytes,1,opt,name=conditions,proto3" json:"conditions,omitempty"`
+	Alerts        []*Alert               `protobuf:"bytes,2,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConditionsResponse) Reset() {
+	*x = ConditionsResponse{}
+	mi := &file_weather_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConditionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConditionsResponse) ProtoMessage() {}
+
+func (x *ConditionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConditionsResponse.ProtoReflect.Descriptor instead.
+func (*ConditionsResponse) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ConditionsResponse) GetConditions() *Conditions {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+func (x *ConditionsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type AlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertsResponse) Reset() {
+	*x = AlertsResponse{}
+	mi := &file_weather_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertsResponse) ProtoMessage() {}
+
+func (x *AlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertsResponse.ProtoReflect.Descriptor instead.
+func (*AlertsResponse) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type Alert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         string                 `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_weather_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Alert) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Alert) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *Alert) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Alert) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type HourlyForecast struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`
+	Hour          string                 `protobuf:"bytes,2,opt,name=hour,proto3" json:"hour,omitempty"`
+	Temperature   float64                `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	FeelsLike     float64                `protobuf:"fixed64,4,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
+	RainChance    float64                `protobuf:"fixed64,5,opt,name=rain_chance,json=rainChance,proto3" json:"rain_chance,omitempty"`
+	WindSpeed     float64                `protobuf:"fixed64,6,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	WindGust      float64                `protobuf:"fixed64,7,opt,name=wind_gust,json=windGust,proto3" json:"wind_gust,omitempty"`
+	WindDirection float64                `protobuf:"fixed64,8,opt,name=wind_direction,json=windDirection,proto3" json:"wind_direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HourlyForecast) Reset() {
+	*x = HourlyForecast{}
+	mi := &file_weather_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HourlyForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HourlyForecast) ProtoMessage() {}
+
+func (x *HourlyForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HourlyForecast.ProtoReflect.Descriptor instead.
+func (*HourlyForecast) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HourlyForecast) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *HourlyForecast) GetHour() string {
+	if x != nil {
+		return x.Hour
+	}
+	return ""
+}
+
+func (x *HourlyForecast) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *HourlyForecast) GetFeelsLike() float64 {
+	if x != nil {
+		return x.FeelsLike
+	}
+	return 0
+}
+
+func (x *HourlyForecast) GetRainChance() float64 {
+	if x != nil {
+		return x.RainChance
+	}
+	return 0
+}
+
+func (x *HourlyForecast) GetWindSpeed() float64 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *HourlyForecast) GetWindGust() float64 {
+	if x != nil {
+		return x.WindGust
+	}
+	return 0
+}
+
+func (x *HourlyForecast) GetWindDirection() float64 {
+	if x != nil {
+		return x.WindDirection
+	}
+	return 0
+}
+
+type DailyTemperatures struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Max           float64                `protobuf:"fixed64,1,opt,name=max,proto3" json:"max,omitempty"`
+	Min           float64                `protobuf:"fixed64,2,opt,name=min,proto3" json:"min,omitempty"`
+	Morning       float64                `protobuf:"fixed64,3,opt,name=morning,proto3" json:"morning,omitempty"`
+	Day           float64                `protobuf:"fixed64,4,opt,name=day,proto3" json:"day,omitempty"`
+	Evening       float64                `protobuf:"fixed64,5,opt,name=evening,proto3" json:"evening,omitempty"`
+	Night         float64                `protobuf:"fixed64,6,opt,name=night,proto3" json:"night,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailyTemperatures) Reset() {
+	*x = DailyTemperatures{}
+	mi := &file_weather_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyTemperatures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyTemperatures) ProtoMessage() {}
+
+func (x *DailyTemperatures) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyTemperatures.ProtoReflect.Descriptor instead.
+func (*DailyTemperatures) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DailyTemperatures) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *DailyTemperatures) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *DailyTemperatures) GetMorning() float64 {
+	if x != nil {
+		return x.Morning
+	}
+	return 0
+}
+
+func (x *DailyTemperatures) GetDay() float64 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+func (x *DailyTemperatures) GetEvening() float64 {
+	if x != nil {
+		return x.Evening
+	}
+	return 0
+}
+
+func (x *DailyTemperatures) GetNight() float64 {
+	if x != nil {
+		return x.Night
+	}
+	return 0
+}
+
+type DailyForecast struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Day              string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`
+	Description      string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Sunrise          string                 `protobuf:"bytes,3,opt,name=sunrise,proto3" json:"sunrise,omitempty"`
+	Sunset           string                 `protobuf:"bytes,4,opt,name=sunset,proto3" json:"sunset,omitempty"`
+	DayLengthSeconds int64                  `protobuf:"varint,5,opt,name=day_length_seconds,json=dayLengthSeconds,proto3" json:"day_length_seconds,omitempty"`
+	Moonrise         string                 `protobuf:"bytes,6,opt,name=moonrise,proto3" json:"moonrise,omitempty"`
+	Moonset          string                 `protobuf:"bytes,7,opt,name=moonset,proto3" json:"moonset,omitempty"`
+	Moonphase        float64                `protobuf:"fixed64,8,opt,name=moonphase,proto3" json:"moonphase,omitempty"`
+	Temp             *DailyTemperatures     `protobuf:"bytes,9,opt,name=temp,proto3" json:"temp,omitempty"`
+	RainChance       float64                `protobuf:"fixed64,10,opt,name=rain_chance,json=rainChance,proto3" json:"rain_chance,omitempty"`
+	WindSpeed        float64                `protobuf:"fixed64,11,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	WindDirection    float64                `protobuf:"fixed64,12,opt,name=wind_direction,json=windDirection,proto3" json:"wind_direction,omitempty"`
+	Uvi              float64                `protobuf:"fixed64,13,opt,name=uvi,proto3" json:"uvi,omitempty"`
+	Alerts           []*Alert               `protobuf:"bytes,14,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DailyForecast) Reset() {
+	*x = DailyForecast{}
+	mi := &file_weather_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyForecast) ProtoMessage() {}
+
+func (x *DailyForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyForecast.ProtoReflect.Descriptor instead.
+func (*DailyForecast) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DailyForecast) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *DailyForecast) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *DailyForecast) GetSunrise() string {
+	if x != nil {
+		return x.Sunrise
+	}
+	return ""
+}
+
+func (x *DailyForecast) GetSunset() string {
+	if x != nil {
+		return x.Sunset
+	}
+	return ""
+}
+
+func (x *DailyForecast) GetDayLengthSeconds() int64 {
+	if x != nil {
+		return x.DayLengthSeconds
+	}
+	return 0
+}
+
+func (x *DailyForecast) GetMoonrise() string {
+	if x != nil {
+		return x.Moonrise
+	}
+	return ""
+}
+
+func (x *DailyForecast) GetMoonset() string {
+	if x != nil {
+		return x.Moonset
+	}
+	return ""
+}
+
+func (x *DailyForecast) GetMoonphase() float64 {
+	if x != nil {
+		return x.Moonphase
+	}
+	return 0
+}
+
+func (x *DailyForecast) GetTemp() *DailyTemperatures {
+	if x != nil {
+		return x.Temp
+	}
+	return nil
+}
+
+func (x *DailyForecast) GetRainChance() float64 {
+	if x != nil {
+		return x.RainChance
+	}
+	return 0
+}
+
+func (x *DailyForecast) GetWindSpeed() float64 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *DailyForecast) GetWindDirection() float64 {
+	if x != nil {
+		return x.WindDirection
+	}
+	return 0
+}
+
+func (x *DailyForecast) GetUvi() float64 {
+	if x != nil {
+		return x.Uvi
+	}
+	return 0
+}
+
+func (x *DailyForecast) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type Forecast struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Place         string                 `protobuf:"bytes,1,opt,name=place,proto3" json:"place,omitempty"`
+	Units         string                 `protobuf:"bytes,2,opt,name=units,proto3" json:"units,omitempty"`
+	Hourly        []*HourlyForecast      `protobuf:"bytes,3,rep,name=hourly,proto3" json:"hourly,omitempty"`
+	Daily         []*DailyForecast       `protobuf:"bytes,4,rep,name=daily,proto3" json:"daily,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Forecast) Reset() {
+	*x = Forecast{}
+	mi := &file_weather_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Forecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Forecast) ProtoMessage() {}
+
+func (x *Forecast) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Forecast.ProtoReflect.Descriptor instead.
+func (*Forecast) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Forecast) GetPlace() string {
+	if x != nil {
+		return x.Place
+	}
+	return ""
+}
+
+func (x *Forecast) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *Forecast) GetHourly() []*HourlyForecast {
+	if x != nil {
+		return x.Hourly
+	}
+	return nil
+}
+
+func (x *Forecast) GetDaily() []*DailyForecast {
+	if x != nil {
+		return x.Daily
+	}
+	return nil
+}
+
+var File_weather_proto protoreflect.FileDescriptor
+
+const file_weather_proto_rawDesc = "" +
+	"\n" +
+	"\rweather.proto\x12\n" +
+	"weather.v1\"-\n" +
+	"\x0fLocationRequest\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\"U\n" +
+	"\fWatchRequest\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\"\x81\x03\n" +
+	"\n" +
+	"Conditions\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\tR\ttimestamp\x12\x18\n" +
+	"\asunrise\x18\x02 \x01(\tR\asunrise\x12\x16\n" +
+	"\x06sunset\x18\x03 \x01(\tR\x06sunset\x12\x18\n" +
+	"\asummary\x18\x04 \x01(\tR\asummary\x12 \n" +
+	"\vtemperature\x18\x05 \x01(\x01R\vtemperature\x12\x1d\n" +
+	"\n" +
+	"feels_like\x18\x06 \x01(\x01R\tfeelsLike\x12\x1b\n" +
+	"\tdew_point\x18\a \x01(\x01R\bdewPoint\x12\x1a\n" +
+	"\bpressure\x18\b \x01(\x05R\bpressure\x12\x1a\n" +
+	"\bhumidity\x18\t \x01(\x05R\bhumidity\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\n" +
+	" \x01(\x01R\twindSpeed\x12\x1b\n" +
+	"\twind_gust\x18\v \x01(\x01R\bwindGust\x12%\n" +
+	"\x0ewind_direction\x18\f \x01(\x01R\rwindDirection\x12\x10\n" +
+	"\x03uvi\x18\r \x01(\x01R\x03uvi\"w\n" +
+	"\x12ConditionsResponse\x126\n" +
+	"\n" +
+	"conditions\x18\x01 \x01(\v2\x16.weather.v1.ConditionsR\n" +
+	"conditions\x12)\n" +
+	"\x06alerts\x18\x02 \x03(\v2\x11.weather.v1.AlertR\x06alerts\";\n" +
+	"\x0eAlertsResponse\x12)\n" +
+	"\x06alerts\x18\x01 \x03(\v2\x11.weather.v1.AlertR\x06alerts\"e\n" +
+	"\x05Alert\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\tR\x03end\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\"\xfb\x01\n" +
+	"\x0eHourlyForecast\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12\x12\n" +
+	"\x04hour\x18\x02 \x01(\tR\x04hour\x12 \n" +
+	"\vtemperature\x18\x03 \x01(\x01R\vtemperature\x12\x1d\n" +
+	"\n" +
+	"feels_like\x18\x04 \x01(\x01R\tfeelsLike\x12\x1f\n" +
+	"\vrain_chance\x18\x05 \x01(\x01R\n" +
+	"rainChance\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\x06 \x01(\x01R\twindSpeed\x12\x1b\n" +
+	"\twind_gust\x18\a \x01(\x01R\bwindGust\x12%\n" +
+	"\x0ewind_direction\x18\b \x01(\x01R\rwindDirection\"\x93\x01\n" +
+	"\x11DailyTemperatures\x12\x10\n" +
+	"\x03max\x18\x01 \x01(\x01R\x03max\x12\x10\n" +
+	"\x03min\x18\x02 \x01(\x01R\x03min\x12\x18\n" +
+	"\amorning\x18\x03 \x01(\x01R\amorning\x12\x10\n" +
+	"\x03day\x18\x04 \x01(\x01R\x03day\x12\x18\n" +
+	"\aevening\x18\x05 \x01(\x01R\aevening\x12\x14\n" +
+	"\x05night\x18\x06 \x01(\x01R\x05night\"\xce\x03\n" +
+	"\rDailyForecast\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x18\n" +
+	"\asunrise\x18\x03 \x01(\tR\asunrise\x12\x16\n" +
+	"\x06sunset\x18\x04 \x01(\tR\x06sunset\x12,\n" +
+	"\x12day_length_seconds\x18\x05 \x01(\x03R\x10dayLengthSeconds\x12\x1a\n" +
+	"\bmoonrise\x18\x06 \x01(\tR\bmoonrise\x12\x18\n" +
+	"\amoonset\x18\a \x01(\tR\amoonset\x12\x1c\n" +
+	"\tmoonphase\x18\b \x01(\x01R\tmoonphase\x121\n" +
+	"\x04temp\x18\t \x01(\v2\x1d.weather.v1.DailyTemperaturesR\x04temp\x12\x1f\n" +
+	"\vrain_chance\x18\n" +
+	" \x01(\x01R\n" +
+	"rainChance\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\v \x01(\x01R\twindSpeed\x12%\n" +
+	"\x0ewind_direction\x18\f \x01(\x01R\rwindDirection\x12\x10\n" +
+	"\x03uvi\x18\r \x01(\x01R\x03uvi\x12)\n" +
+	"\x06alerts\x18\x0e \x03(\v2\x11.weather.v1.AlertR\x06alerts\"\x9b\x01\n" +
+	"\bForecast\x12\x14\n" +
+	"\x05place\x18\x01 \x01(\tR\x05place\x12\x14\n" +
+	"\x05units\x18\x02 \x01(\tR\x05units\x122\n" +
+	"\x06hourly\x18\x03 \x03(\v2\x1a.weather.v1.HourlyForecastR\x06hourly\x12/\n" +
+	"\x05daily\x18\x04 \x03(\v2\x19.weather.v1.DailyForecastR\x05daily2\xb5\x02\n" +
+	"\x0eWeatherService\x12L\n" +
+	"\rGetConditions\x12\x1b.weather.v1.LocationRequest\x1a\x1e.weather.v1.ConditionsResponse\x12@\n" +
+	"\vGetForecast\x12\x1b.weather.v1.LocationRequest\x1a\x14.weather.v1.Forecast\x12D\n" +
+	"\tGetAlerts\x12\x1b.weather.v1.LocationRequest\x1a\x1a.weather.v1.AlertsResponse\x12M\n" +
+	"\x0fWatchConditions\x12\x18.weather.v1.WatchRequest\x1a\x1e.weather.v1.ConditionsResponse0\x01B(Z&github.com/cntzr/weather/cmd/weatherpbb\x06proto3"
+
+var (
+	file_weather_proto_rawDescOnce sync.Once
+	file_weather_proto_rawDescData []byte
+)
+
+func file_weather_proto_rawDescGZIP() []byte {
+	file_weather_proto_rawDescOnce.Do(func() {
+		file_weather_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_weather_proto_rawDesc), len(file_weather_proto_rawDesc)))
+	})
+	return file_weather_proto_rawDescData
+}
+
+var file_weather_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_weather_proto_goTypes = []any{
+	(*LocationRequest)(nil),    // 0: weather.v1.LocationRequest
+	(*WatchRequest)(nil),       // 1: weather.v1.WatchRequest
+	(*Conditions)(nil),         // 2: weather.v1.Conditions
+	(*ConditionsResponse)(nil), // 3: weather.v1.ConditionsResponse
+	(*AlertsResponse)(nil),     // 4: weather.v1.AlertsResponse
+	(*Alert)(nil),              // 5: weather.v1.Alert
+	(*HourlyForecast)(nil),     // 6: weather.v1.HourlyForecast
+	(*DailyTemperatures)(nil),  // 7: weather.v1.DailyTemperatures
+	(*DailyForecast)(nil),      // 8: weather.v1.DailyForecast
+	(*Forecast)(nil),           // 9: weather.v1.Forecast
+}
+var file_weather_proto_depIdxs = []int32{
+	2,  // 0: weather.v1.ConditionsResponse.conditions:type_name -> weather.v1.Conditions
+	5,  // 1: weather.v1.ConditionsResponse.alerts:type_name -> weather.v1.Alert
+	5,  // 2: weather.v1.AlertsResponse.alerts:type_name -> weather.v1.Alert
+	7,  // 3: weather.v1.DailyForecast.temp:type_name -> weather.v1.DailyTemperatures
+	5,  // 4: weather.v1.DailyForecast.alerts:type_name -> weather.v1.Alert
+	6,  // 5: weather.v1.Forecast.hourly:type_name -> weather.v1.HourlyForecast
+	8,  // 6: weather.v1.Forecast.daily:type_name -> weather.v1.DailyForecast
+	0,  // 7: weather.v1.WeatherService.GetConditions:input_type -> weather.v1.LocationRequest
+	0,  // 8: weather.v1.WeatherService.GetForecast:input_type -> weather.v1.LocationRequest
+	0,  // 9: weather.v1.WeatherService.GetAlerts:input_type -> weather.v1.LocationRequest
+	1,  // 10: weather.v1.WeatherService.WatchConditions:input_type -> weather.v1.WatchRequest
+	3,  // 11: weather.v1.WeatherService.GetConditions:output_type -> weather.v1.ConditionsResponse
+	9,  // 12: weather.v1.WeatherService.GetForecast:output_type -> weather.v1.Forecast
+	4,  // 13: weather.v1.WeatherService.GetAlerts:output_type -> weather.v1.AlertsResponse
+	3,  // 14: weather.v1.WeatherService.WatchConditions:output_type -> weather.v1.ConditionsResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_weather_proto_init() }
+func file_weather_proto_init() {
+	if File_weather_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_weather_proto_rawDesc), len(file_weather_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_weather_proto_goTypes,
+		DependencyIndexes: file_weather_proto_depIdxs,
+		MessageInfos:      file_weather_proto_msgTypes,
+	}.Build()
+	File_weather_proto = out.File
+	file_weather_proto_goTypes = nil
+	file_weather_proto_depIdxs = nil
+}
@@ -0,0 +1,122 @@
+package weather
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	// colour modes of the text output, auto colours it if stdout is a terminal and NO_COLOR isn't set
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+
+	// SGR codes of the ANSI escape sequences
+	ansiBlue       = "34"
+	ansiBoldBlue   = "1;34"
+	ansiCyan       = "36"
+	ansiGreen      = "32"
+	ansiYellow     = "33"
+	ansiBoldYellow = "1;33"
+	ansiRed        = "31"
+	ansiBoldRed    = "1;31"
+)
+
+var validColors = map[string]bool{
+	ColorAuto:   true,
+	ColorAlways: true,
+	ColorNever:  true,
+}
+
+// colorize ... the text in the colour of the SGR code, unchanged without code
+func colorize(text, code string) string {
+	if code == "" {
+		return text
+	}
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+// temperatureColor ... blue for frost, cyan below 10 °C, green below 20 °C, yellow below 30 °C and red from 30 °C
+func temperatureColor(celsius float64) string {
+	switch {
+	case celsius <= 0:
+		return ansiBlue
+	case celsius < 10:
+		return ansiCyan
+	case celsius < 20:
+		return ansiGreen
+	case celsius < 30:
+		return ansiYellow
+	}
+	return ansiRed
+}
+
+// rainChanceColor ... cyan from 30 %, bold blue from 60 %, no colour for unlikely rain
+func rainChanceColor(chance float64) string {
+	switch {
+	case chance >= 60:
+		return ansiBoldBlue
+	case chance >= 30:
+		return ansiCyan
+	}
+	return ""
+}
+
+// windColor ... yellow from 7 Beaufort (steifer Wind), red from 9 Beaufort (Sturm), the speed has to be in m/s
+func windColor(s Speed) string {
+	switch {
+	case s.Beaufort() >= 9:
+		return ansiRed
+	case s.Beaufort() >= 7:
+		return ansiYellow
+	}
+	return ""
+}
+
+// alertColor ... yellow for minor, bold yellow for moderate, red for severe and bold red for extreme alerts
+func alertColor(s AlertSeverity) string {
+	switch s {
+	case AlertMinor:
+		return ansiYellow
+	case AlertModerate:
+		return ansiBoldYellow
+	case AlertSevere:
+		return ansiRed
+	case AlertExtreme:
+		return ansiBoldRed
+	}
+	return ""
+}
+
+// UseColor ... true if the output in the colour mode is coloured, auto colours terminals unless NO_COLOR is set
+// or TERM is dumb, see https://no-color.org
+func UseColor(mode string, out *os.File, getenv func(string) string) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorAuto:
+		return isTerminal(out) && getenv("NO_COLOR") == "" && getenv("TERM") != "dumb"
+	}
+	return false
+}
+
+// paint ... the text in the colour of the code if the renderer colours
+func (r TextRenderer) paint(text, code string) string {
+	if !r.Color {
+		return text
+	}
+	return colorize(text, code)
+}
+
+// temperature ... the temperature in the units of the forecast formatted by the verb, coloured by its range
+func (r TextRenderer) temperature(verb string, t float64, f Forecast) string {
+	return r.paint(fmt.Sprintf(verb, t), temperatureColor(convertTemperature(t, f.Units, UnitsMetric)))
+}
+
+// wind ... the text of the wind, coloured by the stronger of speed and gusts in the units of the forecast
+func (r TextRenderer) wind(text string, speed, gust Speed, f Forecast) string {
+	if gust > speed {
+		speed = gust
+	}
+	return r.paint(text, windColor(metersPerSecond(speed, f.Units)))
+}
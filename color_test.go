@@ -0,0 +1,94 @@
+package weather_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestUseColor(t *testing.T) {
+	t.Parallel()
+	f, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	getenv := func(string) string { return "" }
+	if !weather.UseColor(weather.ColorAlways, f, getenv) {
+		t.Error("want colour for always")
+	}
+	if weather.UseColor(weather.ColorNever, f, getenv) || weather.UseColor(weather.ColorAuto, f, getenv) {
+		t.Error("want no colour for never and for a file with auto")
+	}
+}
+
+func TestTextRendererColor(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{
+		Units: weather.UnitsMetric,
+		Daily: []weather.ForecastDaily{
+			{Day: "17.06.2022", Temp: weather.DailyTempBenchmarks{Min: -3, Max: 31}, RainChance: 80, WindSpeed: 22,
+				Alerts: []weather.Alert{{Name: "Orkanböen", Start: "17.06.2022, 18:00", End: "17.06.2022, 22:00", Severity: weather.AlertExtreme}}},
+			{Day: "18.06.2022", Temp: weather.DailyTempBenchmarks{Min: 12, Max: 15}, RainChance: 10, WindSpeed: 3},
+		},
+	}
+	var b bytes.Buffer
+	err := weather.TextRenderer{Color: true}.RenderForecast(&b, f, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Regenwahrscheinlichkeit: \x1b[1;34m80 %\x1b[0m\n",
+		"zwischen \x1b[34m-3\x1b[0m °C und \x1b[31m31\x1b[0m °C",
+		"Wind: \x1b[31m79 km/h",
+		"\x1b[1;31mOrkanböen\x1b[0m (extrem) von",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("want %q in %q", want, b.String())
+		}
+	}
+	b.Reset()
+	err = weather.TextRenderer{Color: true}.RenderForecast(&b, f, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Regenwahrscheinlichkeit: 10 %\n") || !strings.Contains(b.String(), "Wind: 11 km/h") {
+		t.Errorf("want no colour for unlikely rain and light wind, got %q", b.String())
+	}
+	b.Reset()
+	err = weather.TextRenderer{}.RenderForecast(&b, f, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "\x1b[") {
+		t.Errorf("want no escape sequences without colour, got %q", b.String())
+	}
+}
+
+func TestColorFlag(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	out, err := runCLI(t, ts, "week", "--color", "always", "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "\x1b[") {
+		t.Errorf("want a coloured week with --color always, got %q", out)
+	}
+	out, err = runCLI(t, ts, "week", "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "\x1b[") {
+		t.Errorf("want no colour in a file by default, got %q", out)
+	}
+	_, err = runCLI(t, ts, "week", "--color", "rainbow", "Bonn,DE")
+	if err == nil {
+		t.Error("want error for an invalid colour mode, but got nil")
+	}
+}
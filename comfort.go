@@ -0,0 +1,147 @@
+package weather
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// the heat index is defined from 27 °C and 40 % humidity on
+	heatIndexTemperature = 27
+	heatIndexHumidity    = 40
+	// the wind chill is defined up to 10 °C and from 4.8 km/h wind on
+	windChillTemperature = 10
+	windChillSpeed       = 4.8
+	// condensationSpread ... difference of temperature and dew point in K from which on fog and condensation are likely
+	condensationSpread = 2
+)
+
+// Humidex ... perceived temperature in °C of the Canadian weather service, from temperature and dew point
+type Humidex float64
+
+// NewHumidex ... humidex of the temperature and dew point in °C
+func NewHumidex(celsius, dewPoint float64) Humidex {
+	vapourPressure := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+dewPoint)))
+	return Humidex(celsius + 0.5555*(vapourPressure-10))
+}
+
+// Category ... how muggy the air is, angenehm below 30, schwül below 40, otherwise drückend
+func (h Humidex) Category() string {
+	switch {
+	case h < 30:
+		return "angenehm"
+	case h < 40:
+		return "schwül"
+	}
+	return "drückend"
+}
+
+// DewPointCategory ... how the air of the dew point in °C feels: trocken below 10, angenehm below 16,
+// schwül below 21, otherwise tropisch
+func DewPointCategory(dewPoint float64) string {
+	switch {
+	case dewPoint < 10:
+		return "trocken"
+	case dewPoint < 16:
+		return "angenehm"
+	case dewPoint < 21:
+		return "schwül"
+	}
+	return "tropisch"
+}
+
+// HeatIndex ... perceived temperature in °C of hot and humid air after Rothfusz, as used by the US weather service,
+// false below 27 °C or 40 % humidity, where it isn't defined
+func HeatIndex(celsius float64, humidity int) (float64, bool) {
+	if celsius < heatIndexTemperature || humidity < heatIndexHumidity {
+		return celsius, false
+	}
+	t := celsius*9/5 + 32
+	rh := float64(humidity)
+	hi := -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh - 0.00683783*t*t -
+		0.05481717*rh*rh + 0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+	return (hi - 32) * 5 / 9, true
+}
+
+// WindChill ... perceived temperature in °C of cold air in the wind after the formula of the Canadian weather service,
+// the speed has to be in m/s, false above 10 °C or below 4.8 km/h, where it isn't defined
+func WindChill(celsius float64, wind Speed) (float64, bool) {
+	v := wind.KmPerHour()
+	if celsius > windChillTemperature || v < windChillSpeed {
+		return celsius, false
+	}
+	p := math.Pow(v, 0.16)
+	return 13.12 + 0.6215*celsius - 11.37*p + 0.3965*celsius*p, true
+}
+
+// ApparentTemperature ... heat index in the heat, wind chill in the cold wind, otherwise the temperature itself, all in °C
+func ApparentTemperature(celsius float64, humidity int, wind Speed) float64 {
+	if hi, ok := HeatIndex(celsius, humidity); ok {
+		return hi
+	}
+	if wc, ok := WindChill(celsius, wind); ok {
+		return wc
+	}
+	return celsius
+}
+
+// HeatIndex ... heat index of the conditions in the units they were requested with, see HeatIndex
+func (c Conditions) HeatIndex(units string) (float64, bool) {
+	hi, ok := HeatIndex(convertTemperature(c.Temperature, units, UnitsMetric), c.Humidity)
+	return convertTemperature(hi, UnitsMetric, units), ok
+}
+
+// WindChill ... wind chill of the conditions in the units they were requested with, see WindChill
+func (c Conditions) WindChill(units string) (float64, bool) {
+	wc, ok := WindChill(convertTemperature(c.Temperature, units, UnitsMetric), metersPerSecond(c.WindSpeed, units))
+	return convertTemperature(wc, UnitsMetric, units), ok
+}
+
+// ApparentTemperature ... apparent temperature of the conditions in the units they were requested with,
+// unlike FeelsLike of the API computed from the measurements only
+func (c Conditions) ApparentTemperature(units string) float64 {
+	celsius := convertTemperature(c.Temperature, units, UnitsMetric)
+	at := ApparentTemperature(celsius, c.Humidity, metersPerSecond(c.WindSpeed, units))
+	return convertTemperature(at, UnitsMetric, units)
+}
+
+// Humidex ... humidex of the conditions in °C, whatever units they were requested with
+func (c Conditions) Humidex(units string) Humidex {
+	return NewHumidex(convertTemperature(c.Temperature, units, UnitsMetric), convertTemperature(c.DewPoint, units, UnitsMetric))
+}
+
+// DewPointCategory ... category of the dew point of the conditions in the units they were requested with, see DewPointCategory
+func (c Conditions) DewPointCategory(units string) string {
+	return DewPointCategory(convertTemperature(c.DewPoint, units, UnitsMetric))
+}
+
+// CondensationRisk ... true if the temperature is within 2 K of the dew point, so fog forms and surfaces get wet
+func (c Conditions) CondensationRisk(units string) bool {
+	spread := convertTemperature(c.Temperature, units, UnitsMetric) - convertTemperature(c.DewPoint, units, UnitsMetric)
+	return spread <= condensationSpread
+}
+
+// metersPerSecond ... speed in m/s, the API delivers mph for imperial units
+func metersPerSecond(s Speed, units string) Speed {
+	if units == UnitsImperial {
+		return Speed(float64(s) * 0.44704)
+	}
+	return s
+}
+
+// writeComfort ... dew point and humidex, as well as heat index, wind chill and condensation risk if they apply
+func writeComfort(w io.Writer, c Conditions, f Forecast) {
+	fmt.Fprintf(w, "Taupunkt: %.1f %s (%s)\n", c.DewPoint, f.TemperatureUnit(), c.DewPointCategory(f.Units))
+	if c.CondensationRisk(f.Units) {
+		fmt.Fprintln(w, "Achtung: Temperatur nahe am Taupunkt, Gefahr von Nebel und Kondenswasser")
+	}
+	h := c.Humidex(f.Units)
+	fmt.Fprintf(w, "Schwüle: %s (Humidex %.0f)\n", h.Category(), h)
+	if hi, ok := c.HeatIndex(f.Units); ok {
+		fmt.Fprintf(w, "Hitzeindex: %.1f %s\n", hi, f.TemperatureUnit())
+	}
+	if wc, ok := c.WindChill(f.Units); ok {
+		fmt.Fprintf(w, "Windchill: %.1f %s\n", wc, f.TemperatureUnit())
+	}
+}
@@ -0,0 +1,102 @@
+package weather_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+func TestHeatIndex(t *testing.T) {
+	t.Parallel()
+	// 90 °F at 70 % humidity feel like 106 °F according to the table of the US weather service
+	got, ok := weather.HeatIndex(32.2, 70)
+	if !ok || math.Abs(got-41.1) > 0.5 {
+		t.Errorf("want about 41.1 °C, got %.1f (%v)", got, ok)
+	}
+	if _, ok := weather.HeatIndex(25, 90); ok {
+		t.Error("want no heat index below 27 °C")
+	}
+	if _, ok := weather.HeatIndex(35, 20); ok {
+		t.Error("want no heat index in dry air")
+	}
+}
+
+func TestWindChill(t *testing.T) {
+	t.Parallel()
+	// -10 °C at 20 km/h feel like -18 °C according to the table of the Canadian weather service
+	got, ok := weather.WindChill(-10, weather.Speed(20/3.6))
+	if !ok || math.Abs(got+17.9) > 0.1 {
+		t.Errorf("want -17.9 °C, got %.1f (%v)", got, ok)
+	}
+	if _, ok := weather.WindChill(15, 10); ok {
+		t.Error("want no wind chill above 10 °C")
+	}
+	if _, ok := weather.WindChill(0, 1); ok {
+		t.Error("want no wind chill in calm air")
+	}
+}
+
+func TestConditionsApparentTemperature(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		c     weather.Conditions
+		units string
+		want  float64
+	}{
+		{"mild", weather.Conditions{Temperature: 18, Humidity: 60, WindSpeed: 5}, weather.UnitsMetric, 18},
+		{"windy", weather.Conditions{Temperature: -10, Humidity: 60, WindSpeed: weather.Speed(20 / 3.6)}, weather.UnitsMetric, -17.9},
+		{"imperial", weather.Conditions{Temperature: 14, Humidity: 60, WindSpeed: 12.43}, weather.UnitsImperial, -0.2},
+		{"hot", weather.Conditions{Temperature: 305.35, Humidity: 70}, weather.UnitsStandard, 314.2},
+	}
+	for _, tt := range tests {
+		if got := tt.c.ApparentTemperature(tt.units); math.Abs(got-tt.want) > 0.5 {
+			t.Errorf("%s: want %.1f, got %.1f", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestHumidex(t *testing.T) {
+	t.Parallel()
+	// 30 °C at a dew point of 15 °C is 34 according to the table of the Canadian weather service
+	got := weather.NewHumidex(30, 15)
+	if math.Abs(float64(got)-34) > 0.5 || got.Category() != "schwül" {
+		t.Errorf("want about 34 (schwül), got %.1f (%s)", got, got.Category())
+	}
+	c := weather.Conditions{Temperature: 68, DewPoint: 50}
+	if got := c.Humidex(weather.UnitsImperial); got.Category() != "angenehm" {
+		t.Errorf("want 20 °C at a dew point of 10 °C to be angenehm, got %.1f (%s)", got, got.Category())
+	}
+	if got := weather.NewHumidex(35, 25); got.Category() != "drückend" {
+		t.Errorf("want 35 °C at a dew point of 25 °C to be drückend, got %.1f (%s)", got, got.Category())
+	}
+}
+
+func TestDewPointCategory(t *testing.T) {
+	t.Parallel()
+	tests := map[float64]string{-5: "trocken", 9.9: "trocken", 12: "angenehm", 18: "schwül", 22: "tropisch"}
+	for dewPoint, want := range tests {
+		if got := weather.DewPointCategory(dewPoint); got != want {
+			t.Errorf("%.1f: want %s, got %s", dewPoint, want, got)
+		}
+	}
+	c := weather.Conditions{DewPoint: 64.4}
+	if got := c.DewPointCategory(weather.UnitsImperial); got != "schwül" {
+		t.Errorf("want a dew point of 18 °C to be schwül, got %s", got)
+	}
+}
+
+func TestCondensationRisk(t *testing.T) {
+	t.Parallel()
+	if !(weather.Conditions{Temperature: 5, DewPoint: 4}).CondensationRisk(weather.UnitsMetric) {
+		t.Error("want a risk 1 K above the dew point")
+	}
+	if (weather.Conditions{Temperature: 20, DewPoint: 12}).CondensationRisk(weather.UnitsMetric) {
+		t.Error("want no risk 8 K above the dew point")
+	}
+	// 3 °F are less than 2 K
+	if !(weather.Conditions{Temperature: 40, DewPoint: 37}).CondensationRisk(weather.UnitsImperial) {
+		t.Error("want a risk 3 °F above the dew point")
+	}
+}
@@ -0,0 +1,174 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	FunctionCommute = "commute"
+
+	// dryRainChance ... rain chance in percent below which a trip counts as dry
+	dryRainChance = 30
+	// commuteShift and commuteStep ... how far and in which steps earlier and later trips are searched
+	commuteShift = 2 * time.Hour
+	commuteStep  = 15 * time.Minute
+)
+
+type (
+	// CommutePlan ... departure of the way there and back as time after midnight and the duration of each trip
+	CommutePlan struct {
+		Leave    time.Duration
+		Return   time.Duration
+		Duration time.Duration
+	}
+
+	// CommuteTrip ... rain during one trip, Alternative is the nearest dry start if the trip isn't dry
+	CommuteTrip struct {
+		Name        string     `json:"name"`
+		Start       time.Time  `json:"start"`
+		RainChance  float64    `json:"rain_chance"`
+		Rain        float64    `json:"rain"`
+		Dry         bool       `json:"dry"`
+		Alternative *time.Time `json:"alternative,omitempty"`
+	}
+)
+
+// defaultCommute ... trips of the commute command without --leave, --return and --duration
+var defaultCommute = CommutePlan{Leave: 7*time.Hour + 30*time.Minute, Return: 17*time.Hour + 30*time.Minute, Duration: 30 * time.Minute}
+
+// ParseClock ... time of the day like 07:30 as duration after midnight
+func ParseClock(s string) (time.Duration, error) {
+	m, ok := minutes(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid time of day %q, want e.g. 07:30", s)
+	}
+	return time.Duration(m) * time.Minute, nil
+}
+
+// commuteFlags ... flags of the commute command
+func commuteFlags(fs *flag.FlagSet, opts *Options) {
+	opts.Commute = defaultCommute
+	fs.Func("leave", fmt.Sprintf("departure of the way to work like 07:30 (default %s)", clock(defaultCommute.Leave)), func(s string) (err error) {
+		opts.Commute.Leave, err = ParseClock(s)
+		return err
+	})
+	fs.Func("return", fmt.Sprintf("departure of the way back like 17:30 (default %s)", clock(defaultCommute.Return)), func(s string) (err error) {
+		opts.Commute.Return, err = ParseClock(s)
+		return err
+	})
+	fs.DurationVar(&opts.Commute.Duration, "duration", opts.Commute.Duration, "duration of each trip")
+}
+
+func validateCommute(fs *flag.FlagSet, opts *Options) error {
+	if opts.Commute.Duration < time.Minute || opts.Commute.Duration > 6*time.Hour {
+		return fmt.Errorf("invalid duration %s of the trips, want between 1m and 6h", opts.Commute.Duration)
+	}
+	return nil
+}
+
+// tripRain ... highest rain chance and rain in mm/h of the hours overlapping the trip, false if the hourly forecast
+// doesn't cover it
+func tripRain(f Forecast, start time.Time, duration time.Duration) (float64, float64, bool) {
+	end := start.Add(duration)
+	chance, rain := 0.0, 0.0
+	covered := time.Duration(0)
+	for _, slot := range f.Hourly {
+		slotEnd := slot.Time.Add(time.Hour)
+		if !slot.Time.Before(end) || !slotEnd.After(start) {
+			continue
+		}
+		chance = math.Max(chance, slot.RainChance)
+		rain = math.Max(rain, slot.Rain+slot.Snow)
+		from, to := slot.Time, slotEnd
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		covered += to.Sub(from)
+	}
+	return chance, rain, covered >= duration
+}
+
+func isDry(chance, rain float64) bool {
+	return chance < dryRainChance && rain == 0
+}
+
+// GetCommute ... rain of the trips there and back of today and tomorrow, trips of today which have already
+// started and those beyond the hourly forecast are left out
+func GetCommute(f Forecast, plan CommutePlan, now time.Time) []CommuteTrip {
+	trips := []CommuteTrip{}
+	for offset := 0; offset < 2; offset++ {
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+offset, 0, 0, 0, 0, now.Location())
+		for _, leg := range []struct {
+			name string
+			at   time.Duration
+		}{{"Hinweg", plan.Leave}, {"Rückweg", plan.Return}} {
+			start := midnight.Add(leg.at)
+			if start.Before(now) {
+				continue
+			}
+			chance, rain, ok := tripRain(f, start, plan.Duration)
+			if !ok {
+				continue
+			}
+			trip := CommuteTrip{Name: leg.name, Start: start, RainChance: chance, Rain: rain, Dry: isDry(chance, rain)}
+			if !trip.Dry {
+				trip.Alternative = dryAlternative(f, start, plan.Duration, now)
+			}
+			trips = append(trips, trip)
+		}
+	}
+	return trips
+}
+
+// dryAlternative ... the dry start nearest to start within 2 hours, earlier ones first, nil if there is none
+func dryAlternative(f Forecast, start time.Time, duration time.Duration, now time.Time) *time.Time {
+	for shift := commuteStep; shift <= commuteShift; shift += commuteStep {
+		for _, alternative := range []time.Time{start.Add(-shift), start.Add(shift)} {
+			if alternative.Before(now) {
+				continue
+			}
+			chance, rain, ok := tripRain(f, alternative, duration)
+			if ok && isDry(chance, rain) {
+				return &alternative
+			}
+		}
+	}
+	return nil
+}
+
+// Advice ... whether the trip stays dry, otherwise the dry alternative
+func (t CommuteTrip) Advice() string {
+	switch {
+	case t.Dry:
+		return "trocken"
+	case t.Alternative != nil:
+		return fmt.Sprintf("Regen möglich, trocken um %s", t.Alternative.Format("15:04"))
+	}
+	return "Regen möglich, Regenzeug einpacken"
+}
+
+// PrintCommute ... rain of the trips to work and back of today and tomorrow with dry alternatives
+func PrintCommute(f Forecast, plan CommutePlan, now time.Time) {
+	fmt.Println()
+	printHeader(fmt.Sprintf("Arbeitsweg trocken? (%d min Fahrzeit)", int(plan.Duration.Round(time.Minute).Minutes())), f)
+	trips := GetCommute(f, plan, now)
+	if len(trips) == 0 {
+		fmt.Println("Keine Fahrten im Zeitraum der stündlichen Vorhersage.")
+	}
+	for _, t := range trips {
+		fmt.Printf("%s %s %s: %3.0f %%, %.1f mm/h - %s\n",
+			t.Start.Format("02.01.2006"), t.Start.Format("15:04"), t.Name, t.RainChance, t.Rain, t.Advice())
+	}
+	fmt.Println()
+}
+
+// clock ... duration after midnight as time of the day like 07:30
+func clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,69 @@
+package weather_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestParseClock(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParseClock("07:30")
+	if err != nil || got != 7*time.Hour+30*time.Minute {
+		t.Errorf("want 7h30m, got %s, %v", got, err)
+	}
+	if _, err := weather.ParseClock("7.30"); err == nil {
+		t.Error("want an error for 7.30")
+	}
+}
+
+func TestGetCommute(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2022, 6, 17, 0, 0, 0, 0, time.Local)
+	f := weather.Forecast{}
+	for h := 0; h < 48; h++ {
+		at := start.Add(time.Duration(h) * time.Hour)
+		slot := weather.ForecastHourly{Time: at, Day: at.Format("02.01.2006"), Hour: at.Format("15:04")}
+		switch {
+		case h == 17:
+			// a shower on the way back of today
+			slot.RainChance, slot.Rain = 80, 2.5
+		case h >= 31:
+			// rain from 7 o'clock tomorrow on
+			slot.RainChance, slot.Rain = 90, 1.2
+		}
+		f.Hourly = append(f.Hourly, slot)
+	}
+	plan := weather.CommutePlan{Leave: 7*time.Hour + 30*time.Minute, Return: 17*time.Hour + 30*time.Minute, Duration: 25 * time.Minute}
+	// the way to work of today is over
+	got := weather.GetCommute(f, plan, start.Add(9*time.Hour))
+	if len(got) != 3 {
+		t.Fatalf("want 3 trips, got %+v", got)
+	}
+	back, work, home := got[0], got[1], got[2]
+	if back.Dry || back.RainChance != 80 || back.Alternative == nil || back.Alternative.Format("15:04") != "18:00" {
+		t.Errorf("want the shower on the way back and a dry trip at 18:00, got %+v", back)
+	}
+	if work.Dry || work.Alternative == nil || work.Alternative.Format("15:04") != "06:30" {
+		t.Errorf("want the rain on the way to work tomorrow and a dry trip at 06:30, got %+v", work)
+	}
+	if home.Dry || home.Alternative != nil || home.Advice() != "Regen möglich, Regenzeug einpacken" {
+		t.Errorf("want rain without a dry trip on the way back tomorrow, got %+v: %s", home, home.Advice())
+	}
+}
+
+func TestCommuteDuration(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	out, err := runCLI(t, ts, "commute", "--duration", "30m", "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "(30 min Fahrzeit)") {
+		t.Errorf("want the duration in minutes, got %s", out)
+	}
+}
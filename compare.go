@@ -0,0 +1,175 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const FunctionCompare = "compare"
+
+// Comparison ... weather of one of the compared locations
+type Comparison struct {
+	Location   string        `json:"location"`
+	Place      string        `json:"place,omitempty"`
+	Conditions Conditions    `json:"conditions"`
+	Today      ForecastDaily `json:"today"`
+	Units      string        `json:"units,omitempty"`
+}
+
+// validateCompare ... the locations are the positional words only
+func validateCompare(fs *flag.FlagSet, opts *Options) error {
+	if opts.Location != "" || opts.Zip != "" || opts.Here {
+		return fmt.Errorf("%s takes the locations as arguments, --location, --zip and --here are not supported", FunctionCompare)
+	}
+	return nil
+}
+
+func runCompare(env *cliEnv, opts Options) error {
+	if len(opts.Args) < 2 {
+		return fmt.Errorf("usage: %s %s LOCATION LOCATION [LOCATION ...]", env.name, FunctionCompare)
+	}
+	c, err := env.client()
+	if err != nil {
+		return err
+	}
+	c.Units = opts.Units
+	c.Lang = opts.Lang
+	locations := make([]string, len(opts.Args))
+	coordinates := make([]Coordinates, len(opts.Args))
+	errs := make([]error, len(opts.Args))
+	forEachBounded(len(opts.Args), c.concurrency(), func(i int) {
+		locations[i] = env.cfg.ResolveLocation(strings.ReplaceAll(opts.Args[i], " ", "+"))
+		coordinates[i], errs[i] = locateWith(c, locations[i])
+	})
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("%s: %w", opts.Args[i], err)
+		}
+	}
+	results := c.GetWeatherMany(context.Background(), coordinates)
+	comparisons := make([]Comparison, len(results))
+	forEachBounded(len(results), c.concurrency(), func(i int) {
+		r := results[i]
+		if r.Err != nil {
+			errs[i] = r.Err
+			return
+		}
+		comparisons[i], errs[i] = newComparison(c, locations[i], r.Coordinates, r.Conditions, r.Forecast)
+	})
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("%s: %w", opts.Args[i], err)
+		}
+	}
+	if opts.Format == FormatJSON {
+		return printJSON(os.Stdout, comparisons)
+	}
+	PrintComparison(comparisons)
+	return nil
+}
+
+// reverseGeocoder ... providers able to name a place, like Client
+type reverseGeocoder interface {
+	ReverseGeocode(coordinates Coordinates) (Place, error)
+}
+
+// CompareLocation ... weather of one location, ambiguous names are not asked for as the fetches run concurrently
+func CompareLocation(p Provider, location string) (Comparison, error) {
+	coordinates, err := locateWith(p, location)
+	if err != nil {
+		return Comparison{}, err
+	}
+	conditions, forecast, err := p.GetWeather(coordinates)
+	if err != nil {
+		return Comparison{}, err
+	}
+	return newComparison(p, location, coordinates, conditions, forecast)
+}
+
+// locateWith ... coordinates of the location by the provider, "lat,lon" is used directly
+func locateWith(p Provider, location string) (Coordinates, error) {
+	if coordinates, ok := ParseCoordinates(location); ok {
+		return coordinates, nil
+	}
+	return p.GetCoordinates(location)
+}
+
+// newComparison ... comparison of the fetched weather, named by the provider if it is able to
+func newComparison(p Provider, location string, coordinates Coordinates, conditions Conditions, forecast Forecast) (Comparison, error) {
+	if len(forecast.Daily) == 0 {
+		return Comparison{}, errors.New("forecast without days")
+	}
+	comparison := Comparison{
+		Location:   location,
+		Conditions: conditions,
+		Today:      forecast.Daily[0],
+		Units:      forecast.Units,
+	}
+	// the state is left out to keep the columns narrow
+	if g, ok := p.(reverseGeocoder); ok {
+		if place, err := g.ReverseGeocode(coordinates); err == nil {
+			comparison.Place = Place{Name: place.Name, Country: place.Country}.String()
+		}
+	}
+	return comparison, nil
+}
+
+// PrintComparison ... current conditions and today's forecast of several locations side by side
+func PrintComparison(comparisons []Comparison) {
+	WriteComparison(os.Stdout, comparisons)
+}
+
+// WriteComparison ... PrintComparison writing to w
+func WriteComparison(w io.Writer, comparisons []Comparison) {
+	if len(comparisons) == 0 {
+		return
+	}
+	rows := [][]string{
+		{""},
+		{"Beschreibung"},
+		{"Temperatur"},
+		{"gefühlt"},
+		{"Luftfeuchtigkeit"},
+		{"Wind"},
+		{"heute min/max"},
+		{"Regen heute"},
+		{"Warnungen"},
+	}
+	for _, comparison := range comparisons {
+		f := Forecast{Units: comparison.Units}
+		unit := f.TemperatureUnit()
+		title := comparison.Place
+		if title == "" {
+			title = strings.ReplaceAll(comparison.Location, "+", " ")
+		}
+		values := []string{
+			title,
+			comparison.Conditions.Summary,
+			fmt.Sprintf("%.1f %s", comparison.Conditions.Temperature, unit),
+			fmt.Sprintf("%.1f %s", comparison.Conditions.FeelsLike, unit),
+			fmt.Sprintf("%d %%", comparison.Conditions.Humidity),
+			fmt.Sprintf("%s %s", f.FormatSpeed(comparison.Conditions.WindSpeed), comparison.Conditions.WindDirection.Direction()),
+			fmt.Sprintf("%.0f / %.0f %s", comparison.Today.Temp.Min, comparison.Today.Temp.Max, unit),
+			fmt.Sprintf("%.0f %%", comparison.Today.RainChance),
+			fmt.Sprintf("%d", len(comparison.Today.Alerts)),
+		}
+		for i := range rows {
+			rows[i] = append(rows[i], values[i])
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Vergleich vom "+comparisons[0].Conditions.Timestamp)
+	fmt.Fprintln(w, "-----------------------------------------------------")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,138 @@
+package weather_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newMock() *weathertest.Mock {
+	return &weathertest.Mock{
+		Conditions: weather.Conditions{Summary: "Clear", Temperature: 21.5},
+		Forecast: weather.Forecast{
+			Units: weather.UnitsMetric,
+			Daily: []weather.ForecastDaily{{Day: "Fr, 17.06.", Alerts: []weather.Alert{{Name: "Hitze"}}}},
+		},
+	}
+}
+
+func TestCompareLocation(t *testing.T) {
+	t.Parallel()
+	today := weather.ForecastDaily{Day: "Fr, 17.06.", Alerts: []weather.Alert{{Name: "Hitze"}}}
+	tests := map[string]struct {
+		mock          *weathertest.Mock
+		location      string
+		want          weather.Comparison
+		wantLocations []string
+		wantErr       bool
+	}{
+		"name": {
+			mock:          newMock(),
+			location:      "Bonn,DE",
+			want:          weather.Comparison{Location: "Bonn,DE", Conditions: weather.Conditions{Summary: "Clear", Temperature: 21.5}, Today: today, Units: weather.UnitsMetric},
+			wantLocations: []string{"Bonn,DE"},
+		},
+		"coordinates": {
+			mock:     newMock(),
+			location: "50.73,7.1",
+			want:     weather.Comparison{Location: "50.73,7.1", Conditions: weather.Conditions{Summary: "Clear", Temperature: 21.5}, Today: today, Units: weather.UnitsMetric},
+		},
+		"provider error": {
+			mock:          &weathertest.Mock{Err: errors.New("unavailable")},
+			location:      "Bonn,DE",
+			wantLocations: []string{"Bonn,DE"},
+			wantErr:       true,
+		},
+		"no days": {
+			mock:          &weathertest.Mock{},
+			location:      "Bonn,DE",
+			wantLocations: []string{"Bonn,DE"},
+			wantErr:       true,
+		},
+	}
+	for name, tc := range tests {
+		got, err := weather.CompareLocation(tc.mock, tc.location)
+		if tc.wantErr != (err != nil) {
+			t.Errorf("%s: want error %v, got %v", name, tc.wantErr, err)
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("%s: %s", name, cmp.Diff(tc.want, got))
+		}
+		if !cmp.Equal(tc.wantLocations, tc.mock.Locations()) {
+			t.Errorf("%s: %s", name, cmp.Diff(tc.wantLocations, tc.mock.Locations()))
+		}
+	}
+}
+
+func TestWriteComparison(t *testing.T) {
+	t.Parallel()
+	comparisons := []weather.Comparison{
+		{
+			Location:   "New+York",
+			Conditions: weather.Conditions{Timestamp: "Fr, 17.06. 12:00", Summary: "Clear", Temperature: 21.5, Humidity: 40},
+			Today:      weather.ForecastDaily{Temp: weather.DailyTempBenchmarks{Min: 12, Max: 24}, RainChance: 10},
+			Units:      weather.UnitsMetric,
+		},
+		{
+			Location:   "Bonn,DE",
+			Place:      "Bonn, DE",
+			Conditions: weather.Conditions{Summary: "Rain", Temperature: 72},
+			Today:      weather.ForecastDaily{Alerts: []weather.Alert{{Name: "Hitze"}, {Name: "Gewitter"}}},
+			Units:      weather.UnitsImperial,
+		},
+	}
+	tests := map[string][]string{
+		"title":       {"Vergleich vom Fr, 17.06. 12:00"},
+		"header":      {"New York", "Bonn, DE"},
+		"temperature": {"21.5 °C", "72.0 °F"},
+		"min/max":     {"12 / 24 °C"},
+	}
+	var buf bytes.Buffer
+	weather.WriteComparison(&buf, comparisons)
+	got := buf.String()
+	for name, parts := range tests {
+		for _, part := range parts {
+			if !strings.Contains(got, part) {
+				t.Errorf("%s: want %q in output:\n%s", name, part, got)
+			}
+		}
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if strings.HasPrefix(line, "Warnungen") {
+			want := []string{"Warnungen", "0", "2"}
+			if !cmp.Equal(want, strings.Fields(line)) {
+				t.Error(cmp.Diff(want, strings.Fields(line)))
+			}
+		}
+	}
+
+	buf.Reset()
+	weather.WriteComparison(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("want no output without comparisons, got %q", buf.String())
+	}
+}
+
+func TestCompareCommand(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	out, err := runCLI(t, ts, "compare", "Bonn,DE", "50.73,7.1", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []weather.Comparison
+	err = json.Unmarshal(out, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Location != "Bonn,DE" || got[1].Location != "50.73,7.1" {
+		t.Errorf("want comparisons of Bonn,DE and 50.73,7.1 in order, got %+v", got)
+	}
+}
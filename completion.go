@@ -0,0 +1,153 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const CommandCompletion = "completion"
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+func init() {
+	// registered here, as the completion needs the list of all commands itself
+	commands = append(commands, command{
+		name:    CommandCompletion,
+		summary: "Skript für die Shell-Vervollständigung (bash, zsh, fish)",
+		words:   completionShells,
+		run:     runCompletion,
+	})
+}
+
+func runCompletion(env *cliEnv, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s %s bash|zsh|fish", env.name, CommandCompletion)
+	}
+	return WriteCompletion(os.Stdout, args[0], env.name)
+}
+
+// WriteCompletion ... completion script for the given shell, aliases are looked up when completing
+func WriteCompletion(w io.Writer, shell, name string) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, name)
+	case "zsh":
+		writeZshCompletion(w, name)
+	case "fish":
+		writeFishCompletion(w, name)
+	default:
+		return fmt.Errorf("unsupported shell %q, want bash, zsh or fish", shell)
+	}
+	return nil
+}
+
+// commandFlags ... flag names of a command including the leading dashes
+func commandFlags(c command) []string {
+	var fs *flag.FlagSet
+	switch {
+	case c.takesLocation():
+		fs = weatherFlags(c, &Options{})
+	case c.name == CommandSearch:
+		limit := 0
+		fs = searchFlags(&Options{}, &limit)
+	case c.name == CommandDaemon:
+		fs = daemonFlags(&Options{})
+	case c.serve:
+		var listen, apiKey string
+		var ttl time.Duration
+		fs = serveFlags(c.name, &Options{}, &listen, &ttl, &apiKey)
+	default:
+		return nil
+	}
+	flags := []string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, "--"+f.Name)
+	})
+	return flags
+}
+
+// aliasCommand ... shell snippet listing the saved location aliases
+func aliasCommand(name string) string {
+	return name + " locations list 2>/dev/null | cut -d: -f1"
+}
+
+func writeBashCompletion(w io.Writer, name string) {
+	fn := "_" + strings.ReplaceAll(name, "-", "_")
+	names := []string{}
+	for _, c := range commands {
+		names = append(names, c.name)
+	}
+	fmt.Fprintf(w, "# bash completion for %s\n", name)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}" candidates=""`)
+	fmt.Fprintln(w, `    if [ "$COMP_CWORD" -eq 1 ]; then`)
+	fmt.Fprintf(w, "        COMPREPLY=( $(compgen -W %q -- \"$cur\") )\n", strings.Join(names, " "))
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, `    case "${COMP_WORDS[1]}" in`)
+	for _, c := range commands {
+		words := append(commandFlags(c), c.words...)
+		if c.takesLocation() {
+			fmt.Fprintf(w, "        %s) candidates=%q\" $(%s)\" ;;\n", c.name, strings.Join(words, " "), aliasCommand(name))
+			continue
+		}
+		fmt.Fprintf(w, "        %s) candidates=%q ;;\n", c.name, strings.Join(words, " "))
+	}
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, `    COMPREPLY=( $(compgen -W "$candidates" -- "$cur") )`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintf(w, "complete -F %s %s\n", fn, name)
+}
+
+func writeZshCompletion(w io.Writer, name string) {
+	fn := "_" + strings.ReplaceAll(name, "-", "_")
+	fmt.Fprintf(w, "#compdef %s\n\n", name)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintln(w, "    local -a commands candidates")
+	fmt.Fprintln(w, "    commands=(")
+	for _, c := range commands {
+		fmt.Fprintf(w, "        '%s:%s'\n", c.name, strings.ReplaceAll(c.summary, "'", ""))
+	}
+	fmt.Fprintln(w, "    )")
+	fmt.Fprintln(w, "    if (( CURRENT == 2 )); then")
+	fmt.Fprintln(w, "        _describe 'command' commands")
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, `    case "${words[2]}" in`)
+	for _, c := range commands {
+		words := append(commandFlags(c), c.words...)
+		if c.takesLocation() {
+			fmt.Fprintf(w, "        %s) candidates=(%s ${(f)\"$(%s)\"}) ;;\n", c.name, strings.Join(words, " "), aliasCommand(name))
+			continue
+		}
+		fmt.Fprintf(w, "        %s) candidates=(%s) ;;\n", c.name, strings.Join(words, " "))
+	}
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, "    compadd -- $candidates")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintf(w, "\ncompdef %s %s\n", fn, name)
+}
+
+func writeFishCompletion(w io.Writer, name string) {
+	fmt.Fprintf(w, "# fish completion for %s\n", name)
+	fmt.Fprintf(w, "complete -c %s -f\n", name)
+	for _, c := range commands {
+		fmt.Fprintf(w, "complete -c %s -n __fish_use_subcommand -a %s -d '%s'\n", name, c.name, strings.ReplaceAll(c.summary, "'", ""))
+	}
+	for _, c := range commands {
+		condition := fmt.Sprintf("'__fish_seen_subcommand_from %s'", c.name)
+		for _, f := range commandFlags(c) {
+			fmt.Fprintf(w, "complete -c %s -n %s -l %s\n", name, condition, strings.TrimPrefix(f, "--"))
+		}
+		if len(c.words) > 0 {
+			fmt.Fprintf(w, "complete -c %s -n %s -a '%s'\n", name, condition, strings.Join(c.words, " "))
+		}
+		if c.takesLocation() {
+			fmt.Fprintf(w, "complete -c %s -n %s -a '(%s locations list 2>/dev/null | string replace -r \":.*\" \"\")'\n", name, condition, name)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package weather_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	t.Parallel()
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		buf := &bytes.Buffer{}
+		err := weather.WriteCompletion(buf, shell, "weather")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{"current", "aftertomorrow", "units", "locations list"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s: want %q in completion script", shell, want)
+			}
+		}
+	}
+}
+
+func TestWriteCompletionUnknownShell(t *testing.T) {
+	t.Parallel()
+	err := weather.WriteCompletion(&bytes.Buffer{}, "tcsh", "weather")
+	if err == nil {
+		t.Fatal("want error for unsupported shell, but got nil")
+	}
+}
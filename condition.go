@@ -0,0 +1,101 @@
+package weather
+
+// ConditionID ... weather condition code of OpenWeatherMap, e.g. 500 for light rain,
+// the hundreds are the group: 2xx thunderstorm, 3xx drizzle, 5xx rain, 6xx snow, 7xx atmosphere, 800 clear, 80x clouds
+type ConditionID int
+
+// Severity ... how strong the weather of a condition is, from SeverityNone to SeverityExtreme
+type Severity int
+
+const (
+	SeverityNone Severity = iota
+	SeverityLight
+	SeverityModerate
+	SeverityHeavy
+	SeverityExtreme
+)
+
+// severities ... conditions deviating from the SeverityLight of their group, see Severity
+var severities = map[ConditionID]Severity{
+	// thunderstorms are at least moderate
+	200: SeverityModerate, 210: SeverityModerate, 230: SeverityModerate, 231: SeverityModerate,
+	201: SeverityHeavy, 211: SeverityHeavy, 221: SeverityHeavy,
+	202: SeverityExtreme, 212: SeverityExtreme, 232: SeverityExtreme,
+	302: SeverityModerate, 311: SeverityModerate, 312: SeverityModerate, 313: SeverityModerate, 321: SeverityModerate,
+	314: SeverityHeavy,
+	501: SeverityModerate, 521: SeverityModerate, 531: SeverityModerate,
+	502: SeverityHeavy, 503: SeverityHeavy, 511: SeverityHeavy, 522: SeverityHeavy,
+	504: SeverityExtreme,
+	601: SeverityModerate, 611: SeverityModerate, 613: SeverityModerate, 616: SeverityModerate, 621: SeverityModerate,
+	602: SeverityHeavy, 622: SeverityHeavy,
+	741: SeverityModerate, 731: SeverityModerate, 751: SeverityModerate, 761: SeverityModerate,
+	762: SeverityHeavy, 771: SeverityHeavy,
+	781: SeverityExtreme,
+}
+
+// Group ... the hundreds of the condition, e.g. 500 for all kinds of rain
+func (id ConditionID) Group() int {
+	return int(id) / 100 * 100
+}
+
+func (id ConditionID) IsThunderstorm() bool {
+	return id.Group() == 200
+}
+
+func (id ConditionID) IsDrizzle() bool {
+	return id.Group() == 300
+}
+
+// IsRain ... rain of any strength, including drizzle and freezing rain
+func (id ConditionID) IsRain() bool {
+	return id.Group() == 300 || id.Group() == 500
+}
+
+// IsSnow ... snow, including sleet and rain mixed with snow
+func (id ConditionID) IsSnow() bool {
+	return id.Group() == 600
+}
+
+// IsAtmosphere ... reduced visibility like mist, fog, haze, dust, as well as squalls and tornados
+func (id ConditionID) IsAtmosphere() bool {
+	return id.Group() == 700
+}
+
+func (id ConditionID) IsClear() bool {
+	return id == 800
+}
+
+func (id ConditionID) IsCloudy() bool {
+	return id > 800 && id < 900
+}
+
+// IsPrecipitation ... rain, snow or a thunderstorm
+func (id ConditionID) IsPrecipitation() bool {
+	return id.IsThunderstorm() || id.IsRain() || id.IsSnow()
+}
+
+// Severity ... strength of the condition, clear sky and clouds are SeverityNone as well as unknown codes
+func (id ConditionID) Severity() Severity {
+	if s, ok := severities[id]; ok {
+		return s
+	}
+	switch id.Group() {
+	case 200, 300, 500, 600, 700:
+		return SeverityLight
+	}
+	return SeverityNone
+}
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityLight:
+		return "leicht"
+	case SeverityModerate:
+		return "mäßig"
+	case SeverityHeavy:
+		return "stark"
+	case SeverityExtreme:
+		return "extrem"
+	}
+	return "keine"
+}
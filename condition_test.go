@@ -0,0 +1,56 @@
+package weather_test
+
+import (
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+func TestConditionGroups(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		id                                          weather.ConditionID
+		thunderstorm, rain, snow, clear, cloudy, pp bool
+	}{
+		{id: 211, thunderstorm: true, pp: true},
+		{id: 301, rain: true, pp: true},
+		{id: 500, rain: true, pp: true},
+		{id: 616, snow: true, pp: true},
+		{id: 741},
+		{id: 800, clear: true},
+		{id: 804, cloudy: true},
+	}
+	for _, tt := range tests {
+		id := tt.id
+		if id.IsThunderstorm() != tt.thunderstorm || id.IsRain() != tt.rain || id.IsSnow() != tt.snow ||
+			id.IsClear() != tt.clear || id.IsCloudy() != tt.cloudy || id.IsPrecipitation() != tt.pp {
+			t.Errorf("%d: wrong group, want %+v", id, tt)
+		}
+	}
+	if !weather.ConditionID(741).IsAtmosphere() || !weather.ConditionID(300).IsDrizzle() {
+		t.Error("want 741 fog to be atmosphere and 300 to be drizzle")
+	}
+}
+
+func TestConditionSeverity(t *testing.T) {
+	t.Parallel()
+	tests := map[weather.ConditionID]weather.Severity{
+		200: weather.SeverityModerate,
+		202: weather.SeverityExtreme,
+		300: weather.SeverityLight,
+		500: weather.SeverityLight,
+		502: weather.SeverityHeavy,
+		504: weather.SeverityExtreme,
+		602: weather.SeverityHeavy,
+		701: weather.SeverityLight,
+		781: weather.SeverityExtreme,
+		800: weather.SeverityNone,
+		804: weather.SeverityNone,
+		0:   weather.SeverityNone,
+	}
+	for id, want := range tests {
+		if got := id.Severity(); got != want {
+			t.Errorf("%d: want %s, got %s", id, want, got)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package weather
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Config ... user settings, stored as JSON in the user's config directory
+type Config struct {
+	DefaultLocation string            `json:"default_location,omitempty"`
+	Locations       map[string]string `json:"locations,omitempty"`
+	GeoLimit        int               `json:"geo_limit,omitempty"`
+	Units           string            `json:"units,omitempty"`
+	Lang            string            `json:"lang,omitempty"`
+	BaseURL         string            `json:"base_url,omitempty"`
+	// TileURL, IconURL, PollenURL, MeteoAlarmURL and DWDURL are the base URLs of the map tiles, the weather icons,
+	// the pollen forecast and the warnings of MeteoAlarm and the DWD, which are served by hosts of their own
+	TileURL       string `json:"tile_url,omitempty"`
+	IconURL       string `json:"icon_url,omitempty"`
+	PollenURL     string `json:"pollen_url,omitempty"`
+	MeteoAlarmURL string `json:"meteoalarm_url,omitempty"`
+	DWDURL        string `json:"dwd_url,omitempty"`
+	// AlertSources add their alerts to those of the weather API, e.g. AlertSourceMeteoAlarm
+	AlertSources []string `json:"alert_sources,omitempty"`
+	// DWDWarncell is the warn cell of the DWD warnings, without it they are chosen by the place name
+	DWDWarncell string `json:"dwd_warncell,omitempty"`
+	// Translate translates the alerts into the language of the weather, e.g. with a LibreTranslate instance
+	Translate *TranslateConfig `json:"translate,omitempty"`
+	// API is auto, onecall or free, see Client.API
+	API      string          `json:"api,omitempty"`
+	Ntfy     *NtfyConfig     `json:"ntfy,omitempty"`
+	Webhook  *WebhookConfig  `json:"webhook,omitempty"`
+	Telegram *TelegramConfig `json:"telegram,omitempty"`
+	// Store persists every fetched observation if set
+	Store *StoreConfig `json:"store,omitempty"`
+	// InfluxDB receives every refresh of watch and the influxdb jobs of the daemon
+	InfluxDB *InfluxConfig `json:"influxdb,omitempty"`
+	// Schedule are the jobs of the daemon command
+	Schedule []ScheduleEntry `json:"schedule,omitempty"`
+	// Providers are the data sources compared by the consensus command
+	Providers []ProviderConfig `json:"providers,omitempty"`
+	// FireThresholds are the fire danger indexes from which the daily forecast warns by place name, state, country
+	// or "default", see Config.FireThreshold
+	FireThresholds map[string]float64 `json:"fire_thresholds,omitempty"`
+	// FrostThreshold is the temperature in °C from which the daily forecast and the notifications warn of frost,
+	// see Config.FrostLimit
+	FrostThreshold *float64 `json:"frost_threshold,omitempty"`
+	// TemperatureThresholds are notified by the notify command when the temperature crosses them
+	TemperatureThresholds []float64 `json:"temperature_thresholds,omitempty"`
+}
+
+// ConfigPath ... location of the config file, can be overridden with WEATHER_CONFIG
+func ConfigPath() (string, error) {
+	if path := os.Getenv("WEATHER_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "weather", "config.json"), nil
+}
+
+// LoadConfig ... reads the config file, a missing file results in an empty config
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// SaveConfig ... writes the config file, missing directories are created
+func SaveConfig(path string, cfg Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// AddLocation ... stores an alias for a location, an existing alias is overwritten
+func (cfg *Config) AddLocation(alias, location string) error {
+	if alias == "" || location == "" {
+		return errors.New("alias and location must not be empty")
+	}
+	if cfg.Locations == nil {
+		cfg.Locations = map[string]string{}
+	}
+	cfg.Locations[alias] = strings.ReplaceAll(location, " ", "+")
+	return nil
+}
+
+// RemoveLocation ... deletes an alias
+func (cfg *Config) RemoveLocation(alias string) error {
+	if _, ok := cfg.Locations[alias]; !ok {
+		return fmt.Errorf("unknown location alias %q", alias)
+	}
+	delete(cfg.Locations, alias)
+	return nil
+}
+
+// LocationAliases ... sorted list of all aliases
+func (cfg Config) LocationAliases() []string {
+	aliases := make([]string, 0, len(cfg.Locations))
+	for alias := range cfg.Locations {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// ResolveLocation ... replaces an alias by its location, everything else is returned unchanged
+func (cfg Config) ResolveLocation(location string) string {
+	if resolved, ok := cfg.Locations[location]; ok {
+		return resolved
+	}
+	return location
+}
+
+// GetDefaultLocation ... location used when none is given, WEATHER_DEFAULT_LOCATION wins over the config entry
+func GetDefaultLocation(cfg Config) string {
+	if location := os.Getenv("WEATHER_DEFAULT_LOCATION"); location != "" {
+		return location
+	}
+	return cfg.DefaultLocation
+}
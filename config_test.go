@@ -0,0 +1,109 @@
+package weather_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{"default_location":"Bonn,DE"}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := weather.Config{DefaultLocation: "Bonn,DE"}
+	got, err := weather.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	t.Parallel()
+	got, err := weather.LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(weather.Config{}, got) {
+		t.Error(cmp.Diff(weather.Config{}, got))
+	}
+}
+
+func TestDefaultLocationFromEnv(t *testing.T) {
+	t.Setenv("WEATHER_DEFAULT_LOCATION", "Berlin,DE")
+	want := "Berlin,DE"
+	got := weather.GetDefaultLocation(weather.Config{DefaultLocation: "Bonn,DE"})
+	if want != got {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestDefaultLocationFromConfig(t *testing.T) {
+	t.Setenv("WEATHER_DEFAULT_LOCATION", "")
+	want := "Bonn,DE"
+	got := weather.GetDefaultLocation(weather.Config{DefaultLocation: "Bonn,DE"})
+	if want != got {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestSaveAndLoadLocations(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "weather", "config.json")
+	cfg := weather.Config{}
+	err := cfg.AddLocation("home", "Berlin,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.AddLocation("work", "Bad Godesberg,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = weather.SaveConfig(path, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := weather.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(cfg, got) {
+		t.Error(cmp.Diff(cfg, got))
+	}
+	want := []string{"home", "work"}
+	if !cmp.Equal(want, got.LocationAliases()) {
+		t.Error(cmp.Diff(want, got.LocationAliases()))
+	}
+}
+
+func TestResolveLocation(t *testing.T) {
+	t.Parallel()
+	cfg := weather.Config{Locations: map[string]string{"home": "Berlin,DE"}}
+	tests := map[string]string{
+		"home":     "Berlin,DE",
+		"Paris,FR": "Paris,FR",
+	}
+	for input, want := range tests {
+		got := cfg.ResolveLocation(input)
+		if want != got {
+			t.Errorf("%s: want %s, got %s", input, want, got)
+		}
+	}
+}
+
+func TestRemoveUnknownLocation(t *testing.T) {
+	t.Parallel()
+	cfg := weather.Config{}
+	err := cfg.RemoveLocation("home")
+	if err == nil {
+		t.Fatal("want error removing unknown alias, but got nil")
+	}
+}
@@ -0,0 +1,238 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	CommandConsensus = "consensus"
+
+	// rainyChance ... rain chance in percent from which a provider is taken to predict rain
+	rainyChance = 50
+)
+
+type (
+	// ProviderConfig ... data source of the consensus command, OpenWeatherMap with its own endpoints or key
+	// or another source
+	ProviderConfig struct {
+		Name string `json:"name"`
+		// Source is open-meteo for the forecast of Open-Meteo, OpenWeatherMap otherwise
+		Source string `json:"source,omitempty"`
+		// API and BaseURL override the settings of the config file, see Client.API, BaseURL applies to Open-Meteo, too
+		API     string `json:"api,omitempty"`
+		BaseURL string `json:"base_url,omitempty"`
+		// GeoURL is the base URL of the geocoding of Open-Meteo
+		GeoURL string `json:"geo_url,omitempty"`
+		// KeyEnv is the env variable holding the API key, OPENWEATHERMAP_API_KEY if empty
+		KeyEnv string `json:"key_env,omitempty"`
+	}
+
+	// NamedProvider ... provider taking part in a consensus
+	NamedProvider struct {
+		Name     string
+		Provider Provider
+	}
+
+	// ProviderForecast ... forecast of one of the providers of a consensus
+	ProviderForecast struct {
+		Name     string
+		Forecast Forecast
+	}
+
+	// ConsensusDay ... agreement of the providers on one day, the ranges span the values of all providers
+	ConsensusDay struct {
+		Day       string `json:"day"`
+		Providers int    `json:"providers"`
+		// MaxLow and MaxHigh are the lowest and highest maximum temperature, MinLow and MinHigh the same for the minimum
+		MaxLow  float64 `json:"temp_max_low"`
+		MaxHigh float64 `json:"temp_max_high"`
+		MinLow  float64 `json:"temp_min_low"`
+		MinHigh float64 `json:"temp_min_high"`
+		// Spread is the larger difference of the two temperature ranges
+		Spread         float64 `json:"spread"`
+		RainChanceLow  float64 `json:"rain_chance_low"`
+		RainChanceHigh float64 `json:"rain_chance_high"`
+		// Rainy is the number of providers predicting rain, a rain chance of at least 50 %
+		Rainy int `json:"rainy"`
+	}
+)
+
+// GetConsensus ... agreement of the forecasts on the days all of them cover
+func GetConsensus(forecasts []ProviderForecast) []ConsensusDay {
+	days := []ConsensusDay{}
+	if len(forecasts) == 0 {
+		return days
+	}
+	for _, first := range forecasts[0].Forecast.Daily {
+		d := ConsensusDay{
+			Day:           first.Day,
+			MaxLow:        math.Inf(1),
+			MaxHigh:       math.Inf(-1),
+			MinLow:        math.Inf(1),
+			MinHigh:       math.Inf(-1),
+			RainChanceLow: math.Inf(1),
+		}
+		for _, pf := range forecasts {
+			day, ok := findDay(pf.Forecast, first.Day)
+			if !ok {
+				break
+			}
+			d.Providers++
+			d.MaxLow = math.Min(d.MaxLow, day.Temp.Max)
+			d.MaxHigh = math.Max(d.MaxHigh, day.Temp.Max)
+			d.MinLow = math.Min(d.MinLow, day.Temp.Min)
+			d.MinHigh = math.Max(d.MinHigh, day.Temp.Min)
+			d.RainChanceLow = math.Min(d.RainChanceLow, day.RainChance)
+			d.RainChanceHigh = math.Max(d.RainChanceHigh, day.RainChance)
+			if day.RainChance >= rainyChance {
+				d.Rainy++
+			}
+		}
+		if d.Providers < len(forecasts) {
+			continue
+		}
+		d.Spread = math.Max(d.MaxHigh-d.MaxLow, d.MinHigh-d.MinLow)
+		days = append(days, d)
+	}
+	return days
+}
+
+// findDay ... daily forecast of the day, e.g. "17.06.2022"
+func findDay(f Forecast, day string) (ForecastDaily, bool) {
+	for _, d := range f.Daily {
+		if d.Day == day {
+			return d, true
+		}
+	}
+	return ForecastDaily{}, false
+}
+
+// RainAgreed ... true if either all or none of the providers predict rain
+func (d ConsensusDay) RainAgreed() bool {
+	return d.Rainy == 0 || d.Rainy == d.Providers
+}
+
+// Confidence ... hoch if the temperatures are within 2 degrees and the rain is agreed on,
+// gering if they are more than 5 degrees apart or the rain isn't agreed on, otherwise mittel
+func (d ConsensusDay) Confidence() string {
+	switch {
+	case d.Spread <= 2 && d.RainAgreed():
+		return "hoch"
+	case d.Spread > 5 || !d.RainAgreed():
+		return "gering"
+	}
+	return "mittel"
+}
+
+// GetProviderForecasts ... forecasts of all providers for the coordinates, fetched concurrently
+func GetProviderForecasts(providers []NamedProvider, coordinates Coordinates) ([]ProviderForecast, error) {
+	forecasts := make([]ProviderForecast, len(providers))
+	errs := make([]error, len(providers))
+	forEachBounded(len(providers), DefaultConcurrency, func(i int) {
+		forecasts[i].Name = providers[i].Name
+		_, forecasts[i].Forecast, errs[i] = providers[i].Provider.GetWeather(coordinates)
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", providers[i].Name, err)
+		}
+	}
+	return forecasts, nil
+}
+
+// provider ... data source of the provider in the units and language of the options, OpenWeatherMap with the
+// settings of the config file as defaults
+func (p ProviderConfig) provider(cfg Config, opts Options) (Provider, error) {
+	switch p.Source {
+	case "", "openweathermap":
+	case ProviderOpenMeteo:
+		o := NewOpenMeteo(opts.Units)
+		if p.BaseURL != "" {
+			o.BaseURL = p.BaseURL
+		}
+		if p.GeoURL != "" {
+			o.GeoURL = p.GeoURL
+		}
+		return o, nil
+	default:
+		return nil, fmt.Errorf("unknown source %q of provider %s, want openweathermap or open-meteo", p.Source, p.Name)
+	}
+	keyEnv := p.KeyEnv
+	if keyEnv == "" {
+		keyEnv = "OPENWEATHERMAP_API_KEY"
+	}
+	key := os.Getenv(keyEnv)
+	if key == "" {
+		return nil, fmt.Errorf("please set the env variable %s for provider %s", keyEnv, p.Name)
+	}
+	c := NewClientFromConfig(key, cfg)
+	if p.BaseURL != "" {
+		c.BaseURL = p.BaseURL
+	}
+	if p.API != "" {
+		c.API = p.API
+	}
+	c.Units = opts.Units
+	c.Lang = opts.Lang
+	c.Exclude = opts.Exclude
+	return c, nil
+}
+
+// PrintConsensus ... agreement of the providers per day, with the ranges of their forecasts
+func PrintConsensus(days []ConsensusDay, names []string, f Forecast) {
+	fmt.Println()
+	printHeader("Übereinstimmung der Vorhersagen von "+strings.Join(names, ", "), f)
+	unit := f.TemperatureUnit()
+	fmt.Printf("%-10s  %-14s  %-14s  %-10s  %-12s  %s\n", "Tag", "Max", "Min", "Regen", "Regen sagen", "Übereinstimmung")
+	for _, d := range days {
+		fmt.Printf("%-10s  %-14s  %-14s  %-10s  %-12s  %s\n",
+			d.Day,
+			fmt.Sprintf("%.0f - %.0f %s", d.MaxLow, d.MaxHigh, unit),
+			fmt.Sprintf("%.0f - %.0f %s", d.MinLow, d.MinHigh, unit),
+			fmt.Sprintf("%.0f - %.0f %%", d.RainChanceLow, d.RainChanceHigh),
+			fmt.Sprintf("%d von %d", d.Rainy, d.Providers),
+			d.Confidence())
+	}
+	fmt.Println()
+}
+
+func runConsensus(env *cliEnv, opts Options) error {
+	if len(env.cfg.Providers) < 2 {
+		return fmt.Errorf("%s needs at least two providers in the config file", CommandConsensus)
+	}
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return err
+	}
+	providers := []NamedProvider{}
+	names := []string{}
+	for _, p := range env.cfg.Providers {
+		pc, err := p.provider(env.cfg, opts)
+		if err != nil {
+			return err
+		}
+		providers = append(providers, NamedProvider{Name: p.Name, Provider: pc})
+		names = append(names, p.Name)
+	}
+	forecasts, err := GetProviderForecasts(providers, coordinates)
+	if err != nil {
+		return err
+	}
+	days := GetConsensus(forecasts)
+	if opts.Days < len(days) {
+		days = days[:opts.Days]
+	}
+	place := placeName(c, coordinates)
+	if opts.Format == FormatJSON {
+		return printJSON(os.Stdout, struct {
+			Place     string         `json:"place,omitempty"`
+			Providers []string       `json:"providers"`
+			Daily     []ConsensusDay `json:"daily"`
+		}{place, names, days})
+	}
+	PrintConsensus(days, names, Forecast{Place: place, Units: c.Units})
+	return nil
+}
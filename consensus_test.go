@@ -0,0 +1,98 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func consensusForecast(days ...weather.ForecastDaily) weather.Forecast {
+	return weather.Forecast{Daily: days}
+}
+
+func TestGetConsensus(t *testing.T) {
+	t.Parallel()
+	day := func(name string, min, max, rainChance float64) weather.ForecastDaily {
+		return weather.ForecastDaily{Day: name, Temp: weather.DailyTempBenchmarks{Min: min, Max: max}, RainChance: rainChance}
+	}
+	forecasts := []weather.ProviderForecast{
+		{Name: "a", Forecast: consensusForecast(day("17.06.2022", 15, 30, 10), day("18.06.2022", 14, 25, 80), day("19.06.2022", 12, 20, 60))},
+		{Name: "b", Forecast: consensusForecast(day("17.06.2022", 16, 31, 0), day("18.06.2022", 13, 21, 70), day("19.06.2022", 11, 27, 20))},
+		{Name: "c", Forecast: consensusForecast(day("17.06.2022", 15, 29, 20), day("18.06.2022", 14, 24, 90))},
+	}
+	got := weather.GetConsensus(forecasts)
+	if len(got) != 2 {
+		t.Fatalf("want the 2 days covered by all providers, got %+v", got)
+	}
+	today := got[0]
+	if today.Providers != 3 || today.MaxLow != 29 || today.MaxHigh != 31 || today.Spread != 2 || today.Rainy != 0 {
+		t.Errorf("want 3 providers with a maximum of 29 - 31 and no rain, got %+v", today)
+	}
+	if c := today.Confidence(); c != "hoch" {
+		t.Errorf("want high confidence for agreeing providers, got %s", c)
+	}
+	tomorrow := got[1]
+	if tomorrow.Spread != 4 || tomorrow.Rainy != 3 || !tomorrow.RainAgreed() {
+		t.Errorf("want a spread of 4 with rain agreed on, got %+v", tomorrow)
+	}
+	if c := tomorrow.Confidence(); c != "mittel" {
+		t.Errorf("want medium confidence for a spread of 4, got %s", c)
+	}
+	disagreeing := weather.GetConsensus(forecasts[:2])[2]
+	if disagreeing.RainAgreed() || disagreeing.Confidence() != "gering" {
+		t.Errorf("want low confidence if the rain isn't agreed on, got %+v", disagreeing)
+	}
+	if got := weather.GetConsensus(nil); len(got) != 0 {
+		t.Errorf("want no days without forecasts, got %+v", got)
+	}
+}
+
+func TestConsensusCommand(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	t.Setenv("OTHER_API_KEY", "otherDummyAPIKey")
+	config := `{"base_url": "` + ts.URL + `", "providers": [{"name": "onecall", "api": "onecall"}, {"name": "mirror", "base_url": "` + ts.URL + `", "key_env": "OTHER_API_KEY"}]}`
+	out, err := runCLIWithConfig(t, config, "consensus", "Bonn,DE", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Providers []string               `json:"providers"`
+		Daily     []weather.ConsensusDay `json:"daily"`
+	}
+	err = json.Unmarshal(out, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Providers) != 2 || len(got.Daily) != 3 {
+		t.Fatalf("want 3 days of 2 providers, got %+v", got)
+	}
+	if got.Daily[0].Spread != 0 || got.Daily[0].Confidence() != "hoch" {
+		t.Errorf("want identical forecasts to agree, got %+v", got.Daily[0])
+	}
+
+	// Open-Meteo forecasts from other models, so the forecasts differ
+	config = `{"base_url": "` + ts.URL + `", "providers": [{"name": "owm"}, {"name": "om", "source": "open-meteo", "base_url": "` + ts.URL + `"}]}`
+	out, err = runCLIWithConfig(t, config, "consensus", "Bonn,DE", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(out, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Daily) != 3 || got.Daily[0].MaxLow != 30.1 || got.Daily[0].MaxHigh != 31.38 || got.Daily[2].Rainy != 2 {
+		t.Errorf("want the forecasts of OpenWeatherMap and Open-Meteo, got %+v", got.Daily)
+	}
+	config = `{"base_url": "` + ts.URL + `", "providers": [{"name": "owm"}, {"name": "other", "source": "darksky"}]}`
+	if _, err := runCLIWithConfig(t, config, "consensus", "Bonn,DE"); err == nil {
+		t.Error("want error for an unknown source, but got nil")
+	}
+	_, err = runCLI(t, ts, "consensus", "Bonn,DE")
+	if err == nil {
+		t.Error("want error without providers, but got nil")
+	}
+}
@@ -0,0 +1,70 @@
+package weather_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+)
+
+func TestCronMatches(t *testing.T) {
+	t.Parallel()
+	// Friday, 17.06.2022
+	friday := time.Date(2022, 6, 17, 6, 30, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{"* * * * *", friday, true},
+		{"30 6 * * *", friday, true},
+		{"30 7 * * *", friday, false},
+		{"*/15 * * * *", friday, true},
+		{"*/20 * * * *", friday, false},
+		{"0,15,30,45 6-8 * * 1-5", friday, true},
+		{"30 6 * * 0,6", friday, false},
+		{"30 6 * * 7", friday.AddDate(0, 0, 2), true},
+		{"30 6 1 * *", friday, false},
+		// day of month and day of week both restricted, either matches
+		{"30 6 1 * 5", friday, true},
+		{"30 6 17 * 1", friday, true},
+		{"5/10 * * * *", friday.Add(-5 * time.Minute), true},
+		{"@daily", friday, false},
+		{"@daily", time.Date(2022, 6, 17, 0, 0, 0, 0, time.UTC), true},
+		{"@hourly", friday.Add(30 * time.Minute), true},
+	}
+	for _, tc := range tests {
+		c, err := weather.ParseCron(tc.spec)
+		if err != nil {
+			t.Errorf("%s: %v", tc.spec, err)
+			continue
+		}
+		got := c.Matches(tc.t)
+		if tc.want != got {
+			t.Errorf("%s at %s: want %v, got %v", tc.spec, tc.t, tc.want, got)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	t.Parallel()
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@often"} {
+		_, err := weather.ParseCron(spec)
+		if err == nil {
+			t.Errorf("%q: want error, but got nil", spec)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	t.Parallel()
+	c, err := weather.ParseCron("0 7 * * 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := c.Next(time.Date(2022, 6, 17, 6, 30, 0, 0, time.UTC))
+	want := time.Date(2022, 6, 20, 7, 0, 0, 0, time.UTC)
+	if !want.Equal(got) {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,88 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	FunctionDegreeDays = "degree-days"
+
+	// DefaultDegreeDayBase ... base temperature of the degree days in °C, below it is heated, above it is cooled
+	DefaultDegreeDayBase = 18
+)
+
+// DegreeDay ... heating and cooling degree days of one day, Mean is the mean of minimum and maximum
+type DegreeDay struct {
+	Day     string  `json:"day"`
+	Mean    float64 `json:"mean"`
+	Heating float64 `json:"heating"`
+	Cooling float64 `json:"cooling"`
+}
+
+// HeatingDegreeDays ... how far the mean temperature of a day is below the base, 0 if it isn't
+func HeatingDegreeDays(mean, base float64) float64 {
+	return math.Max(0, base-mean)
+}
+
+// CoolingDegreeDays ... how far the mean temperature of a day is above the base, 0 if it isn't
+func CoolingDegreeDays(mean, base float64) float64 {
+	return math.Max(0, mean-base)
+}
+
+// GetDegreeDays ... degree days of the forecast days, the base is in the units of the forecast
+func GetDegreeDays(f Forecast, base float64) []DegreeDay {
+	days := []DegreeDay{}
+	for _, day := range f.Daily {
+		mean := (day.Temp.Min + day.Temp.Max) / 2
+		days = append(days, DegreeDay{
+			Day:     day.Day,
+			Mean:    mean,
+			Heating: HeatingDegreeDays(mean, base),
+			Cooling: CoolingDegreeDays(mean, base),
+		})
+	}
+	return days
+}
+
+// SumDegreeDays ... heating and cooling degree days of all days
+func SumDegreeDays(days []DegreeDay) (heating, cooling float64) {
+	for _, d := range days {
+		heating += d.Heating
+		cooling += d.Cooling
+	}
+	return heating, cooling
+}
+
+// degreeDayFlags ... flags of the degree-days command
+func degreeDayFlags(fs *flag.FlagSet, opts *Options) {
+	fs.Func("base", fmt.Sprintf("base temperature of the degree days in the units of --units (default %g °C)", float64(DefaultDegreeDayBase)), func(s string) (err error) {
+		opts.Base, err = strconv.ParseFloat(s, 64)
+		return err
+	})
+}
+
+// validateDegreeDays ... without --base the default base is converted to the units
+func validateDegreeDays(fs *flag.FlagSet, opts *Options) error {
+	if !isFlagSet(fs, "base") {
+		opts.Base = convertTemperature(DefaultDegreeDayBase, UnitsMetric, opts.Units)
+	}
+	return nil
+}
+
+// PrintDegreeDays ... heating and cooling degree days per day and in total, an estimate of the energy demand
+func PrintDegreeDays(f Forecast, base float64) {
+	fmt.Println()
+	unit := f.TemperatureUnit()
+	printHeader(fmt.Sprintf("Heiz- und Kühlgradtage, Basis %.1f %s", base, unit), f)
+	days := GetDegreeDays(f, base)
+	fmt.Printf("%-10s  %8s  %8s  %8s\n", "Tag", "Mittel", "Heizen", "Kühlen")
+	for _, d := range days {
+		fmt.Printf("%-10s  %5.1f %s  %8.1f  %8.1f\n", d.Day, d.Mean, unit, d.Heating, d.Cooling)
+	}
+	heating, cooling := SumDegreeDays(days)
+	fmt.Printf("%-10s  %8s  %8.1f  %8.1f\n", "Summe", "", heating, cooling)
+	fmt.Println()
+}
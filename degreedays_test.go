@@ -0,0 +1,53 @@
+package weather_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetDegreeDays(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Daily: []weather.ForecastDaily{
+		{Day: "17.06.2022", Temp: weather.DailyTempBenchmarks{Min: 16, Max: 30}},
+		{Day: "18.06.2022", Temp: weather.DailyTempBenchmarks{Min: 4, Max: 12}},
+		{Day: "19.06.2022", Temp: weather.DailyTempBenchmarks{Min: 14, Max: 22}},
+	}}
+	got := weather.GetDegreeDays(f, 18)
+	want := []weather.DegreeDay{
+		{Day: "17.06.2022", Mean: 23, Cooling: 5},
+		{Day: "18.06.2022", Mean: 8, Heating: 10},
+		{Day: "19.06.2022", Mean: 18},
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	heating, cooling := weather.SumDegreeDays(got)
+	if heating != 10 || cooling != 5 {
+		t.Errorf("want 10 heating and 5 cooling degree days, got %g and %g", heating, cooling)
+	}
+}
+
+func TestParseOptionsDegreeDayBase(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args []string
+		want float64
+	}{
+		{[]string{"Bonn"}, 18},
+		{[]string{"Bonn", "--units", "imperial"}, 64.4},
+		{[]string{"Bonn", "--base", "15.5"}, 15.5},
+		{[]string{"Bonn", "--units", "imperial", "--base", "65"}, 65},
+	}
+	for _, tt := range tests {
+		opts, err := weather.ParseOptions(weather.FunctionDegreeDays, tt.args, weather.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(opts.Base-tt.want) > 1e-9 {
+			t.Errorf("%v: want base %g, got %g", tt.args, tt.want, opts.Base)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package weather
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DesktopRainWindow ... rain starting within this time is notified by the --notify flag
+const DesktopRainWindow = time.Hour
+
+// Desktop ... raises notifications on the desktop, with notify-send on Linux and the BSDs and osascript on macOS
+type Desktop struct {
+	GOOS string
+	// Exec runs the notification command, replaceable in tests
+	Exec func(name string, args ...string) error
+}
+
+// NewDesktop ... desktop notifier for the running system
+func NewDesktop() *Desktop {
+	return &Desktop{
+		GOOS: runtime.GOOS,
+		Exec: func(name string, args ...string) error {
+			return exec.Command(name, args...).Run()
+		},
+	}
+}
+
+var appleScriptEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// Notify ... raises the notification, alerts as critical where supported
+func (d *Desktop) Notify(n Notification) error {
+	switch d.GOOS {
+	case "darwin":
+		script := fmt.Sprintf(`display notification "%s" with title "%s"`,
+			appleScriptEscaper.Replace(n.Message), appleScriptEscaper.Replace(n.Title))
+		return d.Exec("osascript", "-e", script)
+	case "windows", "plan9", "js":
+		return fmt.Errorf("desktop notifications are not supported on %s", d.GOOS)
+	}
+	args := []string{"--app-name", "weather"}
+	if n.Kind == NotifyAlert {
+		args = append(args, "--urgency", "critical")
+	}
+	return d.Exec("notify-send", append(args, n.Title, n.Message)...)
+}
+
+// notifyDesktop ... desktop notifications of the --notify flag, remembered in their own state file
+func (env *cliEnv) notifyDesktop(opts Options, f Forecast) error {
+	path := filepath.Join(filepath.Dir(env.cfgPath), "notify-desktop.json")
+	state, err := LoadNotifyState(path)
+	if err != nil {
+		return err
+	}
+	notifications := state.Check(notifyLocation(opts, f), f, DesktopRainWindow, time.Now())
+	err = send([]Notifier{NewDesktop()}, notifications)
+	if err != nil {
+		return err
+	}
+	return SaveNotifyState(path, state)
+}
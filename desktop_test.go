@@ -0,0 +1,61 @@
+package weather_test
+
+import (
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDesktopNotify(t *testing.T) {
+	t.Parallel()
+	alert := weather.Notification{Kind: weather.NotifyAlert, Title: `Wetterwarnung für "Bonn"`, Message: "bis morgen"}
+	rain := weather.Notification{Kind: weather.NotifyRain, Title: "Regen in Bonn", Message: "Ab 15:00 Uhr Regen"}
+	tests := map[string]struct {
+		goos string
+		n    weather.Notification
+		want []string
+	}{
+		"linux alert": {"linux", alert, []string{"notify-send", "--app-name", "weather", "--urgency", "critical", `Wetterwarnung für "Bonn"`, "bis morgen"}},
+		"linux rain":  {"linux", rain, []string{"notify-send", "--app-name", "weather", "Regen in Bonn", "Ab 15:00 Uhr Regen"}},
+		"macOS":       {"darwin", alert, []string{"osascript", "-e", `display notification "bis morgen" with title "Wetterwarnung für \"Bonn\""`}},
+	}
+	for name, tc := range tests {
+		var got []string
+		d := &weather.Desktop{GOOS: tc.goos, Exec: func(name string, args ...string) error {
+			got = append([]string{name}, args...)
+			return nil
+		}}
+		err := d.Notify(tc.n)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("%s: %s", name, cmp.Diff(tc.want, got))
+		}
+	}
+
+	d := &weather.Desktop{GOOS: "windows"}
+	err := d.Notify(rain)
+	if err == nil {
+		t.Error("want error on windows, but got nil")
+	}
+}
+
+func TestParseOptionsDesktopNotify(t *testing.T) {
+	t.Parallel()
+	opts, err := weather.ParseOptions("current", []string{"Bonn", "--notify"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.Notify {
+		t.Error("want desktop notifications for --notify")
+	}
+	opts, err = weather.ParseOptions("notify", []string{"--desktop"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.Desktop {
+		t.Error("want desktop notifier for --desktop")
+	}
+}
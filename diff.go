@@ -0,0 +1,263 @@
+package weather
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	CommandDiff = "diff"
+
+	// NotifyDiff ... kind of the notification of a changed forecast
+	NotifyDiff = "diff"
+	// ActionDiff ... scheduled job notifying of changes of the forecast
+	ActionDiff = "diff"
+
+	// DiffTemperature and DiffRainChance are the smallest changes reported, in degrees and percentage points
+	DiffTemperature = 3
+	DiffRainChance  = 30
+	// DiffRainStart ... smallest shift of the start of the rain reported
+	DiffRainStart = 2 * time.Hour
+)
+
+type (
+	// Change ... meaningful difference between two forecasts, Day is empty for changes of the hourly forecast
+	Change struct {
+		Day     string `json:"day,omitempty"`
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+
+	// ForecastCache ... last fetched forecast of every location, the base of the next diff
+	ForecastCache struct {
+		Forecasts map[string]Forecast `json:"forecasts"`
+	}
+
+	// diffSink ... notifies of the changes since the previous observation of the location
+	diffSink struct {
+		notifiers []Notifier
+		cache     ForecastCache
+		path      string
+	}
+)
+
+// DiffForecasts ... changes of the forecast next compared to previous, the rain is compared from now on
+func DiffForecasts(previous, next Forecast, now time.Time) []Change {
+	changes := []Change{}
+	unit := next.TemperatureUnit()
+	old := map[string]ForecastDaily{}
+	for _, day := range previous.Daily {
+		old[day.Day] = day
+	}
+	for i, day := range next.Daily {
+		before, ok := old[day.Day]
+		if !ok {
+			continue
+		}
+		label := dayLabel(i, day.Day)
+		for _, t := range []struct {
+			field, name string
+			old, new    float64
+		}{
+			{"temp_max", "Höchstwert", before.Temp.Max, day.Temp.Max},
+			{"temp_min", "Tiefstwert", before.Temp.Min, day.Temp.Min},
+		} {
+			delta := t.new - convertTemperature(t.old, previous.Units, next.Units)
+			if math.Abs(delta) < DiffTemperature {
+				continue
+			}
+			direction := "wärmer"
+			if delta < 0 {
+				direction = "kälter"
+			}
+			changes = append(changes, Change{Day: day.Day, Field: t.field, Message: fmt.Sprintf("%s %s jetzt %.0f %s %s (%.0f %s)",
+				label, t.name, math.Abs(delta), unit, direction, t.new, unit)})
+		}
+		if math.Abs(day.RainChance-before.RainChance) >= DiffRainChance {
+			changes = append(changes, Change{Day: day.Day, Field: "rain_chance", Message: fmt.Sprintf("%s Regenwahrscheinlichkeit jetzt %.0f %% statt %.0f %%",
+				label, day.RainChance, before.RainChance)})
+		}
+		known := map[string]bool{}
+		for _, a := range before.Alerts {
+			known[alertKey(a)] = true
+		}
+		for _, a := range day.Alerts {
+			if !known[alertKey(a)] {
+				changes = append(changes, Change{Day: day.Day, Field: "alerts", Message: fmt.Sprintf("%s neue Warnung: %s", label, a.Name)})
+			}
+		}
+	}
+
+	window := 48 * time.Hour
+	oldStart, _, oldRain := rainPeriod(previous.Hourly, now, window)
+	newStart, _, newRain := rainPeriod(next.Hourly, now, window)
+	switch {
+	case oldRain && !newRain:
+		changes = append(changes, Change{Field: "rain", Message: "kein Regen mehr erwartet"})
+	case !oldRain && newRain:
+		changes = append(changes, Change{Field: "rain", Message: fmt.Sprintf("Regen jetzt ab %s erwartet", formatRainStart(newStart.Time, now))})
+	case oldRain && newRain:
+		shift := newStart.Time.Sub(oldStart.Time)
+		if shift >= DiffRainStart || -shift >= DiffRainStart {
+			changes = append(changes, Change{Field: "rain", Message: fmt.Sprintf("Regen jetzt ab %s statt ab %s",
+				formatRainStart(newStart.Time, now), formatRainStart(oldStart.Time, now))})
+		}
+	}
+	return changes
+}
+
+// dayLabel ... the i-th day of the forecast in words
+func dayLabel(i int, day string) string {
+	switch i {
+	case 0:
+		return "Heute"
+	case 1:
+		return "Morgen"
+	case 2:
+		return "Übermorgen"
+	}
+	return "Am " + day
+}
+
+// formatRainStart ... hour of the start, with the day if it isn't today
+func formatRainStart(t, now time.Time) string {
+	y, m, d := t.Date()
+	if ny, nm, nd := now.In(t.Location()).Date(); y == ny && m == nm && d == nd {
+		return t.Format("15:04")
+	}
+	return t.Format("02.01. 15:04")
+}
+
+// DiffNotification ... the changes of the forecast of the location as one notification
+func DiffNotification(location string, changes []Change) Notification {
+	lines := []string{}
+	for _, c := range changes {
+		lines = append(lines, c.Message)
+	}
+	return Notification{
+		Kind:     NotifyDiff,
+		Location: location,
+		Title:    "Vorhersage für " + location + " geändert",
+		Message:  strings.Join(lines, "\n"),
+	}
+}
+
+// PrintChanges ... output of the changes of the forecast
+func PrintChanges(changes []Change, f Forecast) {
+	WriteChanges(os.Stdout, changes, f)
+}
+
+// WriteChanges ... PrintChanges writing to w
+func WriteChanges(w io.Writer, changes []Change, f Forecast) {
+	fmt.Fprintln(w)
+	writeHeader(w, "Änderungen der Vorhersage", f)
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "keine nennenswerten Änderungen")
+	}
+	for _, c := range changes {
+		fmt.Fprintln(w, c.Message)
+	}
+	fmt.Fprintln(w)
+}
+
+// ForecastCachePath ... cache of the diff command, next to the config file
+func ForecastCachePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "forecast.json")
+}
+
+// LoadForecastCache ... reads the cache file, a missing file results in an empty cache
+func LoadForecastCache(path string) (ForecastCache, error) {
+	c := ForecastCache{Forecasts: map[string]Forecast{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return ForecastCache{}, fmt.Errorf("invalid cache file %s: %w", path, err)
+	}
+	if c.Forecasts == nil {
+		c.Forecasts = map[string]Forecast{}
+	}
+	return c, nil
+}
+
+// SaveForecastCache ... writes the cache file, missing directories are created
+func SaveForecastCache(path string, c ForecastCache) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// Update ... changes since the cached forecast of the location, which is replaced by f,
+// ok is false for the first forecast of the location
+func (c *ForecastCache) Update(location string, f Forecast, now time.Time) (changes []Change, ok bool) {
+	if c.Forecasts == nil {
+		c.Forecasts = map[string]Forecast{}
+	}
+	previous, ok := c.Forecasts[location]
+	c.Forecasts[location] = f
+	if !ok {
+		return nil, false
+	}
+	return DiffForecasts(previous, f, now), true
+}
+
+func (d *diffSink) Write(o Observation) error {
+	changes, ok := d.cache.Update(o.Location, o.Forecast, o.Time)
+	err := SaveForecastCache(d.path, d.cache)
+	if err != nil {
+		return err
+	}
+	if !ok || len(changes) == 0 {
+		return nil
+	}
+	return send(d.notifiers, []Notification{DiffNotification(o.Location, changes)})
+}
+
+func runDiff(env *cliEnv, opts Options) error {
+	path := ForecastCachePath(env.cfgPath)
+	cache, err := LoadForecastCache(path)
+	if err != nil {
+		return err
+	}
+	_, forecast, err := env.fetch(opts)
+	if err != nil {
+		return err
+	}
+	location := notifyLocation(opts, forecast)
+	changes, ok := cache.Update(location, forecast, time.Now())
+	err = SaveForecastCache(path, cache)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "first forecast of %s cached, changes are shown from the next call on\n", location)
+		changes = []Change{}
+	}
+	if opts.Format == FormatJSON {
+		return printJSON(os.Stdout, struct {
+			Place   string   `json:"place,omitempty"`
+			Changes []Change `json:"changes"`
+		}{forecast.Place, changes})
+	}
+	PrintChanges(changes, forecast)
+	return nil
+}
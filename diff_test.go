@@ -0,0 +1,122 @@
+package weather_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffForecasts(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC)
+	day := func(name string, min, max, rain float64, alerts ...weather.Alert) weather.ForecastDaily {
+		return weather.ForecastDaily{Day: name, Temp: weather.DailyTempBenchmarks{Min: min, Max: max}, RainChance: rain, Alerts: alerts}
+	}
+	rainAt := func(hours ...int) []weather.ForecastHourly {
+		hourly := []weather.ForecastHourly{}
+		for i := 0; i < 24; i++ {
+			slot := weather.ForecastHourly{Time: now.Add(time.Duration(i) * time.Hour)}
+			for _, h := range hours {
+				if h == i {
+					slot.RainChance = 80
+				}
+			}
+			hourly = append(hourly, slot)
+		}
+		return hourly
+	}
+	previous := weather.Forecast{
+		Units:  weather.UnitsMetric,
+		Daily:  []weather.ForecastDaily{day("17.06.2022", 12, 24, 20), day("18.06.2022", 14, 26, 10), day("19.06.2022", 15, 28, 0)},
+		Hourly: rainAt(1, 2),
+	}
+	tests := map[string]struct {
+		next weather.Forecast
+		want []weather.Change
+	}{
+		"unchanged": {
+			next: previous,
+			want: []weather.Change{},
+		},
+		"small changes": {
+			next: weather.Forecast{
+				Units:  weather.UnitsMetric,
+				Daily:  []weather.ForecastDaily{day("17.06.2022", 13, 22, 40)},
+				Hourly: rainAt(2, 3),
+			},
+			want: []weather.Change{},
+		},
+		"colder and rainy tomorrow": {
+			next: weather.Forecast{
+				Units: weather.UnitsMetric,
+				// the forecast moved on by one day
+				Daily:  []weather.ForecastDaily{day("18.06.2022", 14, 21, 70, weather.Alert{Name: "Gewitter", Start: "18.06.2022 14:00"}), day("19.06.2022", 15, 28, 0), day("20.06.2022", 15, 28, 0)},
+				Hourly: rainAt(6, 7),
+			},
+			want: []weather.Change{
+				{Day: "18.06.2022", Field: "temp_max", Message: "Heute Höchstwert jetzt 5 °C kälter (21 °C)"},
+				{Day: "18.06.2022", Field: "rain_chance", Message: "Heute Regenwahrscheinlichkeit jetzt 70 % statt 10 %"},
+				{Day: "18.06.2022", Field: "alerts", Message: "Heute neue Warnung: Gewitter"},
+				{Field: "rain", Message: "Regen jetzt ab 14:00 statt ab 09:00"},
+			},
+		},
+		"imperial": {
+			next: weather.Forecast{
+				Units: weather.UnitsImperial,
+				// 24 °C and 16 °C
+				Daily: []weather.ForecastDaily{day("17.06.2022", 60.8, 75.2, 20)},
+			},
+			want: []weather.Change{
+				{Day: "17.06.2022", Field: "temp_min", Message: "Heute Tiefstwert jetzt 7 °F wärmer (61 °F)"},
+				{Field: "rain", Message: "kein Regen mehr erwartet"},
+			},
+		},
+		"no hourly forecast": {
+			next: weather.Forecast{Units: weather.UnitsMetric},
+			want: []weather.Change{{Field: "rain", Message: "kein Regen mehr erwartet"}},
+		},
+	}
+	for name, tc := range tests {
+		got := weather.DiffForecasts(previous, tc.next, now)
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("%s: %s", name, cmp.Diff(tc.want, got))
+		}
+	}
+	got := weather.DiffForecasts(weather.Forecast{}, weather.Forecast{Hourly: rainAt(20)}, now)
+	want := []weather.Change{{Field: "rain", Message: "Regen jetzt ab 18.06. 04:00 erwartet"}}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestForecastCache(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "weather", "forecast.json")
+	c, err := weather.LoadForecastCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC)
+	f := weather.Forecast{Units: weather.UnitsMetric, Daily: []weather.ForecastDaily{{Day: "17.06.2022", Temp: weather.DailyTempBenchmarks{Max: 24}}}}
+	_, ok := c.Update("Bonn, DE", f, now)
+	if ok {
+		t.Error("want no changes for the first forecast")
+	}
+	err = weather.SaveForecastCache(path, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = weather.LoadForecastCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Daily[0].Temp.Max = 30
+	changes, ok := c.Update("Bonn, DE", f, now)
+	want := []weather.Change{{Day: "17.06.2022", Field: "temp_max", Message: "Heute Höchstwert jetzt 6 °C wärmer (30 °C)"}}
+	if !ok || !cmp.Equal(want, changes) {
+		t.Errorf("want changes of the cached forecast: %s", cmp.Diff(want, changes))
+	}
+}
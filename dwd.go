@@ -0,0 +1,144 @@
+package weather
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// AlertSourceDWD ... official warnings of the Deutscher Wetterdienst for Germany
+	AlertSourceDWD = "dwd"
+
+	// DefaultDWDURL ... base URL of the warnings of the DWD, the feed of its WarnWetter app
+	DefaultDWDURL = "https://www.dwd.de"
+
+	dwdSender = "Deutscher Wetterdienst"
+	// dwdOpenEnd ... how long an alert without end counts
+	dwdOpenEnd = 24 * time.Hour
+)
+
+type (
+	// DWDResponse ... warnings of the DWD by warn cell, the municipalities and districts it warns for
+	DWDResponse struct {
+		Warnings map[string][]DWDWarning
+	}
+
+	// DWDWarning ... warning of the DWD, Start and End are in milliseconds since 1970, End is nil until further notice,
+	// Level is 1 for a Wetterwarnung, 2 for markantes Wetter, 3 for an Unwetter and 4 for an extremes Unwetter
+	DWDWarning struct {
+		RegionName  string
+		Start       int64
+		End         *int64
+		Level       int
+		Event       string
+		Headline    string
+		Description string
+		Instruction string
+	}
+)
+
+// dwdLevels ... severity and German name of the warning levels of the DWD, 0 is a Vorabinformation
+var dwdLevels = []struct {
+	severity AlertSeverity
+	name     string
+}{
+	{AlertUnknown, "Vorabinformation"},
+	{AlertMinor, "Wetterwarnung"},
+	{AlertModerate, "markantes Wetter"},
+	{AlertSevere, "Unwetter"},
+	{AlertExtreme, "extremes Unwetter"},
+}
+
+// ParseDWDResponse ... alerts of the warn cell, e.g. 805314000 for Bonn, or without it of the regions whose name
+// contains the place. The feed comes as JSONP, which is unwrapped
+func ParseDWDResponse(data []byte, warncell, place string) ([]Alert, error) {
+	payload := data
+	if start := bytes.IndexByte(data, '('); start >= 0 {
+		if end := bytes.LastIndexByte(data, ')'); end > start {
+			payload = data[start+1 : end]
+		}
+	}
+	var resp DWDResponse
+	err := json.Unmarshal(payload, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DWD response %s: %w", data, err)
+	}
+	warnings := []DWDWarning{}
+	if warncell != "" {
+		warnings = resp.Warnings[warncell]
+	} else if place != "" {
+		cells := []string{}
+		for cell := range resp.Warnings {
+			cells = append(cells, cell)
+		}
+		sort.Strings(cells)
+		for _, cell := range cells {
+			for _, w := range resp.Warnings[cell] {
+				if strings.Contains(strings.ToLower(w.RegionName), strings.ToLower(place)) {
+					warnings = append(warnings, w)
+				}
+			}
+		}
+	}
+	alerts := []Alert{}
+	for _, w := range warnings {
+		if w.Level < 0 || w.Level >= len(dwdLevels) {
+			return nil, fmt.Errorf("invalid DWD warning %q: unknown level %d", w.Event, w.Level)
+		}
+		start := time.UnixMilli(w.Start)
+		end := start.Add(dwdOpenEnd)
+		if w.End != nil {
+			end = time.UnixMilli(*w.End)
+		}
+		description := w.Description
+		if w.Instruction != "" {
+			description += "\n" + w.Instruction
+		}
+		a := Alert{
+			Start:       start.Format("02.01.2006, 15:04"),
+			End:         end.Format("02.01.2006, 15:04"),
+			Name:        w.Event,
+			Description: description,
+			Sender:      dwdSender,
+			Severity:    dwdLevels[w.Level].severity,
+			Level:       dwdLevels[w.Level].name,
+			Category:    "met",
+		}
+		a.Certainty = "likely"
+		if w.Level == 0 {
+			a.Urgency, a.Certainty = "future", "possible"
+		}
+		alerts = append(alerts, ClassifyAlert(a))
+	}
+	return MergeAlerts(alerts), nil
+}
+
+func (c *Client) FormatDWDURL() string {
+	return c.DWDURL + "/DWD/warnungen/warnapp/json/warnings.json"
+}
+
+// GetDWD ... official warnings of the DWD for the warn cell or, without it, the place, see ParseDWDResponse
+func (c *Client) GetDWD(warncell string, place Place) ([]Alert, error) {
+	if warncell == "" && !strings.EqualFold(place.Country, "DE") {
+		return nil, fmt.Errorf("the DWD only warns for Germany, not for %q", place.Country)
+	}
+	resp, err := c.HTTPClient.Get(c.FormatDWDURL())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDWDResponse(data, warncell, place.Name)
+}
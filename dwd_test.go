@@ -0,0 +1,108 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDWDResponse(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParseDWDResponse(weathertest.DWDResponse, "805314000", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []weather.Alert{
+		{
+			Start:       "17.06.2022, 18:00",
+			End:         "18.06.2022, 02:00",
+			Name:        "SCHWERES GEWITTER mit HAGEL",
+			Description: "Es treten schwere Gewitter mit Hagel auf.\nSchließen Sie alle Fenster.",
+			Sender:      "Deutscher Wetterdienst",
+			Severity:    weather.AlertSevere,
+			Level:       "Unwetter",
+			Certainty:   "likely",
+			Category:    "met",
+		},
+		{
+			Start:       "19.06.2022, 08:00",
+			End:         "20.06.2022, 08:00",
+			Name:        "HITZE",
+			Description: "Es tritt Hitze auf.",
+			Sender:      "Deutscher Wetterdienst",
+			Severity:    weather.AlertModerate,
+			Level:       "markantes Wetter",
+			Certainty:   "likely",
+			Category:    "met",
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseDWDResponseByPlace(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParseDWDResponse(weathertest.DWDResponse, "", "bad schnuffel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the thunderstorm of the district and the town is the same
+	if len(got) != 2 || got[0].Name != "SCHWERES GEWITTER mit HAGEL" || got[1].Name != "HITZE" {
+		t.Errorf("want the thunderstorm and the heat, got %+v", got)
+	}
+	got, err = weather.ParseDWDResponse(weathertest.DWDResponse, "", "Hamburg")
+	if err != nil || len(got) != 0 {
+		t.Errorf("want no alerts, got %+v, %v", got, err)
+	}
+}
+
+func TestParseDWDResponseInvalid(t *testing.T) {
+	t.Parallel()
+	for _, data := range []string{`warnWetter.loadWarnings([]);`, `{"warnings": {"1": [{"level": 7}]}}`} {
+		if _, err := weather.ParseDWDResponse([]byte(data), "1", ""); err == nil {
+			t.Errorf("%s: want error, but got nil", data)
+		}
+	}
+}
+
+func TestGetDWD(t *testing.T) {
+	t.Parallel()
+	c := weathertest.NewFakeClient(t)
+	got, err := c.GetDWD("809162000", weather.Place{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Level != "extremes Unwetter" || got[0].Severity != weather.AlertExtreme {
+		t.Errorf("want the extreme thunderstorm, got %+v", got)
+	}
+	if _, err := c.GetDWD("", weather.Place{Name: "Wien", Country: "AT"}); err == nil {
+		t.Error("want an error outside Germany")
+	}
+}
+
+func TestAlertSourceDWD(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	t.Cleanup(ts.Close)
+	config := `{"base_url": "` + ts.URL + `", "dwd_url": "` + ts.URL + `", "alert_sources": ["dwd"], "dwd_warncell": "805314000"}`
+	out, err := runCLIWithConfig(t, config, "alert", "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "SCHWERES GEWITTER mit HAGEL (Unwetter) von 17.06.2022, 18:00") {
+		t.Errorf("want the thunderstorm with its level, got %s", out)
+	}
+	out, err = runCLIWithConfig(t, config, "alert", "Bonn,DE", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct{ Count int }
+	if err := json.Unmarshal(out, &got); err != nil || got.Count != 2 {
+		t.Errorf("want 2 alerts, got %s", out)
+	}
+}
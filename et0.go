@@ -0,0 +1,118 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// solarConstant ... in MJ/m² per minute
+	solarConstant = 0.0820
+	// stefanBoltzmann ... in MJ/K⁴/m² per day
+	stefanBoltzmann = 4.903e-9
+	// seaLevelPressure ... in kPa, the daily forecast has no pressure of the station
+	seaLevelPressure = 101.3
+)
+
+// ET0Input ... daily measurements of the reference evapotranspiration, in °C, percent, m/s and degrees
+type ET0Input struct {
+	Date     time.Time
+	Latitude float64
+	TempMin  float64
+	TempMax  float64
+	Humidity int
+	// Wind is the speed 10 m above ground as delivered by the API
+	Wind Speed
+	// Clouds are the cloud cover in percent, a proxy of the hours of sunshine
+	Clouds int
+}
+
+// ReferenceET0 ... evapotranspiration of grass in mm per day after the Penman-Monteith equation of FAO-56,
+// the solar radiation is estimated from the latitude, the date and the cloud cover
+func ReferenceET0(in ET0Input) float64 {
+	mean := (in.TempMin + in.TempMax) / 2
+	slope := 4098 * saturationVapourPressure(mean) / math.Pow(mean+237.3, 2)
+	gamma := 0.000665 * seaLevelPressure
+	es := (saturationVapourPressure(in.TempMin) + saturationVapourPressure(in.TempMax)) / 2
+	ea := es * float64(in.Humidity) / 100
+	// wind 2 m above ground by the logarithmic wind profile
+	u2 := float64(in.Wind) * 4.87 / math.Log(67.8*10-5.42)
+
+	ra := extraterrestrialRadiation(in.Latitude, in.Date.YearDay())
+	sunshine := 1 - float64(in.Clouds)/100
+	rs := (0.25 + 0.5*sunshine) * ra
+	rso := 0.75 * ra
+	rns := 0.77 * rs
+	tMinK, tMaxK := in.TempMin+273.16, in.TempMax+273.16
+	relative := 1.0
+	if rso > 0 {
+		relative = math.Min(rs/rso, 1)
+	}
+	rnl := stefanBoltzmann * (math.Pow(tMaxK, 4) + math.Pow(tMinK, 4)) / 2 * (0.34 - 0.14*math.Sqrt(ea)) * (1.35*relative - 0.35)
+	rn := rns - rnl
+
+	et0 := (0.408*slope*rn + gamma*900/(mean+273)*u2*(es-ea)) / (slope + gamma*(1+0.34*u2))
+	return math.Max(0, et0)
+}
+
+// saturationVapourPressure ... in kPa at the temperature in °C
+func saturationVapourPressure(celsius float64) float64 {
+	return 0.6108 * math.Exp(17.27*celsius/(celsius+237.3))
+}
+
+// extraterrestrialRadiation ... radiation at the top of the atmosphere in MJ/m² per day
+func extraterrestrialRadiation(latitude float64, dayOfYear int) float64 {
+	phi := latitude * math.Pi / 180
+	angle := 2 * math.Pi * float64(dayOfYear) / 365
+	dr := 1 + 0.033*math.Cos(angle)
+	declination := 0.409 * math.Sin(angle-1.39)
+	// polar day and night clamp the sunset hour angle
+	ws := math.Acos(math.Max(-1, math.Min(1, -math.Tan(phi)*math.Tan(declination))))
+	return 24 * 60 / math.Pi * solarConstant * dr *
+		(ws*math.Sin(phi)*math.Sin(declination) + math.Cos(phi)*math.Cos(declination)*math.Sin(ws))
+}
+
+// ET0 ... reference evapotranspiration in mm of the day of the forecast, offset 0 is today,
+// false without the coordinates of the forecast
+func (f Forecast) ET0(offset int) (float64, bool) {
+	if f.Coordinates == nil || offset < 0 || offset >= len(f.Daily) {
+		return 0, false
+	}
+	day := f.Daily[offset]
+	date, err := time.ParseInLocation("02.01.2006", day.Day, time.Local)
+	if err != nil {
+		return 0, false
+	}
+	return ReferenceET0(ET0Input{
+		Date:     date,
+		Latitude: f.Coordinates.Lat,
+		TempMin:  convertTemperature(day.Temp.Min, f.Units, UnitsMetric),
+		TempMax:  convertTemperature(day.Temp.Max, f.Units, UnitsMetric),
+		Humidity: day.Humidity,
+		Wind:     metersPerSecond(day.WindSpeed, f.Units),
+		Clouds:   day.Clouds,
+	}), true
+}
+
+// gardenFlags ... flags of the commands showing the forecast of a day
+func gardenFlags(fs *flag.FlagSet, opts *Options) {
+	fs.BoolVar(&opts.Garden, "garden", false, "add the evapotranspiration (ET0) and the watering need of the day")
+}
+
+// printGarden ... evapotranspiration of the day and how much of it the expected rain makes up
+func printGarden(f Forecast, offset int) {
+	et0, ok := f.ET0(offset)
+	if !ok {
+		return
+	}
+	day := f.Daily[offset]
+	fmt.Printf("Verdunstung (ET0): %.1f mm, Niederschlag: %.1f mm\n", et0, day.Rain+day.Snow)
+	if deficit := et0 - day.Rain - day.Snow; deficit > 0 {
+		fmt.Printf("Zum Ausgleich gießen: etwa %.0f l/m²\n", math.Ceil(deficit))
+	} else {
+		fmt.Println("Gießen nicht nötig.")
+	}
+	fmt.Println()
+}
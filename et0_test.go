@@ -0,0 +1,59 @@
+package weather_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestReferenceET0(t *testing.T) {
+	t.Parallel()
+	// example 18 of FAO-56: Brussels on 6 July with 9.25 of 16.1 possible hours of sunshine, ET0 is 3.9 mm
+	got := weather.ReferenceET0(weather.ET0Input{
+		Date:     time.Date(2022, 7, 6, 0, 0, 0, 0, time.UTC),
+		Latitude: 50.8,
+		TempMin:  12.3,
+		TempMax:  21.5,
+		Humidity: 74,
+		Wind:     2.78,
+		Clouds:   43,
+	})
+	if math.Abs(got-3.9) > 0.3 {
+		t.Errorf("want about 3.9 mm, got %.2f", got)
+	}
+	// no evaporation in the polar night at frost
+	got = weather.ReferenceET0(weather.ET0Input{
+		Date:     time.Date(2022, 12, 21, 0, 0, 0, 0, time.UTC),
+		Latitude: 78,
+		TempMin:  -25,
+		TempMax:  -20,
+		Humidity: 80,
+		Clouds:   100,
+	})
+	if got < 0 || got > 0.2 {
+		t.Errorf("want almost no evapotranspiration, got %.2f", got)
+	}
+}
+
+func TestForecastET0(t *testing.T) {
+	t.Parallel()
+	c := weathertest.NewFakeClient(t)
+	_, f, err := c.GetWeather(weather.Coordinates{Lat: 50.7, Lon: 7.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := f.ET0(0)
+	if !ok || got <= 0 || got > 10 {
+		t.Errorf("want a plausible ET0 of the first day, got %.2f (%v)", got, ok)
+	}
+	f.Coordinates = nil
+	if _, ok := f.ET0(0); ok {
+		t.Error("want no ET0 without coordinates")
+	}
+	if _, ok := f.ET0(len(f.Daily)); ok {
+		t.Error("want no ET0 beyond the forecast")
+	}
+}
@@ -0,0 +1,185 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Subscribe ... channel receiving the events of the location until Unsubscribe is called
+func (s *Server) Subscribe(location string) chan Event {
+	ch := make(chan Event, 16)
+	key := strings.ToLower(location)
+	s.mu.Lock()
+	if s.subscribers == nil {
+		s.subscribers = map[string]*subscription{}
+	}
+	if s.subscribers[key] == nil {
+		s.subscribers[key] = &subscription{location: location, channels: map[chan Event]bool{}}
+	}
+	s.subscribers[key].channels[ch] = true
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *Server) Unsubscribe(location string, ch chan Event) {
+	key := strings.ToLower(location)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subscribers[key]
+	if !ok {
+		return
+	}
+	delete(sub.channels, ch)
+	if len(sub.channels) == 0 {
+		delete(s.subscribers, key)
+	}
+}
+
+// publish ... sends the event to the subscribers of its location, slow subscribers miss events
+func (s *Server) publish(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subscribers[strings.ToLower(e.Location)]
+	if !ok {
+		return
+	}
+	for ch := range sub.channels {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Refresh ... fetches the weather of the location bypassing the cache, changed conditions and new alerts are published
+func (s *Server) Refresh(location string) error {
+	conditions, forecast, err := s.fetch(location)
+	if err != nil {
+		return err
+	}
+	key := strings.ToLower(location)
+	s.mu.Lock()
+	old, ok := s.cache[key]
+	s.store(key, location, cacheEntry{
+		fetched:    time.Now(),
+		conditions: conditions,
+		forecast:   forecast,
+	})
+	s.mu.Unlock()
+	if !ok || old.conditions != conditions {
+		s.publish(Event{Type: EventConditions, Location: location, Conditions: &conditions})
+	}
+	// new alerts and those grown more severe are published
+	known := map[string]AlertSeverity{}
+	if ok {
+		for _, a := range old.forecast.CurrentAlerts() {
+			known[alertKey(a)] = a.Severity
+		}
+	}
+	for _, a := range forecast.CurrentAlerts() {
+		if previous, ok := known[alertKey(a)]; !ok || a.Severity.Rank() > previous.Rank() {
+			a := a
+			s.publish(Event{Type: EventAlert, Location: location, Alert: &a})
+		}
+	}
+	return nil
+}
+
+// Poll ... refreshes the subscribed locations every interval until ctx is done
+func (s *Server) Poll(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, location := range s.subscribed() {
+			err := s.Refresh(location)
+			if err != nil {
+				log.Printf("refresh %s: %v", location, err)
+			}
+		}
+	}
+}
+
+// subscribed ... locations with at least one subscriber
+func (s *Server) subscribed() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	locations := []string{}
+	for _, sub := range s.subscribers {
+		locations = append(locations, sub.location)
+	}
+	return locations
+}
+
+// handleEvents ... streams the events of a location as server-sent events, starting with the current conditions
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{"streaming not supported"})
+		return
+	}
+	location, err := s.normalize(r.URL.Query().Get("location"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	conditions, forecast, err := s.Get(location)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ch := s.Subscribe(location)
+	defer s.Unsubscribe(location, ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	writeEvent(w, Event{Type: EventConditions, Location: location, Conditions: &conditions})
+	for _, a := range forecast.CurrentAlerts() {
+		a := a
+		writeEvent(w, Event{Type: EventAlert, Location: location, Alert: &a})
+	}
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-ch:
+			writeEvent(w, e)
+			flusher.Flush()
+		}
+	}
+}
+
+// cloneForecast ... deep copy of the forecast slices
+func cloneForecast(f Forecast) Forecast {
+	f.Minutely = append([]ForecastMinutely(nil), f.Minutely...)
+	f.Hourly = append([]ForecastHourly(nil), f.Hourly...)
+	daily := make([]ForecastDaily, len(f.Daily))
+	for i, d := range f.Daily {
+		d.Alerts = append([]Alert(nil), d.Alerts...)
+		daily[i] = d
+	}
+	f.Daily = daily
+	return f
+}
+
+func writeEvent(w http.ResponseWriter, e Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+}
@@ -0,0 +1,106 @@
+package weather_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+// readEvent ... next server-sent event of the stream
+func readEvent(t *testing.T, r *bufio.Reader) weather.Event {
+	t.Helper()
+	var e weather.Event
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return e
+		}
+		if strings.HasPrefix(line, "data: ") {
+			err = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	s := weather.NewServer(m, time.Hour)
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	// a missing event fails the test at the deadline instead of blocking forever
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events?location=Bonn", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("want content type text/event-stream, got %q", got)
+	}
+	r := bufio.NewReader(resp.Body)
+	e := readEvent(t, r)
+	if e.Type != weather.EventConditions || e.Conditions.Temperature != 21.5 {
+		t.Errorf("want initial conditions, got %+v", e)
+	}
+	e = readEvent(t, r)
+	if e.Type != weather.EventAlert || e.Alert.Name != "Hitze" {
+		t.Errorf("want initial alert, got %+v", e)
+	}
+
+	conditions, forecast := m.Conditions, m.Forecast
+	conditions.Temperature = 23
+	forecast.Daily = []weather.ForecastDaily{{Day: "Fr, 17.06.", Alerts: []weather.Alert{{Name: "Hitze"}, {Name: "Gewitter"}}}}
+	m.Set(conditions, forecast)
+	err = s.Refresh("Bonn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []weather.Event{
+		{Type: weather.EventConditions, Location: "Bonn", Conditions: &weather.Conditions{Summary: "Clear", Temperature: 23}},
+		{Type: weather.EventAlert, Location: "Bonn", Alert: &weather.Alert{Name: "Gewitter"}},
+	}
+	got := []weather.Event{readEvent(t, r), readEvent(t, r)}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestServerRefreshWithoutChanges(t *testing.T) {
+	t.Parallel()
+	s := weather.NewServer(newMock(), time.Hour)
+	err := s.Refresh("Bonn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := s.Subscribe("bonn")
+	defer s.Unsubscribe("bonn", ch)
+	err = s.Refresh("Bonn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-ch:
+		t.Errorf("want no event for unchanged weather, got %+v", e)
+	default:
+	}
+}
@@ -0,0 +1,127 @@
+package weather
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const CommandExporter = "exporter"
+
+// Exporter ... serves the weather of one location as Prometheus metrics, updated by Refresh
+//
+// The metric names carry metric units, so the Provider has to deliver metric units.
+type Exporter struct {
+	Provider    Provider
+	Coordinates Coordinates
+	// Location is used as value of the location label
+	Location string
+
+	mu         sync.Mutex
+	conditions Conditions
+	forecast   Forecast
+	updated    time.Time
+	err        error
+}
+
+// NewExporter ... exporter for the coordinates, location labels the metrics
+func NewExporter(p Provider, coordinates Coordinates, location string) *Exporter {
+	return &Exporter{
+		Provider:    p,
+		Coordinates: coordinates,
+		Location:    location,
+	}
+}
+
+// Refresh ... fetches the weather, on errors the previous values are kept and weather_up turns 0
+func (e *Exporter) Refresh() error {
+	conditions, forecast, err := e.Provider.GetWeather(e.Coordinates)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.err = err
+	if err != nil {
+		return err
+	}
+	e.conditions = conditions
+	e.forecast = forecast
+	e.updated = time.Now()
+	return nil
+}
+
+// Run ... refreshes the weather every interval, errors are logged
+func (e *Exporter) Run(interval time.Duration) {
+	for range time.Tick(interval) {
+		err := e.Refresh()
+		if err != nil {
+			log.Printf("refresh failed: %v", err)
+		}
+	}
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	e.WriteMetrics(w)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteMetrics ... writes the metrics in the Prometheus text format
+func (e *Exporter) WriteMetrics(w io.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	label := fmt.Sprintf(`{location="%s"}`, labelEscaper.Replace(e.Location))
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s%s %g\n", name, help, name, name, label, value)
+	}
+	up := 1.0
+	if e.err != nil {
+		up = 0
+	}
+	gauge("weather_up", "Whether the last refresh succeeded.", up)
+	if e.updated.IsZero() {
+		return
+	}
+	c := e.conditions
+	gauge("weather_last_update_timestamp_seconds", "Time of the last successful refresh.", float64(e.updated.Unix()))
+	gauge("weather_temperature_celsius", "Current temperature in degrees Celsius (metric units).", c.Temperature)
+	gauge("weather_feels_like_celsius", "Current perceived temperature in degrees Celsius (metric units).", c.FeelsLike)
+	gauge("weather_humidity_percent", "Current relative humidity.", float64(c.Humidity))
+	gauge("weather_pressure_hpa", "Current atmospheric pressure in hectopascal.", float64(c.Pressure))
+	gauge("weather_wind_speed_meters_per_second", "Current wind speed in meters per second (metric units).", float64(c.WindSpeed))
+	gauge("weather_wind_gust_meters_per_second", "Current wind gusts in meters per second (metric units).", float64(c.WindGust))
+	gauge("weather_wind_direction_degrees", "Current wind direction in degrees.", float64(c.WindDirection))
+	if len(e.forecast.Hourly) > 0 {
+		gauge("weather_rain_probability_percent", "Probability of rain within the next hour.", e.forecast.Hourly[0].RainChance)
+	}
+	alerts := 0
+	if len(e.forecast.Daily) > 0 {
+		alerts = len(e.forecast.Daily[0].Alerts)
+	}
+	gauge("weather_alerts", "Number of active weather alerts.", float64(alerts))
+}
+
+func runExporter(env *cliEnv, opts Options) error {
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return err
+	}
+	location := strings.ReplaceAll(opts.Location, "+", " ")
+	if location == "" {
+		location = placeName(c, coordinates)
+	}
+	c.Exclude = opts.Exclude
+	e := NewExporter(env.provider(c, opts), coordinates, location)
+	err = e.Refresh()
+	if err != nil {
+		log.Printf("refresh failed: %v", err)
+	}
+	go e.Run(opts.Interval)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	log.Printf("listening on %s", opts.Listen)
+	return newHTTPServer(opts.Listen, mux).ListenAndServe()
+}
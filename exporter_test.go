@@ -0,0 +1,110 @@
+package weather_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestExporterMetrics(t *testing.T) {
+	t.Parallel()
+	m := &weathertest.Mock{
+		Conditions: weather.Conditions{Temperature: 21.5, Humidity: 40, Pressure: 1015, WindSpeed: 3.2},
+		Forecast: weather.Forecast{
+			Hourly: []weather.ForecastHourly{{RainChance: 30}},
+			Daily:  []weather.ForecastDaily{{Alerts: []weather.Alert{{Name: "Hitze"}}}},
+		},
+	}
+	e := weather.NewExporter(m, weather.Coordinates{Lat: 50.73, Lon: 7.1}, `Bonn "DE"`)
+	err := e.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := httptest.NewRecorder()
+	e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	got := resp.Body.String()
+	for _, want := range []string{
+		"# HELP weather_temperature_celsius Current temperature in degrees Celsius (metric units).\n",
+		"# TYPE weather_temperature_celsius gauge\n",
+		`weather_up{location="Bonn \"DE\""} 1` + "\n",
+		`weather_temperature_celsius{location="Bonn \"DE\""} 21.5` + "\n",
+		`weather_humidity_percent{location="Bonn \"DE\""} 40` + "\n",
+		`weather_pressure_hpa{location="Bonn \"DE\""} 1015` + "\n",
+		`weather_wind_speed_meters_per_second{location="Bonn \"DE\""} 3.2` + "\n",
+		`weather_rain_probability_percent{location="Bonn \"DE\""} 30` + "\n",
+		`weather_alerts{location="Bonn \"DE\""} 1` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in metrics:\n%s", want, got)
+		}
+	}
+}
+
+func TestExporterKeepsValuesOnError(t *testing.T) {
+	t.Parallel()
+	m := &weathertest.Mock{Conditions: weather.Conditions{Temperature: 12}}
+	e := weather.NewExporter(m, weather.Coordinates{}, "Bonn")
+	err := e.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Err = errors.New("service unavailable")
+	if e.Refresh() == nil {
+		t.Fatal("want error")
+	}
+	var got strings.Builder
+	e.WriteMetrics(&got)
+	for _, want := range []string{`weather_up{location="Bonn"} 0`, `weather_temperature_celsius{location="Bonn"} 12`} {
+		if !strings.Contains(got.String(), want) {
+			t.Errorf("want %q in metrics:\n%s", want, got.String())
+		}
+	}
+}
+
+func TestExporterWithoutData(t *testing.T) {
+	t.Parallel()
+	m := &weathertest.Mock{Err: errors.New("service unavailable")}
+	e := weather.NewExporter(m, weather.Coordinates{}, "Bonn")
+	e.Refresh()
+	var got strings.Builder
+	e.WriteMetrics(&got)
+	if strings.Contains(got.String(), "weather_temperature_celsius") {
+		t.Errorf("want no temperature before the first refresh:\n%s", got.String())
+	}
+}
+
+func TestParseOptionsExporter(t *testing.T) {
+	t.Parallel()
+	opts, err := weather.ParseOptions("exporter", []string{"--location", "Berlin,DE", "--listen", ":9999", "--interval", "2m"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Listen != ":9999" || opts.Interval != 2*time.Minute || opts.Location != "Berlin,DE" {
+		t.Errorf("unexpected options %+v", opts)
+	}
+	_, err = weather.ParseOptions("exporter", []string{"--interval", "10s"}, weather.Config{})
+	if err == nil {
+		t.Error("want error for an interval below one minute")
+	}
+}
+
+func TestParseOptionsExporterMetricUnits(t *testing.T) {
+	t.Parallel()
+	opts, err := weather.ParseOptions("exporter", nil, weather.Config{Units: weather.UnitsImperial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Units != weather.UnitsMetric {
+		t.Errorf("want metric units regardless of the config, got %q", opts.Units)
+	}
+	_, err = weather.ParseOptions("exporter", []string{"--units", "imperial"}, weather.Config{})
+	if err == nil {
+		t.Error("want error for --units, which the exporter doesn't offer")
+	}
+}
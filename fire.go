@@ -0,0 +1,111 @@
+package weather
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultFireThreshold ... fire danger index from which the daily forecast warns, the start of hoch
+	DefaultFireThreshold = 40
+	// recentPeriod ... how far back the rain dampens the fire danger
+	recentPeriod = 7 * 24 * time.Hour
+	// soakingRain ... rain in mm within the recent period which wets the fuels as far as the weather can
+	soakingRain = 20
+)
+
+// FireDanger ... fire danger of a day, Warning is set if the index reaches the threshold of the region
+type FireDanger struct {
+	Index   float64 `json:"index"`
+	Warning bool    `json:"warning"`
+}
+
+// FireWeatherIndex ... Fosberg fire weather index between 0 and 100 of temperature in °C, humidity and wind in m/s,
+// how fast a fire would spread in dry fuels
+func FireWeatherIndex(celsius float64, humidity int, wind Speed) float64 {
+	t := celsius*9/5 + 32
+	h := float64(humidity)
+	// equilibrium moisture content of the fuels in percent
+	var m float64
+	switch {
+	case h < 10:
+		m = 0.03229 + 0.281073*h - 0.000578*h*t
+	case h < 50:
+		m = 2.22749 + 0.160107*h - 0.01478*t
+	default:
+		m = 21.0606 + 0.005565*h*h - 0.00035*h*t - 0.483199*h
+	}
+	x := m / 30
+	eta := 1 - 2*x + 1.5*x*x - 0.5*x*x*x
+	mph := float64(wind) / 0.44704
+	return math.Max(0, math.Min(100, eta*math.Sqrt(1+mph*mph)/0.3002))
+}
+
+// FireDangerIndex ... fire weather index dampened by the rain of the last 7 days in mm,
+// down to a fifth after 20 mm
+func FireDangerIndex(celsius float64, humidity int, wind Speed, recentRain float64) float64 {
+	wetness := math.Min(recentRain, soakingRain) / soakingRain
+	return FireWeatherIndex(celsius, humidity, wind) * (1 - 0.8*wetness)
+}
+
+// Level ... gering below 20, mäßig below 40, hoch below 60, otherwise sehr hoch
+func (d FireDanger) Level() string {
+	switch {
+	case d.Index < 20:
+		return "gering"
+	case d.Index < 40:
+		return "mäßig"
+	case d.Index < 60:
+		return "hoch"
+	}
+	return "sehr hoch"
+}
+
+// RecentRain ... precipitation in mm of the days of the observations before today, oldest first,
+// each day counts with the daily forecast of its latest observation
+func RecentRain(observations []Observation, today string) float64 {
+	days := map[string]float64{}
+	for _, o := range observations {
+		day := o.Time.Format("02.01.2006")
+		if day == today || len(o.Forecast.Daily) == 0 {
+			continue
+		}
+		days[day] = o.Forecast.Daily[0].Rain + o.Forecast.Daily[0].Snow
+	}
+	rain := 0.0
+	for _, r := range days {
+		rain += r
+	}
+	return rain
+}
+
+// SetFireDanger ... fire danger of the forecast days, the rain of the days before the forecast is recentRain,
+// later days also count the forecast rain of the week before, the day itself included
+func SetFireDanger(f *Forecast, recentRain, threshold float64) {
+	for i := range f.Daily {
+		rain := 0.0
+		if i < int(recentPeriod/(24*time.Hour)) {
+			rain = recentRain
+		}
+		for j := i; j >= 0 && j > i-7; j-- {
+			rain += f.Daily[j].Rain + f.Daily[j].Snow
+		}
+		day := &f.Daily[i]
+		index := FireDangerIndex(convertTemperature(day.Temp.Max, f.Units, UnitsMetric), day.Humidity,
+			metersPerSecond(day.WindSpeed, f.Units), rain)
+		index = math.Round(index*10) / 10
+		day.FireDanger = &FireDanger{Index: index, Warning: index >= threshold}
+	}
+}
+
+// FireThreshold ... fire danger threshold of the place like "Bonn, North Rhine-Westphalia, DE", the name wins over
+// the state and the country, "default" of fire_thresholds over DefaultFireThreshold
+func (cfg Config) FireThreshold(place string) float64 {
+	for _, region := range append(strings.Split(place, ", "), "default") {
+		if threshold, ok := cfg.FireThresholds[region]; ok {
+			return threshold
+		}
+	}
+	return DefaultFireThreshold
+}
@@ -0,0 +1,82 @@
+package weather_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+)
+
+func TestFireWeatherIndex(t *testing.T) {
+	t.Parallel()
+	humid := weather.FireWeatherIndex(15, 90, 1)
+	dry := weather.FireWeatherIndex(35, 10, 12)
+	if humid > 10 {
+		t.Errorf("want a low index in calm humid air, got %.1f", humid)
+	}
+	if dry < 60 {
+		t.Errorf("want a high index in hot, dry and windy air, got %.1f", dry)
+	}
+	if got := weather.FireWeatherIndex(45, 2, 40); got != 100 {
+		t.Errorf("want the index capped at 100, got %.1f", got)
+	}
+	dampened := weather.FireDangerIndex(35, 10, 12, 10)
+	if math.Abs(dampened-dry*0.6) > 1e-9 {
+		t.Errorf("want 10 mm of rain to take 40 %% off %.1f, got %.1f", dry, dampened)
+	}
+	if soaked := weather.FireDangerIndex(35, 10, 12, 50); math.Abs(soaked-dry*0.2) > 1e-9 {
+		t.Errorf("want soaking rain to leave a fifth of %.1f, got %.1f", dry, soaked)
+	}
+}
+
+func TestRecentRain(t *testing.T) {
+	t.Parallel()
+	at := func(day, hour int, rain float64) weather.Observation {
+		return weather.Observation{
+			Time:     time.Date(2022, 6, day, hour, 0, 0, 0, time.Local),
+			Forecast: weather.Forecast{Daily: []weather.ForecastDaily{{Rain: rain}}},
+		}
+	}
+	observations := []weather.Observation{at(14, 8, 1), at(14, 20, 3), at(15, 12, 2), at(17, 9, 5)}
+	// the latest observation of a day counts, today is left to the forecast
+	if got := weather.RecentRain(observations, "17.06.2022"); got != 5 {
+		t.Errorf("want 5 mm, got %.1f", got)
+	}
+}
+
+func TestSetFireDanger(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Units: weather.UnitsMetric, Daily: []weather.ForecastDaily{
+		{Temp: weather.DailyTempBenchmarks{Max: 35}, Humidity: 10, WindSpeed: 12},
+		{Temp: weather.DailyTempBenchmarks{Max: 35}, Humidity: 10, WindSpeed: 12, Rain: 30},
+	}}
+	weather.SetFireDanger(&f, 0, 40)
+	first, second := f.Daily[0].FireDanger, f.Daily[1].FireDanger
+	if first == nil || !first.Warning || first.Level() != "sehr hoch" {
+		t.Errorf("want a warning of the dry day, got %+v", first)
+	}
+	if second == nil || second.Warning || second.Index >= first.Index {
+		t.Errorf("want no warning of the rainy day, got %+v", second)
+	}
+}
+
+func TestConfigFireThreshold(t *testing.T) {
+	t.Parallel()
+	cfg := weather.Config{FireThresholds: map[string]float64{"Bonn": 30, "Brandenburg": 35, "ES": 50}}
+	tests := map[string]float64{
+		"Bonn, North Rhine-Westphalia, DE": 30,
+		"Potsdam, Brandenburg, DE":         35,
+		"Sevilla, Andalusia, ES":           50,
+		"Köln, North Rhine-Westphalia, DE": weather.DefaultFireThreshold,
+	}
+	for place, want := range tests {
+		if got := cfg.FireThreshold(place); got != want {
+			t.Errorf("%s: want %g, got %g", place, want, got)
+		}
+	}
+	cfg.FireThresholds["default"] = 45
+	if got := cfg.FireThreshold("Köln, North Rhine-Westphalia, DE"); got != 45 {
+		t.Errorf("want the default of the config, got %g", got)
+	}
+}
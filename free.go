@@ -0,0 +1,319 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// APIAuto, APIOneCall and APIFree select the endpoints of Client.GetWeather:
+	// auto uses One Call 3.0 and switches to the free 2.5 endpoints once the key is rejected with 401
+	APIAuto    = "auto"
+	APIOneCall = "onecall"
+	APIFree    = "free"
+
+	// ProviderFree ... name of the free weather data source
+	ProviderFree = "OpenWeatherMap 2.5 (weather, forecast)"
+)
+
+type (
+	// FreeWeatherResponse ... current conditions of /data/2.5/weather
+	FreeWeatherResponse struct {
+		DT      int64
+		Weather []struct {
+			ID          ConditionID
+			Description string
+			Icon        string
+		}
+		Main struct {
+			Temp       float64
+			Feels_Like float64
+			Pressure   int
+			Humidity   int
+		}
+		Visibility int
+		Wind       struct {
+			Speed Speed
+			Deg   Direction
+			Gust  Speed
+		}
+		Clouds struct {
+			All int
+		}
+		Rain Precipitation
+		Snow Precipitation
+		Sys  struct {
+			Sunrise int64
+			Sunset  int64
+		}
+	}
+
+	// FreeForecastResponse ... forecast of /data/2.5/forecast in steps of 3 hours for 5 days
+	FreeForecastResponse struct {
+		List []struct {
+			DT   int64
+			Main struct {
+				Temp       float64
+				Feels_Like float64
+				Temp_Min   float64
+				Temp_Max   float64
+				Pressure   int
+				Humidity   int
+			}
+			Weather []struct {
+				ID          ConditionID
+				Description string
+				Icon        string
+			}
+			Clouds struct {
+				All int
+			}
+			Wind struct {
+				Speed Speed
+				Deg   Direction
+				Gust  Speed
+			}
+			PoP  float64
+			Rain struct {
+				ThreeHours float64 `json:"3h"`
+			}
+			Snow struct {
+				ThreeHours float64 `json:"3h"`
+			}
+		}
+		City struct {
+			Sunrise int64
+			Sunset  int64
+		}
+	}
+)
+
+// ParseFreeResponses ... conditions and forecast assembled from the free endpoints, in the units they were requested with.
+// Hourly holds one slot every 3 hours with the precipitation of the 3 hours, the daily forecast is aggregated from them.
+// The free endpoints have neither the minutely forecast, UV index, moon data nor alerts, the dew point is calculated.
+func ParseFreeResponses(weatherData, forecastData []byte, units string) (Conditions, Forecast, error) {
+	var current FreeWeatherResponse
+	err := json.Unmarshal(weatherData, &current)
+	if err != nil {
+		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: %w", weatherData, err)
+	}
+	if len(current.Weather) < 1 {
+		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: want at least one Weather element", weatherData)
+	}
+	var resp FreeForecastResponse
+	err = json.Unmarshal(forecastData, &resp)
+	if err != nil {
+		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: %w", forecastData, err)
+	}
+	if len(resp.List) < 12 {
+		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: want at least some List elements", forecastData)
+	}
+	conditions := Conditions{
+		Timestamp:     time.Unix(current.DT, 0).Format("02.01.2006 15:04 MST"),
+		Sunrise:       time.Unix(current.Sys.Sunrise, 0).Format("15:04"),
+		Sunset:        time.Unix(current.Sys.Sunset, 0).Format("15:04"),
+		Summary:       current.Weather[0].Description,
+		Temperature:   current.Main.Temp,
+		FeelsLike:     current.Main.Feels_Like,
+		DewPoint:      dewPoint(current.Main.Temp, current.Main.Humidity, units),
+		Pressure:      current.Main.Pressure,
+		Humidity:      current.Main.Humidity,
+		WindSpeed:     current.Wind.Speed,
+		WindGust:      current.Wind.Gust,
+		WindDirection: current.Wind.Deg,
+		Visibility:    current.Visibility,
+		Clouds:        current.Clouds.All,
+		Rain:          current.Rain.OneHour,
+		Snow:          current.Snow.OneHour,
+		Icon:          current.Weather[0].Icon,
+		Condition:     current.Weather[0].ID,
+	}
+	forecast := Forecast{
+		Minutely: []ForecastMinutely{},
+		Hourly:   []ForecastHourly{},
+		Daily:    []ForecastDaily{},
+	}
+	// the extremes of the days, taken from the min and max of every slot
+	min, max := map[string]float64{}, map[string]float64{}
+	for _, slot := range resp.List {
+		s := ForecastHourly{
+			Time:          time.Unix(slot.DT, 0),
+			Day:           time.Unix(slot.DT, 0).Format("02.01.2006"),
+			Hour:          time.Unix(slot.DT, 0).Format("15:04"),
+			Temperature:   slot.Main.Temp,
+			FeelsLike:     slot.Main.Feels_Like,
+			RainChance:    slot.PoP * 100,
+			WindSpeed:     slot.Wind.Speed,
+			WindGust:      slot.Wind.Gust,
+			WindDirection: slot.Wind.Deg,
+			Humidity:      slot.Main.Humidity,
+			Pressure:      slot.Main.Pressure,
+			Clouds:        slot.Clouds.All,
+			Rain:          slot.Rain.ThreeHours,
+			Snow:          slot.Snow.ThreeHours,
+		}
+		if len(slot.Weather) > 0 {
+			s.Description = slot.Weather[0].Description
+			s.Icon = slot.Weather[0].Icon
+			s.Condition = slot.Weather[0].ID
+		}
+		forecast.Hourly = append(forecast.Hourly, s)
+		if v, ok := min[s.Day]; !ok || slot.Main.Temp_Min < v {
+			min[s.Day] = slot.Main.Temp_Min
+		}
+		if v, ok := max[s.Day]; !ok || slot.Main.Temp_Max > v {
+			max[s.Day] = slot.Main.Temp_Max
+		}
+	}
+	var slots []ForecastHourly
+	for i, s := range forecast.Hourly {
+		slots = append(slots, s)
+		if i < len(forecast.Hourly)-1 && forecast.Hourly[i+1].Day == s.Day {
+			continue
+		}
+		day := dailyOfSlots(slots)
+		day.Temp.Min, day.Temp.Max = min[s.Day], max[s.Day]
+		// sunrise and sunset are only known for today, later days differ by a few minutes
+		day.Sunrise = conditions.Sunrise
+		day.Sunset = conditions.Sunset
+		day.DayLength = time.Duration(resp.City.Sunset-resp.City.Sunrise) * time.Second
+		forecast.Daily = append(forecast.Daily, day)
+		slots = nil
+	}
+	if len(forecast.Daily) < 3 {
+		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: want at least List elements till after tomorrow", forecastData)
+	}
+	return conditions, forecast, nil
+}
+
+// dailyOfSlots ... forecast of the day of the slots: the temperatures and the description of the nearest slot
+// to the time of the day, the strongest wind, the highest rain chance and the sum of the precipitation
+func dailyOfSlots(slots []ForecastHourly) ForecastDaily {
+	day := ForecastDaily{
+		Day:    slots[0].Day,
+		Alerts: []Alert{},
+	}
+	humidity, clouds := 0, 0
+	for _, s := range slots {
+		day.RainChance = math.Max(day.RainChance, s.RainChance)
+		if s.WindSpeed >= day.WindSpeed {
+			day.WindSpeed, day.WindDirection = s.WindSpeed, s.WindDirection
+		}
+		if s.WindGust > day.WindGust {
+			day.WindGust = s.WindGust
+		}
+		humidity += s.Humidity
+		clouds += s.Clouds
+		day.Rain += s.Rain
+		day.Snow += s.Snow
+	}
+	day.Humidity = humidity / len(slots)
+	day.Clouds = clouds / len(slots)
+	day.Temp.Morning = nearestSlot(slots, 6).Temperature
+	day.Temp.Day = nearestSlot(slots, 12).Temperature
+	day.Temp.Evening = nearestSlot(slots, 18).Temperature
+	day.Temp.Night = nearestSlot(slots, 0).Temperature
+	day.Description = nearestSlot(slots, 12).Description
+	day.Icon = nearestSlot(slots, 12).Icon
+	day.Condition = nearestSlot(slots, 12).Condition
+	return day
+}
+
+// nearestSlot ... slot closest to the hour of the day, the earlier one of two as close
+func nearestSlot(slots []ForecastHourly, hour int) ForecastHourly {
+	nearest, best := slots[0], 24
+	for _, s := range slots {
+		d := s.Time.Hour() - hour
+		if d < 0 {
+			d = -d
+		}
+		if d < best {
+			nearest, best = s, d
+		}
+	}
+	return nearest
+}
+
+// dewPoint ... Magnus formula, the temperature is in the given units
+func dewPoint(temp float64, humidity int, units string) float64 {
+	// the dew point of dry air is arbitrarily low, 1 % keeps it finite
+	if humidity < 1 {
+		humidity = 1
+	}
+	const b, c = 17.62, 243.12
+	t := convertTemperature(temp, units, UnitsMetric)
+	gamma := math.Log(float64(humidity)/100) + b*t/(c+t)
+	return convertTemperature(c*gamma/(b-gamma), UnitsMetric, units)
+}
+
+func (c *Client) FormatFreeWeatherURL(coordinates Coordinates) string {
+	return fmt.Sprintf("%s/data/2.5/weather?lat=%g&lon=%g&units=%s&lang=%s&appid=%s", c.BaseURL, coordinates.Lat, coordinates.Lon, c.Units, c.Lang, c.APIKey)
+}
+
+func (c *Client) FormatFreeForecastURL(coordinates Coordinates) string {
+	return fmt.Sprintf("%s/data/2.5/forecast?lat=%g&lon=%g&units=%s&lang=%s&appid=%s", c.BaseURL, coordinates.Lat, coordinates.Lon, c.Units, c.Lang, c.APIKey)
+}
+
+// GetWeatherFree ... weather of the free 2.5 endpoints, available to every API key, see ParseFreeResponses
+func (c *Client) GetWeatherFree(coordinates Coordinates) (Conditions, Forecast, error) {
+	return c.getWeatherFree(context.Background(), coordinates)
+}
+
+func (c *Client) getWeatherFree(ctx context.Context, coordinates Coordinates) (Conditions, Forecast, error) {
+	weatherData, err := c.getData(ctx, c.FormatFreeWeatherURL(coordinates))
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	forecastData, err := c.getData(ctx, c.FormatFreeForecastURL(coordinates))
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	conditions, forecast, err := ParseFreeResponses(weatherData, forecastData, c.Units)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	forecast.Units = c.Units
+	forecast.Coordinates = &coordinates
+	return conditions, forecast, nil
+}
+
+// getData ... body of a successful GET
+func (c *Client) getData(ctx context.Context, URL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// oneCallDenied ... whether auto switched to the free endpoints
+func (c *Client) oneCallDenied() bool {
+	return atomic.LoadInt32(&c.denied) == 1
+}
+
+// ProviderName ... weather data source of GetWeather
+func (c *Client) ProviderName() string {
+	switch {
+	case c.API == APIFree:
+		return ProviderFree
+	case c.API == APIOneCall:
+		return ProviderOneCall
+	case c.oneCallDenied():
+		return ProviderFree + ", One Call 3.0 abgelehnt"
+	}
+	return ProviderOneCall + ", 2.5 als Rückfall"
+}
@@ -0,0 +1,158 @@
+package weather_test
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func readFreeResponses(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	weatherData, err := os.ReadFile("testdata/weather_25.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	forecastData, err := os.ReadFile("testdata/forecast_25.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return weatherData, forecastData
+}
+
+func TestParseFreeResponses(t *testing.T) {
+	t.Parallel()
+	weatherData, forecastData := readFreeResponses(t)
+	conditions, fc, err := weather.ParseFreeResponses(weatherData, forecastData, weather.UnitsStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conditions.Summary != "clear sky" || conditions.Temperature != 290.8 || conditions.WindGust != 3.29 || conditions.Clouds != 1 {
+		t.Errorf("unexpected conditions %+v", conditions)
+	}
+	// Magnus formula for 17.65 °C and 33 %
+	if math.Abs(conditions.DewPoint-274.31) > 0.05 {
+		t.Errorf("want dew point 274.31 K, got %.2f", conditions.DewPoint)
+	}
+	if len(fc.Hourly) != 40 || len(fc.Daily) != 6 {
+		t.Fatalf("want 40 slots of 6 days, got %d of %d", len(fc.Hourly), len(fc.Daily))
+	}
+	want := weather.ForecastDaily{
+		Day:         "19.04.2022",
+		Description: "clear sky",
+		Icon:        "01d",
+		Condition:   800,
+		Sunrise:     "06:31",
+		Sunset:      "20:29",
+		DayLength:   50327 * time.Second,
+		Temp: weather.DailyTempBenchmarks{
+			Max:     290.05,
+			Min:     277.35,
+			Morning: 278.45,
+			Day:     286.85,
+			Evening: 289.25,
+			Night:   277.75,
+		},
+		WindSpeed:     3.1,
+		WindDirection: 185,
+		WindGust:      4.65,
+		Humidity:      47,
+		Clouds:        12,
+		Alerts:        []weather.Alert{},
+	}
+	got := fc.Daily[1]
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	rainy := fc.Daily[2]
+	if rainy.RainChance != 80 || math.Abs(rainy.Rain-2.4) > 1e-9 {
+		t.Errorf("want 2.4 mm at 80 %% on %s, got %.1f mm at %.0f %%", rainy.Day, rainy.Rain, rainy.RainChance)
+	}
+}
+
+func TestParseFreeResponsesInvalid(t *testing.T) {
+	t.Parallel()
+	weatherData, forecastData := readFreeResponses(t)
+	invalid, err := os.ReadFile("testdata/weather_25_invalid.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = weather.ParseFreeResponses(invalid, forecastData, weather.UnitsStandard)
+	if err == nil {
+		t.Error("want error for conditions without weather, but got nil")
+	}
+	_, _, err = weather.ParseFreeResponses(weatherData, []byte(`{"list": []}`), weather.UnitsStandard)
+	if err == nil {
+		t.Error("want error for an empty forecast, but got nil")
+	}
+}
+
+// freeServer ... rejects One Call 3.0 like for keys without subscription and serves the free endpoints,
+// oneCalls counts the requests of One Call
+func freeServer(t *testing.T, oneCalls *int) *httptest.Server {
+	t.Helper()
+	weatherData, forecastData := readFreeResponses(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/data/3.0/onecall", func(w http.ResponseWriter, r *http.Request) {
+		*oneCalls++
+		http.Error(w, `{"cod":401}`, http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/data/2.5/weather", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(weatherData)
+	})
+	mux.HandleFunc("/data/2.5/forecast", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(forecastData)
+	})
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestGetWeatherFallsBackToFree(t *testing.T) {
+	t.Parallel()
+	oneCalls := 0
+	ts := freeServer(t, &oneCalls)
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	for i := 0; i < 2; i++ {
+		_, fc, err := c.GetWeather(weather.Coordinates{Lat: 1.0, Lon: 2.0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fc.Daily) != 6 {
+			t.Errorf("want 6 days of the free forecast, got %d", len(fc.Daily))
+		}
+	}
+	if oneCalls != 1 {
+		t.Errorf("want One Call asked once, got %d", oneCalls)
+	}
+	if c.ProviderName() == weather.ProviderOneCall {
+		t.Errorf("want free provider after the fallback, got %s", c.ProviderName())
+	}
+}
+
+func TestGetWeatherOneCallOnly(t *testing.T) {
+	t.Parallel()
+	oneCalls := 0
+	ts := freeServer(t, &oneCalls)
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.API = weather.APIOneCall
+	_, _, err := c.GetWeather(weather.Coordinates{Lat: 1.0, Lon: 2.0})
+	if err == nil {
+		t.Error("want error for rejected One Call without fallback, but got nil")
+	}
+	c.API = weather.APIFree
+	_, _, err = c.GetWeather(weather.Coordinates{Lat: 1.0, Lon: 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oneCalls != 1 {
+		t.Errorf("want One Call asked once, got %d", oneCalls)
+	}
+}
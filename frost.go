@@ -0,0 +1,80 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultFrostThreshold ... lowest temperature in °C from which the daily forecast warns of ground frost,
+// in clear and calm nights the ground gets some degrees colder than the air 2 m above it
+const DefaultFrostThreshold = 2.0
+
+// Frost ... frost of the night before a day, Min is the lowest temperature of the night in the units of the forecast,
+// Ground is set if only the ground freezes and the air stays above 0 °C
+type Frost struct {
+	Min    float64 `json:"min"`
+	Ground bool    `json:"ground"`
+}
+
+// GetFrost ... frost of the day if its minimum or night temperature doesn't exceed the threshold in °C, nil otherwise
+func GetFrost(day ForecastDaily, units string, threshold float64) *Frost {
+	min := math.Min(day.Temp.Min, day.Temp.Night)
+	celsius := convertTemperature(min, units, UnitsMetric)
+	if celsius > threshold {
+		return nil
+	}
+	return &Frost{Min: min, Ground: celsius >= 0}
+}
+
+// SetFrost ... frost of the forecast days with the threshold in °C
+func SetFrost(f *Forecast, threshold float64) {
+	for i := range f.Daily {
+		f.Daily[i].Frost = GetFrost(f.Daily[i], f.Units, threshold)
+	}
+}
+
+// String ... Bodenfrost or Frost
+func (fr Frost) String() string {
+	if fr.Ground {
+		return "Bodenfrost"
+	}
+	return "Frost"
+}
+
+// FrostLimit ... frost_threshold of the config file in °C, DefaultFrostThreshold without it
+func (cfg Config) FrostLimit() float64 {
+	if cfg.FrostThreshold != nil {
+		return *cfg.FrostThreshold
+	}
+	return DefaultFrostThreshold
+}
+
+// CheckFrost ... notifications for the days of the forecast with frost not notified yet, threshold is in °C
+func (s *NotifyState) CheckFrost(location string, f Forecast, threshold float64) []Notification {
+	notified := s.location(location)
+	notifications := []Notification{}
+	known := map[string]bool{}
+	for _, day := range notified.Frost {
+		known[day] = true
+	}
+	// past days are forgotten, so the state doesn't grow
+	notified.Frost = []string{}
+	for _, day := range f.Daily {
+		frost := GetFrost(day, f.Units, threshold)
+		if frost == nil {
+			continue
+		}
+		notified.Frost = append(notified.Frost, day.Day)
+		if known[day.Day] {
+			continue
+		}
+		notifications = append(notifications, Notification{
+			Kind:     NotifyFrost,
+			Location: location,
+			Title:    fmt.Sprintf("%s in %s", frost, location),
+			Message:  fmt.Sprintf("%s in der Nacht zum %s, bis %.1f %s", frost, day.Day, frost.Min, f.TemperatureUnit()),
+			Frost:    frost,
+		})
+	}
+	return notifications
+}
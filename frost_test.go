@@ -0,0 +1,45 @@
+package weather_test
+
+import (
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetFrost(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		temp  weather.DailyTempBenchmarks
+		units string
+		want  *weather.Frost
+	}{
+		{"mild", weather.DailyTempBenchmarks{Min: 6, Night: 7}, weather.UnitsMetric, nil},
+		{"ground frost", weather.DailyTempBenchmarks{Min: 3, Night: 1.5}, weather.UnitsMetric, &weather.Frost{Min: 1.5, Ground: true}},
+		{"frost", weather.DailyTempBenchmarks{Min: -3, Night: -1}, weather.UnitsMetric, &weather.Frost{Min: -3}},
+		{"imperial", weather.DailyTempBenchmarks{Min: 30, Night: 31}, weather.UnitsImperial, &weather.Frost{Min: 30}},
+	}
+	for _, tt := range tests {
+		got := weather.GetFrost(weather.ForecastDaily{Temp: tt.temp}, tt.units, weather.DefaultFrostThreshold)
+		if !cmp.Equal(tt.want, got) {
+			t.Errorf("%s: %s", tt.name, cmp.Diff(tt.want, got))
+		}
+	}
+}
+
+func TestCheckFrost(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Units: weather.UnitsMetric, Daily: []weather.ForecastDaily{
+		{Day: "17.10.2022", Temp: weather.DailyTempBenchmarks{Min: 5, Night: 6}},
+		{Day: "18.10.2022", Temp: weather.DailyTempBenchmarks{Min: -2, Night: -1}},
+	}}
+	var state weather.NotifyState
+	got := state.CheckFrost("Bonn", f, weather.DefaultFrostThreshold)
+	if len(got) != 1 || got[0].Kind != weather.NotifyFrost || got[0].Message != "Frost in der Nacht zum 18.10.2022, bis -2.0 °C" {
+		t.Fatalf("want a frost notification, got %+v", got)
+	}
+	if got := state.CheckFrost("Bonn", f, weather.DefaultFrostThreshold); len(got) != 0 {
+		t.Errorf("want the frost notified only once, got %+v", got)
+	}
+}
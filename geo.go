@@ -0,0 +1,134 @@
+package weather
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultGeoLimit ... number of matches requested when a location might be ambiguous
+	DefaultGeoLimit = 5
+	// MaxGeoLimit ... maximum number of matches the geocoding API delivers
+	MaxGeoLimit = 5
+)
+
+// GeoResult ... one match of a geocoding query
+type GeoResult struct {
+	Place
+	Coordinates
+}
+
+// ParseGeoResults ... all matches of a geocoding query
+func ParseGeoResults(data []byte) ([]GeoResult, error) {
+	var resp GeoResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API response %s: %w", data, err)
+	}
+	if len(resp) < 1 {
+		return nil, fmt.Errorf("invalid API response %s: want at least one set of coordinates", data)
+	}
+	results := []GeoResult{}
+	for _, r := range resp {
+		result := GeoResult{
+			Place: Place{
+				Name:    r.Name,
+				State:   r.State,
+				Country: r.Country,
+			},
+			Coordinates: Coordinates{
+				Lat: r.Lat,
+				Lon: r.Lon,
+			},
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// SelectCandidate ... asks the user to pick one of several matches by number
+func SelectCandidate(candidates []GeoResult, in io.Reader, out io.Writer) (GeoResult, error) {
+	if len(candidates) == 0 {
+		return GeoResult{}, errors.New("no candidates to choose from")
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	fmt.Fprintln(out, "Mehrere Orte gefunden:")
+	for i, candidate := range candidates {
+		fmt.Fprintf(out, "%d) %s (%g,%g)\n", i+1, candidate.Place, candidate.Lat, candidate.Lon)
+	}
+	fmt.Fprintf(out, "Auswahl [1-%d]: ", len(candidates))
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return GeoResult{}, err
+	}
+	choice, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || choice < 1 || choice > len(candidates) {
+		return GeoResult{}, fmt.Errorf("invalid choice %q, want a number between 1 and %d", strings.TrimSpace(answer), len(candidates))
+	}
+	return candidates[choice-1], nil
+}
+
+// PrintSearchResults ... output of all places matching a search, with coordinates usable as location
+func PrintSearchResults(query string, results []GeoResult) {
+	fmt.Println()
+	fmt.Printf("Suchergebnisse für %q\n", query)
+	fmt.Println("-----------------------------------------------------")
+	for _, r := range results {
+		fmt.Printf("%s: %g,%g\n", r.Place, r.Lat, r.Lon)
+	}
+	fmt.Println()
+}
+
+func (c *Client) FormatGeoSearchURL(location string, limit int) string {
+	return fmt.Sprintf("%s/geo/1.0/direct?q=%s&limit=%d&appid=%s", c.BaseURL, location, limit, c.APIKey)
+}
+
+// GetCoordinatesAll ... up to GeoLimit matches of a location, so callers can implement their own selection
+func (c *Client) GetCoordinatesAll(location string) ([]GeoResult, error) {
+	limit := c.GeoLimit
+	// clients built as struct literal leave the limit unset
+	if limit == 0 {
+		limit = DefaultGeoLimit
+	}
+	if limit < 0 || limit > MaxGeoLimit {
+		return nil, fmt.Errorf("geocoding limit %d is out of range, should be between 1 and %d", limit, MaxGeoLimit)
+	}
+	URL := c.FormatGeoSearchURL(location, limit)
+	resp, err := c.HTTPClient.Get(URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGeoResults(data)
+}
+
+// LocateInteractive ... like Locate, but lets the user choose if the location is ambiguous
+func (c *Client) LocateInteractive(location string, in io.Reader, out io.Writer) (Coordinates, error) {
+	if coordinates, ok := ParseCoordinates(location); ok {
+		return coordinates, nil
+	}
+	candidates, err := c.GetCoordinatesAll(location)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	candidate, err := SelectCandidate(candidates, in, out)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	return candidate.Coordinates, nil
+}
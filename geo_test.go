@@ -0,0 +1,151 @@
+package weather_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseGeoResults(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/geo_service_multiple.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := weather.ParseGeoResults(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("want 3 candidates, got %d", len(got))
+	}
+	want := weather.GeoResult{
+		Place:       weather.Place{Name: "Springfield", State: "Missouri", Country: "US"},
+		Coordinates: weather.Coordinates{Lat: 37.2081729, Lon: -93.2922715},
+	}
+	if !cmp.Equal(want, got[1]) {
+		t.Error(cmp.Diff(want, got[1]))
+	}
+}
+
+func TestSelectCandidate(t *testing.T) {
+	t.Parallel()
+	candidates := []weather.GeoResult{
+		{Place: weather.Place{Name: "Springfield", State: "Illinois", Country: "US"}},
+		{Place: weather.Place{Name: "Springfield", State: "Missouri", Country: "US"}},
+	}
+	out := &bytes.Buffer{}
+	got, err := weather.SelectCandidate(candidates, strings.NewReader("2\n"), out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(candidates[1], got) {
+		t.Error(cmp.Diff(candidates[1], got))
+	}
+	if !strings.Contains(out.String(), "2) Springfield, Missouri, US") {
+		t.Errorf("want numbered candidates in output, got %q", out.String())
+	}
+}
+
+func TestSelectCandidateInvalidChoice(t *testing.T) {
+	t.Parallel()
+	candidates := []weather.GeoResult{{}, {}}
+	_, err := weather.SelectCandidate(candidates, strings.NewReader("7\n"), io.Discard)
+	if err == nil {
+		t.Fatal("want error for choice out of range, but got nil")
+	}
+}
+
+func TestGetCoordinatesAll(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("limit") != "5" {
+				t.Errorf("want limit 5, got %s", r.URL.Query().Get("limit"))
+			}
+			f, err := os.Open("testdata/geo_service_multiple.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			io.Copy(w, f)
+		}))
+	defer ts.Close()
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.HTTPClient = ts.Client()
+	got, err := c.GetCoordinatesAll("Springfield")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Errorf("want 3 candidates, got %d", len(got))
+	}
+}
+
+func TestGetCoordinatesAllConfiguredLimit(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("limit") != "2" {
+				t.Errorf("want limit 2, got %s", r.URL.Query().Get("limit"))
+			}
+			f, err := os.Open("testdata/geo_service_multiple.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			io.Copy(w, f)
+		}))
+	defer ts.Close()
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.HTTPClient = ts.Client()
+	c.GeoLimit = 2
+	_, err := c.GetCoordinatesAll("Springfield")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetCoordinatesAllInvalidLimit(t *testing.T) {
+	t.Parallel()
+	for _, limit := range []int{-1, 9} {
+		c := weather.NewClient("dummyAPIKey")
+		c.GeoLimit = limit
+		_, err := c.GetCoordinatesAll("Springfield")
+		if err == nil {
+			t.Errorf("want error for limit %d out of range, but got nil", limit)
+		}
+	}
+}
+
+func TestGetCoordinatesAllUnsetLimit(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("limit"); got != strconv.Itoa(weather.DefaultGeoLimit) {
+				t.Errorf("want default limit %d, got %q", weather.DefaultGeoLimit, got)
+			}
+			f, err := os.Open("testdata/geo_service_multiple.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			io.Copy(w, f)
+		}))
+	defer ts.Close()
+	c := &weather.Client{APIKey: "dummyAPIKey", BaseURL: ts.URL, HTTPClient: ts.Client()}
+	_, err := c.GetCoordinatesAll("Springfield")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,204 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// historyRetention ... how long hourly values are kept for the Grafana datasource
+	historyRetention = 7 * 24 * time.Hour
+)
+
+type (
+	// history ... hourly values of one location, the latest forecast of an hour replaces earlier ones
+	history struct {
+		location string
+		updated  time.Time
+		hours    map[int64]ForecastHourly
+	}
+
+	// grafanaQuery ... request of the simple-json datasource to /grafana/query
+	grafanaQuery struct {
+		Range struct {
+			From time.Time `json:"from"`
+			To   time.Time `json:"to"`
+		} `json:"range"`
+		Targets []struct {
+			Target string `json:"target"`
+		} `json:"targets"`
+	}
+
+	// grafanaSeries ... time series answering one target, datapoints are value and Unix milliseconds
+	grafanaSeries struct {
+		Target     string       `json:"target"`
+		Datapoints [][2]float64 `json:"datapoints"`
+	}
+)
+
+// grafanaMetrics ... hourly values offered by the Grafana datasource, targets are written as METRIC:LOCATION
+var grafanaMetrics = map[string]func(h ForecastHourly) float64{
+	"temperature": func(h ForecastHourly) float64 { return h.Temperature },
+	"feels_like":  func(h ForecastHourly) float64 { return h.FeelsLike },
+	"rain_chance": func(h ForecastHourly) float64 { return h.RainChance },
+	"wind_speed":  func(h ForecastHourly) float64 { return float64(h.WindSpeed) },
+	"wind_gust":   func(h ForecastHourly) float64 { return float64(h.WindGust) },
+}
+
+// GrafanaMetrics ... names of the metrics offered by the Grafana datasource, sorted
+func GrafanaMetrics() []string {
+	names := []string{}
+	for name := range grafanaMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// record ... adds the hourly forecast of an entry to the history, dropping hours beyond the retention
+// and the least recently updated location if there are too many
+//
+// s.mu must be held.
+func (s *Server) record(key, location string, entry cacheEntry) {
+	if s.history == nil {
+		s.history = map[string]*history{}
+	}
+	h := s.history[key]
+	if h == nil {
+		if len(s.history) >= maxCacheEntries {
+			oldest := ""
+			for k, other := range s.history {
+				if oldest == "" || other.updated.Before(s.history[oldest].updated) {
+					oldest = k
+				}
+			}
+			delete(s.history, oldest)
+		}
+		h = &history{location: location, hours: map[int64]ForecastHourly{}}
+		s.history[key] = h
+	}
+	h.updated = entry.fetched
+	for _, hour := range entry.forecast.Hourly {
+		if !hour.Time.IsZero() {
+			h.hours[hour.Time.Unix()] = hour
+		}
+	}
+	for t := range h.hours {
+		if entry.fetched.Sub(time.Unix(t, 0)) > historyRetention {
+			delete(h.hours, t)
+		}
+	}
+}
+
+// History ... hourly values of the location between from and to, oldest first, a zero to means no upper bound
+//
+// The history is filled whenever the server fetches the location, past hours keep their last forecast.
+func (s *Server) History(location string, from, to time.Time) []ForecastHourly {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hours := []ForecastHourly{}
+	h, ok := s.history[strings.ToLower(location)]
+	if !ok {
+		return hours
+	}
+	for _, hour := range h.hours {
+		if hour.Time.Before(from) || (!to.IsZero() && hour.Time.After(to)) {
+			continue
+		}
+		hours = append(hours, hour)
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i].Time.Before(hours[j].Time) })
+	return hours
+}
+
+// historyLocations ... locations with a history, sorted
+func (s *Server) historyLocations() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	locations := []string{}
+	for _, h := range s.history {
+		locations = append(locations, h.location)
+	}
+	sort.Strings(locations)
+	return locations
+}
+
+// handleGrafana ... connection test of the datasource
+func (s *Server) handleGrafana(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/grafana/" {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+	}{"ok"})
+}
+
+// handleGrafanaSearch ... targets for the location sent as target, or for all locations with a history
+func (s *Server) handleGrafanaSearch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Target string `json:"target"`
+	}
+	if r.Body != nil {
+		// an empty body asks for all targets
+		json.NewDecoder(r.Body).Decode(&req)
+	}
+	locations := s.historyLocations()
+	if location, err := s.normalize(req.Target); err == nil {
+		locations = []string{location}
+	}
+	targets := []string{}
+	for _, location := range locations {
+		for _, metric := range GrafanaMetrics() {
+			targets = append(targets, metric+":"+location)
+		}
+	}
+	writeJSON(w, http.StatusOK, targets)
+}
+
+// handleGrafanaQuery ... time series of the targets within the range of the query
+func (s *Server) handleGrafanaQuery(w http.ResponseWriter, r *http.Request) {
+	var req grafanaQuery
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: invalid query: %v", ErrBadRequest, err))
+		return
+	}
+	series := []grafanaSeries{}
+	for _, target := range req.Targets {
+		result, err := s.grafanaTarget(target.Target, req.Range.From, req.Range.To)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		series = append(series, result)
+	}
+	writeJSON(w, http.StatusOK, series)
+}
+
+// grafanaTarget ... time series of one METRIC:LOCATION target, the location is fetched first if not cached
+func (s *Server) grafanaTarget(target string, from, to time.Time) (grafanaSeries, error) {
+	parts := strings.SplitN(target, ":", 2)
+	value, ok := grafanaMetrics[parts[0]]
+	if len(parts) != 2 || !ok {
+		return grafanaSeries{}, fmt.Errorf("%w: invalid target %q, want METRIC:LOCATION with metric one of %s",
+			ErrBadRequest, target, strings.Join(GrafanaMetrics(), ", "))
+	}
+	location, err := s.normalize(parts[1])
+	if err != nil {
+		return grafanaSeries{}, err
+	}
+	_, _, err = s.Get(location)
+	if err != nil {
+		return grafanaSeries{}, err
+	}
+	result := grafanaSeries{Target: target, Datapoints: [][2]float64{}}
+	for _, hour := range s.History(location, from, to) {
+		result.Datapoints = append(result.Datapoints, [2]float64{value(hour), float64(hour.Time.UnixMilli())})
+	}
+	return result, nil
+}
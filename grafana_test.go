@@ -0,0 +1,140 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+// hourlyForecast ... forecast with hourly temperatures and rain chances starting at start
+func hourlyForecast(start time.Time, temperatures ...float64) weather.Forecast {
+	f := weather.Forecast{Units: weather.UnitsMetric, Daily: []weather.ForecastDaily{{}}}
+	for i, temperature := range temperatures {
+		f.Hourly = append(f.Hourly, weather.ForecastHourly{
+			Time:        start.Add(time.Duration(i) * time.Hour),
+			Temperature: temperature,
+			RainChance:  float64(10 * i),
+		})
+	}
+	return f
+}
+
+func grafanaQuery(t *testing.T, h http.Handler, body string) (int, []struct {
+	Target     string
+	Datapoints [][2]float64
+}) {
+	t.Helper()
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/grafana/query", strings.NewReader(body)))
+	var series []struct {
+		Target     string
+		Datapoints [][2]float64
+	}
+	if resp.Code == http.StatusOK {
+		err := json.Unmarshal(resp.Body.Bytes(), &series)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.Code, series
+}
+
+func TestGrafanaQuery(t *testing.T) {
+	t.Parallel()
+	// hours beyond the retention of a week are dropped, so the test starts now
+	start := time.Now().UTC().Truncate(time.Hour)
+	m := newMock()
+	m.Forecast = hourlyForecast(start, 20, 21, 22)
+	s := weather.NewServer(m, time.Hour)
+	h := s.Handler()
+	ms := func(hours int) float64 {
+		return float64(start.Add(time.Duration(hours) * time.Hour).UnixMilli())
+	}
+
+	code, got := grafanaQuery(t, h, fmt.Sprintf(`{
+		"range": {"from": %q, "to": %q},
+		"targets": [{"target": "temperature:Bonn"}, {"target": "rain_chance:Bonn"}]
+	}`, start.Add(time.Hour).Format(time.RFC3339), start.Add(5*time.Hour).Format(time.RFC3339)))
+	if code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", code)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 series, got %+v", got)
+	}
+	want := [][2]float64{{21, ms(1)}, {22, ms(2)}}
+	if got[0].Target != "temperature:Bonn" || !cmp.Equal(want, got[0].Datapoints) {
+		t.Errorf("temperature: %s", cmp.Diff(want, got[0].Datapoints))
+	}
+	want = [][2]float64{{10, ms(1)}, {20, ms(2)}}
+	if !cmp.Equal(want, got[1].Datapoints) {
+		t.Errorf("rain chance: %s", cmp.Diff(want, got[1].Datapoints))
+	}
+
+	// past hours stay in the history, later forecasts replace the values of their hours
+	m.Set(m.Conditions, hourlyForecast(start.Add(2*time.Hour), 25, 26))
+	err := s.Refresh("Bonn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, got = grafanaQuery(t, h, `{"targets": [{"target": "temperature:Bonn"}]}`)
+	want = [][2]float64{{20, ms(0)}, {21, ms(1)}, {25, ms(2)}, {26, ms(3)}}
+	if len(got) != 1 || !cmp.Equal(want, got[0].Datapoints) {
+		t.Errorf("history: %+v", got)
+	}
+}
+
+func TestGrafanaQueryInvalid(t *testing.T) {
+	t.Parallel()
+	h := weather.NewServer(newMock(), time.Hour).Handler()
+	tests := map[string]string{
+		"body":     `{`,
+		"metric":   `{"targets": [{"target": "snow:Bonn"}]}`,
+		"location": `{"targets": [{"target": "temperature:"}]}`,
+		"format":   `{"targets": [{"target": "temperature"}]}`,
+	}
+	for name, body := range tests {
+		code, _ := grafanaQuery(t, h, body)
+		if code != http.StatusBadRequest {
+			t.Errorf("%s: want status 400, got %d", name, code)
+		}
+	}
+}
+
+func TestGrafanaSearch(t *testing.T) {
+	t.Parallel()
+	s := weather.NewServer(newMock(), time.Hour)
+	h := s.Handler()
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/grafana/", nil))
+	if resp.Code != http.StatusOK {
+		t.Errorf("want status 200 for the connection test, got %d", resp.Code)
+	}
+
+	_, _, err := s.Get("Bonn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]string{
+		``:                   {"feels_like:Bonn", "rain_chance:Bonn", "temperature:Bonn", "wind_gust:Bonn", "wind_speed:Bonn"},
+		`{"target": "Köln"}`: {"feels_like:Köln", "rain_chance:Köln", "temperature:Köln", "wind_gust:Köln", "wind_speed:Köln"},
+	}
+	for body, want := range tests {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/grafana/search", strings.NewReader(body)))
+		var got []string
+		err := json.Unmarshal(resp.Body.Bytes(), &got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(want, got) {
+			t.Errorf("%q: %s", body, cmp.Diff(want, got))
+		}
+	}
+}
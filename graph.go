@@ -0,0 +1,328 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FunctionGraph = "graph"
+
+	// GraphRows ... height of the braille chart in lines, 4 dots each
+	GraphRows = 4
+	// RainRows ... height of the rain chart in lines, 8 steps each
+	RainRows = 3
+)
+
+type (
+	// Graph ... hourly values of one measurement on one day, e.g. the temperatures of today
+	Graph struct {
+		Place  string    `json:"place,omitempty"`
+		Name   string    `json:"name"`
+		Title  string    `json:"title"`
+		Day    string    `json:"day"`
+		Unit   string    `json:"unit"`
+		Hours  []string  `json:"hours"`
+		Values []float64 `json:"values"`
+	}
+
+	// graphKind ... key of GetGraphData, title and unit of a measurement of the graph command
+	graphKind struct {
+		key   string
+		title string
+		unit  func(f Forecast) string
+	}
+)
+
+// graphKinds ... measurements of the graph command by their name
+var graphKinds = map[string]graphKind{
+	"temp":      {"Temp", "Temperatur", Forecast.TemperatureUnit},
+	"feelslike": {"FeelsLike", "Gefühlte Temperatur", Forecast.TemperatureUnit},
+	"rain":      {"Rain", "Regenwahrscheinlichkeit", percent},
+	"wind":      {"Wind", "Wind", speedUnit},
+	"gust":      {"Gust", "Böen", speedUnit},
+	"humidity":  {"Humidity", "Luftfeuchtigkeit", percent},
+	"pressure":  {"Pressure", "Luftdruck", func(Forecast) string { return "hPa" }},
+	"clouds":    {"Clouds", "Bewölkung", percent},
+}
+
+// graphDays ... days of the graph command by their name, the hourly forecast covers at most 48 hours
+var graphDays = map[string]int{
+	FunctionToday:         0,
+	"heute":               0,
+	FunctionTomorrow:      1,
+	"morgen":              1,
+	FunctionAfterTomorrow: 2,
+	"übermorgen":          2,
+}
+
+// brailleDots ... bits of the braille dots from the top, left and right column of a character
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+func percent(Forecast) string {
+	return "%"
+}
+
+func speedUnit(f Forecast) string {
+	if f.Units == UnitsImperial {
+		return "mph"
+	}
+	return "km/h"
+}
+
+// graphNames ... names of the measurements of the graph command, sorted for help and completion
+func graphNames() []string {
+	names := []string{}
+	for name := range graphKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// graphFlags ... flags of the graph command
+func graphFlags(fs *flag.FlagSet, opts *Options) {
+	fs.StringVar(&opts.Output, "png", "", "write a chart of the temperatures and rain chances of the next 48 hours to the PNG file")
+}
+
+// validateGraph ... the measurement and optionally the day or a weekday are the words in front of the location,
+// --png needs neither
+func validateGraph(fs *flag.FlagSet, opts *Options) error {
+	if opts.Output != "" {
+		return nil
+	}
+	if len(opts.Args) == 0 {
+		return fmt.Errorf("%s needs a measurement in front of the location: %s", FunctionGraph, strings.Join(graphNames(), ", "))
+	}
+	opts.Graph = strings.ToLower(opts.Args[0])
+	if _, ok := graphKinds[opts.Graph]; !ok {
+		return fmt.Errorf("invalid measurement %q, want one of %s", opts.Args[0], strings.Join(graphNames(), ", "))
+	}
+	opts.Args = opts.Args[1:]
+	if len(opts.Args) == 0 {
+		return nil
+	}
+	word := strings.ToLower(opts.Args[0])
+	day, ok := graphDays[word]
+	// weekdays like forecast takes them, as far as the hourly forecast reaches
+	if weekday, isWeekday := weekdays[word]; isWeekday {
+		day, ok = weekdayOffset(weekday, time.Now().Weekday()), true
+		if day > graphDays[FunctionAfterTomorrow] {
+			return fmt.Errorf("%s is beyond the hourly forecast of %s, which reaches till %s", opts.Args[0], FunctionGraph, FunctionAfterTomorrow)
+		}
+	}
+	if ok {
+		opts.Day = day
+		opts.Args = opts.Args[1:]
+	}
+	return nil
+}
+
+// GetGraph ... hourly values of the measurement on the day, offset 0 is today, speeds in km/h or mph
+func GetGraph(f Forecast, name string, offset int) (Graph, error) {
+	kind, ok := graphKinds[name]
+	if !ok {
+		return Graph{}, fmt.Errorf("invalid graph %q, want one of %s", name, strings.Join(graphNames(), ", "))
+	}
+	if offset < 0 || offset >= len(f.Daily) {
+		return Graph{}, fmt.Errorf("offset %d is out of range, the forecast has %d days", offset, len(f.Daily))
+	}
+	g := Graph{Place: f.Place, Name: name, Title: kind.title, Day: f.Daily[offset].Day, Unit: kind.unit(f), Hours: []string{}}
+	for _, slot := range f.Hourly {
+		if slot.Day == g.Day {
+			g.Hours = append(g.Hours, slot.Hour)
+		}
+	}
+	if len(g.Hours) == 0 {
+		return Graph{}, fmt.Errorf("no hourly forecast for %s, it covers the next 48 hours", g.Day)
+	}
+	g.Values = GetGraphData(f, kind.key, offset)
+	if g.Unit == "km/h" {
+		for i, v := range g.Values {
+			g.Values[i] = Speed(v).KmPerHour()
+		}
+	}
+	return g, nil
+}
+
+// bounds ... lowest and highest value of the graph
+func (g Graph) bounds() (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range g.Values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	return min, max
+}
+
+// Sparkline ... one bar per hour from the lowest to the highest value
+func (g Graph) Sparkline() string {
+	min, max := g.bounds()
+	shifted := make([]float64, len(g.Values))
+	for i, v := range g.Values {
+		shifted[i] = v - min
+	}
+	// a flat line is drawn in the middle
+	if max == min {
+		return sparkline(shifted, 2)
+	}
+	return sparkline(shifted, max-min)
+}
+
+// BrailleChart ... line chart of the values in braille characters, rows lines high and one character per value,
+// the right half of a character shows the middle between the value and the next one
+func BrailleChart(values []float64, rows int) []string {
+	if len(values) == 0 || rows < 1 {
+		return nil
+	}
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	height := rows * 4
+	level := func(v float64) int {
+		if max == min {
+			return (height - 1) / 2
+		}
+		return int(math.Round((v - min) / (max - min) * float64(height-1)))
+	}
+	points := []int{}
+	for i, v := range values {
+		points = append(points, level(v))
+		next := v
+		if i+1 < len(values) {
+			next = (v + values[i+1]) / 2
+		}
+		points = append(points, level(next))
+	}
+	grid := make([][]rune, rows)
+	for row := range grid {
+		grid[row] = make([]rune, len(values))
+	}
+	for x, p := range points {
+		// the dots up or down to the previous point keep the line connected
+		low, high := p, p
+		if x > 0 && points[x-1] < p-1 {
+			low = points[x-1] + 1
+		}
+		if x > 0 && points[x-1] > p+1 {
+			high = points[x-1] - 1
+		}
+		for l := low; l <= high; l++ {
+			top := height - 1 - l
+			grid[top/4][x/2] |= brailleDots[top%4][x%2]
+		}
+	}
+	lines := []string{}
+	for _, row := range grid {
+		var b strings.Builder
+		for _, dots := range row {
+			b.WriteRune(0x2800 + dots)
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// RenderGraph ... braille chart of the graph with the highest and lowest value left of it and the first and last
+// hour below it
+func (r TextRenderer) RenderGraph(w io.Writer, g Graph) error {
+	ew := &errWriter{w: w}
+	fmt.Fprintln(ew)
+	writeHeader(ew, fmt.Sprintf("%s am %s", g.Title, g.Day), Forecast{Place: g.Place})
+	min, max := g.bounds()
+	label := func(v float64) string {
+		return fmt.Sprintf("%.0f %s", v, g.Unit)
+	}
+	width := len([]rune(label(max)))
+	if n := len([]rune(label(min))); n > width {
+		width = n
+	}
+	for i, line := range BrailleChart(g.Values, GraphRows) {
+		left := ""
+		switch i {
+		case 0:
+			left = label(max)
+		case GraphRows - 1:
+			left = label(min)
+		}
+		fmt.Fprintf(ew, "%*s %s\n", width, left, line)
+	}
+	first, last := g.Hours[0], g.Hours[len(g.Hours)-1]
+	gap := len(g.Values) - len(first) - len(last)
+	if gap < 1 {
+		gap = 1
+	}
+	fmt.Fprintf(ew, "%*s %s%*s%s\n", width, "", first, gap, "", last)
+	fmt.Fprintln(ew)
+	return ew.err
+}
+
+// BarChart ... vertical bars of the values between 0 and top in block characters, rows lines high and one
+// character per value, higher values are cut
+func BarChart(values []float64, top float64, rows int) []string {
+	lines := []string{}
+	for row := 0; row < rows; row++ {
+		base := (rows - 1 - row) * 8
+		var b strings.Builder
+		for _, v := range values {
+			eighths := int(math.Round(math.Min(v, top) / top * float64(rows*8)))
+			switch fill := eighths - base; {
+			case fill <= 0:
+				b.WriteRune(' ')
+			case fill >= 8:
+				b.WriteRune(sparkBlocks[len(sparkBlocks)-1])
+			default:
+				b.WriteRune(sparkBlocks[fill-1])
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// hourLabels ... the hours divisible by 3 below their column, e.g. "00 03 06", as far as they don't overlap
+func hourLabels(hours []string) string {
+	var b strings.Builder
+	for i, hour := range hours {
+		h, err := strconv.Atoi(strings.SplitN(hour, ":", 2)[0])
+		if err != nil || h%3 != 0 || b.Len() > i {
+			continue
+		}
+		b.WriteString(strings.Repeat(" ", i-b.Len()))
+		fmt.Fprintf(&b, "%02d", h)
+	}
+	return b.String()
+}
+
+// writeRainChart ... bars of the hourly rain chances of the graph, coloured like the rain chances,
+// nothing if it stays dry
+func (r TextRenderer) writeRainChart(w io.Writer, g Graph) {
+	_, max := g.bounds()
+	if max <= 0 {
+		return
+	}
+	for i, line := range BarChart(g.Values, 100, RainRows) {
+		left := ""
+		if i == 0 {
+			left = "100 %"
+		}
+		var b strings.Builder
+		for x, bar := range []rune(strings.TrimRight(line, " ")) {
+			if bar == ' ' {
+				b.WriteRune(bar)
+				continue
+			}
+			b.WriteString(r.paint(string(bar), rainChanceColor(g.Values[x])))
+		}
+		fmt.Fprintln(w, strings.TrimRight(fmt.Sprintf("%5s %s", left, b.String()), " "))
+	}
+	fmt.Fprintf(w, "%5s %s\n", "", hourLabels(g.Hours))
+}
@@ -0,0 +1,138 @@
+package weather_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBrailleChart(t *testing.T) {
+	t.Parallel()
+	want := []string{
+		"⠀⡠⠋",
+		"⡰⠁⠀",
+	}
+	got := weather.BrailleChart([]float64{0, 4, 8}, 2)
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	flat := weather.BrailleChart([]float64{5, 5}, 1)
+	if len(flat) != 1 || flat[0] != "⠤⠤" {
+		t.Errorf("want a flat line in the middle, got %q", flat)
+	}
+	if weather.BrailleChart(nil, 4) != nil {
+		t.Error("want no chart without values")
+	}
+}
+
+func TestGetGraph(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{
+		Place: "Bonn, DE",
+		Hourly: []weather.ForecastHourly{
+			{Day: "17.06.2022", Hour: "22:00", Temperature: 20, WindSpeed: 5},
+			{Day: "17.06.2022", Hour: "23:00", Temperature: 18, WindSpeed: 10},
+		},
+		Daily: []weather.ForecastDaily{{Day: "17.06.2022"}, {Day: "18.06.2022"}},
+	}
+	want := weather.Graph{Place: "Bonn, DE", Name: "wind", Title: "Wind", Day: "17.06.2022", Unit: "km/h",
+		Hours: []string{"22:00", "23:00"}, Values: []float64{18, 36}}
+	got, err := weather.GetGraph(f, "wind", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	if g, _ := weather.GetGraph(f, "temp", 0); g.Sparkline() != "█▁" {
+		t.Errorf("want the sparkline from the highest to the lowest temperature, got %q", g.Sparkline())
+	}
+	for name, tc := range map[string]struct {
+		graph  string
+		offset int
+	}{
+		"unknown":    {"moon", 0},
+		"no hourly":  {"temp", 1},
+		"past range": {"temp", 2},
+	} {
+		if _, err := weather.GetGraph(f, tc.graph, tc.offset); err == nil {
+			t.Errorf("%s: want error, but got nil", name)
+		}
+	}
+}
+
+func TestGraphCommand(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	out, err := runCLI(t, ts, "graph", "temp", "tomorrow", "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "Temperatur am 18.06.2022") || !strings.ContainsRune(string(out), '⠀') {
+		t.Errorf("want the braille chart of the temperatures of tomorrow, got %q", out)
+	}
+	_, err = runCLI(t, ts, "graph", "moon", "Bonn,DE")
+	if err == nil {
+		t.Error("want error for an unknown measurement, but got nil")
+	}
+}
+
+func TestParseOptionsGraphWeekday(t *testing.T) {
+	t.Parallel()
+	tomorrow := (time.Now().Weekday() + 1) % 7
+	got, err := weather.ParseOptions(weather.FunctionGraph, []string{"temp", tomorrow.String(), "Bonn,DE"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Day != 1 || got.Location != "Bonn,DE" {
+		t.Errorf("want day 1 of Bonn,DE, got day %d of %q", got.Day, got.Location)
+	}
+	beyond := (time.Now().Weekday() + 4) % 7
+	if _, err := weather.ParseOptions(weather.FunctionGraph, []string{"temp", beyond.String(), "Bonn,DE"}, weather.Config{}); err == nil {
+		t.Errorf("%s: want error beyond the hourly forecast, but got nil", beyond)
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	t.Parallel()
+	want := []string{
+		"  ▄█",
+		" ███",
+	}
+	got := weather.BarChart([]float64{0, 50, 75, 120}, 100, 2)
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestRenderForecastRainChart(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Daily: []weather.ForecastDaily{{Day: "17.06.2022"}}}
+	for _, hour := range []string{"12:00", "13:00", "14:00", "15:00"} {
+		f.Hourly = append(f.Hourly, weather.ForecastHourly{Day: "17.06.2022", Hour: hour, RainChance: 100})
+	}
+	var b bytes.Buffer
+	err := weather.TextRenderer{}.RenderForecast(&b, f, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "100 % ████\n      ████\n      ████\n      12 15\n"
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("want the rain chart %q in %q", want, b.String())
+	}
+	f.Hourly[0].RainChance, f.Hourly[1].RainChance, f.Hourly[2].RainChance, f.Hourly[3].RainChance = 0, 0, 0, 0
+	b.Reset()
+	err = weather.TextRenderer{}.RenderForecast(&b, f, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "100 %") {
+		t.Errorf("want no rain chart on a dry day, got %q", b.String())
+	}
+}
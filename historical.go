@@ -0,0 +1,103 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const FunctionHistoryAt = "history-at"
+
+// historicalLayouts ... accepted points in time of history-at, in local time, a day alone means its midnight
+var historicalLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// TimemachineResponse ... observed conditions of the OneCall timemachine API
+type TimemachineResponse struct {
+	Data []CurrentResponse
+}
+
+// ParseHistoricalTime ... point in time like "2023-07-01 14:00" in the location loc
+func ParseHistoricalTime(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range historicalLayouts {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, want e.g. 2023-07-01 14:00", s)
+}
+
+// parseHistoricalAt ... point in time of history-at in local time
+func parseHistoricalAt(s string, now time.Time) (time.Time, error) {
+	return ParseHistoricalTime(s, time.Local)
+}
+
+func ParseTimemachineResponse(data []byte) (Conditions, error) {
+	var resp TimemachineResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return Conditions{}, fmt.Errorf("invalid API response %s: %w", data, err)
+	}
+	if len(resp.Data) < 1 || len(resp.Data[0].Weather) < 1 {
+		return Conditions{}, fmt.Errorf("invalid API response %s: want at least one Data element with Weather", data)
+	}
+	return resp.Data[0].conditions(), nil
+}
+
+func (c *Client) FormatTimemachineURL(coordinates Coordinates, t time.Time) string {
+	return fmt.Sprintf("%s/data/3.0/onecall/timemachine?lat=%g&lon=%g&dt=%d&units=%s&lang=%s&appid=%s", c.BaseURL, coordinates.Lat, coordinates.Lon, t.Unix(), c.Units, c.Lang, c.APIKey)
+}
+
+// GetHistorical ... observed conditions at the coordinates at a past point in time
+func (c *Client) GetHistorical(coordinates Coordinates, t time.Time) (Conditions, error) {
+	URL := c.FormatTimemachineURL(coordinates, t)
+	resp, err := c.HTTPClient.Get(URL)
+	if err != nil {
+		return Conditions{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Conditions{}, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Conditions{}, err
+	}
+	return ParseTimemachineResponse(data)
+}
+
+// PrintHistorical ... output of the observed conditions of a past point in time
+func PrintHistorical(c Conditions, f Forecast) {
+	fmt.Println()
+	printHeader("Wetter vom "+c.Timestamp, f)
+	fmt.Printf("Sonne: %s / %s\n", c.Sunrise, c.Sunset)
+	TextRenderer{}.writeConditions(os.Stdout, c, f)
+	fmt.Println()
+}
+
+func runHistoryAt(env *cliEnv, opts Options) error {
+	if opts.At.After(time.Now()) {
+		return fmt.Errorf("%s is in the future, use forecast instead", opts.At.Format("2006-01-02 15:04"))
+	}
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return err
+	}
+	conditions, err := c.GetHistorical(coordinates, opts.At)
+	if err != nil {
+		return err
+	}
+	place := placeName(c, coordinates)
+	if opts.Format == FormatJSON {
+		return printJSON(os.Stdout, struct {
+			Place      string     `json:"place,omitempty"`
+			Time       time.Time  `json:"time"`
+			Conditions Conditions `json:"conditions"`
+		}{place, opts.At, conditions})
+	}
+	PrintHistorical(conditions, Forecast{Place: place, Units: c.Units})
+	return nil
+}
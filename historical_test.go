@@ -0,0 +1,116 @@
+package weather_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseTimemachineResponse(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/timemachine.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := weather.Conditions{
+		Timestamp:     "01.07.2023 14:00 CEST",
+		Sunrise:       "05:15",
+		Sunset:        "21:44",
+		Summary:       "Mäßig bewölkt",
+		Temperature:   22.67,
+		FeelsLike:     22.31,
+		DewPoint:      12.38,
+		Pressure:      1014,
+		Humidity:      52,
+		WindSpeed:     4.12,
+		WindGust:      7.2,
+		WindDirection: 250,
+		UVI:           6.21,
+		Visibility:    10000,
+		Clouds:        40,
+		Rain:          0.25,
+		Icon:          "03d",
+		Condition:     802,
+	}
+	got, err := weather.ParseTimemachineResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseTimemachineResponseEmpty(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/timemachine_invalid.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = weather.ParseTimemachineResponse(data)
+	if err == nil {
+		t.Fatal("want error parsing invalid response, but got nil")
+	}
+}
+
+func TestGetHistorical(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2023, 7, 1, 14, 0, 0, 0, time.UTC)
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/data/3.0/onecall/timemachine" {
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+			if got := r.URL.Query().Get("dt"); got != "1688220000" {
+				t.Errorf("want dt 1688220000, got %s", got)
+			}
+			f, err := os.Open("testdata/timemachine.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			io.Copy(w, f)
+		}))
+	defer ts.Close()
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.HTTPClient = ts.Client()
+	got, err := c.GetHistorical(weather.Coordinates{Lat: 1.0, Lon: 2.0}, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Temperature != 22.67 {
+		t.Errorf("want 22.67 °C, got %.2f", got.Temperature)
+	}
+}
+
+func TestParseOptionsHistoryAt(t *testing.T) {
+	t.Parallel()
+	want := time.Date(2023, 7, 1, 14, 0, 0, 0, time.Local)
+	tests := map[string][]string{
+		"quoted":   {"2023-07-01 14:00", "Berlin,DE"},
+		"unquoted": {"2023-07-01", "14:00", "Berlin,DE"},
+		"flag":     {"--units", "imperial", "2023-07-01T14:00", "Berlin,DE"},
+	}
+	for name, args := range tests {
+		got, err := weather.ParseOptions("history-at", args, weather.Config{})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !got.At.Equal(want) || got.Location != "Berlin,DE" {
+			t.Errorf("%s: want %s in Berlin,DE, got %s in %s", name, want, got.At, got.Location)
+		}
+	}
+	for _, args := range [][]string{{}, {"yesterday", "Berlin,DE"}} {
+		_, err := weather.ParseOptions("history-at", args, weather.Config{})
+		if err == nil {
+			t.Errorf("%v: want error, but got nil", args)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const CommandHistory = "history"
+
+type (
+	// HistoryReport ... statistics of the stored observations of a location, temperatures in Units
+	HistoryReport struct {
+		Location     string       `json:"location"`
+		Units        string       `json:"units"`
+		From         time.Time    `json:"from"`
+		To           time.Time    `json:"to"`
+		Observations int          `json:"observations"`
+		Temperature  Statistics   `json:"temperature"`
+		Pressure     Statistics   `json:"pressure"`
+		Humidity     Statistics   `json:"humidity"`
+		Days         []HistoryDay `json:"days"`
+	}
+
+	// Statistics ... of one measurement, Trend is the change per day of a linear fit
+	Statistics struct {
+		Min   float64 `json:"min"`
+		Max   float64 `json:"max"`
+		Mean  float64 `json:"mean"`
+		Trend float64 `json:"trend"`
+	}
+
+	// HistoryDay ... observed range of one day, pressure and humidity are daily means
+	HistoryDay struct {
+		Day          string  `json:"day"`
+		Min          float64 `json:"min"`
+		Max          float64 `json:"max"`
+		Pressure     float64 `json:"pressure"`
+		Humidity     float64 `json:"humidity"`
+		Observations int     `json:"observations"`
+	}
+)
+
+// AnalyzeHistory ... statistics of the observations, oldest first, days are those in the location of the times,
+// temperatures are converted to units
+func AnalyzeHistory(location, units string, observations []Observation) HistoryReport {
+	r := HistoryReport{Location: location, Units: units, Observations: len(observations), Days: []HistoryDay{}}
+	if len(observations) == 0 {
+		return r
+	}
+	r.From = observations[0].Time
+	r.To = observations[len(observations)-1].Time
+	var temperatures, pressures, humidities, days []float64
+	for _, o := range observations {
+		temperature := convertTemperature(o.Conditions.Temperature, o.Forecast.Units, units)
+		temperatures = append(temperatures, temperature)
+		pressures = append(pressures, float64(o.Conditions.Pressure))
+		humidities = append(humidities, float64(o.Conditions.Humidity))
+		days = append(days, o.Time.Sub(r.From).Hours()/24)
+
+		day := o.Time.Format("02.01.2006")
+		if n := len(r.Days); n == 0 || r.Days[n-1].Day != day {
+			r.Days = append(r.Days, HistoryDay{Day: day, Min: temperature, Max: temperature})
+		}
+		d := &r.Days[len(r.Days)-1]
+		if temperature < d.Min {
+			d.Min = temperature
+		}
+		if temperature > d.Max {
+			d.Max = temperature
+		}
+		// summed up here, divided below
+		d.Pressure += float64(o.Conditions.Pressure)
+		d.Humidity += float64(o.Conditions.Humidity)
+		d.Observations++
+	}
+	for i := range r.Days {
+		r.Days[i].Pressure /= float64(r.Days[i].Observations)
+		r.Days[i].Humidity /= float64(r.Days[i].Observations)
+	}
+	r.Temperature = statistics(days, temperatures)
+	r.Pressure = statistics(days, pressures)
+	r.Humidity = statistics(days, humidities)
+	return r
+}
+
+// statistics ... of the values measured at the days since the first one
+func statistics(days, values []float64) Statistics {
+	s := Statistics{Min: values[0], Max: values[0]}
+	var meanDay float64
+	for i, v := range values {
+		if v < s.Min {
+			s.Min = v
+		}
+		if v > s.Max {
+			s.Max = v
+		}
+		s.Mean += v
+		meanDay += days[i]
+	}
+	n := float64(len(values))
+	s.Mean /= n
+	meanDay /= n
+	// slope of the least squares line, zero for observations at one time
+	var covariance, variance float64
+	for i, v := range values {
+		covariance += (days[i] - meanDay) * (v - s.Mean)
+		variance += (days[i] - meanDay) * (days[i] - meanDay)
+	}
+	if variance > 0 {
+		s.Trend = covariance / variance
+	}
+	return s
+}
+
+// convertTemperature ... from the units the temperature was fetched in to other units
+func convertTemperature(t float64, from, to string) float64 {
+	if from == "" {
+		from = UnitsMetric
+	}
+	if from == to {
+		return t
+	}
+	// via Celsius
+	switch from {
+	case UnitsImperial:
+		t = (t - 32) * 5 / 9
+	case UnitsStandard:
+		t -= 273.15
+	}
+	switch to {
+	case UnitsImperial:
+		return t*9/5 + 32
+	case UnitsStandard:
+		return t + 273.15
+	}
+	return t
+}
+
+// Direction ... trend in words, changes below 0.1 per day count as steady
+func (s Statistics) Direction() string {
+	switch {
+	case s.Trend >= 0.1:
+		return "steigend"
+	case s.Trend <= -0.1:
+		return "fallend"
+	}
+	return "gleichbleibend"
+}
+
+// PrintHistory ... output of the observed trends and the range of every day
+func PrintHistory(r HistoryReport) {
+	WriteHistory(os.Stdout, r)
+}
+
+// WriteHistory ... PrintHistory writing to w
+func WriteHistory(w io.Writer, r HistoryReport) {
+	unit := Forecast{Units: r.Units}.TemperatureUnit()
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Verlauf vom %s bis %s (%d Messungen)\n", r.From.Format("02.01.2006"), r.To.Format("02.01.2006"), r.Observations)
+	fmt.Fprintln(w, "Ort: "+r.Location)
+	fmt.Fprintln(w, "-----------------------------------------------------")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, m := range []struct {
+		name   string
+		s      Statistics
+		format string
+	}{
+		{"Temperatur", r.Temperature, "%.1f " + unit},
+		{"Luftdruck", r.Pressure, "%.0f hPa"},
+		{"Luftfeuchtigkeit", r.Humidity, "%.0f %%"},
+	} {
+		fmt.Fprintf(tw, "%s:\t"+m.format+" bis "+m.format+"\tMittel "+m.format+"\t%s (%+.1f pro Tag)\n",
+			m.name, m.s.Min, m.s.Max, m.s.Mean, m.s.Direction(), m.s.Trend)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Tag\tmin\tmax\tLuftdruck\tLuftfeuchtigkeit")
+	for _, d := range r.Days {
+		fmt.Fprintf(tw, "%s\t%.1f %s\t%.1f %s\t%.0f hPa\t%.0f %%\n", d.Day, d.Min, unit, d.Max, unit, d.Pressure, d.Humidity)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+}
+
+func runHistory(env *cliEnv, opts Options) error {
+	s, err := env.store()
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return errors.New("no store configured, add store to the config file")
+	}
+	defer s.Close()
+	// the observations are stored under the name the weather commands record them with
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return err
+	}
+	location := notifyLocation(opts, Forecast{Place: placeName(c, coordinates)})
+	to := time.Now()
+	observations, err := s.Observations(location, to.AddDate(0, 0, -opts.Days), to)
+	if err != nil {
+		return err
+	}
+	if len(observations) == 0 {
+		return fmt.Errorf("no observations of %s stored in the last %d days", location, opts.Days)
+	}
+	r := AnalyzeHistory(location, opts.Units, observations)
+	if opts.Format == FormatJSON {
+		return printJSON(os.Stdout, r)
+	}
+	PrintHistory(r)
+	return nil
+}
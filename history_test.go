@@ -0,0 +1,72 @@
+package weather_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAnalyzeHistory(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2022, 6, 17, 0, 0, 0, 0, time.UTC)
+	observation := func(hours int, temperature float64, pressure, humidity int, units string) weather.Observation {
+		return weather.Observation{
+			Location:   "Bonn, DE",
+			Time:       start.Add(time.Duration(hours) * time.Hour),
+			Conditions: weather.Conditions{Temperature: temperature, Pressure: pressure, Humidity: humidity},
+			Forecast:   weather.Forecast{Units: units},
+		}
+	}
+	observations := []weather.Observation{
+		observation(0, 20, 1010, 60, weather.UnitsMetric),
+		observation(12, 24, 1012, 50, weather.UnitsMetric),
+		// fetched in Fahrenheit, 22 °C
+		observation(24, 71.6, 1014, 70, weather.UnitsImperial),
+		observation(36, 26, 1016, 40, weather.UnitsMetric),
+	}
+	got := weather.AnalyzeHistory("Bonn, DE", weather.UnitsMetric, observations)
+	want := weather.HistoryReport{
+		Location:     "Bonn, DE",
+		Units:        weather.UnitsMetric,
+		From:         start,
+		To:           start.Add(36 * time.Hour),
+		Observations: 4,
+		Temperature:  weather.Statistics{Min: 20, Max: 26, Mean: 23, Trend: 3.2},
+		Pressure:     weather.Statistics{Min: 1010, Max: 1016, Mean: 1013, Trend: 4},
+		Humidity:     weather.Statistics{Min: 40, Max: 70, Mean: 55, Trend: -8},
+		Days: []weather.HistoryDay{
+			{Day: "17.06.2022", Min: 20, Max: 24, Pressure: 1011, Humidity: 55, Observations: 2},
+			{Day: "18.06.2022", Min: 22, Max: 26, Pressure: 1015, Humidity: 55, Observations: 2},
+		},
+	}
+	approx := cmp.Comparer(func(a, b float64) bool { return a-b < 1e-9 && b-a < 1e-9 })
+	if !cmp.Equal(want, got, approx) {
+		t.Error(cmp.Diff(want, got, approx))
+	}
+	if got.Temperature.Direction() != "steigend" || got.Humidity.Direction() != "fallend" {
+		t.Errorf("unexpected directions %q, %q", got.Temperature.Direction(), got.Humidity.Direction())
+	}
+
+	var b bytes.Buffer
+	weather.WriteHistory(&b, got)
+	for _, line := range []string{"Ort: Bonn, DE", "steigend (+3.2 pro Tag)", "18.06.2022  22.0 °C  26.0 °C  1015 hPa"} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("want %q in output:\n%s", line, b.String())
+		}
+	}
+}
+
+func TestAnalyzeHistorySingleObservation(t *testing.T) {
+	t.Parallel()
+	o := weather.Observation{Time: time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC), Conditions: weather.Conditions{Temperature: 293.15}, Forecast: weather.Forecast{Units: weather.UnitsStandard}}
+	got := weather.AnalyzeHistory("Bonn", weather.UnitsImperial, []weather.Observation{o})
+	want := weather.Statistics{Min: 68, Max: 68, Mean: 68}
+	approx := cmp.Comparer(func(a, b float64) bool { return a-b < 1e-9 && b-a < 1e-9 })
+	if !cmp.Equal(want, got.Temperature, approx) {
+		t.Error(cmp.Diff(want, got.Temperature, approx))
+	}
+}
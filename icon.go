@@ -0,0 +1,200 @@
+package weather
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultIconURL ... base URL of the weather icons of OpenWeatherMap
+	DefaultIconURL = "https://openweathermap.org/img/wn"
+
+	// graphics protocols of the terminals the icons are rendered in, auto picks one by the environment
+	GraphicsAuto  = "auto"
+	GraphicsKitty = "kitty"
+	GraphicsSixel = "sixel"
+
+	// kittyChunk ... base64 bytes per escape sequence of the kitty graphics protocol
+	kittyChunk = 4096
+)
+
+var validGraphics = map[string]bool{
+	GraphicsAuto:  true,
+	GraphicsKitty: true,
+	GraphicsSixel: true,
+}
+
+// IconURL ... URL of the weather icon like 10d in double size, empty without icon
+func IconURL(icon string) string {
+	return formatIconURL(DefaultIconURL, icon)
+}
+
+// IconURL ... URL of the icon of the conditions, empty without icon
+func (c Conditions) IconURL() string {
+	return IconURL(c.Icon)
+}
+
+// IconURL ... URL of the icon of the day, empty without icon
+func (d ForecastDaily) IconURL() string {
+	return IconURL(d.Icon)
+}
+
+func formatIconURL(base, icon string) string {
+	if icon == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s@2x.png", base, icon)
+}
+
+func (c *Client) FormatIconURL(icon string) string {
+	return formatIconURL(c.IconURL, icon)
+}
+
+// GetIcon ... weather icon like 10d as PNG
+func (c *Client) GetIcon(icon string) ([]byte, error) {
+	if icon == "" {
+		return nil, fmt.Errorf("no icon given")
+	}
+	resp, err := c.HTTPClient.Get(c.FormatIconURL(icon))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// DetectGraphics ... kitty or sixel if the terminal described by the environment is known to support it, else empty
+func DetectGraphics(getenv func(string) string) string {
+	term := getenv("TERM")
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || getenv("TERM_PROGRAM") == "WezTerm":
+		return GraphicsKitty
+	case strings.Contains(term, "sixel") || term == "foot" || strings.HasPrefix(term, "mlterm"):
+		return GraphicsSixel
+	}
+	return ""
+}
+
+// WriteImage ... renders the PNG inline with the graphics protocol, kitty or sixel
+func WriteImage(w io.Writer, data []byte, graphics string) error {
+	switch graphics {
+	case GraphicsKitty:
+		return WriteKitty(w, data)
+	case GraphicsSixel:
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("invalid icon: %w", err)
+		}
+		return WriteSixel(w, img)
+	}
+	return fmt.Errorf("unknown graphics %q, want kitty or sixel", graphics)
+}
+
+// WriteKitty ... PNG as escape sequences of the kitty graphics protocol, followed by a newline
+func WriteKitty(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	bw := bufio.NewWriter(w)
+	for first := true; first || encoded != ""; first = false {
+		chunk := encoded
+		if len(chunk) > kittyChunk {
+			chunk = chunk[:kittyChunk]
+		}
+		encoded = encoded[len(chunk):]
+		more := 0
+		if encoded != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(bw, "\x1b_Ga=T,f=100,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			fmt.Fprintf(bw, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	fmt.Fprintln(bw)
+	return bw.Flush()
+}
+
+// WriteSixel ... image as sixel graphics in the 216 colours of a 6x6x6 cube,
+// transparent pixels keep the background, followed by a newline
+func WriteSixel(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "\x1bP0;1;0q\"1;1;%d;%d", b.Dx(), b.Dy())
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(bw, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+	}
+	for top := b.Min.Y; top < b.Max.Y; top += 6 {
+		// the bits of the 6 rows of the band per colour and column
+		band := map[int][]byte{}
+		for x := b.Min.X; x < b.Max.X; x++ {
+			for dy := 0; dy < 6 && top+dy < b.Max.Y; dy++ {
+				index, ok := sixelColor(img, x, top+dy)
+				if !ok {
+					continue
+				}
+				if band[index] == nil {
+					band[index] = make([]byte, b.Dx())
+				}
+				band[index][x-b.Min.X] |= 1 << dy
+			}
+		}
+		colors := make([]int, 0, len(band))
+		for index := range band {
+			colors = append(colors, index)
+		}
+		sort.Ints(colors)
+		for i, index := range colors {
+			if i > 0 {
+				bw.WriteByte('$')
+			}
+			fmt.Fprintf(bw, "#%d", index)
+			writeSixelRow(bw, band[index])
+		}
+		bw.WriteByte('-')
+	}
+	fmt.Fprint(bw, "\x1b\\\n")
+	return bw.Flush()
+}
+
+// sixelColor ... index of the pixel in the colour cube, false if it is mostly transparent
+func sixelColor(img image.Image, x, y int) (int, bool) {
+	r, g, b, a := img.At(x, y).RGBA()
+	if a < 0x8000 {
+		return 0, false
+	}
+	// the colours are premultiplied, the cube has 6 levels per channel
+	level := func(v uint32) int {
+		return int((v*0xffff/a*5 + 0x7fff) / 0xffff)
+	}
+	return level(r)*36 + level(g)*6 + level(b), true
+}
+
+// writeSixelRow ... the sixels of one colour of a band, repetitions are run-length encoded
+func writeSixelRow(w *bufio.Writer, bits []byte) {
+	for i := 0; i < len(bits); {
+		n := 1
+		for i+n < len(bits) && bits[i+n] == bits[i] {
+			n++
+		}
+		c := 63 + bits[i]
+		if n > 3 {
+			fmt.Fprintf(w, "!%d%c", n, c)
+		} else {
+			for j := 0; j < n; j++ {
+				w.WriteByte(c)
+			}
+		}
+		i += n
+	}
+}
@@ -0,0 +1,107 @@
+package weather_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestIconURL(t *testing.T) {
+	t.Parallel()
+	c := weather.Conditions{Icon: "10d"}
+	want := "https://openweathermap.org/img/wn/10d@2x.png"
+	if got := c.IconURL(); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got := (weather.ForecastDaily{}).IconURL(); got != "" {
+		t.Errorf("want no URL without icon, got %s", got)
+	}
+}
+
+func TestGetIcon(t *testing.T) {
+	t.Parallel()
+	c := weathertest.NewFakeClient(t)
+	got, err := c.GetIcon("10d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, weathertest.IconResponse) {
+		t.Errorf("want the icon PNG, got %d bytes", len(got))
+	}
+}
+
+func TestDetectGraphics(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		env  map[string]string
+		want string
+	}{
+		"kitty":   {map[string]string{"TERM": "xterm-kitty"}, weather.GraphicsKitty},
+		"wezterm": {map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "WezTerm"}, weather.GraphicsKitty},
+		"foot":    {map[string]string{"TERM": "foot"}, weather.GraphicsSixel},
+		"xterm":   {map[string]string{"TERM": "xterm-256color"}, ""},
+	}
+	for name, tc := range tests {
+		got := weather.DetectGraphics(func(key string) string { return tc.env[key] })
+		if got != tc.want {
+			t.Errorf("%s: want %q, got %q", name, tc.want, got)
+		}
+	}
+}
+
+func TestWriteKitty(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	err := weather.WriteKitty(&buf, bytes.Repeat([]byte{0}, 4000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	// 4000 bytes are 5336 base64 bytes, sent in two chunks
+	if !strings.HasPrefix(got, "\x1b_Ga=T,f=100,m=1;") || !strings.Contains(got, "\x1b_Gm=0;") || strings.Count(got, "\x1b\\") != 2 {
+		t.Errorf("want two chunks of the kitty graphics protocol, got %q", got)
+	}
+}
+
+func TestWriteSixel(t *testing.T) {
+	t.Parallel()
+	img := image.NewNRGBA(image.Rect(0, 0, 5, 7))
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 6; y++ {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	err := weather.WriteSixel(&buf, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	// red is colour 180 of the cube, the 6 full rows are "~", the transparent 7th row is left out
+	if !strings.HasPrefix(got, "\x1bP0;1;0q\"1;1;5;7") || !strings.HasSuffix(got, "#180!5~--\x1b\\\n") {
+		t.Errorf("want a red band of 5 sixels, got %q", got[len(got)-20:])
+	}
+}
+
+func TestCurrentIcon(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	config := `{"base_url": "` + ts.URL + `", "icon_url": "` + ts.URL + `/img/wn"}`
+	out, err := runCLIWithConfig(t, config, "current", "Bonn,DE", "--icon", "kitty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out, []byte("\x1b_Ga=T,f=100")) {
+		t.Errorf("want the icon in the kitty graphics protocol, got %q", out)
+	}
+	_, err = runCLIWithConfig(t, config, "current", "Bonn,DE", "--icon", "ascii")
+	if err == nil {
+		t.Error("want error for unknown graphics, but got nil")
+	}
+}
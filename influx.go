@@ -0,0 +1,125 @@
+package weather
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ActionInflux ... scheduled job writing the weather to InfluxDB
+	ActionInflux = "influxdb"
+
+	// InfluxBuffer ... lines kept for the next write while InfluxDB is unavailable, the oldest are dropped
+	InfluxBuffer = 10000
+)
+
+type (
+	// Influx ... writes every observation to an InfluxDB v2 bucket, failed writes are buffered and sent with the next one
+	Influx struct {
+		URL        string
+		Org        string
+		Bucket     string
+		Token      string
+		Buffer     int
+		HTTPClient *http.Client
+		// pending are the lines of the failed writes, oldest first
+		pending []string
+	}
+
+	// InfluxConfig ... InfluxDB settings of the config file
+	InfluxConfig struct {
+		URL    string `json:"url"`
+		Org    string `json:"org"`
+		Bucket string `json:"bucket"`
+		Token  string `json:"token"`
+	}
+)
+
+var influxEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// NewInflux ... sink writing to the bucket of the config
+func NewInflux(cfg InfluxConfig) *Influx {
+	return &Influx{
+		URL:        cfg.URL,
+		Org:        cfg.Org,
+		Bucket:     cfg.Bucket,
+		Token:      cfg.Token,
+		Buffer:     InfluxBuffer,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// LineProtocol ... the observation as a point of the measurement weather in the InfluxDB line protocol,
+// with second precision
+func LineProtocol(o Observation) string {
+	c := o.Conditions
+	units := o.Forecast.Units
+	if units == "" {
+		units = UnitsMetric
+	}
+	fields := []string{
+		"temperature=" + formatInfluxFloat(c.Temperature),
+		"feels_like=" + formatInfluxFloat(c.FeelsLike),
+		"dew_point=" + formatInfluxFloat(c.DewPoint),
+		fmt.Sprintf("pressure=%di", c.Pressure),
+		fmt.Sprintf("humidity=%di", c.Humidity),
+		"wind_speed=" + formatInfluxFloat(float64(c.WindSpeed)),
+		"wind_gust=" + formatInfluxFloat(float64(c.WindGust)),
+		"wind_direction=" + formatInfluxFloat(float64(c.WindDirection)),
+		"uvi=" + formatInfluxFloat(float64(c.UVI)),
+	}
+	if len(o.Forecast.Hourly) > 0 {
+		fields = append(fields, "rain_chance="+formatInfluxFloat(o.Forecast.Hourly[0].RainChance))
+	}
+	fields = append(fields, fmt.Sprintf("alerts=%di", len(o.Forecast.CurrentAlerts())))
+	return fmt.Sprintf("weather,location=%s,units=%s %s %d",
+		influxEscaper.Replace(o.Location), influxEscaper.Replace(units), strings.Join(fields, ","), o.Time.Unix())
+}
+
+func formatInfluxFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Write ... writes the observation together with the buffered ones, which are only dropped if InfluxDB rejects them
+func (i *Influx) Write(o Observation) error {
+	i.pending = append(i.pending, LineProtocol(o))
+	if i.Buffer > 0 && len(i.pending) > i.Buffer {
+		i.pending = i.pending[len(i.pending)-i.Buffer:]
+	}
+	retry, err := i.post(strings.Join(i.pending, "\n"))
+	if err != nil && retry {
+		return fmt.Errorf("influxdb: %w, %d points buffered", err, len(i.pending))
+	}
+	i.pending = nil
+	if err != nil {
+		return fmt.Errorf("influxdb: %w", err)
+	}
+	return nil
+}
+
+// post ... one write, retry tells whether the lines are worth another attempt
+func (i *Influx) post(lines string) (bool, error) {
+	query := url.Values{"org": {i.Org}, "bucket": {i.Bucket}, "precision": {"s"}}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(i.URL, "/")+"/api/v2/write?"+query.Encode(), strings.NewReader(lines))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if i.Token != "" {
+		req.Header.Set("Authorization", "Token "+i.Token)
+	}
+	resp, err := i.HTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	return false, nil
+}
@@ -0,0 +1,73 @@
+package weather_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLineProtocol(t *testing.T) {
+	t.Parallel()
+	o := weather.Observation{
+		Location:   "Bonn, DE",
+		Time:       time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC),
+		Conditions: weather.Conditions{Temperature: 21.5, FeelsLike: 21, DewPoint: 12.25, Pressure: 1015, Humidity: 55, WindSpeed: 3.5, WindDirection: 270},
+		Forecast: weather.Forecast{
+			Units:  weather.UnitsMetric,
+			Hourly: []weather.ForecastHourly{{RainChance: 20}},
+		},
+	}
+	want := `weather,location=Bonn\,\ DE,units=metric temperature=21.5,feels_like=21,dew_point=12.25,pressure=1015i,humidity=55i,` +
+		`wind_speed=3.5,wind_gust=0,wind_direction=270,uvi=0,rain_chance=20,alerts=0i 1655467200`
+	got := weather.LineProtocol(o)
+	if want != got {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestInfluxBuffering(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	statuses := []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK, http.StatusBadRequest}
+	var bodies, queries, tokens []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		w.WriteHeader(statuses[0])
+		statuses = statuses[1:]
+	}))
+	defer ts.Close()
+	i := weather.NewInflux(weather.InfluxConfig{URL: ts.URL, Org: "home", Bucket: "weather", Token: "secret"})
+	i.Buffer = 2
+	start := time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC)
+	for n, wantErr := range []bool{true, true, false, true} {
+		err := i.Write(weather.Observation{Location: "Bonn", Time: start.Add(time.Duration(n) * time.Hour)})
+		if wantErr != (err != nil) {
+			t.Errorf("write %d: want error %v, got %v", n, wantErr, err)
+		}
+	}
+	lines := func(body string) int { return len(strings.Split(body, "\n")) }
+	// the third write sends the two buffered points only, the first one was dropped,
+	// the rejected fourth is not buffered
+	got := []int{lines(bodies[0]), lines(bodies[1]), lines(bodies[2]), lines(bodies[3])}
+	if !cmp.Equal([]int{1, 2, 2, 1}, got) {
+		t.Error(cmp.Diff([]int{1, 2, 2, 1}, got))
+	}
+	if !strings.HasSuffix(bodies[2], " 1655474400") {
+		t.Errorf("want the newest points kept, got %s", bodies[2])
+	}
+	if queries[0] != "/api/v2/write?bucket=weather&org=home&precision=s" || tokens[0] != "Token secret" {
+		t.Errorf("unexpected request %s with %q", queries[0], tokens[0])
+	}
+}
@@ -0,0 +1,78 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type (
+	// Geolocator ... determines the caller's approximate position, used by --here
+	Geolocator interface {
+		Locate() (Coordinates, error)
+	}
+
+	// IPLocator ... Geolocator based on the public IP, talking to an ipinfo.io compatible service
+	IPLocator struct {
+		URL        string
+		HTTPClient *http.Client
+	}
+
+	IPLocationResponse struct {
+		Loc   string
+		Bogon bool
+		Error *struct {
+			Title   string
+			Message string
+		}
+	}
+)
+
+// DefaultGeolocator ... backend used by the CLI, can be replaced by embedding applications
+var DefaultGeolocator Geolocator = NewIPLocator()
+
+func NewIPLocator() *IPLocator {
+	return &IPLocator{
+		URL: "https://ipinfo.io/json",
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func ParseIPLocationResponse(data []byte) (Coordinates, error) {
+	var resp IPLocationResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("invalid API response %s: %w", data, err)
+	}
+	switch {
+	case resp.Error != nil:
+		return Coordinates{}, fmt.Errorf("IP geolocation failed: %s", resp.Error.Message)
+	case resp.Bogon:
+		return Coordinates{}, fmt.Errorf("IP geolocation failed: reserved range")
+	}
+	coordinates, ok := ParseCoordinates(resp.Loc)
+	if !ok {
+		return Coordinates{}, fmt.Errorf("invalid API response %s: want loc as lat,lon", data)
+	}
+	return coordinates, nil
+}
+
+func (l *IPLocator) Locate() (Coordinates, error) {
+	resp, err := l.HTTPClient.Get(l.URL)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Coordinates{}, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	return ParseIPLocationResponse(data)
+}
@@ -0,0 +1,72 @@
+package weather_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseIPLocationResponse(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/ip_location.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := weather.Coordinates{Lat: 50.7374, Lon: 7.0982}
+	got, err := weather.ParseIPLocationResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseIPLocationResponseFailed(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/ip_location_invalid.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = weather.ParseIPLocationResponse(data)
+	if err == nil {
+		t.Fatal("want error parsing failed lookup, but got nil")
+	}
+}
+
+func TestParseIPLocationResponseError(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{"error":{"title":"Wrong ip","message":"Please provide a valid IP address"}}`)
+	_, err := weather.ParseIPLocationResponse(data)
+	if err == nil {
+		t.Fatal("want error parsing error response, but got nil")
+	}
+}
+
+func TestIPLocatorLocate(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f, err := os.Open("testdata/ip_location.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			io.Copy(w, f)
+		}))
+	defer ts.Close()
+	var l weather.Geolocator = &weather.IPLocator{URL: ts.URL, HTTPClient: ts.Client()}
+	want := weather.Coordinates{Lat: 50.7374, Lon: 7.0982}
+	got, err := l.Locate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,107 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	FunctionLaundry = "laundry"
+
+	// dryingHour ... hour score from which the washing dries well
+	dryingHour = 0.5
+)
+
+// DryingDay ... how well the washing dries outside between sunrise and sunset of a day, Score is 0 to 100,
+// From and To are the longest stretch of good drying hours, empty if there is none
+type DryingDay struct {
+	Day   string `json:"day"`
+	Score int    `json:"score"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+	// Hours are the daylight hours of the hourly forecast
+	Hours int `json:"hours"`
+}
+
+// DryingScore ... how well the washing dries in an hour from 0 to 1, from temperature in °C, humidity, wind in m/s
+// and rain chance, warm dry air counts most, the wind helps
+func DryingScore(celsius float64, humidity int, wind Speed, rainChance float64) float64 {
+	clamp := func(v float64) float64 {
+		return math.Max(0, math.Min(1, v))
+	}
+	warmth := clamp(celsius / 25)
+	dryness := clamp(float64(100-humidity) / 60)
+	breeze := clamp(float64(wind) / 6)
+	return (0.35*warmth + 0.4*dryness + 0.25*breeze) * (1 - clamp(rainChance/100))
+}
+
+// GetDrying ... drying of the days of the hourly forecast between sunrise and sunset
+func GetDrying(f Forecast) []DryingDay {
+	days := []DryingDay{}
+	for _, daily := range f.Daily {
+		d := DryingDay{Day: daily.Day}
+		sum := 0.0
+		// from and to of the current stretch of good drying hours
+		from, to := "", ""
+		for _, slot := range f.Hourly {
+			if slot.Day != daily.Day || slot.Hour < daily.Sunrise || slot.Hour >= daily.Sunset {
+				continue
+			}
+			score := DryingScore(convertTemperature(slot.Temperature, f.Units, UnitsMetric), slot.Humidity,
+				metersPerSecond(slot.WindSpeed, f.Units), slot.RainChance)
+			sum += score
+			d.Hours++
+			if score < dryingHour || slot.Rain > 0 || slot.Snow > 0 {
+				from = ""
+				continue
+			}
+			if from == "" {
+				from = slot.Hour
+			}
+			to = slot.Time.Add(time.Hour).Format("15:04")
+			if d.From == "" || stretch(from, to) > stretch(d.From, d.To) {
+				d.From, d.To = from, to
+			}
+		}
+		if d.Hours == 0 {
+			continue
+		}
+		d.Score = int(math.Round(sum / float64(d.Hours) * 100))
+		days = append(days, d)
+	}
+	return days
+}
+
+// stretch ... minutes from one time of the day like 09:00 to another
+func stretch(from, to string) int {
+	f, _ := minutes(from)
+	t, _ := minutes(to)
+	if t < f {
+		t += 24 * 60
+	}
+	return t - f
+}
+
+// Advice ... answer to the question whether the washing can hang outside
+func (d DryingDay) Advice() string {
+	switch {
+	case d.Score >= 60:
+		return "Ja, raus mit der Wäsche!"
+	case d.Score >= 40 && d.From != "":
+		return fmt.Sprintf("Mit etwas Glück, am besten von %s bis %s.", d.From, d.To)
+	case d.From != "" && stretch(d.From, d.To) >= 3*60:
+		return fmt.Sprintf("Nur von %s bis %s.", d.From, d.To)
+	}
+	return "Nein, lieber drinnen trocknen."
+}
+
+// PrintLaundry ... whether the washing dries outside today and tomorrow
+func PrintLaundry(f Forecast) {
+	fmt.Println()
+	printHeader("Wäsche draußen trocknen?", f)
+	for _, d := range GetDrying(f) {
+		fmt.Printf("%s: %d von 100 Punkten. %s\n", d.Day, d.Score, d.Advice())
+	}
+	fmt.Println()
+}
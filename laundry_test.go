@@ -0,0 +1,62 @@
+package weather_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+)
+
+func TestDryingScore(t *testing.T) {
+	t.Parallel()
+	if got := weather.DryingScore(25, 40, 6, 0); got != 1 {
+		t.Errorf("want perfect drying in warm, dry and breezy air, got %.2f", got)
+	}
+	if got := weather.DryingScore(5, 100, 0, 0); got > 0.1 {
+		t.Errorf("want almost no drying in cold fog, got %.2f", got)
+	}
+	if got := weather.DryingScore(25, 40, 6, 100); got != 0 {
+		t.Errorf("want no drying in certain rain, got %.2f", got)
+	}
+}
+
+func TestGetDrying(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2022, 6, 17, 0, 0, 0, 0, time.Local)
+	f := weather.Forecast{Units: weather.UnitsMetric, Daily: []weather.ForecastDaily{
+		{Day: "17.06.2022", Sunrise: "06:00", Sunset: "21:00"},
+		{Day: "18.06.2022", Sunrise: "06:00", Sunset: "21:00"},
+		{Day: "19.06.2022", Sunrise: "06:00", Sunset: "21:00"},
+	}}
+	for h := 0; h < 48; h++ {
+		at := start.Add(time.Duration(h) * time.Hour)
+		slot := weather.ForecastHourly{Time: at, Day: at.Format("02.01.2006"), Hour: at.Format("15:04")}
+		switch {
+		case h < 24:
+			// sunny and dry
+			slot.Temperature, slot.Humidity, slot.WindSpeed = 24, 40, 4
+		case at.Hour() >= 10 && at.Hour() < 14:
+			// a dry spell in the rain
+			slot.Temperature, slot.Humidity, slot.WindSpeed = 18, 60, 3
+		default:
+			slot.Temperature, slot.Humidity, slot.RainChance, slot.Rain = 14, 95, 90, 1.2
+		}
+		f.Hourly = append(f.Hourly, slot)
+	}
+	got := weather.GetDrying(f)
+	if len(got) != 2 {
+		t.Fatalf("want the 2 days of the hourly forecast, got %+v", got)
+	}
+	sunny, rainy := got[0], got[1]
+	if sunny.Hours != 15 || sunny.From != "06:00" || sunny.To != "21:00" || sunny.Advice() != "Ja, raus mit der Wäsche!" {
+		t.Errorf("want a sunny day of drying, got %+v: %s", sunny, sunny.Advice())
+	}
+	if rainy.From != "10:00" || rainy.To != "14:00" || rainy.Score >= 40 {
+		t.Errorf("want the dry spell of the rainy day, got %+v", rainy)
+	}
+	want := fmt.Sprintf("Nur von %s bis %s.", rainy.From, rainy.To)
+	if rainy.Advice() != want {
+		t.Errorf("want %q, got %q", want, rainy.Advice())
+	}
+}
@@ -0,0 +1,131 @@
+package weather
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CompactWidth ... terminals narrower than this get the compact layout with fewer columns, e.g. tmux panes
+	CompactWidth = 100
+	// WideWidth ... terminals from this width get the wide layout, the hours of each day side by side
+	WideWidth = 140
+
+	// hourCellWidth ... width of the temperature, rain, wind and description of an hour in the wide layout
+	hourCellWidth = 46
+	// dayColumnGap ... space in front of each day column of the wide layout
+	dayColumnGap = 3
+)
+
+// TerminalWidth ... columns of the terminal, COLUMNS overrides the size of the terminal, 0 if the output
+// isn't a terminal
+func TerminalWidth(out *os.File, getenv func(string) string) int {
+	if columns, err := strconv.Atoi(getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if !isTerminal(out) {
+		return 0
+	}
+	return windowWidth(out)
+}
+
+// compact ... true if the width calls for the compact layout, an unknown width keeps the full tables
+func (r TextRenderer) compact() bool {
+	return r.Width > 0 && r.Width < CompactWidth
+}
+
+// wide ... true if the width leaves room for the hours of the days side by side
+func (r TextRenderer) wide() bool {
+	return r.Width >= WideWidth
+}
+
+// hourValues ... temperature, rain chance and wind of an hour
+func (r TextRenderer) hourValues(slot ForecastHourly, f Forecast) string {
+	wind := f.FormatSpeed(slot.WindSpeed) + " " + slot.WindDirection.Direction()
+	return fmt.Sprintf("%s%s  %s  %s",
+		r.temperature("%5.1f", slot.Temperature, f), f.TemperatureUnit(),
+		r.paint(fmt.Sprintf("%3.0f %%", slot.RainChance), rainChanceColor(slot.RainChance)),
+		r.wind(fmt.Sprintf("%-12s", wind), slot.WindSpeed, slot.WindGust, f))
+}
+
+// writeHourlyCompact ... the hours in one column, each day led by its date
+func (r TextRenderer) writeHourlyCompact(w io.Writer, f Forecast, slots []ForecastHourly) {
+	fmt.Fprintf(w, "%-5s  %7s  %5s  %-12s  %s\n", "Zeit", "Temp", "Regen", "Wind", "Beschreibung")
+	day := ""
+	for _, slot := range slots {
+		if slot.Day != day {
+			fmt.Fprintln(w, slot.Day)
+			day = slot.Day
+		}
+		fmt.Fprintf(w, "%-5s  %s  %s\n", slot.Hour, r.hourValues(slot, f), slot.Description)
+	}
+}
+
+// writeHourlyWide ... a column per day with a row per hour of the day, as many days side by side as the width takes
+func (r TextRenderer) writeHourlyWide(w io.Writer, f Forecast, slots []ForecastHourly) {
+	days := []string{}
+	byDay := map[string]map[string]ForecastHourly{}
+	for _, slot := range slots {
+		if byDay[slot.Day] == nil {
+			days = append(days, slot.Day)
+			byDay[slot.Day] = map[string]ForecastHourly{}
+		}
+		byDay[slot.Day][slot.Hour] = slot
+	}
+	perBlock := (r.Width - 5) / (dayColumnGap + hourCellWidth)
+	for start := 0; start < len(days); start += perBlock {
+		end := start + perBlock
+		if end > len(days) {
+			end = len(days)
+		}
+		block := days[start:end]
+		if start > 0 {
+			fmt.Fprintln(w)
+		}
+		header := fmt.Sprintf("%-5s", "Zeit")
+		hours := []string{}
+		seen := map[string]bool{}
+		for _, day := range block {
+			header += fmt.Sprintf("%*s%-*s", dayColumnGap, "", hourCellWidth, day)
+			for hour := range byDay[day] {
+				if !seen[hour] {
+					hours = append(hours, hour)
+					seen[hour] = true
+				}
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(header, " "))
+		sort.Strings(hours)
+		for _, hour := range hours {
+			row := fmt.Sprintf("%-5s", hour)
+			for _, day := range block {
+				cell := fmt.Sprintf("%*s", hourCellWidth, "")
+				if slot, ok := byDay[day][hour]; ok {
+					cell = fmt.Sprintf("%s  %-16.16s", r.hourValues(slot, f), slot.Description)
+				}
+				row += fmt.Sprintf("%*s%s", dayColumnGap, "", cell)
+			}
+			fmt.Fprintln(w, strings.TrimRight(row, " "))
+		}
+	}
+}
+
+// writeWeekCompact ... the outlook of the days without sun, humidity and clouds
+func (r TextRenderer) writeWeekCompact(w io.Writer, f Forecast) {
+	unit := f.TemperatureUnit()
+	fmt.Fprintf(w, "%-10s  %6s  %6s  %5s  %-12s  %s\n", "Tag", "Min", "Max", "Regen", "Wind", "Beschreibung")
+	for _, day := range f.Daily {
+		wind := f.FormatSpeed(day.WindSpeed) + " " + day.WindDirection.Direction()
+		fmt.Fprintf(w, "%-10s  %s%s  %s%s  %s  %s  %s\n",
+			day.Day,
+			r.temperature("%4.0f", day.Temp.Min, f), unit,
+			r.temperature("%4.0f", day.Temp.Max, f), unit,
+			r.paint(fmt.Sprintf("%3.0f %%", day.RainChance), rainChanceColor(day.RainChance)),
+			r.wind(fmt.Sprintf("%-12s", wind), day.WindSpeed, day.WindGust, f),
+			day.Description)
+	}
+}
@@ -0,0 +1,89 @@
+package weather_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+func TestTerminalWidth(t *testing.T) {
+	t.Parallel()
+	f, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	env := map[string]string{"COLUMNS": "72"}
+	if got := weather.TerminalWidth(f, func(key string) string { return env[key] }); got != 72 {
+		t.Errorf("want the width of COLUMNS, got %d", got)
+	}
+	if got := weather.TerminalWidth(f, func(string) string { return "" }); got != 0 {
+		t.Errorf("want no width of a file, got %d", got)
+	}
+}
+
+func TestRenderHourlyLayouts(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/weather_30.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, f, err := weather.ParseWeatherResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	render := func(width int) []string {
+		var b bytes.Buffer
+		err := weather.TextRenderer{Width: width}.RenderHourly(&b, f, 30)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Split(b.String(), "\n")
+	}
+	longest := func(lines []string) int {
+		n := 0
+		for _, line := range lines {
+			if l := len([]rune(line)); l > n {
+				n = l
+			}
+		}
+		return n
+	}
+	full := render(0)
+	if !strings.HasPrefix(full[3], "Tag") || !strings.Contains(full[3], "Druck") {
+		t.Errorf("want the full table without width, got %q", full[3])
+	}
+	compact := render(60)
+	if strings.Contains(compact[3], "Druck") || compact[4] != f.Hourly[0].Day {
+		t.Errorf("want the compact layout led by the day, got %q", compact[3:5])
+	}
+	if longest(compact) >= weather.CompactWidth {
+		t.Errorf("want compact lines below %d, got %d", weather.CompactWidth, longest(compact))
+	}
+	wide := render(160)
+	if !strings.Contains(wide[3], f.Hourly[0].Day) || !strings.Contains(wide[3], f.Hourly[29].Day) {
+		t.Errorf("want the days side by side, got %q", wide[3])
+	}
+	if longest(wide) > 160 {
+		t.Errorf("want wide lines within 160 columns, got %d", longest(wide))
+	}
+	if len(wide) >= len(full) {
+		t.Errorf("want fewer lines side by side, got %d for %d", len(wide), len(full))
+	}
+}
+
+func TestRenderWeekCompact(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Daily: []weather.ForecastDaily{{Day: "17.06.2022", Sunrise: "04:58", Sunset: "21:48", Description: "Klarer Himmel"}}}
+	var b bytes.Buffer
+	err := weather.TextRenderer{Width: 60}.RenderWeek(&b, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "04:58") || !strings.Contains(b.String(), "Klarer Himmel") {
+		t.Errorf("want the week without the sun, got %q", b.String())
+	}
+}
@@ -0,0 +1,58 @@
+package weather
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency ... requests running at once when fetching several locations
+const DefaultConcurrency = 4
+
+// WeatherResult ... weather of one of several coordinates, Err is set if it couldn't be fetched
+type WeatherResult struct {
+	Coordinates Coordinates
+	Conditions  Conditions
+	Forecast    Forecast
+	Err         error
+}
+
+// GetWeatherMany ... weather of all coordinates, at most Concurrency requests run at once.
+// The results are in the order of the coordinates, coordinates not fetched before ctx is done get its error.
+func (c *Client) GetWeatherMany(ctx context.Context, coordinates []Coordinates) []WeatherResult {
+	results := make([]WeatherResult, len(coordinates))
+	forEachBounded(len(coordinates), c.concurrency(), func(i int) {
+		r := &results[i]
+		r.Coordinates = coordinates[i]
+		if r.Err = ctx.Err(); r.Err != nil {
+			return
+		}
+		r.Conditions, r.Forecast, r.Err = c.getWeather(ctx, coordinates[i])
+	})
+	return results
+}
+
+func (c *Client) concurrency() int {
+	if c.Concurrency > 0 {
+		return c.Concurrency
+	}
+	return DefaultConcurrency
+}
+
+// forEachBounded ... calls fn for 0 to n-1 with at most limit calls at once and waits for all of them
+func forEachBounded(n, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+	running := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		running <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-running }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,76 @@
+package weather_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestGetWeatherMany(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	running, max := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > max {
+			max = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if r.URL.Query().Get("lat") == "3" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(weathertest.WeatherResponse)
+	}))
+	defer ts.Close()
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.Concurrency = 2
+	coordinates := []weather.Coordinates{}
+	for lat := 1.0; lat <= 6; lat++ {
+		coordinates = append(coordinates, weather.Coordinates{Lat: lat, Lon: 7})
+	}
+	results := c.GetWeatherMany(context.Background(), coordinates)
+	if len(results) != len(coordinates) {
+		t.Fatalf("want %d results, got %d", len(coordinates), len(results))
+	}
+	for i, r := range results {
+		if r.Coordinates != coordinates[i] {
+			t.Errorf("want result %d for %v, got %v", i, coordinates[i], r.Coordinates)
+		}
+		if wantErr := r.Coordinates.Lat == 3; wantErr != (r.Err != nil) {
+			t.Errorf("%v: want error %v, got %v", r.Coordinates, wantErr, r.Err)
+		}
+		if r.Err == nil && len(r.Forecast.Daily) != 8 {
+			t.Errorf("%v: want 8 days, got %d", r.Coordinates, len(r.Forecast.Daily))
+		}
+	}
+	if max > 2 {
+		t.Errorf("want at most 2 requests at once, got %d", max)
+	}
+}
+
+func TestGetWeatherManyCanceled(t *testing.T) {
+	t.Parallel()
+	c := weathertest.NewFakeClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := c.GetWeatherMany(ctx, []weather.Coordinates{{Lat: 1, Lon: 2}, {Lat: 3, Lon: 4}})
+	for _, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("%v: want context canceled, got %v", r.Coordinates, r.Err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// AlertSourceMeteoAlarm ... official warnings of the European weather services by MeteoAlarm
+	AlertSourceMeteoAlarm = "meteoalarm"
+
+	// DefaultMeteoAlarmURL ... base URL of the CAP feeds of MeteoAlarm
+	DefaultMeteoAlarmURL = "https://feeds.meteoalarm.org"
+)
+
+type (
+	// MeteoAlarmResponse ... warnings of a country in the Common Alerting Protocol (CAP)
+	MeteoAlarmResponse struct {
+		Warnings []struct {
+			Alert struct {
+				Identifier string
+				Info       []capInfo
+			}
+		}
+	}
+
+	// capInfo ... one language of a CAP alert
+	capInfo struct {
+		Language    string
+		Category    []string
+		Event       string
+		Urgency     string
+		Severity    string
+		Certainty   string
+		Effective   string
+		Onset       string
+		Expires     string
+		SenderName  string
+		Headline    string
+		Description string
+		Area        []struct {
+			AreaDesc string
+			Polygon  []string
+		}
+	}
+)
+
+// meteoAlarmFeeds ... feeds of the member countries of MeteoAlarm by their ISO 3166 code
+var meteoAlarmFeeds = map[string]string{
+	"AT": "austria", "BA": "bosnia-herzegovina", "BE": "belgium", "BG": "bulgaria", "CH": "switzerland",
+	"CY": "cyprus", "CZ": "czechia", "DE": "germany", "DK": "denmark", "EE": "estonia", "ES": "spain",
+	"FI": "finland", "FR": "france", "GB": "united-kingdom", "GR": "greece", "HR": "croatia", "HU": "hungary",
+	"IE": "ireland", "IL": "israel", "IS": "iceland", "IT": "italy", "LT": "lithuania", "LU": "luxembourg",
+	"LV": "latvia", "MD": "moldova", "ME": "montenegro", "MK": "republic-of-north-macedonia", "MT": "malta",
+	"NL": "netherlands", "NO": "norway", "PL": "poland", "PT": "portugal", "RO": "romania", "RS": "serbia",
+	"SE": "sweden", "SI": "slovenia", "SK": "slovakia", "UA": "ukraine",
+}
+
+// inPolygon ... true if the coordinates lie in the CAP polygon of "lat,lon" pairs separated by spaces
+func inPolygon(coordinates Coordinates, polygon string) bool {
+	points := []Coordinates{}
+	for _, pair := range strings.Fields(polygon) {
+		lat, lon, ok := strings.Cut(pair, ",")
+		if !ok {
+			return false
+		}
+		p := Coordinates{}
+		var err error
+		p.Lat, err = strconv.ParseFloat(lat, 64)
+		if err != nil {
+			return false
+		}
+		p.Lon, err = strconv.ParseFloat(lon, 64)
+		if err != nil {
+			return false
+		}
+		points = append(points, p)
+	}
+	// ray casting to the east
+	inside := false
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		a, b := points[i], points[j]
+		if (a.Lat > coordinates.Lat) != (b.Lat > coordinates.Lat) &&
+			coordinates.Lon < (b.Lon-a.Lon)*(coordinates.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// capTime ... CAP time in the format of the alerts
+func capTime(s string) (string, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return "", err
+	}
+	return t.Local().Format("02.01.2006, 15:04"), nil
+}
+
+// capLanguage ... info of the language, e.g. de for de-DE, the first one if there is none in it
+func capLanguage(infos []capInfo, lang string) capInfo {
+	for _, info := range infos {
+		if strings.EqualFold(strings.SplitN(info.Language, "-", 2)[0], lang) {
+			return info
+		}
+	}
+	return infos[0]
+}
+
+// ParseMeteoAlarmResponse ... alerts of the feed for the coordinates, those whose area polygon contains them or,
+// without polygons, whose area description names the place, in the language if the alert has it
+func ParseMeteoAlarmResponse(data []byte, coordinates Coordinates, place, lang string) ([]Alert, error) {
+	var resp MeteoAlarmResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MeteoAlarm response %s: %w", data, err)
+	}
+	alerts := []Alert{}
+	for _, w := range resp.Warnings {
+		if len(w.Alert.Info) == 0 {
+			continue
+		}
+		info := capLanguage(w.Alert.Info, lang)
+		found := false
+		for _, area := range info.Area {
+			for _, polygon := range area.Polygon {
+				found = found || inPolygon(coordinates, polygon)
+			}
+			if len(area.Polygon) == 0 && place != "" {
+				found = found || strings.Contains(strings.ToLower(area.AreaDesc), strings.ToLower(place))
+			}
+		}
+		if !found {
+			continue
+		}
+		onset := info.Onset
+		if onset == "" {
+			onset = info.Effective
+		}
+		start, err := capTime(onset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MeteoAlarm alert %s: %w", w.Alert.Identifier, err)
+		}
+		end, err := capTime(info.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MeteoAlarm alert %s: %w", w.Alert.Identifier, err)
+		}
+		description := info.Description
+		if description == "" {
+			description = info.Headline
+		}
+		a := Alert{
+			Start:       start,
+			End:         end,
+			Name:        info.Event,
+			Description: description,
+			Sender:      info.SenderName,
+			Severity:    AlertSeverity(strings.ToLower(info.Severity)),
+			Urgency:     strings.ToLower(info.Urgency),
+			Certainty:   strings.ToLower(info.Certainty),
+		}
+		if len(info.Category) > 0 {
+			a.Category = strings.ToLower(info.Category[0])
+		}
+		alerts = append(alerts, ClassifyAlert(a))
+	}
+	return alerts, nil
+}
+
+func (c *Client) FormatMeteoAlarmURL(country string) (string, error) {
+	feed, ok := meteoAlarmFeeds[strings.ToUpper(country)]
+	if !ok {
+		return "", fmt.Errorf("MeteoAlarm has no warnings for the country %q", country)
+	}
+	return fmt.Sprintf("%s/api/v1/warnings/feeds-%s", c.MeteoAlarmURL, feed), nil
+}
+
+// GetMeteoAlarm ... official warnings of MeteoAlarm for the coordinates of the place, Europe only
+func (c *Client) GetMeteoAlarm(coordinates Coordinates, place Place) ([]Alert, error) {
+	URL, err := c.FormatMeteoAlarmURL(place.Country)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Get(URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMeteoAlarmResponse(data, coordinates, place.Name, c.Lang)
+}
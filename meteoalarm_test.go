@@ -0,0 +1,124 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseMeteoAlarmResponse(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParseMeteoAlarmResponse(weathertest.MeteoAlarmResponse,
+		weather.Coordinates{Lat: 55.123456, Lon: 3.7654321}, "Bad Schnuffel", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []weather.Alert{
+		{
+			Start:       "17.06.2022, 18:00",
+			End:         "18.06.2022, 02:00",
+			Name:        "UNWETTERWARNUNG vor SCHWEREM GEWITTER",
+			Description: "Es treten schwere Gewitter mit Starkregen und Hagel auf.",
+			Sender:      "Deutscher Wetterdienst",
+			Severity:    weather.AlertSevere,
+			Urgency:     "immediate",
+			Certainty:   "likely",
+			Category:    "met",
+		},
+		{
+			Start:       "19.06.2022, 06:00",
+			End:         "19.06.2022, 18:00",
+			Name:        "STARKWIND",
+			Description: "Es treten Windböen bis 60 km/h auf.",
+			Sender:      "Deutscher Wetterdienst",
+			Severity:    weather.AlertModerate,
+			Urgency:     "future",
+			Certainty:   "likely",
+			Category:    "met",
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	english, err := weather.ParseMeteoAlarmResponse(weathertest.MeteoAlarmResponse, weather.Coordinates{}, "Bad Schnuffel", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(english) != 1 || english[0].Name != "severe thunderstorms" {
+		t.Errorf("want the English thunderstorm only, got %+v", english)
+	}
+}
+
+func TestParseMeteoAlarmResponseInvalid(t *testing.T) {
+	t.Parallel()
+	for _, data := range []string{`[]`, `{"warnings": [{"alert": {"info": [{"onset": "morgen", "area": [{"areaDesc": "Bonn"}]}]}}]}`} {
+		if _, err := weather.ParseMeteoAlarmResponse([]byte(data), weather.Coordinates{}, "Bonn", "de"); err == nil {
+			t.Errorf("%s: want error, but got nil", data)
+		}
+	}
+}
+
+func TestFormatMeteoAlarmURL(t *testing.T) {
+	t.Parallel()
+	c := weather.NewClient("dummyAPIKey")
+	got, err := c.FormatMeteoAlarmURL("at")
+	want := "https://feeds.meteoalarm.org/api/v1/warnings/feeds-austria"
+	if err != nil || got != want {
+		t.Errorf("want %s, got %s, %v", want, got, err)
+	}
+	if _, err := c.FormatMeteoAlarmURL("US"); err == nil {
+		t.Error("want an error for a country without MeteoAlarm")
+	}
+}
+
+func TestAddAlerts(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Daily: []weather.ForecastDaily{
+		{Day: "17.06.2022", Alerts: []weather.Alert{
+			{Name: "Gewitter", Start: "17.06.2022, 18:00", End: "18.06.2022, 02:00", Sender: "OWM"},
+		}},
+		{Day: "18.06.2022", Alerts: []weather.Alert{}},
+		{Day: "19.06.2022", Alerts: []weather.Alert{}},
+	}}
+	weather.AddAlerts(&f, []weather.Alert{
+		{Name: "Gewitter", Start: "17.06.2022, 18:00", End: "18.06.2022, 02:00", Sender: "DWD"},
+		{Name: "Wind", Start: "19.06.2022, 06:00", End: "19.06.2022, 18:00", Sender: "DWD"},
+	})
+	got := []string{}
+	for _, day := range f.Daily {
+		for _, a := range day.Alerts {
+			got = append(got, day.Day+" "+a.Name+" "+a.Sender)
+		}
+	}
+	want := []string{"17.06.2022 Gewitter OWM, DWD", "18.06.2022 Gewitter DWD", "19.06.2022 Wind DWD"}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestAlertSourceMeteoAlarm(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	t.Cleanup(ts.Close)
+	out, err := runCLIWithConfig(t, `{"base_url": "`+ts.URL+`", "meteoalarm_url": "`+ts.URL+`", "alert_sources": ["meteoalarm"]}`,
+		"alert", "Bonn,DE", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Alerts []struct {
+			Name     string
+			Severity string
+		}
+	}
+	err = json.Unmarshal(out, &got)
+	if err != nil {
+		t.Fatalf("invalid output %s: %v", out, err)
+	}
+	if len(got.Alerts) != 2 || got.Alerts[0].Severity != "severe" {
+		t.Errorf("want the 2 MeteoAlarm alerts, got %s", out)
+	}
+}
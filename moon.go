@@ -0,0 +1,196 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"time"
+)
+
+// SynodicMonth ... mean days from one new moon to the next
+const SynodicMonth = 29.530588853
+
+// Illumination ... lit fraction of the moon, 0 at new moon and 1 at full moon
+func (p Phase) Illumination() float64 {
+	return (1 - math.Cos(2*math.Pi*float64(p))) / 2
+}
+
+// Waxing ... true from new moon till full moon
+func (p Phase) Waxing() bool {
+	return p >= 0 && p < 0.5
+}
+
+// Direction ... zunehmend or abnehmend
+func (p Phase) Direction() string {
+	if p.Waxing() {
+		return "zunehmend"
+	}
+	return "abnehmend"
+}
+
+// moonGlyphs ... Unicode moon of the eighths of the lunation, starting with the new moon
+var moonGlyphs = [8]string{"🌑", "🌒", "🌓", "🌔", "🌕", "🌖", "🌗", "🌘"}
+
+// Glyph ... Unicode moon of the phase, chosen by the lit fraction with the midpoints between the eight phases
+// as boundaries: new moon below 12.5 %, crescent till 37.5 %, half moon till 62.5 %, gibbous till 87.5 % and
+// full moon above, waxing or waning like Description
+func (p Phase) Glyph() string {
+	p = Phase(math.Mod(float64(p), 1))
+	i := int(math.Round(p.Illumination() * 4))
+	if !p.Waxing() && i > 0 && i < 4 {
+		i = 8 - i
+	}
+	return moonGlyphs[i]
+}
+
+// describe ... description of the phase, led by its glyph if icons is set
+func (p Phase) describe(icons bool) string {
+	if icons {
+		return p.Glyph() + " " + p.Description()
+	}
+	return p.Description()
+}
+
+// moonIconsFlags ... flags of the commands describing the moon phase
+func moonIconsFlags(fs *flag.FlagSet, opts *Options) {
+	fs.BoolVar(&opts.Icons, "icons", false, "show the moon phases with their glyphs like 🌔")
+}
+
+// Age ... days since the last new moon
+func (p Phase) Age() float64 {
+	return math.Mod(float64(p), 1) * SynodicMonth
+}
+
+// FormatMoon ... illumination, direction and age of the moon, e.g. "73 % beleuchtet, zunehmend, 9.8 Tage alt"
+func (p Phase) FormatMoon() string {
+	return fmt.Sprintf("%.0f %% beleuchtet, %s, %.1f Tage alt", p.Illumination()*100, p.Direction(), p.Age())
+}
+
+// formatLight ... illumination and age of the moon, for lines with the description, which tells the direction already
+func (p Phase) formatLight() string {
+	return fmt.Sprintf("%.0f %% beleuchtet, %.1f Tage alt", p.Illumination()*100, p.Age())
+}
+
+// moonPhaseTerms ... coefficients of the periodic terms of Meeus, Astronomical Algorithms, chapter 49,
+// for new and full moon, the smaller planetary terms are left out, which keeps the times within a few minutes
+var moonPhaseTerms = [2][15]float64{
+	{-0.40720, 0.17241, 0.01608, 0.01039, 0.00739, -0.00514, 0.00208, -0.00111, -0.00057, 0.00056, -0.00042, 0.00042, 0.00038, -0.00024, -0.00017},
+	{-0.40614, 0.17302, 0.01614, 0.01043, 0.00734, -0.00515, 0.00209, -0.00111, -0.00057, 0.00056, -0.00042, 0.00042, 0.00038, -0.00024, -0.00017},
+}
+
+// moonPhaseJDE ... Julian Ephemeris Day of the new moon k lunations after the one of 6 January 2000,
+// the full moon after it if full is set
+func moonPhaseJDE(k float64, full bool) float64 {
+	terms := moonPhaseTerms[0]
+	if full {
+		k += 0.5
+		terms = moonPhaseTerms[1]
+	}
+	t := k / 1236.85
+	jde := 2451550.09766 + 29.530588861*k + 0.00015437*t*t - 0.000000150*t*t*t + 0.00000000073*t*t*t*t
+	e := 1 - 0.002516*t - 0.0000074*t*t
+	rad := func(deg float64) float64 {
+		return math.Mod(deg, 360) * math.Pi / 180
+	}
+	m := rad(2.5534 + 29.10535670*k - 0.0000014*t*t - 0.00000011*t*t*t)
+	mm := rad(201.5643 + 385.81693528*k + 0.0107582*t*t + 0.00001238*t*t*t - 0.000000058*t*t*t*t)
+	f := rad(160.7108 + 390.67050284*k - 0.0016118*t*t - 0.00000227*t*t*t + 0.000000011*t*t*t*t)
+	omega := rad(124.7746 - 1.56375588*k + 0.0020672*t*t + 0.00000215*t*t*t)
+	args := [15]float64{
+		math.Sin(mm), e * math.Sin(m), math.Sin(2 * mm), math.Sin(2 * f), e * math.Sin(mm-m), e * math.Sin(mm+m),
+		e * e * math.Sin(2*m), math.Sin(mm - 2*f), math.Sin(mm + 2*f), e * math.Sin(2*mm+m), math.Sin(3 * mm),
+		e * math.Sin(m+2*f), e * math.Sin(m-2*f), e * math.Sin(2*mm-m), math.Sin(omega),
+	}
+	for i, a := range args {
+		jde += terms[i] * a
+	}
+	return jde
+}
+
+// julianDay ... Julian Day of the time
+func julianDay(t time.Time) float64 {
+	return float64(t.Unix())/86400 + 2440587.5
+}
+
+// fromJulianDay ... time of the Julian Day, the difference of about a minute between ephemeris and universal time
+// is ignored
+func fromJulianDay(jd float64) time.Time {
+	return time.Unix(int64(math.Round((jd-2440587.5)*86400)), 0)
+}
+
+// NextMoonPhase ... first new moon after the time, the first full moon if full is set
+func NextMoonPhase(after time.Time, full bool) time.Time {
+	jd := julianDay(after)
+	// start a lunation early, as the true phases differ from the mean ones by up to 14 hours
+	k := math.Floor((jd-2451550.09766)/SynodicMonth) - 1
+	for {
+		phase := moonPhaseJDE(k, full)
+		if phase > jd {
+			return fromJulianDay(phase)
+		}
+		k++
+	}
+}
+
+// MoonPhaseAt ... phase of the moon at the time, the fraction of the time from the last new moon to the full moon
+// in the first half of the lunation, from the full moon to the next new moon in the second, as the moon moves faster
+// close to the earth
+func MoonPhaseAt(t time.Time) Phase {
+	// a lunation lasts between 29.27 and 29.83 days, so the first new moon 30 days before the next one is the last one
+	next := NextMoonPhase(t, false)
+	previous := NextMoonPhase(next.Add(-30*24*time.Hour), false)
+	full := NextMoonPhase(previous, true)
+	if t.Before(full) {
+		return Phase(0.5 * float64(t.Sub(previous)) / float64(full.Sub(previous)))
+	}
+	return Phase(0.5 + 0.5*float64(t.Sub(full))/float64(next.Sub(full)))
+}
+
+// moonPhaseOfDay ... phase of the moon at noon of the day, the exact quarter if the moon reaches it during the day,
+// as in the forecast of the API
+func moonPhaseOfDay(day time.Time) Phase {
+	y, m, d := day.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+	from, to := MoonPhaseAt(start), MoonPhaseAt(end)
+	if to < from {
+		// new moon during the day
+		return 0
+	}
+	for _, quarter := range []Phase{0.25, 0.5, 0.75} {
+		if from < quarter && to >= quarter {
+			return quarter
+		}
+	}
+	return MoonPhaseAt(start.Add(12 * time.Hour))
+}
+
+// nextMoonsAfter ... time the next full and new moon of the forecast follow, the start of its first day,
+// now if that is today
+func (f Forecast) nextMoonsAfter(now time.Time) time.Time {
+	if len(f.Daily) == 0 {
+		return now
+	}
+	first, err := time.ParseInLocation("02.01.2006", f.Daily[0].Day, time.Local)
+	if err != nil || (!now.Before(first) && now.Before(first.AddDate(0, 0, 1))) {
+		return now
+	}
+	return first
+}
+
+// MoonForecast ... forecast of the days from the date on with the moon phases computed without the weather API,
+// moonrise and moonset are "--:--"
+func MoonForecast(date time.Time, days int) Forecast {
+	f := Forecast{Daily: []ForecastDaily{}}
+	for i := 0; i < days; i++ {
+		d := date.AddDate(0, 0, i)
+		f.Daily = append(f.Daily, ForecastDaily{
+			Day:       d.Format("02.01.2006"),
+			Moonrise:  "--:--",
+			Moonset:   "--:--",
+			Moonphase: moonPhaseOfDay(d),
+			Alerts:    []Alert{},
+		})
+	}
+	return f
+}
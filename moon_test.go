@@ -0,0 +1,119 @@
+package weather_test
+
+import (
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestPhase(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		phase        weather.Phase
+		illumination float64
+		waxing       bool
+		age          float64
+	}{
+		{0, 0, true, 0},
+		{0.25, 0.5, true, 7.38},
+		{0.5, 1, false, 14.77},
+		{0.75, 0.5, false, 22.15},
+	}
+	for _, tt := range tests {
+		p := tt.phase
+		if math.Abs(p.Illumination()-tt.illumination) > 0.001 || p.Waxing() != tt.waxing || math.Abs(p.Age()-tt.age) > 0.01 {
+			t.Errorf("phase %g: want %g, %t, %g, got %g, %t, %g", p, tt.illumination, tt.waxing, tt.age, p.Illumination(), p.Waxing(), p.Age())
+		}
+	}
+	want := "50 % beleuchtet, zunehmend, 7.4 Tage alt"
+	if got := weather.Phase(0.25).FormatMoon(); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestNextMoonPhase(t *testing.T) {
+	t.Parallel()
+	after := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		full bool
+		want time.Time
+	}{
+		{true, time.Date(2022, 7, 13, 18, 37, 0, 0, time.UTC)},
+		{false, time.Date(2022, 7, 28, 17, 54, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got := weather.NextMoonPhase(after, tt.full)
+		if d := got.Sub(tt.want); d < -5*time.Minute || d > 5*time.Minute {
+			t.Errorf("full %t: want %s, got %s", tt.full, tt.want, got.UTC())
+		}
+	}
+}
+
+func TestPhaseGlyph(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		phase weather.Phase
+		want  string
+	}{
+		{0, "🌑"},
+		{0.25, "🌓"},
+		{0.5, "🌕"},
+		{0.2, "🌒"},
+		{0.65, "🌖"},
+		{0.7034, "🌖"},
+		{0.75, "🌗"},
+		{0.81, "🌘"},
+		{0.97, "🌑"},
+		{1, "🌑"},
+	}
+	for _, tt := range tests {
+		if got := tt.phase.Glyph(); got != tt.want {
+			t.Errorf("phase %g: want %s, got %s", tt.phase, tt.want, got)
+		}
+	}
+}
+
+func TestMoonPhaseAt(t *testing.T) {
+	t.Parallel()
+	// full moon of 13 July 2022 at 18:37 UTC
+	if got := weather.MoonPhaseAt(time.Date(2022, 7, 13, 18, 37, 0, 0, time.UTC)); math.Abs(float64(got)-0.5) > 0.01 {
+		t.Errorf("want 0.5 at full moon, got %g", got)
+	}
+	if got := weather.MoonPhaseAt(time.Date(2022, 7, 28, 18, 0, 0, 0, time.UTC)); got > 0.01 {
+		t.Errorf("want 0 after new moon, got %g", got)
+	}
+}
+
+func TestMoonForecast(t *testing.T) {
+	t.Parallel()
+	f := weather.MoonForecast(time.Date(2022, 7, 12, 0, 0, 0, 0, time.UTC), 3)
+	if len(f.Daily) != 3 || f.Daily[1].Day != "13.07.2022" || f.Daily[1].Moonphase != 0.5 || f.Daily[1].Moonrise != "--:--" {
+		t.Fatalf("want the full moon on 13.07.2022, got %+v", f.Daily)
+	}
+	if p := f.Daily[0].Moonphase; p <= 0.4 || p >= 0.5 {
+		t.Errorf("want a waxing moon the day before, got %g", p)
+	}
+}
+
+func TestMoonDirectionOnce(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	tests := map[string]string{
+		"moon":    "abnehmender Mond (vor Halbmond), 86 % beleuchtet, 18.3 Tage alt\n",
+		"current": "abnehmender Mond (vor Halbmond) (86 % beleuchtet, 18.3 Tage alt)\n",
+	}
+	for cmd, want := range tests {
+		out, err := runCLI(t, ts, cmd, "Bonn,DE")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(out), want) {
+			t.Errorf("%s: want %q, got %s", cmd, want, out)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package weather
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	CommandNotify = "notify"
+
+	// kinds of notifications
+	NotifyAlert       = "alert"
+	NotifyRain        = "rain"
+	NotifyTemperature = "temperature"
+	NotifyFrost       = "frost"
+
+	// RainThreshold ... rain chance in percent from which an hour counts as rainy
+	RainThreshold = 50.0
+
+	// DefaultNtfyURL ... server used for ntfy topics without a configured server
+	DefaultNtfyURL = "https://ntfy.sh"
+)
+
+type (
+	// Notification ... message about a new weather alert or upcoming rain
+	Notification struct {
+		Kind     string `json:"kind"`
+		Location string `json:"location"`
+		Title    string `json:"title"`
+		Message  string `json:"message"`
+		// Alert is set for alert notifications, Rain for rain notifications,
+		// Temperature and Threshold for temperature notifications, Frost for frost notifications
+		Alert       *Alert          `json:"alert,omitempty"`
+		Rain        *ForecastHourly `json:"rain,omitempty"`
+		Temperature *float64        `json:"temperature,omitempty"`
+		Threshold   *float64        `json:"threshold,omitempty"`
+		Frost       *Frost          `json:"frost,omitempty"`
+	}
+
+	// Notifier ... delivers notifications, like Ntfy
+	Notifier interface {
+		Notify(n Notification) error
+	}
+
+	// NotifyState ... what was notified already, so every alert and rain period is only notified once
+	NotifyState struct {
+		Locations map[string]*NotifiedLocation `json:"locations"`
+	}
+
+	// NotifiedLocation ... notified alerts with their highest notified severity, the end of the notified rain period,
+	// the last temperature and the notified frost days of one location
+	NotifiedLocation struct {
+		Alerts          []string                 `json:"alerts"`
+		AlertSeverities map[string]AlertSeverity `json:"alert_severities,omitempty"`
+		RainUntil       time.Time                `json:"rain_until"`
+		Temperature     *float64                 `json:"temperature,omitempty"`
+		Frost           []string                 `json:"frost,omitempty"`
+	}
+
+	// Ntfy ... pushes notifications to a topic of ntfy.sh or a self-hosted ntfy server
+	Ntfy struct {
+		URL   string
+		Topic string
+		// Token is sent as bearer token for protected topics
+		Token      string
+		HTTPClient *http.Client
+	}
+
+	// NtfyConfig ... ntfy settings of the config file
+	NtfyConfig struct {
+		URL   string `json:"url,omitempty"`
+		Topic string `json:"topic"`
+		Token string `json:"token,omitempty"`
+	}
+)
+
+// NewNtfy ... notifier for a topic on ntfy.sh
+func NewNtfy(topic string) *Ntfy {
+	return &Ntfy{
+		URL:        DefaultNtfyURL,
+		Topic:      topic,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify ... publishes the notification, alerts with high priority
+func (n *Ntfy) Notify(note Notification) error {
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(n.URL, "/")+"/"+n.Topic, strings.NewReader(note.Message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", note.Title)
+	switch note.Kind {
+	case NotifyAlert:
+		req.Header.Set("Priority", "high")
+		req.Header.Set("Tags", "warning")
+	case NotifyRain:
+		req.Header.Set("Tags", "umbrella")
+	case NotifyFrost:
+		req.Header.Set("Tags", "snowflake")
+	}
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	return nil
+}
+
+// alertKey ... identifies an alert across refreshes
+func alertKey(a Alert) string {
+	return a.event() + "|" + a.Start
+}
+
+// location ... state of the location, created on first use
+func (s *NotifyState) location(location string) *NotifiedLocation {
+	if s.Locations == nil {
+		s.Locations = map[string]*NotifiedLocation{}
+	}
+	notified := s.Locations[location]
+	if notified == nil {
+		notified = &NotifiedLocation{}
+		s.Locations[location] = notified
+	}
+	return notified
+}
+
+// Check ... notifications for alerts of the location not notified yet or grown more severe since and for rain starting
+// within window, the state remembers them for the next check
+func (s *NotifyState) Check(location string, f Forecast, window time.Duration, now time.Time) []Notification {
+	notified := s.location(location)
+	notifications := []Notification{}
+
+	known := map[string]bool{}
+	for _, key := range notified.Alerts {
+		known[key] = true
+	}
+	severities := notified.AlertSeverities
+	// ended alerts are forgotten, so the state doesn't grow
+	notified.Alerts = []string{}
+	notified.AlertSeverities = map[string]AlertSeverity{}
+	for _, a := range f.CurrentAlerts() {
+		a := a
+		key := alertKey(a)
+		notified.Alerts = append(notified.Alerts, key)
+		notified.AlertSeverities[key] = a.Severity
+		title := "Wetterwarnung"
+		if known[key] {
+			// states of older versions lack the severities, their alerts aren't taken as escalated
+			previous, ok := severities[key]
+			if !ok {
+				continue
+			}
+			if a.Severity.Rank() <= previous.Rank() {
+				notified.AlertSeverities[key] = previous
+				continue
+			}
+			title = "Verschärfte Wetterwarnung"
+		}
+		notifications = append(notifications, Notification{
+			Kind:     NotifyAlert,
+			Location: location,
+			Title:    fmt.Sprintf("%s für %s: %s", title, location, a.Name),
+			Message:  fmt.Sprintf("%s bis %s\n%s", a.Start, a.End, a.Description),
+			Alert:    &a,
+		})
+	}
+
+	start, end, ok := rainPeriod(f.Hourly, now, window)
+	if ok {
+		// a period continuing the notified one extends it instead of being notified again
+		if start.Time.After(notified.RainUntil) {
+			rain := start
+			notifications = append(notifications, Notification{
+				Kind:     NotifyRain,
+				Location: location,
+				Title:    fmt.Sprintf("Regen in %s", location),
+				Message:  fmt.Sprintf("Ab %s Uhr Regen (%.0f %%)", start.Hour, start.RainChance),
+				Rain:     &rain,
+			})
+		}
+		notified.RainUntil = end
+	}
+	return notifications
+}
+
+// CheckTemperature ... notifications for the thresholds crossed since the last check of the location,
+// the first check only remembers the temperature
+func (s *NotifyState) CheckTemperature(location string, c Conditions, unit string, thresholds []float64) []Notification {
+	notified := s.location(location)
+	notifications := []Notification{}
+	temperature := c.Temperature
+	if notified.Temperature != nil {
+		last := *notified.Temperature
+		for _, threshold := range thresholds {
+			threshold := threshold
+			var verb string
+			switch {
+			case last < threshold && temperature >= threshold:
+				verb = "gestiegen"
+			case last >= threshold && temperature < threshold:
+				verb = "gefallen"
+			default:
+				continue
+			}
+			notifications = append(notifications, Notification{
+				Kind:        NotifyTemperature,
+				Location:    location,
+				Title:       fmt.Sprintf("Temperatur in %s", location),
+				Message:     fmt.Sprintf("Die Temperatur ist auf %.1f %s %s (Schwelle %g %s)", temperature, unit, verb, threshold, unit),
+				Temperature: &temperature,
+				Threshold:   &threshold,
+			})
+		}
+	}
+	notified.Temperature = &temperature
+	return notifications
+}
+
+// rainPeriod ... first rainy hour starting within window from now and the end of its period
+func rainPeriod(hourly []ForecastHourly, now time.Time, window time.Duration) (ForecastHourly, time.Time, bool) {
+	for i, slot := range hourly {
+		// the current hour started before now, but still counts
+		if slot.Time.Add(time.Hour).Before(now) || slot.RainChance < RainThreshold {
+			continue
+		}
+		if slot.Time.After(now.Add(window)) {
+			break
+		}
+		end := slot.Time.Add(time.Hour)
+		for _, next := range hourly[i+1:] {
+			if next.RainChance < RainThreshold {
+				break
+			}
+			end = next.Time.Add(time.Hour)
+		}
+		return slot, end, true
+	}
+	return ForecastHourly{}, time.Time{}, false
+}
+
+// NotifyStatePath ... state of the notify command, next to the config file
+func NotifyStatePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "notify.json")
+}
+
+// LoadNotifyState ... reads the state file, a missing file results in an empty state
+func LoadNotifyState(path string) (NotifyState, error) {
+	var s NotifyState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return NotifyState{}, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// SaveNotifyState ... writes the state file, missing directories are created
+func SaveNotifyState(path string, s NotifyState) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// notifiers ... notifiers configured by the flags and the config file, at least one
+func (env *cliEnv) notifiers(opts Options) ([]Notifier, error) {
+	notifiers := env.configuredNotifiers(opts)
+	if len(notifiers) == 0 {
+		return nil, errors.New("no notifier configured, use --ntfy TOPIC, --webhook URL, --desktop or set ntfy, webhook or telegram in the config file")
+	}
+	return notifiers, nil
+}
+
+// configuredNotifiers ... notifiers configured by the flags and the config file, maybe none
+func (env *cliEnv) configuredNotifiers(opts Options) []Notifier {
+	notifiers := []Notifier{}
+	ntfy := env.cfg.Ntfy
+	if opts.Ntfy != "" {
+		ntfy = &NtfyConfig{Topic: opts.Ntfy}
+		if env.cfg.Ntfy != nil {
+			ntfy.URL, ntfy.Token = env.cfg.Ntfy.URL, env.cfg.Ntfy.Token
+		}
+	}
+	if ntfy != nil && ntfy.Topic != "" {
+		n := NewNtfy(ntfy.Topic)
+		if ntfy.URL != "" {
+			n.URL = ntfy.URL
+		}
+		n.Token = ntfy.Token
+		notifiers = append(notifiers, n)
+	}
+	if opts.Desktop {
+		notifiers = append(notifiers, NewDesktop())
+	}
+	webhook := env.cfg.Webhook
+	if opts.Webhook != "" {
+		webhook = &WebhookConfig{URL: opts.Webhook}
+		if env.cfg.Webhook != nil {
+			webhook.Headers, webhook.Retries = env.cfg.Webhook.Headers, env.cfg.Webhook.Retries
+		}
+	}
+	if webhook != nil && webhook.URL != "" {
+		w := NewWebhook(webhook.URL)
+		w.Headers = webhook.Headers
+		if webhook.Retries > 0 {
+			w.Retries = webhook.Retries
+		}
+		notifiers = append(notifiers, w)
+	}
+	if t := env.cfg.Telegram; t != nil && t.Token != "" && t.ChatID != "" {
+		notifiers = append(notifiers, NewTelegram(t.Token, t.ChatID))
+	}
+	return notifiers
+}
+
+// notifyLocation ... name of the location in notifications and the state
+func notifyLocation(opts Options, f Forecast) string {
+	switch {
+	case f.Place != "":
+		return f.Place
+	case opts.Zip != "":
+		return opts.Zip
+	case opts.Location != "":
+		return strings.ReplaceAll(opts.Location, "+", " ")
+	}
+	return "hier"
+}
+
+// send ... delivers the notifications to all notifiers, failing notifiers don't stop the others
+func send(notifiers []Notifier, notifications []Notification) error {
+	var errs []string
+	for _, n := range notifications {
+		for _, notifier := range notifiers {
+			err := notifier.Notify(n)
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notification failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// notifyFlags ... flags of the notifying commands
+func notifyFlags(fs *flag.FlagSet, opts *Options) {
+	opts.RainWithin = 2 * time.Hour
+	fs.DurationVar(&opts.RainWithin, "rain-within", opts.RainWithin, "notify of rain starting within this time")
+	fs.StringVar(&opts.Ntfy, "ntfy", "", "ntfy topic to push to, defaults to ntfy.topic of the config file")
+	fs.StringVar(&opts.Webhook, "webhook", "", "URL to post the notifications to as JSON, defaults to webhook.url of the config file")
+	fs.BoolVar(&opts.Desktop, "desktop", false, "raise desktop notifications")
+	fs.BoolVar(&opts.Briefing, "briefing", false, "send a briefing of today's weather first")
+}
+
+func validateNotify(fs *flag.FlagSet, opts *Options) error {
+	if opts.RainWithin < time.Hour || opts.RainWithin > 48*time.Hour {
+		return fmt.Errorf("invalid rain window %s, want between 1h and 48h", opts.RainWithin)
+	}
+	return nil
+}
+
+func runNotify(env *cliEnv, opts Options) error {
+	notifiers, err := env.notifiers(opts)
+	if err != nil {
+		return err
+	}
+	conditions, forecast, err := env.fetch(opts)
+	if err != nil {
+		return err
+	}
+	path := NotifyStatePath(env.cfgPath)
+	state, err := LoadNotifyState(path)
+	if err != nil {
+		return err
+	}
+	location := notifyLocation(opts, forecast)
+	notifications := state.Check(location, forecast, opts.RainWithin, time.Now())
+	notifications = append(notifications, state.CheckTemperature(location, conditions, forecast.TemperatureUnit(), env.cfg.TemperatureThresholds)...)
+	notifications = append(notifications, state.CheckFrost(location, forecast, env.cfg.FrostLimit())...)
+	if opts.Briefing {
+		notifications = append([]Notification{Briefing(location, conditions, forecast)}, notifications...)
+	}
+	if opts.Format == FormatJSON {
+		err = printJSON(os.Stdout, notifications)
+	} else {
+		for _, n := range notifications {
+			fmt.Println(n.Title)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	err = send(notifiers, notifications)
+	if err != nil {
+		return err
+	}
+	return SaveNotifyState(path, state)
+}
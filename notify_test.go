@@ -0,0 +1,254 @@
+package weather_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+// rainForecast ... hourly forecast starting at start with the given rain chances
+func rainForecast(start time.Time, chances ...float64) weather.Forecast {
+	f := weather.Forecast{Daily: []weather.ForecastDaily{{}}}
+	for i, chance := range chances {
+		t := start.Add(time.Duration(i) * time.Hour)
+		f.Hourly = append(f.Hourly, weather.ForecastHourly{Time: t, Hour: t.Format("15:04"), RainChance: chance})
+	}
+	return f
+}
+
+func kinds(notifications []weather.Notification) []string {
+	got := []string{}
+	for _, n := range notifications {
+		got = append(got, n.Kind)
+	}
+	return got
+}
+
+func TestNotifyStateAlerts(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC)
+	f := rainForecast(now, 0, 0)
+	f.Daily[0].Alerts = []weather.Alert{{Name: "Hitze", Start: "17.06.2022, 12:00"}}
+	var s weather.NotifyState
+
+	got := s.Check("Bonn", f, 2*time.Hour, now)
+	if len(got) != 1 || got[0].Kind != weather.NotifyAlert || got[0].Title != "Wetterwarnung für Bonn: Hitze" {
+		t.Errorf("want alert notification, got %+v", got)
+	}
+	got = s.Check("Bonn", f, 2*time.Hour, now)
+	if len(got) != 0 {
+		t.Errorf("want known alert not notified again, got %+v", got)
+	}
+	got = s.Check("Köln", f, 2*time.Hour, now)
+	if len(got) != 1 {
+		t.Errorf("want alert notified for another location, got %+v", got)
+	}
+	f.Daily[0].Alerts = append(f.Daily[0].Alerts, weather.Alert{Name: "Gewitter", Start: "17.06.2022, 18:00"})
+	got = s.Check("Bonn", f, 2*time.Hour, now)
+	if len(got) != 1 || got[0].Alert.Name != "Gewitter" {
+		t.Errorf("want only the new alert, got %+v", got)
+	}
+	// a translated alert is still the one notified before
+	f.Daily[0].Alerts[1] = weather.Alert{Name: "Thunderstorm", Event: "Gewitter", Start: "17.06.2022, 18:00"}
+	got = s.Check("Bonn", f, 2*time.Hour, now)
+	if len(got) != 0 {
+		t.Errorf("want the translated alert not notified again, got %+v", got)
+	}
+}
+
+func TestNotifyStateRain(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 6, 17, 12, 30, 0, 0, time.UTC)
+	start := now.Truncate(time.Hour)
+	tests := map[string]struct {
+		chances []float64
+		want    []string
+	}{
+		"dry":           {[]float64{0, 10, 20, 30}, []string{}},
+		"current hour":  {[]float64{80, 10}, []string{weather.NotifyRain}},
+		"within window": {[]float64{0, 0, 60, 70}, []string{weather.NotifyRain}},
+		"after window":  {[]float64{0, 0, 0, 90}, []string{}},
+		"below limit":   {[]float64{0, 49, 0}, []string{}},
+	}
+	for name, tc := range tests {
+		var s weather.NotifyState
+		got := kinds(s.Check("Bonn", rainForecast(start, tc.chances...), 2*time.Hour, now))
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("%s: %s", name, cmp.Diff(tc.want, got))
+		}
+	}
+}
+
+func TestNotifyStateRainPeriodOnce(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC)
+	var s weather.NotifyState
+	got := s.Check("Bonn", rainForecast(start, 0, 60, 70, 0, 0, 0), 2*time.Hour, start)
+	if len(got) != 1 || got[0].Message != "Ab 13:00 Uhr Regen (60 %)" {
+		t.Errorf("want rain notification, got %+v", got)
+	}
+	// an hour later it rains and the period goes on longer than forecast before
+	got = s.Check("Bonn", rainForecast(start.Add(time.Hour), 60, 70, 80, 0, 0), 2*time.Hour, start.Add(time.Hour))
+	if len(got) != 0 {
+		t.Errorf("want the running rain period not notified again, got %+v", got)
+	}
+	// after a dry hour the next period is notified
+	got = s.Check("Bonn", rainForecast(start.Add(4*time.Hour), 0, 90), 2*time.Hour, start.Add(4*time.Hour))
+	if len(got) != 1 {
+		t.Errorf("want the next rain period notified, got %+v", got)
+	}
+}
+
+func TestNotifyStateFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "weather", "notify.json")
+	s, err := weather.LoadNotifyState(path)
+	if err != nil {
+		t.Fatalf("want empty state for missing file, got %v", err)
+	}
+	f := rainForecast(time.Now(), 90)
+	f.Daily[0].Alerts = []weather.Alert{{Name: "Hitze"}}
+	s.Check("Bonn", f, time.Hour, time.Now())
+	err = weather.SaveNotifyState(path, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := weather.LoadNotifyState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := loaded.Check("Bonn", f, time.Hour, time.Now())
+	if len(got) != 0 {
+		t.Errorf("want nothing new after reloading the state, got %+v", got)
+	}
+}
+
+func TestNtfy(t *testing.T) {
+	t.Parallel()
+	var got *http.Request
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wetter-bonn" {
+			http.NotFound(w, r)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		got, body = r, string(data)
+	}))
+	defer ts.Close()
+	n := weather.NewNtfy("wetter-bonn")
+	n.URL = ts.URL
+	n.Token = "secret"
+	err := n.Notify(weather.Notification{Kind: weather.NotifyAlert, Title: "Wetterwarnung für Bonn: Hitze", Message: "bis morgen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Method != http.MethodPost || got.URL.Path != "/wetter-bonn" || body != "bis morgen" {
+		t.Errorf("unexpected request %s %s with body %q", got.Method, got.URL.Path, body)
+	}
+	headers := map[string]string{
+		"Title":         "Wetterwarnung für Bonn: Hitze",
+		"Priority":      "high",
+		"Tags":          "warning",
+		"Authorization": "Bearer secret",
+	}
+	for name, want := range headers {
+		if got.Header.Get(name) != want {
+			t.Errorf("%s: want %q, got %q", name, want, got.Header.Get(name))
+		}
+	}
+
+	n.Topic = "missing"
+	err = n.Notify(weather.Notification{})
+	if err == nil {
+		t.Error("want error for status 404, but got nil")
+	}
+}
+
+func TestParseOptionsNotify(t *testing.T) {
+	t.Parallel()
+	opts, err := weather.ParseOptions("notify", []string{"Bonn", "--rain-within", "3h", "--ntfy", "wetter"}, weather.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.RainWithin != 3*time.Hour || opts.Ntfy != "wetter" || opts.Location != "Bonn" {
+		t.Errorf("unexpected options %+v", opts)
+	}
+	_, err = weather.ParseOptions("notify", []string{"--rain-within", "30m"}, weather.Config{})
+	if err == nil {
+		t.Error("want error for a rain window below one hour")
+	}
+}
+
+func TestNotifyStateTemperature(t *testing.T) {
+	t.Parallel()
+	thresholds := []float64{0, 30}
+	var s weather.NotifyState
+	steps := []struct {
+		temperature float64
+		want        []float64
+	}{
+		{temperature: 25},
+		{temperature: 31, want: []float64{30}},
+		{temperature: 32},
+		{temperature: -2, want: []float64{0, 30}},
+		{temperature: 0, want: []float64{0}},
+	}
+	for _, step := range steps {
+		got := []float64{}
+		for _, n := range s.CheckTemperature("Bonn", weather.Conditions{Temperature: step.temperature}, "°C", thresholds) {
+			got = append(got, *n.Threshold)
+		}
+		if step.want == nil {
+			step.want = []float64{}
+		}
+		if !cmp.Equal(step.want, got) {
+			t.Errorf("%.0f °C: %s", step.temperature, cmp.Diff(step.want, got))
+		}
+	}
+}
+
+func TestNotifyStateEscalatedAlerts(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC)
+	f := rainForecast(now, 0, 0)
+	storm := func(severity weather.AlertSeverity) []weather.Alert {
+		return []weather.Alert{{Name: "Sturm", Start: "17.06.2022, 18:00", Severity: severity}}
+	}
+	var s weather.NotifyState
+	f.Daily[0].Alerts = storm(weather.AlertModerate)
+	if got := s.Check("Bonn", f, time.Hour, now); len(got) != 1 {
+		t.Fatalf("want the new alert, got %+v", got)
+	}
+	f.Daily[0].Alerts = storm(weather.AlertSevere)
+	got := s.Check("Bonn", f, time.Hour, now)
+	if len(got) != 1 || got[0].Title != "Verschärfte Wetterwarnung für Bonn: Sturm" {
+		t.Errorf("want the escalated alert, got %+v", got)
+	}
+	f.Daily[0].Alerts = storm(weather.AlertModerate)
+	if got := s.Check("Bonn", f, time.Hour, now); len(got) != 0 {
+		t.Errorf("want a milder alert not notified, got %+v", got)
+	}
+	f.Daily[0].Alerts = storm(weather.AlertSevere)
+	if got := s.Check("Bonn", f, time.Hour, now); len(got) != 0 {
+		t.Errorf("want the notified severity not notified again, got %+v", got)
+	}
+
+	// a state without severities
+	old := weather.NotifyState{Locations: map[string]*weather.NotifiedLocation{
+		"Bonn": {Alerts: []string{"Sturm|17.06.2022, 18:00"}},
+	}}
+	if got := old.Check("Bonn", f, time.Hour, now); len(got) != 0 {
+		t.Errorf("want the known alert of an old state not notified, got %+v", got)
+	}
+	f.Daily[0].Alerts = storm(weather.AlertExtreme)
+	if got := old.Check("Bonn", f, time.Hour, now); len(got) != 1 {
+		t.Errorf("want the escalation after the old state, got %+v", got)
+	}
+}
@@ -0,0 +1,100 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const FunctionNowcast = "nowcast"
+
+type (
+	// ForecastMinutely ... precipitation in mm/h of one minute of the next hour
+	ForecastMinutely struct {
+		Time          time.Time `json:"time"`
+		Precipitation float64   `json:"precipitation"`
+	}
+
+	// Nowcast ... whether it rains now and when that changes within the minutely forecast
+	Nowcast struct {
+		Raining bool `json:"raining"`
+		// Change is the first minute the rain starts or stops, nil if it doesn't change
+		Change *time.Time `json:"change,omitempty"`
+	}
+)
+
+// sparkBlocks ... bars of a sparkline, from no to full height
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// GetNowcast ... start or end of the rain within the minutely forecast
+func GetNowcast(f Forecast) (Nowcast, error) {
+	if len(f.Minutely) == 0 {
+		return Nowcast{}, errors.New("no minutely forecast available for the location")
+	}
+	n := Nowcast{Raining: f.Minutely[0].Precipitation > 0}
+	for _, m := range f.Minutely[1:] {
+		if (m.Precipitation > 0) != n.Raining {
+			change := m.Time
+			n.Change = &change
+			break
+		}
+	}
+	return n, nil
+}
+
+// Describe ... the nowcast in words, minutes are counted from now
+func (n Nowcast) Describe(now time.Time) string {
+	switch {
+	case n.Change == nil && n.Raining:
+		return "Es regnet die ganze nächste Stunde."
+	case n.Change == nil:
+		return "In der nächsten Stunde regnet es nicht."
+	}
+	minutes := int(math.Ceil(n.Change.Sub(now).Minutes()))
+	if minutes < 0 {
+		minutes = 0
+	}
+	if n.Raining {
+		return fmt.Sprintf("Es regnet noch bis %s (in %d Minuten).", n.Change.Format("15:04"), minutes)
+	}
+	return fmt.Sprintf("Ab %s regnet es (in %d Minuten).", n.Change.Format("15:04"), minutes)
+}
+
+// sparkline ... one bar per value, top is the value of a full bar, higher values are cut
+func sparkline(values []float64, top float64) string {
+	var b strings.Builder
+	for _, v := range values {
+		i := int(math.Round(math.Min(v, top) / top * float64(len(sparkBlocks)-1)))
+		if i < 0 {
+			i = 0
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// PrintNowcast ... start or end of the rain within the next hour with the precipitation of every minute
+func PrintNowcast(f Forecast) error {
+	n, err := GetNowcast(f)
+	if err != nil {
+		return err
+	}
+	first, last := f.Minutely[0], f.Minutely[len(f.Minutely)-1]
+	fmt.Println()
+	printHeader(fmt.Sprintf("Niederschlag von %s - %s", first.Time.Format("15:04"), last.Time.Format("15:04")), f)
+	fmt.Println(n.Describe(time.Now()))
+	fmt.Println()
+	values := []float64{}
+	max := 0.0
+	for _, m := range f.Minutely {
+		values = append(values, m.Precipitation)
+		max = math.Max(max, m.Precipitation)
+	}
+	// scaled to the maximum, but at least to 1 mm/h, so light rain stays low
+	fmt.Printf("%s %s %s\n", first.Time.Format("15:04"), sparkline(values, math.Max(max, 1)), last.Time.Format("15:04"))
+	fmt.Printf("höchstens %.1f mm/h\n", max)
+	fmt.Println()
+	return nil
+}
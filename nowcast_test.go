@@ -0,0 +1,70 @@
+package weather_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetNowcast(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 6, 17, 14, 0, 0, 0, time.UTC)
+	minutely := func(precipitation ...float64) weather.Forecast {
+		f := weather.Forecast{}
+		for i, p := range precipitation {
+			f.Minutely = append(f.Minutely, weather.ForecastMinutely{Time: now.Add(time.Duration(i) * time.Minute), Precipitation: p})
+		}
+		return f
+	}
+	tests := map[string]struct {
+		f        weather.Forecast
+		want     string
+		wantErr  bool
+		wantRain bool
+	}{
+		"dry":       {f: minutely(0, 0, 0), want: "In der nächsten Stunde regnet es nicht."},
+		"starts":    {f: minutely(0, 0, 0.2, 0.5), want: "Ab 14:02 regnet es (in 2 Minuten)."},
+		"stops":     {f: minutely(1.5, 0.4, 0, 0), want: "Es regnet noch bis 14:02 (in 2 Minuten).", wantRain: true},
+		"raining":   {f: minutely(0.1, 0.3), want: "Es regnet die ganze nächste Stunde.", wantRain: true},
+		"no values": {f: weather.Forecast{}, wantErr: true},
+	}
+	for name, tc := range tests {
+		n, err := weather.GetNowcast(tc.f)
+		if tc.wantErr != (err != nil) {
+			t.Errorf("%s: want error %v, got %v", name, tc.wantErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if tc.wantRain != n.Raining {
+			t.Errorf("%s: want raining %v, got %v", name, tc.wantRain, n.Raining)
+		}
+		got := n.Describe(now)
+		if tc.want != got {
+			t.Errorf("%s: want %q, got %q", name, tc.want, got)
+		}
+	}
+}
+
+func TestMinutelyFromParseWeatherResponse(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/weather_30.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, f, err := weather.ParseWeatherResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Minutely) != 61 {
+		t.Fatalf("want 61 minutes, got %d", len(f.Minutely))
+	}
+	want := weather.ForecastMinutely{Time: time.Unix(1655479440, 0), Precipitation: 0.115}
+	if !cmp.Equal(want, f.Minutely[0]) {
+		t.Error(cmp.Diff(want, f.Minutely[0]))
+	}
+}
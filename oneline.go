@@ -0,0 +1,127 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	FunctionOneline = "oneline"
+
+	// DefaultOnelineTemplate ... line of the oneline command like "Berlin: 🌦 +18°C ↗12km/h 64%"
+	DefaultOnelineTemplate = "%l: %c %t %w %h"
+)
+
+// windArrows ... arrows pointing where the wind blows to, for the directions it comes from in steps of 45° from north
+var windArrows = [8]string{"↓", "↙", "←", "↖", "↑", "↗", "→", "↘"}
+
+// Arrow ... arrow pointing where the wind of the direction blows to, e.g. ↓ for wind from the north
+func (d Direction) Arrow() string {
+	return windArrows[int(math.Round(math.Mod(float64(d), 360)/45))%8]
+}
+
+// Emoji ... symbol of the weather condition like 🌦, the clear sky at night is the moon
+func (c Conditions) Emoji() string {
+	id := c.Condition
+	switch {
+	case id.IsThunderstorm():
+		return "⛈"
+	case id.IsDrizzle():
+		return "🌦"
+	case id.IsSnow():
+		return "🌨"
+	case id.IsRain():
+		return "🌧"
+	case id.IsAtmosphere():
+		return "🌫"
+	case id == 800 && strings.HasSuffix(c.Icon, "n"):
+		return "🌙"
+	case id == 800:
+		return "☀"
+	case id == 801:
+		return "🌤"
+	case id == 802:
+		return "⛅"
+	case id > 802:
+		return "☁"
+	}
+	return ""
+}
+
+// onelineFlags ... flags of the oneline command
+func onelineFlags(fs *flag.FlagSet, opts *Options) {
+	opts.Template = DefaultOnelineTemplate
+	templateFlag(fs, opts)
+}
+
+// templateFlag ... --template of the commands printing the line of FormatOneline
+func templateFlag(fs *flag.FlagSet, opts *Options) {
+	fs.StringVar(&opts.Template, "template", opts.Template,
+		"line with the placeholders %l place, %c symbol, %C summary, %t temperature, %f feels like, %w wind, "+
+			"%h humidity, %p rain chance, %P pressure, %u UV index and %m moon")
+}
+
+// FormatOneline ... the weather in one line for prompts and status bars, the template takes
+// %l place, %c symbol, %C summary, %t temperature, %f feels like, %w wind, %h humidity,
+// %p rain chance of today, %P pressure, %u UV index, %m moon and %% for a percent sign like wttr.in
+func FormatOneline(template string, c Conditions, f Forecast) string {
+	unit := f.TemperatureUnit()
+	// the place without the country, e.g. Berlin of "Berlin, DE"
+	place := strings.TrimSpace(strings.SplitN(f.Place, ",", 2)[0])
+	rain := 0.0
+	if len(f.Daily) > 0 {
+		rain = f.Daily[0].RainChance
+	}
+	var b strings.Builder
+	runes := []rune(template)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' || i+1 == len(runes) {
+			b.WriteRune(runes[i])
+			continue
+		}
+		i++
+		switch runes[i] {
+		case 'l':
+			b.WriteString(place)
+		case 'c':
+			b.WriteString(c.Emoji())
+		case 'C':
+			b.WriteString(c.Summary)
+		case 't':
+			b.WriteString(signedDegrees(c.Temperature) + unit)
+		case 'f':
+			b.WriteString(signedDegrees(c.FeelsLike) + unit)
+		case 'w':
+			b.WriteString(c.WindDirection.Arrow() + strings.ReplaceAll(f.FormatSpeed(c.WindSpeed), " ", ""))
+		case 'h':
+			fmt.Fprintf(&b, "%d%%", c.Humidity)
+		case 'p':
+			fmt.Fprintf(&b, "%.0f%%", rain)
+		case 'P':
+			fmt.Fprintf(&b, "%dhPa", c.Pressure)
+		case 'u':
+			fmt.Fprintf(&b, "%.0f", float64(c.UVI))
+		case 'm':
+			b.WriteString(MoonPhaseAt(time.Now()).Glyph())
+		case '%':
+			b.WriteRune('%')
+		default:
+			// unknown placeholders are kept as they are
+			b.WriteRune('%')
+			b.WriteRune(runes[i])
+		}
+	}
+	return b.String()
+}
+
+// signedDegrees ... whole degrees with their sign like +18, -0.4 is +0
+func signedDegrees(t float64) string {
+	t = math.Round(t)
+	if t == 0 {
+		t = 0
+	}
+	return fmt.Sprintf("%+.0f", t)
+}
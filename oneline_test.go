@@ -0,0 +1,48 @@
+package weather_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestFormatOneline(t *testing.T) {
+	t.Parallel()
+	c := weather.Conditions{Temperature: 17.6, FeelsLike: -0.4, WindSpeed: 10 / 3.6, WindDirection: 225, Humidity: 64,
+		Pressure: 1013, Summary: "leichter Regen", Condition: 500}
+	f := weather.Forecast{Place: "Berlin, DE", Units: weather.UnitsMetric, Daily: []weather.ForecastDaily{{RainChance: 80}}}
+	tests := map[string]string{
+		weather.DefaultOnelineTemplate: "Berlin: 🌧 +18°C ↗10km/h 64%",
+		"%C, gefühlt %f, %p Regen":     "leichter Regen, gefühlt +0°C, 80% Regen",
+		"%P 100%% %x":                  "1013hPa 100% %x",
+	}
+	for template, want := range tests {
+		if got := weather.FormatOneline(template, c, f); got != want {
+			t.Errorf("%q: want %q, got %q", template, want, got)
+		}
+	}
+}
+
+func TestDirectionArrow(t *testing.T) {
+	t.Parallel()
+	for d, want := range map[weather.Direction]string{0: "↓", 90: "←", 200: "↑", 270: "→", 359: "↓"} {
+		if got := d.Arrow(); got != want {
+			t.Errorf("%v°: want %s, got %s", float64(d), want, got)
+		}
+	}
+}
+
+func TestOnelineCommand(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	out, err := runCLI(t, ts, "oneline", "--template", "%l %t", "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(out)), "\n"); len(lines) != 1 || !strings.HasPrefix(lines[0], "Bad Schnuffel +") {
+		t.Errorf("want one line with place and temperature, got %q", out)
+	}
+}
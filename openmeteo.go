@@ -0,0 +1,202 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOpenMeteoURL and DefaultOpenMeteoGeoURL ... base URLs of the forecast and the geocoding API of Open-Meteo
+	DefaultOpenMeteoURL    = "https://api.open-meteo.com"
+	DefaultOpenMeteoGeoURL = "https://geocoding-api.open-meteo.com"
+
+	// ProviderOpenMeteo ... name of the Open-Meteo data source, also the source of a provider in the config file
+	ProviderOpenMeteo = "open-meteo"
+	// openMeteoForecastDays ... days of the forecast, as many as One Call has
+	openMeteoForecastDays = 8
+)
+
+type (
+	// OpenMeteo ... Provider of the forecast of Open-Meteo, which needs no API key and is computed from other weather
+	// models than OpenWeatherMap. It has the current conditions and the daily forecast only, without alerts
+	OpenMeteo struct {
+		BaseURL    string
+		GeoURL     string
+		Units      string
+		HTTPClient *http.Client
+	}
+
+	OpenMeteoResponse struct {
+		UTCOffset      int `json:"utc_offset_seconds"`
+		CurrentWeather struct {
+			Time          string
+			Temperature   float64
+			WindSpeed     Speed     `json:"windspeed"`
+			WindDirection Direction `json:"winddirection"`
+		} `json:"current_weather"`
+		Daily struct {
+			Time           []string
+			TemperatureMax []float64  `json:"temperature_2m_max"`
+			TemperatureMin []float64  `json:"temperature_2m_min"`
+			RainChance     []*float64 `json:"precipitation_probability_max"`
+			Rain           []float64  `json:"rain_sum"`
+		}
+	}
+
+	OpenMeteoGeoResponse struct {
+		Results []struct {
+			Latitude    float64
+			Longitude   float64
+			CountryCode string `json:"country_code"`
+		}
+	}
+)
+
+// NewOpenMeteo ... Open-Meteo provider for the units
+func NewOpenMeteo(units string) *OpenMeteo {
+	return &OpenMeteo{
+		BaseURL:    DefaultOpenMeteoURL,
+		GeoURL:     DefaultOpenMeteoGeoURL,
+		Units:      units,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ParseOpenMeteoResponse ... current temperature and wind and the daily temperatures, rain chance and rain of Open-Meteo,
+// in the units they were requested with, see OpenMeteo.FormatURL. Kelvin isn't offered by Open-Meteo, so standard
+// units are converted from °C. Days without a rain chance, which some models lack, get a chance of 0.
+func ParseOpenMeteoResponse(data []byte, units string) (Conditions, Forecast, error) {
+	var resp OpenMeteoResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: %w", data, err)
+	}
+	d := resp.Daily
+	if len(d.Time) == 0 || len(d.TemperatureMax) < len(d.Time) || len(d.TemperatureMin) < len(d.Time) {
+		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: want daily temperatures", data)
+	}
+	temperature := func(t float64) float64 {
+		if units == UnitsStandard {
+			return t + 273.15
+		}
+		return t
+	}
+	conditions := Conditions{
+		Temperature:   temperature(resp.CurrentWeather.Temperature),
+		WindSpeed:     resp.CurrentWeather.WindSpeed,
+		WindDirection: resp.CurrentWeather.WindDirection,
+	}
+	// the time is that of the location, which is UTCOffset ahead of UTC
+	if t, err := time.Parse("2006-01-02T15:04", resp.CurrentWeather.Time); err == nil {
+		conditions.Timestamp = t.Add(-time.Duration(resp.UTCOffset) * time.Second).Local().Format("02.01.2006 15:04 MST")
+	}
+	forecast := Forecast{Units: units, Minutely: []ForecastMinutely{}, Hourly: []ForecastHourly{}, Daily: []ForecastDaily{}}
+	for i, day := range d.Time {
+		t, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return Conditions{}, Forecast{}, fmt.Errorf("invalid API response: day %q: %w", day, err)
+		}
+		daily := ForecastDaily{
+			Day:    t.Format("02.01.2006"),
+			Temp:   DailyTempBenchmarks{Min: temperature(d.TemperatureMin[i]), Max: temperature(d.TemperatureMax[i])},
+			Alerts: []Alert{},
+		}
+		if i < len(d.RainChance) && d.RainChance[i] != nil {
+			daily.RainChance = *d.RainChance[i]
+		}
+		if i < len(d.Rain) {
+			daily.Rain = d.Rain[i]
+		}
+		forecast.Daily = append(forecast.Daily, daily)
+	}
+	return conditions, forecast, nil
+}
+
+// FormatURL ... forecast URL of the coordinates in the units of the provider
+func (o *OpenMeteo) FormatURL(coordinates Coordinates) string {
+	units := "&windspeed_unit=ms"
+	if o.Units == UnitsImperial {
+		units = "&temperature_unit=fahrenheit&windspeed_unit=mph"
+	}
+	return fmt.Sprintf("%s/v1/forecast?latitude=%g&longitude=%g&current_weather=true&daily=%s&timezone=auto&forecast_days=%d%s",
+		o.BaseURL, coordinates.Lat, coordinates.Lon,
+		"temperature_2m_max,temperature_2m_min,precipitation_probability_max,rain_sum", openMeteoForecastDays, units)
+}
+
+// FormatGeoURL ... geocoding URL of the place name
+func (o *OpenMeteo) FormatGeoURL(name string) string {
+	return fmt.Sprintf("%s/v1/search?name=%s&count=10&format=json", o.GeoURL, url.QueryEscape(name))
+}
+
+// GetWeather ... current conditions and daily forecast at the coordinates
+func (o *OpenMeteo) GetWeather(coordinates Coordinates) (Conditions, Forecast, error) {
+	data, err := o.get(o.FormatURL(coordinates))
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	conditions, forecast, err := ParseOpenMeteoResponse(data, o.Units)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	forecast.Coordinates = &coordinates
+	return conditions, forecast, nil
+}
+
+// GetCoordinates ... coordinates of a location like "Bonn,DE", the first match in the country if one is given
+func (o *OpenMeteo) GetCoordinates(location string) (Coordinates, error) {
+	parts := strings.Split(strings.ReplaceAll(location, "+", " "), ",")
+	country := ""
+	if len(parts) > 1 {
+		country = strings.ToUpper(strings.TrimSpace(parts[len(parts)-1]))
+	}
+	data, err := o.get(o.FormatGeoURL(strings.TrimSpace(parts[0])))
+	if err != nil {
+		return Coordinates{}, err
+	}
+	var resp OpenMeteoGeoResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("invalid API response %s: %w", data, err)
+	}
+	for _, r := range resp.Results {
+		if country == "" || r.CountryCode == country {
+			return Coordinates{Lat: r.Latitude, Lon: r.Longitude}, nil
+		}
+	}
+	return Coordinates{}, fmt.Errorf("location %q not found", location)
+}
+
+// Get ... resolves the location and fetches its weather in one go
+func (o *OpenMeteo) Get(location string) (Conditions, Forecast, error) {
+	coordinates, ok := ParseCoordinates(location)
+	if !ok {
+		var err error
+		coordinates, err = o.GetCoordinates(location)
+		if err != nil {
+			return Conditions{}, Forecast{}, err
+		}
+	}
+	return o.GetWeather(coordinates)
+}
+
+// ProviderName ... weather data source of GetWeather
+func (o *OpenMeteo) ProviderName() string {
+	return "Open-Meteo"
+}
+
+func (o *OpenMeteo) get(URL string) ([]byte, error) {
+	resp, err := o.HTTPClient.Get(URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
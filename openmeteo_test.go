@@ -0,0 +1,70 @@
+package weather_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseOpenMeteoResponse(t *testing.T) {
+	t.Parallel()
+	conditions, forecast, err := weather.ParseOpenMeteoResponse(weathertest.OpenMeteoResponse, weather.UnitsMetric)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := weather.Conditions{Timestamp: "17.06.2022 17:00 CEST", Temperature: 30.4, WindSpeed: 2.8, WindDirection: 240}
+	if !cmp.Equal(want, conditions) {
+		t.Error(cmp.Diff(want, conditions))
+	}
+	if len(forecast.Daily) != 8 {
+		t.Fatalf("want 8 days, got %d", len(forecast.Daily))
+	}
+	wantDay := weather.ForecastDaily{Day: "19.06.2022", Temp: weather.DailyTempBenchmarks{Min: 15.1, Max: 25.9}, RainChance: 70, Rain: 2.1, Alerts: []weather.Alert{}}
+	if !cmp.Equal(wantDay, forecast.Daily[2]) {
+		t.Error(cmp.Diff(wantDay, forecast.Daily[2]))
+	}
+	if forecast.Daily[7].RainChance != 0 {
+		t.Errorf("want no rain chance for a day without one, got %g", forecast.Daily[7].RainChance)
+	}
+	_, standard, err := weather.ParseOpenMeteoResponse(weathertest.OpenMeteoResponse, weather.UnitsStandard)
+	if err != nil || standard.Daily[0].Temp.Max != 30.1+273.15 {
+		t.Errorf("want the temperature in Kelvin, got %+v, %v", standard.Daily[0].Temp, err)
+	}
+	for _, data := range []string{`[]`, `{}`, `{"daily": {"time": ["2022-06-17"], "temperature_2m_max": [30.1]}}`} {
+		if _, _, err := weather.ParseOpenMeteoResponse([]byte(data), weather.UnitsMetric); err == nil {
+			t.Errorf("%s: want error, but got nil", data)
+		}
+	}
+}
+
+func TestOpenMeteo(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	o := weather.NewOpenMeteo(weather.UnitsMetric)
+	o.BaseURL = ts.URL
+	o.GeoURL = ts.URL
+	coordinates, err := o.GetCoordinates("Bonn,US")
+	if err != nil || coordinates != (weather.Coordinates{Lat: 38.43, Lon: -77.53}) {
+		t.Errorf("want the Bonn of the country, got %v, %v", coordinates, err)
+	}
+	if _, err := o.GetCoordinates("Bonn,FR"); err == nil {
+		t.Error("want error for a country without the place, but got nil")
+	}
+	_, forecast, err := o.Get("Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forecast.Coordinates == nil || forecast.Coordinates.Lat != 50.73438 || forecast.Daily[0].Temp.Max != 30.1 {
+		t.Errorf("want the forecast of Bonn, DE, got %+v", forecast)
+	}
+	want := "https://api.open-meteo.com/v1/forecast?latitude=50.7&longitude=7.1&current_weather=true" +
+		"&daily=temperature_2m_max,temperature_2m_min,precipitation_probability_max,rain_sum&timezone=auto&forecast_days=8" +
+		"&temperature_unit=fahrenheit&windspeed_unit=mph"
+	if got := weather.NewOpenMeteo(weather.UnitsImperial).FormatURL(weather.Coordinates{Lat: 50.7, Lon: 7.1}); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,189 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	FunctionPollen = "pollen"
+
+	// DefaultPollenURL ... base URL of the air quality API of Open-Meteo, which forecasts the pollen in Europe
+	DefaultPollenURL = "https://air-quality-api.open-meteo.com"
+	// pollenForecastDays ... days of the pollen forecast, Open-Meteo has 4 at most
+	pollenForecastDays = 4
+)
+
+type (
+	// PollenDay ... pollen loads of one day
+	PollenDay struct {
+		Day   string       `json:"day"`
+		Loads []PollenLoad `json:"loads"`
+	}
+
+	// PollenLoad ... highest hourly concentration of one pollen type of a day in grains/m³
+	PollenLoad struct {
+		Type  string  `json:"type"`
+		Name  string  `json:"name"`
+		Max   float64 `json:"max"`
+		Level string  `json:"level"`
+	}
+
+	PollenResponse struct {
+		Hourly map[string]json.RawMessage
+	}
+
+	// pollenType ... pollen of the forecast, the limits in grains/m³ separate gering, mäßig, hoch and sehr hoch
+	pollenType struct {
+		name   string
+		german string
+		limits [3]float64
+	}
+)
+
+// pollenTypes ... pollen of Central Europe in the order of their season, the more allergenic the lower the limits
+var pollenTypes = []pollenType{
+	{"alder", "Erle", [3]float64{10, 50, 100}},
+	{"birch", "Birke", [3]float64{10, 50, 100}},
+	{"grass", "Gräser", [3]float64{5, 20, 50}},
+	{"mugwort", "Beifuß", [3]float64{5, 15, 30}},
+	{"ragweed", "Ambrosia", [3]float64{3, 10, 20}},
+}
+
+// pollenLevel ... keine below 1 grain/m³, otherwise the level of the limits of the type
+func pollenLevel(t pollenType, v float64) string {
+	switch {
+	case v < 1:
+		return "keine"
+	case v < t.limits[0]:
+		return "gering"
+	case v < t.limits[1]:
+		return "mäßig"
+	case v < t.limits[2]:
+		return "hoch"
+	}
+	return "sehr hoch"
+}
+
+// ParsePollenResponse ... daily maxima of the hourly pollen forecast, hours without data are skipped
+func ParsePollenResponse(data []byte) ([]PollenDay, error) {
+	var resp PollenResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API response %s: %w", data, err)
+	}
+	var times []string
+	err = json.Unmarshal(resp.Hourly["time"], &times)
+	if err != nil || len(times) == 0 {
+		return nil, fmt.Errorf("invalid API response %s: want hourly times", data)
+	}
+	days := []PollenDay{}
+	index := map[string]int{}
+	// hourDays are the indexes of the days of the hours
+	hourDays := make([]int, len(times))
+	for h, hour := range times {
+		t, err := time.Parse("2006-01-02T15:04", hour)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API response: time %q: %w", hour, err)
+		}
+		day := t.Format("02.01.2006")
+		if _, ok := index[day]; !ok {
+			index[day] = len(days)
+			loads := make([]PollenLoad, len(pollenTypes))
+			for i, pt := range pollenTypes {
+				loads[i] = PollenLoad{Type: pt.name, Name: pt.german}
+			}
+			days = append(days, PollenDay{Day: day, Loads: loads})
+		}
+		hourDays[h] = index[day]
+	}
+	for i, pt := range pollenTypes {
+		var values []*float64
+		err := json.Unmarshal(resp.Hourly[pt.name+"_pollen"], &values)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API response: %s pollen: %w", pt.name, err)
+		}
+		for h, v := range values {
+			if v == nil || h >= len(times) {
+				continue
+			}
+			load := &days[hourDays[h]].Loads[i]
+			if *v > load.Max {
+				load.Max = *v
+			}
+		}
+	}
+	for _, d := range days {
+		for i := range d.Loads {
+			d.Loads[i].Level = pollenLevel(pollenTypes[i], d.Loads[i].Max)
+		}
+	}
+	return days, nil
+}
+
+func (c *Client) FormatPollenURL(coordinates Coordinates) string {
+	types := []string{}
+	for _, pt := range pollenTypes {
+		types = append(types, pt.name+"_pollen")
+	}
+	return fmt.Sprintf("%s/v1/air-quality?latitude=%g&longitude=%g&hourly=%s&timezone=auto&forecast_days=%d",
+		c.PollenURL, coordinates.Lat, coordinates.Lon, strings.Join(types, ","), pollenForecastDays)
+}
+
+// GetPollen ... pollen forecast of the next days at the coordinates, only available in Europe
+func (c *Client) GetPollen(coordinates Coordinates) ([]PollenDay, error) {
+	resp, err := c.HTTPClient.Get(c.FormatPollenURL(coordinates))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePollenResponse(data)
+}
+
+// PrintPollen ... pollen load per day and type, with the highest concentration
+func PrintPollen(days []PollenDay, f Forecast) {
+	fmt.Println()
+	printHeader("Pollenflug", f)
+	for _, d := range days {
+		fmt.Printf("%s:\n", d.Day)
+		for _, load := range d.Loads {
+			fmt.Printf("  %-9s %-9s (%.0f Pollen/m³)\n", load.Name, load.Level, load.Max)
+		}
+	}
+	fmt.Println()
+}
+
+func runPollen(env *cliEnv, opts Options) error {
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return err
+	}
+	days, err := c.GetPollen(coordinates)
+	if err != nil {
+		return err
+	}
+	if opts.Days < len(days) {
+		days = days[:opts.Days]
+	}
+	place := placeName(c, coordinates)
+	if opts.Format == FormatJSON {
+		return printJSON(os.Stdout, struct {
+			Place string      `json:"place,omitempty"`
+			Daily []PollenDay `json:"daily"`
+		}{place, days})
+	}
+	PrintPollen(days, Forecast{Place: place})
+	return nil
+}
@@ -0,0 +1,67 @@
+package weather_test
+
+import (
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePollenResponse(t *testing.T) {
+	t.Parallel()
+	got, err := weather.ParsePollenResponse(weathertest.PollenResponse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []weather.PollenDay{
+		{Day: "17.06.2022", Loads: []weather.PollenLoad{
+			{Type: "alder", Name: "Erle", Max: 0, Level: "keine"},
+			{Type: "birch", Name: "Birke", Max: 6, Level: "gering"},
+			{Type: "grass", Name: "Gräser", Max: 78, Level: "sehr hoch"},
+			{Type: "mugwort", Name: "Beifuß", Max: 9.6, Level: "mäßig"},
+			{Type: "ragweed", Name: "Ambrosia", Max: 0, Level: "keine"},
+		}},
+		{Day: "18.06.2022", Loads: []weather.PollenLoad{
+			{Type: "alder", Name: "Erle", Max: 0, Level: "keine"},
+			{Type: "birch", Name: "Birke", Max: 2.4, Level: "gering"},
+			{Type: "grass", Name: "Gräser", Max: 24, Level: "hoch"},
+			{Type: "mugwort", Name: "Beifuß", Max: 9.6, Level: "mäßig"},
+			{Type: "ragweed", Name: "Ambrosia", Max: 0, Level: "keine"},
+		}},
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParsePollenResponseInvalid(t *testing.T) {
+	t.Parallel()
+	for _, data := range []string{`{}`, `{"hourly": {"time": ["2022-06-17T00:00"]}}`, `{"hourly": {"time": ["17.06."]}}`} {
+		if _, err := weather.ParsePollenResponse([]byte(data)); err == nil {
+			t.Errorf("%s: want error, but got nil", data)
+		}
+	}
+}
+
+func TestGetPollen(t *testing.T) {
+	t.Parallel()
+	c := weathertest.NewFakeClient(t)
+	got, err := c.GetPollen(weather.Coordinates{Lat: 50.72, Lon: 7.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("want 2 days, got %d", len(got))
+	}
+}
+
+func TestFormatPollenURL(t *testing.T) {
+	t.Parallel()
+	c := weather.NewClient("key")
+	want := "https://air-quality-api.open-meteo.com/v1/air-quality?latitude=50.72&longitude=7.1" +
+		"&hourly=alder_pollen,birch_pollen,grass_pollen,mugwort_pollen,ragweed_pollen&timezone=auto&forecast_days=4"
+	if got := c.FormatPollenURL(weather.Coordinates{Lat: 50.72, Lon: 7.1}); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,72 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// pressureTrendPeriod ... period of the pressure tendency, 3 hours as in the synoptic reports
+	pressureTrendPeriod = 3 * time.Hour
+	// pressureTrendTolerance ... how far the age of the compared observation may deviate from the period
+	pressureTrendTolerance = time.Hour
+	// pressureSteady ... change in hPa per 3 hours below which the pressure counts as steady
+	pressureSteady = 1
+)
+
+// PressureTrend ... tendency of the air pressure, rising pressure promises fair weather, falling pressure a change
+type PressureTrend struct {
+	// Change is the change of the pressure in hPa within 3 hours
+	Change float64 `json:"change"`
+}
+
+// GetPressureTrend ... tendency of the pressure up to the latest of the observations, oldest first,
+// compared with the one closest to 3 hours before it, false if none is between 2 and 4 hours older
+func GetPressureTrend(observations []Observation) (PressureTrend, bool) {
+	if len(observations) < 2 {
+		return PressureTrend{}, false
+	}
+	latest := observations[len(observations)-1]
+	var past Observation
+	best := pressureTrendTolerance + 1
+	for _, o := range observations[:len(observations)-1] {
+		deviation := absDuration(latest.Time.Sub(o.Time) - pressureTrendPeriod)
+		if deviation < best {
+			past = o
+			best = deviation
+		}
+	}
+	if best > pressureTrendTolerance {
+		return PressureTrend{}, false
+	}
+	change := float64(latest.Conditions.Pressure-past.Conditions.Pressure) *
+		float64(pressureTrendPeriod) / float64(latest.Time.Sub(past.Time))
+	return PressureTrend{Change: math.Round(change*10) / 10}, true
+}
+
+// Tendency ... steigend, fallend or gleichbleibend for changes below 1 hPa
+func (t PressureTrend) Tendency() string {
+	switch {
+	case t.Change >= pressureSteady:
+		return "steigend"
+	case t.Change <= -pressureSteady:
+		return "fallend"
+	}
+	return "gleichbleibend"
+}
+
+// Arrow ... trend arrow of the tendency as on a barograph
+func (t PressureTrend) Arrow() string {
+	switch t.Tendency() {
+	case "steigend":
+		return "↗"
+	case "fallend":
+		return "↘"
+	}
+	return "→"
+}
+
+func (t PressureTrend) String() string {
+	return fmt.Sprintf("%s %s (%+.1f hPa in 3 h)", t.Arrow(), t.Tendency(), t.Change)
+}
@@ -0,0 +1,73 @@
+package weather_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+)
+
+// pressureAt ... observation of the pressure hours after noon
+func pressureAt(hours float64, pressure int) weather.Observation {
+	start := time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC)
+	return weather.Observation{
+		Time:       start.Add(time.Duration(hours * float64(time.Hour))),
+		Conditions: weather.Conditions{Pressure: pressure},
+	}
+}
+
+func TestGetPressureTrend(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		observations []weather.Observation
+		want         weather.PressureTrend
+		tendency     string
+		arrow        string
+	}{
+		{
+			name:         "rising",
+			observations: []weather.Observation{pressureAt(0, 1010), pressureAt(1, 1011), pressureAt(3, 1013)},
+			want:         weather.PressureTrend{Change: 3},
+			tendency:     "steigend",
+			arrow:        "↗",
+		},
+		{
+			name:         "falling, scaled to 3 hours",
+			observations: []weather.Observation{pressureAt(0, 1020), pressureAt(2, 1016)},
+			want:         weather.PressureTrend{Change: -6},
+			tendency:     "fallend",
+			arrow:        "↘",
+		},
+		{
+			name:         "steady",
+			observations: []weather.Observation{pressureAt(0, 1015), pressureAt(3.5, 1015)},
+			want:         weather.PressureTrend{Change: 0},
+			tendency:     "gleichbleibend",
+			arrow:        "→",
+		},
+	}
+	for _, tt := range tests {
+		got, ok := weather.GetPressureTrend(tt.observations)
+		if !ok || got != tt.want {
+			t.Errorf("%s: want %+v, got %+v (%v)", tt.name, tt.want, got, ok)
+		}
+		if got.Tendency() != tt.tendency || got.Arrow() != tt.arrow {
+			t.Errorf("%s: want %s %s, got %s %s", tt.name, tt.arrow, tt.tendency, got.Arrow(), got.Tendency())
+		}
+	}
+}
+
+func TestGetPressureTrendWithoutPast(t *testing.T) {
+	t.Parallel()
+	for _, observations := range [][]weather.Observation{
+		nil,
+		{pressureAt(0, 1010)},
+		{pressureAt(0, 1010), pressureAt(1, 1012)},
+		{pressureAt(0, 1010), pressureAt(5, 1012)},
+	} {
+		if got, ok := weather.GetPressureTrend(observations); ok {
+			t.Errorf("want no trend for %d observations, got %+v", len(observations), got)
+		}
+	}
+}
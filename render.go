@@ -0,0 +1,238 @@
+package weather
+
+import (
+	"fmt"
+	"io"
+)
+
+type (
+	// Renderer ... output of the weather into a writer, e.g. os.Stdout for the CLI or a buffer in tests
+	Renderer interface {
+		RenderCurrent(w io.Writer, c Conditions, f Forecast) error
+		RenderForecast(w io.Writer, f Forecast, offset int) error
+		RenderHourly(w io.Writer, f Forecast, hours int) error
+		RenderWeek(w io.Writer, f Forecast) error
+		RenderAlerts(w io.Writer, f Forecast) error
+		RenderGraph(w io.Writer, g Graph) error
+	}
+
+	// TextRenderer ... the plain text of the CLI, with Icons the moon phase is led by its glyph, with Color
+	// temperatures, rain chances, strong wind and alerts are coloured by ANSI escape sequences
+	TextRenderer struct {
+		Icons bool
+		Color bool
+		// Width is the width of the terminal, which picks the layout of the tables, 0 keeps the full tables
+		Width int
+	}
+
+	// errWriter ... keeps the first error of the writes, so the lines can be written without checking each
+	errWriter struct {
+		w   io.Writer
+		err error
+	}
+)
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
+}
+
+// writeHeader ... title of every output, followed by the resolved place if known
+func writeHeader(w io.Writer, title string, f Forecast) {
+	fmt.Fprintln(w, title)
+	if f.Place != "" {
+		fmt.Fprintln(w, "Ort: "+f.Place)
+	}
+	fmt.Fprintln(w, "-----------------------------------------------------")
+}
+
+// RenderCurrent ... the current weather conditions, the moon and the alerts of today
+func (r TextRenderer) RenderCurrent(w io.Writer, c Conditions, f Forecast) error {
+	ew := &errWriter{w: w}
+	fmt.Fprintln(ew)
+	writeHeader(ew, "Aktuelles Wetter vom "+c.Timestamp, f)
+	fmt.Fprintf(ew, "Sonne: %s / %s\n", c.Sunrise, c.Sunset)
+	fmt.Fprintf(ew, "Mond: %s / %s, %s (%s)\n", f.Daily[0].Moonrise, f.Daily[0].Moonset, f.Daily[0].Moonphase.describe(r.Icons),
+		f.Daily[0].Moonphase.formatLight())
+	r.writeConditions(ew, c, f)
+	fmt.Fprintln(ew)
+	r.writeAlerts(ew, f.Daily[0].Alerts)
+	return ew.err
+}
+
+// writeConditions ... the measurements of the conditions, shared by the current and the historical weather
+func (r TextRenderer) writeConditions(w io.Writer, c Conditions, f Forecast) {
+	fmt.Fprintf(w, "Beschreibung: %s\n", c.Summary)
+	fmt.Fprintf(w, "Temperatur: %s %s, gefühlt %s %[2]s\n",
+		r.temperature("%.1f", c.Temperature, f), f.TemperatureUnit(), r.temperature("%.1f", c.FeelsLike, f))
+	writeComfort(w, c, f)
+	if c.PressureTrend != nil {
+		fmt.Fprintf(w, "Luftdruck: %d hPa %s\n", c.Pressure, c.PressureTrend)
+	} else {
+		fmt.Fprintf(w, "Luftdruck: %d hPa\n", c.Pressure)
+	}
+	fmt.Fprintf(w, "Luftfeuchtigkeit: %d %%\n", c.Humidity)
+	fmt.Fprintf(w, "Wind: %s\n", r.wind(fmt.Sprintf("%s aus %s, in Böen %s",
+		f.FormatSpeed(c.WindSpeed), c.WindDirection.Direction(), f.FormatSpeed(c.WindGust)), c.WindSpeed, c.WindGust, f))
+	fmt.Fprintf(w, "UV-Index: %.1f (%s)\n", c.UVI, c.UVI.Category())
+	fmt.Fprintf(w, "Bewölkung: %d %%\n", c.Clouds)
+	fmt.Fprintf(w, "Sichtweite: %.1f km\n", float64(c.Visibility)/1000)
+	if c.Rain > 0 || c.Snow > 0 {
+		fmt.Fprintf(w, "Niederschlag: %s\n", FormatPrecipitation(c.Rain, c.Snow))
+	}
+}
+
+// RenderForecast ... the forecast of one day, offset 0 is today
+func (r TextRenderer) RenderForecast(w io.Writer, f Forecast, offset int) error {
+	if offset < 0 || offset >= len(f.Daily) {
+		return fmt.Errorf("offset %d is out of range, the forecast has %d days", offset, len(f.Daily))
+	}
+	ew := &errWriter{w: w}
+	fmt.Fprintln(ew)
+	day := f.Daily[offset]
+	writeHeader(ew, "Vorhersage für "+day.Day, f)
+	fmt.Fprintf(ew, "Sonne: %s / %s\n", day.Sunrise, day.Sunset)
+	fmt.Fprintf(ew, "Tageslänge: %s\n", f.FormatDayLengthOf(offset))
+	fmt.Fprintf(ew, "Beschreibung: %s\n", day.Description)
+	if day.Summary != "" {
+		fmt.Fprintf(ew, "Zusammenfassung: %s\n", day.Summary)
+	}
+	fmt.Fprintf(ew, "Regenwahrscheinlichkeit: %s\n", r.paint(fmt.Sprintf("%.0f %%", day.RainChance), rainChanceColor(day.RainChance)))
+	if day.FireDanger != nil && day.FireDanger.Warning {
+		fmt.Fprintf(ew, "Achtung: Waldbrandgefahr %s (Index %.0f)\n", day.FireDanger.Level(), day.FireDanger.Index)
+	}
+	if day.Frost != nil {
+		fmt.Fprintf(ew, "Achtung: %s in der Nacht, bis %.1f %s\n", day.Frost, day.Frost.Min, f.TemperatureUnit())
+	}
+	fmt.Fprintf(ew, "Wind: %s\n", r.wind(fmt.Sprintf("%s aus %s, in Böen %s",
+		f.FormatSpeed(day.WindSpeed), day.WindDirection.Direction(), f.FormatSpeed(day.WindGust)), day.WindSpeed, day.WindGust, f))
+	fmt.Fprintf(ew, "Luftfeuchtigkeit: %d %%\n", day.Humidity)
+	fmt.Fprintf(ew, "Bewölkung: %d %%\n", day.Clouds)
+	fmt.Fprintln(ew)
+	fmt.Fprintln(ew, "Temperaturen ...")
+	unit := f.TemperatureUnit()
+	fmt.Fprintf(ew, "... zwischen %s %s und %s %[2]s\n",
+		r.temperature("%.0f", day.Temp.Min, f),
+		unit,
+		r.temperature("%.0f", day.Temp.Max, f))
+	fmt.Fprintf(ew, "... %s.\n", FormatDayTemperatures(day.Temp, unit))
+	if g, err := GetGraph(f, "temp", offset); err == nil {
+		min, max := g.bounds()
+		fmt.Fprintf(ew, "... im Verlauf ab %s: %s (%.0f - %.0f %s)\n", g.Hours[0], g.Sparkline(), min, max, unit)
+	}
+	fmt.Fprintln(ew)
+	fmt.Fprintln(ew, GetRainyPeriods(f, offset))
+	if g, err := GetGraph(f, "rain", offset); err == nil {
+		r.writeRainChart(ew, g)
+	}
+	fmt.Fprintln(ew)
+	r.writeAlerts(ew, day.Alerts)
+	return ew.err
+}
+
+// RenderAlerts ... alerts for today and the next days
+func (r TextRenderer) RenderAlerts(w io.Writer, f Forecast) error {
+	ew := &errWriter{w: w}
+	fmt.Fprintln(ew)
+	writeHeader(ew, fmt.Sprintf("Warnungen vom %s - %s", f.Daily[0].Day, f.Daily[len(f.Daily)-1].Day), f)
+	alerts := f.Alerts()
+	if len(alerts) == 0 {
+		fmt.Fprintln(ew, "Es liegen keine Warnungen vor.")
+	}
+	r.writeAlerts(ew, alerts)
+	fmt.Fprintln(ew)
+	return ew.err
+}
+
+// writeAlerts ... name, severity, time, sender and description of the alerts, each once, the most severe first
+func (r TextRenderer) writeAlerts(w io.Writer, alerts []Alert) {
+	for _, a := range SortAlerts(MergeAlerts(alerts)) {
+		r.writeAlert(w, a)
+	}
+}
+
+// writeAlert ... name, severity, time, sender and description of the alert, the name coloured by the severity
+func (r TextRenderer) writeAlert(w io.Writer, a Alert) {
+	name := r.paint(a.Name, alertColor(a.Severity))
+	if label := a.label(); label != "" {
+		fmt.Fprintf(w, "%s (%s) von %s - %s\n", name, label, a.Start, a.End)
+	} else {
+		fmt.Fprintf(w, "%s von %s - %s\n", name, a.Start, a.End)
+	}
+	if a.Sender != "" {
+		fmt.Fprintf(w, "Herausgegeben von %s\n", a.Sender)
+	}
+	fmt.Fprintln(w, a.Description)
+	fmt.Fprintln(w)
+}
+
+// RenderWeek ... compact table with the outlook for all available days, narrow terminals get fewer columns
+func (r TextRenderer) RenderWeek(w io.Writer, f Forecast) error {
+	ew := &errWriter{w: w}
+	fmt.Fprintln(ew)
+	writeHeader(ew, "Wochenübersicht", f)
+	if r.compact() {
+		r.writeWeekCompact(ew, f)
+		fmt.Fprintln(ew)
+		return ew.err
+	}
+	unit := f.TemperatureUnit()
+	fmt.Fprintf(ew, "%-10s  %-13s  %6s  %6s  %5s  %-12s  %7s  %6s  %s\n", "Tag", "Sonne", "Min", "Max", "Regen", "Wind", "Feuchte", "Wolken", "Beschreibung")
+	for _, day := range f.Daily {
+		wind := f.FormatSpeed(day.WindSpeed) + " " + day.WindDirection.Direction()
+		fmt.Fprintf(ew, "%-10s  %-13s  %s%s  %s%s  %s  %s  %5d %%  %4d %%  %s\n",
+			day.Day,
+			day.Sunrise+" - "+day.Sunset,
+			r.temperature("%4.0f", day.Temp.Min, f), unit,
+			r.temperature("%4.0f", day.Temp.Max, f), unit,
+			r.paint(fmt.Sprintf("%3.0f %%", day.RainChance), rainChanceColor(day.RainChance)),
+			r.wind(fmt.Sprintf("%-12s", wind), day.WindSpeed, day.WindGust, f),
+			day.Humidity,
+			day.Clouds,
+			day.Description)
+	}
+	fmt.Fprintln(ew)
+	return ew.err
+}
+
+// RenderHourly ... table with the forecast for the next hours, at most 48 hours are available. Narrow terminals
+// get a single column of fewer values, wide ones the days side by side
+func (r TextRenderer) RenderHourly(w io.Writer, f Forecast, hours int) error {
+	if hours > len(f.Hourly) {
+		hours = len(f.Hourly)
+	}
+	ew := &errWriter{w: w}
+	fmt.Fprintln(ew)
+	writeHeader(ew, fmt.Sprintf("Vorhersage für die nächsten %d Stunden", hours), f)
+	switch {
+	case r.compact():
+		r.writeHourlyCompact(ew, f, f.Hourly[:hours])
+		fmt.Fprintln(ew)
+		return ew.err
+	case r.wide():
+		r.writeHourlyWide(ew, f, f.Hourly[:hours])
+		fmt.Fprintln(ew)
+		return ew.err
+	}
+	unit := f.TemperatureUnit()
+	fmt.Fprintf(ew, "%-10s  %-5s  %7s  %8s  %5s  %-12s  %6s  %8s  %s\n", "Tag", "Zeit", "Temp", "gefühlt", "Regen", "Wind", "Feuchte", "Druck", "Beschreibung")
+	for _, slot := range f.Hourly[:hours] {
+		wind := f.FormatSpeed(slot.WindSpeed) + " " + slot.WindDirection.Direction()
+		fmt.Fprintf(ew, "%-10s  %-5s  %s%s  %s%s  %s  %s  %5d %%  %4d hPa  %s\n",
+			slot.Day,
+			slot.Hour,
+			r.temperature("%5.1f", slot.Temperature, f), unit,
+			r.temperature("%6.1f", slot.FeelsLike, f), unit,
+			r.paint(fmt.Sprintf("%3.0f %%", slot.RainChance), rainChanceColor(slot.RainChance)),
+			r.wind(fmt.Sprintf("%-12s", wind), slot.WindSpeed, slot.WindGust, f),
+			slot.Humidity,
+			slot.Pressure,
+			slot.Description)
+	}
+	fmt.Fprintln(ew)
+	return ew.err
+}
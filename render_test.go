@@ -0,0 +1,64 @@
+package weather_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+// failingWriter ... writer failing every write
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestTextRenderer(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/weather_30.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, f, err := weather.ParseWeatherResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Place = "Bad Schnuffel, DE"
+	r := weather.TextRenderer{}
+	cases := []struct {
+		name   string
+		render func(b *bytes.Buffer) error
+		want   []string
+	}{
+		{"current", func(b *bytes.Buffer) error { return r.RenderCurrent(b, c, f) }, []string{"Aktuelles Wetter vom ", "Ort: Bad Schnuffel, DE\n", "Temperatur: "}},
+		{"forecast", func(b *bytes.Buffer) error { return r.RenderForecast(b, f, 1) }, []string{"Vorhersage für " + f.Daily[1].Day, "Temperaturen ..."}},
+		{"hourly", func(b *bytes.Buffer) error { return r.RenderHourly(b, f, 3) }, []string{"Vorhersage für die nächsten 3 Stunden", f.Hourly[2].Hour}},
+		{"week", func(b *bytes.Buffer) error { return r.RenderWeek(b, f) }, []string{"Wochenübersicht", f.Daily[len(f.Daily)-1].Day}},
+		{"alerts", func(b *bytes.Buffer) error { return r.RenderAlerts(b, f) }, []string{"Warnungen vom " + f.Daily[0].Day}},
+	}
+	for _, tc := range cases {
+		var b bytes.Buffer
+		err := tc.render(&b)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("%s: want %q in %q", tc.name, want, b.String())
+			}
+		}
+	}
+}
+
+func TestTextRendererWriteError(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Daily: []weather.ForecastDaily{{Day: "17.06.2022"}}}
+	err := weather.TextRenderer{}.RenderWeek(failingWriter{}, f)
+	if err == nil || err.Error() != "disk full" {
+		t.Errorf("want the error of the writer, got %v", err)
+	}
+}
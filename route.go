@@ -0,0 +1,267 @@
+package weather
+
+import (
+	"context"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CommandRoute = "route"
+
+	// DefaultRouteEvery ... distance in km between the sampled points of a route
+	DefaultRouteEvery = 10.0
+	// defaultRouteSpeed ... travel speed of --speed in m/s, that of a bike
+	defaultRouteSpeed = Speed(20 / 3.6)
+	// MaxRoutePoints ... sampled points of a route at most, each costs a request
+	MaxRoutePoints = 50
+
+	// earthRadius ... mean radius of the earth in km
+	earthRadius = 6371.0
+)
+
+// RoutePoint ... sampled point of a route with its distance from the start in km and the estimated arrival,
+// Hourly is nil if the arrival is beyond the hourly forecast
+type RoutePoint struct {
+	Coordinates Coordinates     `json:"coordinates"`
+	Distance    float64         `json:"distance_km"`
+	ETA         time.Time       `json:"eta"`
+	Hourly      *ForecastHourly `json:"hourly,omitempty"`
+}
+
+// gpxFile ... the parts of a GPX file needed for a route, tracks and routes
+type gpxFile struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+type gpxPoint struct {
+	Lat float64 `xml:"lat,attr"`
+	Lon float64 `xml:"lon,attr"`
+}
+
+// ParseGPX ... points of the tracks of a GPX file, or of its routes if there are no tracks
+func ParseGPX(data []byte) ([]Coordinates, error) {
+	var gpx gpxFile
+	err := xml.Unmarshal(data, &gpx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GPX: %w", err)
+	}
+	points := []Coordinates{}
+	for _, track := range gpx.Tracks {
+		for _, segment := range track.Segments {
+			for _, p := range segment.Points {
+				points = append(points, Coordinates{Lat: p.Lat, Lon: p.Lon})
+			}
+		}
+	}
+	if len(points) == 0 {
+		for _, route := range gpx.Routes {
+			for _, p := range route.Points {
+				points = append(points, Coordinates{Lat: p.Lat, Lon: p.Lon})
+			}
+		}
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("invalid GPX: want at least one track or route point")
+	}
+	return points, nil
+}
+
+// ParseSpeed ... travel speed like "20km/h", "12mph" or "5m/s" in m/s, a bare number is taken as km/h
+func ParseSpeed(s string) (Speed, error) {
+	value := strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	factor := 1 / 3.6
+	for _, unit := range []struct {
+		suffix string
+		factor float64
+	}{{"km/h", 1 / 3.6}, {"kmh", 1 / 3.6}, {"mph", 0.44704}, {"m/s", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSuffix(value, unit.suffix)
+			factor = unit.factor
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || v <= 0 {
+		return 0, fmt.Errorf("invalid speed %q, want e.g. 20km/h, 12mph or 5m/s", s)
+	}
+	return Speed(v * factor), nil
+}
+
+// Distance ... great-circle distance between two coordinates in km
+func Distance(a, b Coordinates) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// SampleRoute ... points every km along the track, including its start and end,
+// with the arrival when leaving at start with the given speed in m/s
+func SampleRoute(track []Coordinates, every float64, start time.Time, speed Speed) []RoutePoint {
+	if len(track) == 0 {
+		return []RoutePoint{}
+	}
+	eta := func(distance float64) time.Time {
+		return start.Add(time.Duration(distance * 1000 / float64(speed) * float64(time.Second)))
+	}
+	points := []RoutePoint{{Coordinates: track[0], ETA: start}}
+	travelled := 0.0
+	next := every
+	for i := 1; i < len(track); i++ {
+		segment := Distance(track[i-1], track[i])
+		// several samples may fall into one long segment, they are interpolated
+		for segment > 0 && next <= travelled+segment {
+			share := (next - travelled) / segment
+			points = append(points, RoutePoint{
+				Coordinates: Coordinates{
+					Lat: track[i-1].Lat + (track[i].Lat-track[i-1].Lat)*share,
+					Lon: track[i-1].Lon + (track[i].Lon-track[i-1].Lon)*share,
+				},
+				Distance: next,
+				ETA:      eta(next),
+			})
+			next += every
+		}
+		travelled += segment
+	}
+	if last := points[len(points)-1]; travelled > last.Distance {
+		points = append(points, RoutePoint{Coordinates: track[len(track)-1], Distance: travelled, ETA: eta(travelled)})
+	}
+	return points
+}
+
+// GetRouteWeather ... the points with the hourly forecast at their arrival, fetched concurrently
+func (c *Client) GetRouteWeather(ctx context.Context, points []RoutePoint) ([]RoutePoint, error) {
+	coordinates := make([]Coordinates, len(points))
+	for i, p := range points {
+		coordinates[i] = p.Coordinates
+	}
+	withWeather := make([]RoutePoint, len(points))
+	for i, r := range c.GetWeatherMany(ctx, coordinates) {
+		if r.Err != nil {
+			return nil, fmt.Errorf("km %.0f: %w", points[i].Distance, r.Err)
+		}
+		withWeather[i] = points[i]
+		if slot, ok := GetHourlyAt(r.Forecast, points[i].ETA); ok {
+			withWeather[i].Hourly = &slot
+		}
+	}
+	return withWeather, nil
+}
+
+// PrintRoute ... forecast along a route at the arrival at each point
+func PrintRoute(points []RoutePoint, speed Speed, f Forecast) {
+	WriteRoute(os.Stdout, points, speed, f)
+}
+
+// WriteRoute ... PrintRoute writing to w
+func WriteRoute(w io.Writer, points []RoutePoint, speed Speed, f Forecast) {
+	if len(points) == 0 {
+		return
+	}
+	last := points[len(points)-1]
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Wetter entlang der Route, %.0f km mit %.0f km/h ab %s\n",
+		last.Distance, speed.KmPerHour(), points[0].ETA.Format("02.01.2006 15:04"))
+	fmt.Fprintln(w, "-----------------------------------------------------")
+	unit := f.TemperatureUnit()
+	fmt.Fprintf(w, "%5s  %-16s  %-16s  %7s  %5s  %-12s  %s\n", "km", "Ankunft", "Position", "Temp", "Regen", "Wind", "Beschreibung")
+	for _, p := range points {
+		position := fmt.Sprintf("%.3f,%.3f", p.Coordinates.Lat, p.Coordinates.Lon)
+		if p.Hourly == nil {
+			fmt.Fprintf(w, "%5.0f  %-16s  %-16s  keine Stundenvorhersage\n", p.Distance, p.ETA.Format("02.01.2006 15:04"), position)
+			continue
+		}
+		wind := f.FormatSpeed(p.Hourly.WindSpeed) + " " + p.Hourly.WindDirection.Direction()
+		fmt.Fprintf(w, "%5.0f  %-16s  %-16s  %5.1f%s  %3.0f %%  %-12s  %s\n",
+			p.Distance,
+			p.ETA.Format("02.01.2006 15:04"),
+			position,
+			p.Hourly.Temperature, unit,
+			p.Hourly.RainChance,
+			wind,
+			p.Hourly.Description)
+	}
+	fmt.Fprintln(w)
+}
+
+// routeFlags ... flags of the route command
+func routeFlags(fs *flag.FlagSet, opts *Options) {
+	opts.Speed = defaultRouteSpeed
+	opts.Every = DefaultRouteEvery
+	fs.Func("speed", "travel speed, e.g. 20km/h, 12mph or 5m/s (default 20km/h)", func(s string) (err error) {
+		opts.Speed, err = ParseSpeed(s)
+		return err
+	})
+	fs.Func("start", "departure like 9:00, the next one from now on, or 2023-07-01 09:00 (default now)", func(s string) (err error) {
+		opts.Start, err = ParseTimeOfDay(s, time.Now())
+		return err
+	})
+	fs.Float64Var(&opts.Every, "every", opts.Every, "distance between the forecast points in km")
+}
+
+// validateRoute ... the GPX file takes the place of the location
+func validateRoute(fs *flag.FlagSet, opts *Options) error {
+	if len(opts.Args) != 1 || opts.Location != "" || opts.Zip != "" || opts.Here {
+		return fmt.Errorf("%s takes one GPX file instead of the location", CommandRoute)
+	}
+	if opts.Every <= 0 {
+		return fmt.Errorf("invalid distance %g km between the points, want more than 0", opts.Every)
+	}
+	return nil
+}
+
+func runRoute(env *cliEnv, opts Options) error {
+	data, err := os.ReadFile(opts.Args[0])
+	if err != nil {
+		return err
+	}
+	track, err := ParseGPX(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", opts.Args[0], err)
+	}
+	start := opts.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+	points := SampleRoute(track, opts.Every, start, opts.Speed)
+	if len(points) > MaxRoutePoints {
+		return fmt.Errorf("the route has %d points every %g km, at most %d are fetched, increase --every", len(points), opts.Every, MaxRoutePoints)
+	}
+	c, err := env.client()
+	if err != nil {
+		return err
+	}
+	c.Units = opts.Units
+	c.Lang = opts.Lang
+	c.Exclude = opts.Exclude
+	points, err = c.GetRouteWeather(context.Background(), points)
+	if err != nil {
+		return err
+	}
+	if opts.Format == FormatJSON {
+		return printJSON(os.Stdout, struct {
+			Speed  float64      `json:"speed_kmh"`
+			Units  string       `json:"units"`
+			Points []RoutePoint `json:"points"`
+		}{opts.Speed.KmPerHour(), c.Units, points})
+	}
+	PrintRoute(points, opts.Speed, Forecast{Units: c.Units})
+	return nil
+}
@@ -0,0 +1,123 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestParseGPX(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/route.gpx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := weather.ParseGPX(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 || got[0] != (weather.Coordinates{Lat: 50.7374, Lon: 7.0982}) {
+		t.Errorf("want 4 points starting in Bonn, got %v", got)
+	}
+	route := `<gpx><rte><rtept lat="1" lon="2"/><rtept lat="3" lon="4"/></rte></gpx>`
+	got, err = weather.ParseGPX([]byte(route))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("want the 2 route points, got %v", got)
+	}
+	for _, invalid := range []string{"", "<gpx></gpx>", "no xml"} {
+		_, err = weather.ParseGPX([]byte(invalid))
+		if err == nil {
+			t.Errorf("%q: want error, but got nil", invalid)
+		}
+	}
+}
+
+func TestParseSpeed(t *testing.T) {
+	t.Parallel()
+	tests := map[string]float64{
+		"20km/h":  20,
+		"20 km/h": 20,
+		"36kmh":   36,
+		"18":      18,
+		"10mph":   16.09344,
+		"5m/s":    18,
+	}
+	for input, want := range tests {
+		got, err := weather.ParseSpeed(input)
+		if err != nil {
+			t.Fatalf("%s: %v", input, err)
+		}
+		if math.Abs(got.KmPerHour()-want) > 0.001 {
+			t.Errorf("%s: want %.3f km/h, got %.3f", input, want, got.KmPerHour())
+		}
+	}
+	for _, invalid := range []string{"", "fast", "0km/h", "-5mph", "NaN"} {
+		_, err := weather.ParseSpeed(invalid)
+		if err == nil {
+			t.Errorf("%q: want error, but got nil", invalid)
+		}
+	}
+}
+
+func TestSampleRoute(t *testing.T) {
+	t.Parallel()
+	// a degree of latitude is about 111.2 km
+	track := []weather.Coordinates{{Lat: 50, Lon: 7}, {Lat: 50.5, Lon: 7}}
+	start := time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC)
+	got := weather.SampleRoute(track, 20, start, 20/3.6)
+	if len(got) != 4 {
+		t.Fatalf("want start, 20 km, 40 km and end, got %+v", got)
+	}
+	if got[1].Distance != 20 || got[1].ETA != start.Add(time.Hour) {
+		t.Errorf("want km 20 after an hour, got %+v", got[1])
+	}
+	if math.Abs(got[1].Coordinates.Lat-50.18) > 0.01 {
+		t.Errorf("want km 20 at latitude 50.18, got %v", got[1].Coordinates)
+	}
+	if end := got[3]; math.Abs(end.Distance-55.6) > 0.1 || end.Coordinates != track[1] {
+		t.Errorf("want the end of the track at 55.6 km, got %+v", end)
+	}
+}
+
+func TestRouteCommand(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	// the start is the first hour of the canned forecast
+	start := time.Unix(1655478000, 0).Format("2006-01-02 15:04")
+	out, err := runCLI(t, ts, "route", "testdata/route.gpx", "--speed", "25km/h", "--start", start, "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Speed  float64              `json:"speed_kmh"`
+		Points []weather.RoutePoint `json:"points"`
+	}
+	err = json.Unmarshal(out, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Points) != 4 {
+		t.Fatalf("want points at 0, 10, 20 km and the end, got %+v", got.Points)
+	}
+	for _, p := range got.Points {
+		if p.Hourly == nil {
+			t.Errorf("km %.0f: want the hourly forecast at %s", p.Distance, p.ETA)
+		}
+	}
+	if got.Points[1].Hourly != nil && got.Points[1].Hourly.Hour != time.Unix(1655478000, 0).Format("15:04") {
+		t.Errorf("want the first hour at km 10 after 24 minutes, got %s", got.Points[1].Hourly.Hour)
+	}
+	_, err = runCLI(t, ts, "route", "--speed", "fast", "testdata/route.gpx")
+	if err == nil {
+		t.Error("want error for an invalid speed, but got nil")
+	}
+}
@@ -0,0 +1,280 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	CommandDaemon = "daemon"
+
+	// actions of scheduled jobs
+	ActionPrint    = "print"
+	ActionExport   = "export"
+	ActionNotify   = "notify"
+	ActionBriefing = "briefing"
+	ActionStore    = "store"
+)
+
+type (
+	// ScheduleEntry ... scheduled job of the config file, Path is the file of the export action
+	ScheduleEntry struct {
+		Location string `json:"location"`
+		Cron     string `json:"cron"`
+		Action   string `json:"action"`
+		Path     string `json:"path,omitempty"`
+	}
+
+	// Job ... writes the weather of a location to a sink whenever the schedule matches
+	Job struct {
+		Location string
+		Schedule Cron
+		Sink     Sink
+	}
+
+	// Scheduler ... runs the jobs of several locations in one process, the weather of the locations due
+	// is fetched concurrently, the jobs run one after the other
+	Scheduler struct {
+		Provider Provider
+		Jobs     []Job
+	}
+
+	// exportSink ... appends every observation as one line of JSON to a file
+	exportSink struct {
+		path string
+	}
+
+	// briefingSink ... sends the briefing of every observation
+	briefingSink struct {
+		notifiers []Notifier
+	}
+)
+
+// Tick ... runs the jobs due in the minute of t, locations needed by several jobs are fetched once
+func (s *Scheduler) Tick(t time.Time) {
+	type result struct {
+		conditions Conditions
+		forecast   Forecast
+		err        error
+	}
+	due := []string{}
+	fetched := map[string]*result{}
+	for _, job := range s.Jobs {
+		if job.Schedule.Matches(t) && fetched[job.Location] == nil {
+			due = append(due, job.Location)
+			fetched[job.Location] = &result{}
+		}
+	}
+	// the locations are looked up in order, only the weather is fetched concurrently
+	coordinates := make([]Coordinates, len(due))
+	for i, location := range due {
+		coordinates[i], fetched[location].err = locateWith(s.Provider, location)
+	}
+	forEachBounded(len(due), DefaultConcurrency, func(i int) {
+		r := fetched[due[i]]
+		if r.err == nil {
+			r.conditions, r.forecast, r.err = s.Provider.GetWeather(coordinates[i])
+		}
+	})
+	for _, job := range s.Jobs {
+		if !job.Schedule.Matches(t) {
+			continue
+		}
+		r := fetched[job.Location]
+		if r.err != nil {
+			log.Printf("%s: %v", job.Location, r.err)
+			continue
+		}
+		err := job.Sink.Write(Observation{
+			Location:   strings.ReplaceAll(job.Location, "+", " "),
+			Time:       t,
+			Conditions: r.conditions,
+			Forecast:   r.forecast,
+		})
+		if err != nil {
+			log.Printf("%s: %v", job.Location, err)
+		}
+	}
+}
+
+// Run ... ticks at the start of every minute until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		next := time.Now().Truncate(time.Minute).Add(time.Minute)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.Tick(next)
+	}
+}
+
+func (e exportSink) Write(o Observation) error {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(e.path), 0o755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(e.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (b briefingSink) Write(o Observation) error {
+	return send(b.notifiers, []Notification{Briefing(o.Location, o.Conditions, o.Forecast)})
+}
+
+// scheduler ... jobs of the schedule in the config file, notify jobs share one state
+func (env *cliEnv) scheduler(opts Options) (*Scheduler, error) {
+	if len(env.cfg.Schedule) == 0 {
+		return nil, errors.New("no jobs configured, add them to schedule in the config file")
+	}
+	c, err := env.client()
+	if err != nil {
+		return nil, err
+	}
+	c.Units = opts.Units
+	c.Lang = opts.Lang
+	s := &Scheduler{Provider: env.provider(c, opts)}
+	notifiers := env.configuredNotifiers(opts)
+	var notify *NotifySink
+	var diff *diffSink
+	var store Store
+	var influx *Influx
+	for i, entry := range env.cfg.Schedule {
+		schedule, err := ParseCron(entry.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %d: %w", i+1, err)
+		}
+		var sink Sink
+		switch entry.Action {
+		case ActionPrint:
+			sink = &displaySink{w: os.Stdout}
+		case ActionExport:
+			if entry.Path == "" {
+				return nil, fmt.Errorf("schedule %d: export needs a path", i+1)
+			}
+			sink = exportSink{path: entry.Path}
+		case ActionNotify, ActionBriefing, ActionDiff:
+			if len(notifiers) == 0 {
+				return nil, fmt.Errorf("schedule %d: %s needs a notifier, set ntfy, webhook or telegram in the config file", i+1, entry.Action)
+			}
+			if entry.Action == ActionBriefing {
+				sink = briefingSink{notifiers: notifiers}
+				break
+			}
+			if entry.Action == ActionDiff {
+				if diff == nil {
+					path := ForecastCachePath(env.cfgPath)
+					cache, err := LoadForecastCache(path)
+					if err != nil {
+						return nil, err
+					}
+					diff = &diffSink{notifiers: notifiers, cache: cache, path: path}
+				}
+				sink = diff
+				break
+			}
+			if notify == nil {
+				path := NotifyStatePath(env.cfgPath)
+				state, err := LoadNotifyState(path)
+				if err != nil {
+					return nil, err
+				}
+				frost := env.cfg.FrostLimit()
+				notify = &NotifySink{
+					Notifiers:      notifiers,
+					State:          state,
+					RainWithin:     opts.RainWithin,
+					Thresholds:     env.cfg.TemperatureThresholds,
+					FrostThreshold: &frost,
+					Path:           path,
+				}
+			}
+			sink = notify
+		case ActionStore:
+			if store == nil {
+				store, err = env.store()
+				if err != nil {
+					return nil, err
+				}
+				if store == nil {
+					return nil, fmt.Errorf("schedule %d: store needs store in the config file", i+1)
+				}
+			}
+			sink = store
+		case ActionInflux:
+			if env.cfg.InfluxDB == nil {
+				return nil, fmt.Errorf("schedule %d: influxdb needs influxdb in the config file", i+1)
+			}
+			if influx == nil {
+				influx = NewInflux(*env.cfg.InfluxDB)
+			}
+			sink = influx
+		default:
+			return nil, fmt.Errorf("schedule %d: unknown action %q, want print, export, notify, briefing, diff, store or influxdb", i+1, entry.Action)
+		}
+		location := entry.Location
+		if location == "" {
+			location = GetDefaultLocation(env.cfg)
+		}
+		if location == "" {
+			return nil, fmt.Errorf("schedule %d: no location given and no default location configured", i+1)
+		}
+		location = env.cfg.ResolveLocation(strings.ReplaceAll(location, " ", "+"))
+		s.Jobs = append(s.Jobs, Job{Location: location, Schedule: schedule, Sink: sink})
+	}
+	return s, nil
+}
+
+func daemonFlags(opts *Options) *flag.FlagSet {
+	fs := flag.NewFlagSet(CommandDaemon, flag.ContinueOnError)
+	fs.StringVar(&opts.Units, "units", opts.Units, "units: metric, imperial or standard")
+	fs.StringVar(&opts.Lang, "lang", opts.Lang, "language of the weather descriptions")
+	fs.DurationVar(&opts.RainWithin, "rain-within", opts.RainWithin, "notify of rain starting within this time")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [FLAGS]\n\nRuns the jobs of schedule in the config file.\n\nFlags:\n", CommandDaemon)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func runDaemon(env *cliEnv, args []string) error {
+	opts := NewOptions(env.cfg)
+	opts.RainWithin = 2 * time.Hour
+	err := daemonFlags(&opts).Parse(args)
+	if err != nil {
+		return err
+	}
+	if !validUnits[opts.Units] {
+		return fmt.Errorf("invalid units %q, want metric, imperial or standard", opts.Units)
+	}
+	s, err := env.scheduler(opts)
+	if err != nil {
+		return err
+	}
+	log.Printf("running %d scheduled jobs", len(s.Jobs))
+	s.Run(context.Background())
+	return nil
+}
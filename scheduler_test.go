@@ -0,0 +1,37 @@
+package weather_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchedulerTick(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	every, _ := weather.ParseCron("* * * * *")
+	morning, _ := weather.ParseCron("0 7 * * *")
+	bonn := &recordingSink{}
+	honnef := &recordingSink{}
+	s := &weather.Scheduler{Provider: m, Jobs: []weather.Job{
+		{Location: "Bonn", Schedule: every, Sink: bonn},
+		{Location: "Bonn", Schedule: morning, Sink: bonn},
+		{Location: "Bad+Honnef", Schedule: morning, Sink: honnef},
+	}}
+
+	s.Tick(time.Date(2022, 6, 17, 6, 59, 0, 0, time.UTC))
+	s.Tick(time.Date(2022, 6, 17, 7, 0, 0, 0, time.UTC))
+	if len(bonn.observations) != 3 || len(honnef.observations) != 1 {
+		t.Errorf("want 3 and 1 observations, got %d and %d", len(bonn.observations), len(honnef.observations))
+	}
+	if honnef.observations[0].Location != "Bad Honnef" {
+		t.Errorf("want location for display, got %q", honnef.observations[0].Location)
+	}
+	// the second job of Bonn reuses the weather fetched for the first one
+	want := []string{"Bonn", "Bonn", "Bad+Honnef"}
+	if !cmp.Equal(want, m.Locations()) {
+		t.Error(cmp.Diff(want, m.Locations()))
+	}
+}
@@ -0,0 +1,217 @@
+package weather
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:generate go test -run TestSchemasUpToDate -update
+
+const CommandSchema = "schema"
+
+// schemaDraft ... JSON Schema version of the generated schemas
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+type (
+	// currentJSON ... JSON output of the current conditions, of the current command and /v1/current
+	currentJSON struct {
+		Place      string     `json:"place,omitempty"`
+		Conditions Conditions `json:"conditions"`
+		Alerts     []Alert    `json:"alerts"`
+	}
+
+	// alertsJSON ... JSON output of the alert command, alerts of all days of the forecast
+	alertsJSON struct {
+		Place  string     `json:"place,omitempty"`
+		Count  int        `json:"count"`
+		Alerts []dayAlert `json:"alerts"`
+	}
+
+	// dayAlert ... alert with the day of the forecast it was issued for
+	dayAlert struct {
+		Day string `json:"day"`
+		Alert
+	}
+)
+
+//go:embed schema
+var schemaFiles embed.FS
+
+// schemaTypes ... types of the published schemas by name, the files are schema/<name>.schema.json
+var schemaTypes = map[string]any{
+	"alert":      Alert{},
+	"alerts":     alertsJSON{},
+	"conditions": Conditions{},
+	"current":    currentJSON{},
+	"forecast":   Forecast{},
+}
+
+// marshaledAs ... types with a MarshalJSON are described by the type they are marshaled as
+var marshaledAs = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(ForecastDaily{}): reflect.TypeOf(dailyJSON{}),
+}
+
+// SchemaNames ... names of the published JSON Schemas, sorted
+func SchemaNames() []string {
+	names := []string{}
+	for name := range schemaTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Schema ... embedded JSON Schema of the output, e.g. "forecast"
+func Schema(name string) ([]byte, error) {
+	if _, ok := schemaTypes[name]; !ok {
+		return nil, fmt.Errorf("unknown schema %q, want one of %s", name, strings.Join(SchemaNames(), ", "))
+	}
+	return schemaFiles.ReadFile("schema/" + name + ".schema.json")
+}
+
+// GenerateSchema ... JSON Schema of the output, generated from the Go types; go generate updates the embedded files
+func GenerateSchema(name string) ([]byte, error) {
+	v, ok := schemaTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q, want one of %s", name, strings.Join(SchemaNames(), ", "))
+	}
+	schema := typeSchema(reflect.TypeOf(v))
+	schema["$schema"] = schemaDraft
+	schema["title"] = name
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// typeSchema ... schema of the JSON encoding/json produces for t
+func typeSchema(t reflect.Type) map[string]any {
+	if m, ok := marshaledAs[t]; ok {
+		t = m
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Ptr:
+		return nullable(typeSchema(t.Elem()))
+	case reflect.Slice:
+		// nil slices are encoded as null
+		return map[string]any{"type": []string{"array", "null"}, "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": []string{"object", "null"}, "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		required := []string{}
+		addProperties(t, properties, &required)
+		sort.Strings(required)
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	// anything else is not part of the output
+	return map[string]any{}
+}
+
+// addProperties ... properties of the fields of the struct, embedded structs are inlined as by encoding/json
+func addProperties(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addProperties(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = typeSchema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// nullable ... schema also allowing null
+func nullable(schema map[string]any) map[string]any {
+	if t, ok := schema["type"].(string); ok {
+		schema["type"] = []string{t, "null"}
+	}
+	return schema
+}
+
+// forecastAlerts ... alerts of all days of the forecast, each once with the first day it is given for
+func forecastAlerts(f Forecast) []dayAlert {
+	alerts := []dayAlert{}
+	index := map[string]int{}
+	for _, day := range f.Daily {
+		for _, a := range day.Alerts {
+			id := alertID(a)
+			if i, ok := index[id]; ok {
+				alerts[i].Alert = mergeAlert(alerts[i].Alert, a)
+				continue
+			}
+			index[id] = len(alerts)
+			alerts = append(alerts, dayAlert{day.Day, a})
+		}
+	}
+	return alerts
+}
+
+// handleSchema ... /schema lists the schemas, /schema/<name> serves one of them
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/schema"), "/"), ".schema.json")
+	if name == "" {
+		writeJSON(w, http.StatusOK, SchemaNames())
+		return
+	}
+	data, err := Schema(name)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(data)
+}
+
+func runSchema(env *cliEnv, args []string) error {
+	if len(args) == 0 {
+		for _, name := range SchemaNames() {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	data, err := Schema(args[0])
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,159 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "rewrite the embedded JSON Schemas")
+
+func TestSchemasUpToDate(t *testing.T) {
+	for _, name := range weather.SchemaNames() {
+		want, err := weather.GenerateSchema(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *update {
+			err := os.WriteFile(filepath.Join("schema", name+".schema.json"), want, 0o644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		got, err := weather.Schema(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(want) != string(got) {
+			t.Errorf("%s: embedded schema is outdated, run go generate: %s", name, cmp.Diff(string(want), string(got)))
+		}
+	}
+}
+
+func TestSchemaMatchesOutput(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{
+		Place:  "Bonn, DE",
+		Units:  weather.UnitsMetric,
+		Hourly: []weather.ForecastHourly{{Time: time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC), Day: "17.06.2022", Hour: "12:00", RainChance: 20}},
+		Daily:  []weather.ForecastDaily{{Day: "17.06.2022", DayLength: 16 * time.Hour, Alerts: []weather.Alert{{Name: "Hitze"}}}},
+	}
+	tests := map[string]any{
+		"forecast":   f,
+		"conditions": weather.Conditions{Summary: "Clear", Temperature: 21.5},
+		"alert":      weather.Alert{Name: "Hitze"},
+	}
+	for name, v := range tests {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var output any
+		json.Unmarshal(data, &output)
+		schemaData, err := weather.Schema(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var schema map[string]any
+		err = json.Unmarshal(schemaData, &schema)
+		if err != nil {
+			t.Fatalf("%s: invalid schema: %v", name, err)
+		}
+		for _, problem := range validate(schema, output, name) {
+			t.Error(problem)
+		}
+	}
+}
+
+// validate ... problems of the JSON value according to the subset of JSON Schema the generated schemas use
+func validate(schema map[string]any, v any, path string) []string {
+	types := []string{}
+	switch t := schema["type"].(type) {
+	case string:
+		types = append(types, t)
+	case []any:
+		for _, t := range t {
+			types = append(types, t.(string))
+		}
+	}
+	kind := "null"
+	switch v := v.(type) {
+	case bool:
+		kind = "boolean"
+	case float64:
+		kind = "number"
+		if v == float64(int64(v)) {
+			kind = "integer"
+		}
+	case string:
+		kind = "string"
+	case []any:
+		kind = "array"
+	case map[string]any:
+		kind = "object"
+	}
+	ok := false
+	for _, t := range types {
+		ok = ok || t == kind || (t == "number" && kind == "integer")
+	}
+	if !ok {
+		return []string{path + ": want " + strings.Join(types, " or ") + ", got " + kind}
+	}
+	problems := []string{}
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			problems = append(problems, validate(schema["items"].(map[string]any), item, path+"[]")...)
+		}
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		for key, value := range v {
+			property, ok := properties[key].(map[string]any)
+			if !ok {
+				problems = append(problems, path+"."+key+": not in the schema")
+				continue
+			}
+			problems = append(problems, validate(property, value, path+"."+key)...)
+		}
+		required, _ := schema["required"].([]any)
+		for _, key := range required {
+			if _, ok := v[key.(string)]; !ok {
+				problems = append(problems, path+"."+key.(string)+": missing")
+			}
+		}
+	}
+	return problems
+}
+
+func TestServerSchema(t *testing.T) {
+	t.Parallel()
+	s := weather.NewServer(newMock(), time.Minute)
+	s.APIKeys = []string{"secret"}
+	tests := map[string]struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		"list":    {"/schema", http.StatusOK, "application/json"},
+		"schema":  {"/schema/forecast", http.StatusOK, "application/schema+json"},
+		"file":    {"/schema/alert.schema.json", http.StatusOK, "application/schema+json"},
+		"unknown": {"/schema/moon", http.StatusNotFound, "application/json"},
+	}
+	for name, tc := range tests {
+		resp := httptest.NewRecorder()
+		s.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if resp.Code != tc.status || resp.Header().Get("Content-Type") != tc.contentType {
+			t.Errorf("%s: want %d %s, got %d %s", name, tc.status, tc.contentType, resp.Code, resp.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package weather
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	CommandServe = "serve"
+
+	// event types of /events
+	EventConditions = "conditions"
+	EventAlert      = "alert"
+
+	// maxCacheEntries bounds the cache, the oldest entry makes room for a new one
+	maxCacheEntries = 1000
+)
+
+type (
+	// Server ... HTTP server exposing the weather as JSON, responses of the provider are cached for TTL
+	//
+	// The zero value needs a Provider only, without TTL nothing is cached.
+	Server struct {
+		Provider Provider
+		TTL      time.Duration
+		// APIKeys protect the endpoints if set, a key is accepted as X-API-Key header, bearer token or key parameter
+		APIKeys []string
+		// Resolve maps locations before they are looked up, e.g. to replace aliases
+		Resolve func(location string) string
+
+		mu          sync.Mutex
+		cache       map[string]cacheEntry
+		inflight    map[string]*flight
+		history     map[string]*history
+		subscribers map[string]*subscription
+	}
+
+	// Event ... update of a location sent to the subscribers of /events
+	Event struct {
+		Type       string      `json:"type"`
+		Location   string      `json:"location"`
+		Conditions *Conditions `json:"conditions,omitempty"`
+		Alert      *Alert      `json:"alert,omitempty"`
+	}
+
+	subscription struct {
+		location string
+		channels map[chan Event]bool
+	}
+
+	cacheEntry struct {
+		fetched    time.Time
+		conditions Conditions
+		forecast   Forecast
+	}
+
+	// flight ... request to the provider, which concurrent misses of the same location wait for
+	flight struct {
+		done       chan struct{}
+		conditions Conditions
+		forecast   Forecast
+		err        error
+	}
+)
+
+// NewServer ... server for the provider with responses cached for ttl
+func NewServer(p Provider, ttl time.Duration) *Server {
+	return &Server{
+		Provider: p,
+		TTL:      ttl,
+	}
+}
+
+// Handler ... routes of the server, /v1/current, /v1/forecast, /v1/alerts and /v1/chart.svg expect a location parameter,
+// /grafana/ is a datasource for the simple-json and Infinity plugins of Grafana, /schema serves the JSON Schemas
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/current", s.authorized(s.handleCurrent))
+	mux.HandleFunc("/v1/forecast", s.authorized(s.handleForecast))
+	mux.HandleFunc("/v1/alerts", s.authorized(s.handleAlerts))
+	mux.HandleFunc("/v1/chart.svg", s.authorized(s.handleChart))
+	mux.HandleFunc("/events", s.authorized(s.handleEvents))
+	mux.HandleFunc("/grafana/", s.authorized(s.handleGrafana))
+	mux.HandleFunc("/grafana/search", s.authorized(s.handleGrafanaSearch))
+	mux.HandleFunc("/grafana/query", s.authorized(s.handleGrafanaQuery))
+	// the schemas contain no weather data, so clients can fetch them without a key
+	mux.HandleFunc("/schema", s.handleSchema)
+	mux.HandleFunc("/schema/", s.handleSchema)
+	return mux
+}
+
+func (s *Server) handleCurrent(w http.ResponseWriter, r *http.Request) {
+	conditions, forecast, err := s.get(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, currentJSON{Conditions: conditions, Alerts: forecast.CurrentAlerts()})
+}
+
+func (s *Server) handleForecast(w http.ResponseWriter, r *http.Request) {
+	_, forecast, err := s.get(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, forecast)
+}
+
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	_, forecast, err := s.get(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	alerts := forecastAlerts(forecast)
+	writeJSON(w, http.StatusOK, alertsJSON{forecast.Place, len(alerts), alerts})
+}
+
+// authorized ... rejects requests without one of the API keys, if keys are configured
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.APIKeys) == 0 {
+			next(w, r)
+			return
+		}
+		key := r.Header.Get("X-API-Key")
+		if key == "" {
+			key = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if key == "" {
+			key = r.URL.Query().Get("key")
+		}
+		if s.ValidKey(key) {
+			next(w, r)
+			return
+		}
+		writeJSON(w, http.StatusUnauthorized, errorResponse{"missing or invalid API key"})
+	}
+}
+
+// ValidKey ... whether key is one of the API keys, compared in constant time
+func (s *Server) ValidKey(key string) bool {
+	for _, valid := range s.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(valid)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrBadRequest ... marks errors caused by the request instead of the provider
+var ErrBadRequest = errors.New("bad request")
+
+// get ... weather for the location of the request, from the cache if fresh enough
+func (s *Server) get(r *http.Request) (Conditions, Forecast, error) {
+	return s.Lookup(r.URL.Query().Get("location"))
+}
+
+// Lookup ... weather for a location given by a client, aliases are resolved first
+func (s *Server) Lookup(location string) (Conditions, Forecast, error) {
+	location, err := s.normalize(location)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	return s.Get(location)
+}
+
+// normalize ... location given by a client as passed to the provider
+func (s *Server) normalize(location string) (string, error) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return "", fmt.Errorf("%w: missing location parameter", ErrBadRequest)
+	}
+	location = strings.ReplaceAll(location, " ", "+")
+	if s.Resolve != nil {
+		location = s.Resolve(location)
+	}
+	return location, nil
+}
+
+// Get ... weather for the location, from the cache if fresh enough
+//
+// Concurrent misses of the same location share one request to the provider.
+func (s *Server) Get(location string) (Conditions, Forecast, error) {
+	key := strings.ToLower(location)
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	if ok && time.Now().Sub(entry.fetched) < s.TTL {
+		s.mu.Unlock()
+		return entry.conditions, entry.forecast, nil
+	}
+	if f, ok := s.inflight[key]; ok {
+		s.mu.Unlock()
+		<-f.done
+		return f.conditions, f.forecast, f.err
+	}
+	f := &flight{done: make(chan struct{})}
+	if s.inflight == nil {
+		s.inflight = map[string]*flight{}
+	}
+	s.inflight[key] = f
+	s.mu.Unlock()
+
+	f.conditions, f.forecast, f.err = s.fetch(location)
+	s.mu.Lock()
+	delete(s.inflight, key)
+	if f.err == nil {
+		s.store(key, location, cacheEntry{
+			fetched:    time.Now(),
+			conditions: f.conditions,
+			forecast:   f.forecast,
+		})
+	}
+	s.mu.Unlock()
+	close(f.done)
+	return f.conditions, f.forecast, f.err
+}
+
+// fetch ... weather from the provider, the forecast is copied as the provider may reuse its slices
+func (s *Server) fetch(location string) (Conditions, Forecast, error) {
+	if s.Provider == nil {
+		return Conditions{}, Forecast{}, errors.New("server without provider")
+	}
+	conditions, forecast, err := s.Provider.Get(location)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	return conditions, cloneForecast(forecast), nil
+}
+
+// store ... caches the entry and records its history, dropping expired entries first and the oldest one
+// if the cache is full, entries of subscribed locations are kept to detect changes on the next refresh
+//
+// s.mu must be held.
+func (s *Server) store(key, location string, entry cacheEntry) {
+	s.record(key, location, entry)
+	if s.cache == nil {
+		s.cache = map[string]cacheEntry{}
+	}
+	oldest := ""
+	for k, e := range s.cache {
+		if k == key || s.subscribers[k] != nil {
+			continue
+		}
+		if entry.fetched.Sub(e.fetched) >= s.TTL {
+			delete(s.cache, k)
+			continue
+		}
+		if oldest == "" || e.fetched.Before(s.cache[oldest].fetched) {
+			oldest = k
+		}
+	}
+	if _, ok := s.cache[key]; !ok && len(s.cache) >= maxCacheEntries && oldest != "" {
+		delete(s.cache, oldest)
+	}
+	s.cache[key] = entry
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError ... bad requests are explained, provider errors are only logged, as they may contain the URL with the API key
+func writeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrBadRequest) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+	log.Printf("upstream error: %v", err)
+	writeJSON(w, http.StatusBadGateway, errorResponse{"upstream unavailable"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func runServe(env *cliEnv, args []string) error {
+	s, listen, err := env.server(CommandServe, ":8080", args)
+	if err != nil {
+		return err
+	}
+	go s.Poll(context.Background(), s.TTL)
+	log.Printf("listening on %s", listen)
+	return newHTTPServer(listen, s.Handler()).ListenAndServe()
+}
+
+// newHTTPServer ... server with timeouts against slow clients, without write timeout as /events streams
+func newHTTPServer(addr string, h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+}
+
+// serveFlags ... flags of the serve commands, writing into the given variables
+func serveFlags(name string, opts *Options, listen *string, ttl *time.Duration, apiKey *string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(listen, "listen", *listen, "address to listen on")
+	fs.DurationVar(ttl, "ttl", *ttl, "how long responses are cached")
+	fs.StringVar(apiKey, "api-key", *apiKey, "key required from clients, defaults to WEATHER_SERVE_API_KEY")
+	fs.StringVar(&opts.Units, "units", opts.Units, "units: metric, imperial or standard")
+	fs.StringVar(&opts.Lang, "lang", opts.Lang, "language of the weather descriptions")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [FLAGS]\n\nFlags:\n", name)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// server ... server configured by the flags of the serve commands, together with the listen address
+func (env *cliEnv) server(name, listen string, args []string) (*Server, string, error) {
+	opts := NewOptions(env.cfg)
+	ttl := 10 * time.Minute
+	apiKey := os.Getenv("WEATHER_SERVE_API_KEY")
+	fs := serveFlags(name, &opts, &listen, &ttl, &apiKey)
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, "", err
+	}
+	if !validUnits[opts.Units] {
+		return nil, "", fmt.Errorf("invalid units %q, want metric, imperial or standard", opts.Units)
+	}
+	c, err := env.client()
+	if err != nil {
+		return nil, "", err
+	}
+	c.Units = opts.Units
+	c.Lang = opts.Lang
+	// completed like the weather of the commands, with the alert sources, the pressure trend and more
+	s := NewServer(env.provider(c, opts), ttl)
+	s.Resolve = env.cfg.ResolveLocation
+	if apiKey != "" {
+		s.APIKeys = []string{apiKey}
+	}
+	return s, listen, nil
+}
@@ -0,0 +1,285 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServerCurrent(t *testing.T) {
+	t.Parallel()
+	s := weather.NewServer(newMock(), time.Minute)
+	resp := httptest.NewRecorder()
+	s.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/current?location=Bonn", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", resp.Code)
+	}
+	var got struct {
+		Conditions weather.Conditions
+		Alerts     []weather.Alert
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Conditions.Temperature != 21.5 {
+		t.Errorf("want temperature 21.5, got %v", got.Conditions.Temperature)
+	}
+	want := []weather.Alert{{Name: "Hitze"}}
+	if !cmp.Equal(want, got.Alerts) {
+		t.Error(cmp.Diff(want, got.Alerts))
+	}
+}
+
+func TestServerCachesResponses(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	h := weather.NewServer(m, time.Hour).Handler()
+	for _, target := range []string{"/v1/forecast?location=Bonn", "/v1/current?location=bonn"} {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+		if resp.Code != http.StatusOK {
+			t.Fatalf("%s: want status 200, got %d", target, resp.Code)
+		}
+	}
+	want := []string{"Bonn"}
+	if !cmp.Equal(want, m.Locations()) {
+		t.Error(cmp.Diff(want, m.Locations()))
+	}
+}
+
+func TestServerRefetchesAfterTTL(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	h := weather.NewServer(m, 0).Handler()
+	for i := 0; i < 2; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/forecast?location=Bonn", nil))
+	}
+	if len(m.Locations()) != 2 {
+		t.Errorf("want 2 provider calls, got %d", len(m.Locations()))
+	}
+}
+
+// blockingProvider ... provider whose Get waits for release, so requests overlap
+type blockingProvider struct {
+	*weathertest.Mock
+	started chan bool
+	release chan bool
+}
+
+func (p blockingProvider) Get(location string) (weather.Conditions, weather.Forecast, error) {
+	p.started <- true
+	<-p.release
+	return p.Mock.Get(location)
+}
+
+func TestServerSharesConcurrentMisses(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	p := blockingProvider{m, make(chan bool, 10), make(chan bool)}
+	s := weather.NewServer(p, time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := s.Get("Bonn")
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	<-p.started
+	// give the other requests time to queue up behind the first one
+	time.Sleep(20 * time.Millisecond)
+	close(p.release)
+	wg.Wait()
+	want := []string{"Bonn"}
+	if !cmp.Equal(want, m.Locations()) {
+		t.Error(cmp.Diff(want, m.Locations()))
+	}
+}
+
+func TestServerZeroValue(t *testing.T) {
+	t.Parallel()
+	s := &weather.Server{Provider: newMock(), TTL: time.Minute}
+	resp := httptest.NewRecorder()
+	s.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/current?location=Bonn", nil))
+	if resp.Code != http.StatusOK {
+		t.Errorf("want status 200, got %d", resp.Code)
+	}
+	ch := s.Subscribe("Bonn")
+	s.Unsubscribe("Bonn", ch)
+	err := s.Refresh("Bonn")
+	if err != nil {
+		t.Error(err)
+	}
+
+	resp = httptest.NewRecorder()
+	(&weather.Server{}).Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/current?location=Bonn", nil))
+	if resp.Code != http.StatusBadGateway {
+		t.Errorf("want status 502 without provider, got %d", resp.Code)
+	}
+}
+
+func TestServerResolvesAliases(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	s := weather.NewServer(m, time.Minute)
+	s.Resolve = weather.Config{Locations: map[string]string{"home": "Bonn,DE"}}.ResolveLocation
+	s.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/forecast?location=home", nil))
+	want := []string{"Bonn,DE"}
+	if !cmp.Equal(want, m.Locations()) {
+		t.Error(cmp.Diff(want, m.Locations()))
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	m.Err = errors.New("location not found")
+	h := weather.NewServer(m, time.Minute).Handler()
+	tests := map[string]int{
+		"/v1/current":                   http.StatusBadRequest,
+		"/v1/forecast?location=Nowhere": http.StatusBadGateway,
+	}
+	for target, want := range tests {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+		if resp.Code != want {
+			t.Errorf("%s: want status %d, got %d", target, want, resp.Code)
+		}
+		if !strings.Contains(resp.Body.String(), `"error"`) {
+			t.Errorf("%s: want error in body, got %q", target, resp.Body.String())
+		}
+	}
+}
+
+func TestServerHidesUpstreamErrors(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	m.Err = errors.New(`Get "https://api.openweathermap.org/data/3.0/onecall?appid=SECRET-OWM-KEY": dial tcp: i/o timeout`)
+	resp := httptest.NewRecorder()
+	weather.NewServer(m, time.Minute).Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/current?location=Bonn", nil))
+	if resp.Code != http.StatusBadGateway {
+		t.Errorf("want status 502, got %d", resp.Code)
+	}
+	if strings.Contains(resp.Body.String(), "SECRET-OWM-KEY") {
+		t.Errorf("want API key hidden, got %q", resp.Body.String())
+	}
+}
+
+func TestServerAPIKey(t *testing.T) {
+	t.Parallel()
+	s := weather.NewServer(newMock(), time.Minute)
+	s.APIKeys = []string{"secret"}
+	h := s.Handler()
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		target string
+		want   int
+	}{
+		{name: "missing", target: "/v1/current?location=Bonn", want: http.StatusUnauthorized},
+		{name: "wrong", header: "X-API-Key", value: "guess", target: "/v1/current?location=Bonn", want: http.StatusUnauthorized},
+		{name: "header", header: "X-API-Key", value: "secret", target: "/v1/current?location=Bonn", want: http.StatusOK},
+		{name: "bearer", header: "Authorization", value: "Bearer secret", target: "/v1/current?location=Bonn", want: http.StatusOK},
+		{name: "query", target: "/v1/current?location=Bonn&key=secret", want: http.StatusOK},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		if tc.header != "" {
+			req.Header.Set(tc.header, tc.value)
+		}
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		if resp.Code != tc.want {
+			t.Errorf("%s: want status %d, got %d", tc.name, tc.want, resp.Code)
+		}
+	}
+}
+
+func TestServerChart(t *testing.T) {
+	t.Parallel()
+	m := newMock()
+	m.Forecast.Hourly = []weather.ForecastHourly{{Day: "Fr, 17.06.", Hour: "12:00", Temperature: 21.5, RainChance: 40}}
+	h := weather.NewServer(m, time.Minute).Handler()
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/chart.svg?location=Bonn&series=temp,rain", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", resp.Code)
+	}
+	if got := resp.Header().Get("Content-Type"); got != "image/svg+xml" {
+		t.Errorf("want content type image/svg+xml, got %q", got)
+	}
+	if !strings.HasPrefix(resp.Body.String(), "<svg ") || strings.Contains(resp.Body.String(), `class="wind"`) {
+		t.Errorf("want the SVG chart without wind, got %q", resp.Body.String())
+	}
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/chart.svg?location=Bonn&series=moon", nil))
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("want status 400 for an unknown series, got %d", resp.Code)
+	}
+
+	resp = httptest.NewRecorder()
+	weather.NewServer(newMock(), time.Minute).Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/chart.svg?location=Bonn", nil))
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("want status 404 without hourly forecast, got %d", resp.Code)
+	}
+}
+
+// served ... server of the serve command of TestServeConfiguredProvider, kept outside of the closure,
+// which stays registered for later runs of the test
+var served *weather.Server
+
+func TestServeConfiguredProvider(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	t.Cleanup(ts.Close)
+	cfg := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(cfg, []byte(`{"base_url": "`+ts.URL+`", "meteoalarm_url": "`+ts.URL+`", "alert_sources": ["meteoalarm"]}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WEATHER_CONFIG", cfg)
+	t.Setenv("OPENWEATHERMAP_API_KEY", "dummyAPIKey")
+	sc := weather.ServeCommand{
+		Name: "test-serve",
+		Serve: func(s *weather.Server, listen string) error {
+			served = s
+			return nil
+		},
+	}
+	err = weather.Run([]string{"weather", sc.Name}, sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := httptest.NewRecorder()
+	served.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/alerts?location=Bonn,DE", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d: %s", resp.Code, resp.Body)
+	}
+	var got struct {
+		Alerts []struct {
+			Severity string
+		}
+	}
+	err = json.Unmarshal(resp.Body.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("invalid response %s: %v", resp.Body, err)
+	}
+	if len(got.Alerts) != 2 || got.Alerts[0].Severity != "severe" {
+		t.Errorf("want the 2 MeteoAlarm alerts, got %s", resp.Body)
+	}
+}
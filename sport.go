@@ -0,0 +1,149 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	FunctionRun = "run"
+
+	// the hours of the day considered for outdoor sport, from 06:00 till 22:00
+	sportFrom = "06:00"
+	sportTo   = "22:00"
+	// sportWindow ... longest window of consecutive hours suggested for a day
+	sportWindow = 2 * time.Hour
+	// sportPairBonus ... points a single hour has to beat the best window of 2 hours by to be suggested instead
+	sportPairBonus = 5
+)
+
+type (
+	// SportHour ... how good an hour of the forecast is for running and other outdoor sport, Score is 0 to 100
+	SportHour struct {
+		Time  time.Time `json:"time"`
+		Day   string    `json:"day"`
+		Hour  string    `json:"hour"`
+		Score int       `json:"score"`
+	}
+
+	// SportWindow ... best window of 1 to 2 hours of a day, Score is the mean of its hours
+	SportWindow struct {
+		Day   string `json:"day"`
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Score int    `json:"score"`
+	}
+)
+
+// SportScore ... how good the weather is for outdoor exercise from 0 to 1, from temperature in °C, humidity,
+// wind in m/s, UV index, rain chance and precipitation in mm, best between 8 and 15 °C with little wind and sun
+func SportScore(celsius float64, humidity int, wind Speed, uvi UVIndex, rainChance, rain float64) float64 {
+	clamp := func(v float64) float64 {
+		return math.Max(0, math.Min(1, v))
+	}
+	warmth := 1.0
+	switch {
+	case celsius < 8:
+		warmth = clamp((celsius + 10) / 18)
+	case celsius > 15:
+		warmth = clamp((30 - celsius) / 15)
+	}
+	// humid air only hurts when it's warm
+	muggy := 1.0
+	if celsius > 15 {
+		muggy = 1 - 0.4*clamp(float64(humidity-60)/40)
+	}
+	breeze := 1 - clamp((float64(wind)-5)/10)
+	sun := 1 - 0.5*clamp((float64(uvi)-3)/7)
+	wet := 1 - 0.6*clamp(rainChance/100)
+	if rain > 0.5 {
+		wet *= 0.3
+	}
+	return warmth * muggy * breeze * sun * wet
+}
+
+// GetSportHours ... hours of the hourly forecast between 06:00 and 22:00 scored for outdoor sport
+func GetSportHours(f Forecast) []SportHour {
+	hours := []SportHour{}
+	for _, slot := range f.Hourly {
+		if slot.Hour < sportFrom || slot.Hour >= sportTo {
+			continue
+		}
+		score := SportScore(convertTemperature(slot.Temperature, f.Units, UnitsMetric), slot.Humidity,
+			metersPerSecond(slot.WindSpeed, f.Units), slot.UVI, slot.RainChance, slot.Rain+slot.Snow)
+		hours = append(hours, SportHour{Time: slot.Time, Day: slot.Day, Hour: slot.Hour, Score: int(math.Round(score * 100))})
+	}
+	return hours
+}
+
+// BestSportWindows ... best window of each day, 2 consecutive hours unless a single hour scores more than
+// 5 points better, the earliest of equal ones, the slots of 3 hours of the free API count as one window
+func BestSportWindows(hours []SportHour) []SportWindow {
+	windows := []SportWindow{}
+	// single and pair are the best single hour and the best 2 hours of the current day
+	var single, pair *SportWindow
+	flush := func() {
+		switch {
+		case pair != nil && (single == nil || pair.Score >= single.Score-sportPairBonus):
+			windows = append(windows, *pair)
+		case single != nil:
+			windows = append(windows, *single)
+		}
+		single, pair = nil, nil
+	}
+	for i, h := range hours {
+		if i > 0 && hours[i-1].Day != h.Day {
+			flush()
+		}
+		length := time.Hour
+		next := i+1 < len(hours) && hours[i+1].Day == h.Day
+		if next {
+			length = hours[i+1].Time.Sub(h.Time)
+		}
+		if single == nil || h.Score > single.Score {
+			single = &SportWindow{Day: h.Day, From: h.Hour, To: h.Time.Add(length).Format("15:04"), Score: h.Score}
+		}
+		if !next || length != time.Hour {
+			continue
+		}
+		mean := int(math.Round(float64(h.Score+hours[i+1].Score) / 2))
+		if pair == nil || mean > pair.Score {
+			pair = &SportWindow{Day: h.Day, From: h.Hour, To: h.Time.Add(sportWindow).Format("15:04"), Score: mean}
+		}
+	}
+	flush()
+	return windows
+}
+
+// sportRating ... sehr gut from 80, gut from 60, mäßig from 40, otherwise schlecht
+func sportRating(score int) string {
+	switch {
+	case score >= 80:
+		return "sehr gut"
+	case score >= 60:
+		return "gut"
+	case score >= 40:
+		return "mäßig"
+	}
+	return "schlecht"
+}
+
+// PrintSport ... best windows of the days for a run followed by the scores of the hours
+func PrintSport(f Forecast, hours int) {
+	fmt.Println()
+	printHeader("Laufwetter", f)
+	sportHours := GetSportHours(f)
+	for _, w := range BestSportWindows(sportHours) {
+		fmt.Printf("%s: am besten von %s bis %s (%d Punkte, %s)\n", w.Day, w.From, w.To, w.Score, sportRating(w.Score))
+	}
+	fmt.Println()
+	end := time.Now().Add(time.Duration(hours) * time.Hour)
+	for _, h := range sportHours {
+		if h.Time.After(end) {
+			break
+		}
+		fmt.Printf("%s %s  %3d  %s\n", h.Day, h.Hour, h.Score, sportRating(h.Score))
+	}
+	fmt.Println()
+}
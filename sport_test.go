@@ -0,0 +1,57 @@
+package weather_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSportScore(t *testing.T) {
+	t.Parallel()
+	if got := weather.SportScore(12, 60, 2, 1, 0, 0); got != 1 {
+		t.Errorf("want perfect running weather at 12 °C, got %.2f", got)
+	}
+	if got := weather.SportScore(30, 90, 2, 8, 0, 0); got != 0 {
+		t.Errorf("want no running at 30 °C, got %.2f", got)
+	}
+	if got := weather.SportScore(12, 60, 2, 1, 90, 2); got > 0.2 {
+		t.Errorf("want a poor score in heavy rain, got %.2f", got)
+	}
+}
+
+func TestBestSportWindows(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2022, 6, 17, 6, 0, 0, 0, time.Local)
+	hour := func(day, h, score int) weather.SportHour {
+		at := start.AddDate(0, 0, day).Add(time.Duration(h) * time.Hour)
+		return weather.SportHour{Time: at, Day: at.Format("02.01.2006"), Hour: at.Format("15:04"), Score: score}
+	}
+	hours := []weather.SportHour{
+		hour(0, 0, 70), hour(0, 1, 85), hour(0, 2, 80), hour(0, 3, 40),
+		// a single peak beats the pairs around it
+		hour(1, 0, 30), hour(1, 1, 95), hour(1, 2, 40),
+	}
+	want := []weather.SportWindow{
+		{Day: "17.06.2022", From: "07:00", To: "09:00", Score: 83},
+		{Day: "18.06.2022", From: "07:00", To: "08:00", Score: 95},
+	}
+	if got := weather.BestSportWindows(hours); !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestGetSportHours(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2022, 6, 17, 0, 0, 0, 0, time.Local)
+	f := weather.Forecast{Units: weather.UnitsMetric}
+	for h := 0; h < 24; h++ {
+		at := start.Add(time.Duration(h) * time.Hour)
+		f.Hourly = append(f.Hourly, weather.ForecastHourly{Time: at, Day: at.Format("02.01.2006"), Hour: at.Format("15:04"), Temperature: 12})
+	}
+	got := weather.GetSportHours(f)
+	if len(got) != 16 || got[0].Hour != "06:00" || got[15].Hour != "21:00" || got[0].Score != 100 {
+		t.Errorf("want the 16 hours from 06:00 till 22:00, got %+v", got)
+	}
+}
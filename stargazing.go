@@ -0,0 +1,145 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	FunctionStargazing = "stargazing"
+
+	// the dark hours of a night, from 22:00 till 04:00 of the next day, in minutes after midnight of the day
+	darkStart = 22 * 60
+	darkEnd   = 28 * 60
+	// moonGlare ... share of the score a full moon above the horizon all night takes away
+	moonGlare = 0.7
+)
+
+// StargazingNight ... how good the night following the day is for watching the stars, Score is 0 to 100
+type StargazingNight struct {
+	Day    string `json:"day"`
+	Clouds int    `json:"clouds"`
+	// Illumination is the lit fraction of the moon, MoonUp the fraction of the dark hours it is above the horizon
+	Illumination float64 `json:"moon_illumination"`
+	MoonUp       float64 `json:"moon_up"`
+	Score        int     `json:"score"`
+}
+
+// minutes ... minutes after midnight of a time like 21:30, false if it isn't one
+func minutes(hhmm string) (int, bool) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// moonUpFraction ... fraction of the dark hours the moon is above the horizon, taking the moon to rise and set
+// at the same time the next day, all night without usable times
+func moonUpFraction(moonrise, moonset string) float64 {
+	rise, okRise := minutes(moonrise)
+	set, okSet := minutes(moonset)
+	if !okRise || !okSet {
+		return 1
+	}
+	if set < rise {
+		// up over midnight
+		set += 24 * 60
+	}
+	up := 0
+	// the moon up in the evening of the day or after midnight, which is the day after
+	for _, shift := range []int{-24 * 60, 0, 24 * 60} {
+		from, to := rise+shift, set+shift
+		if from < darkStart {
+			from = darkStart
+		}
+		if to > darkEnd {
+			to = darkEnd
+		}
+		if to > from {
+			up += to - from
+		}
+	}
+	return math.Min(1, float64(up)/float64(darkEnd-darkStart))
+}
+
+// nightClouds ... mean cloud cover of the dark hours of the night after the day in the hourly forecast,
+// the cloud cover of the day if the hourly forecast doesn't reach that far
+func nightClouds(f Forecast, offset int) int {
+	day := f.Daily[offset]
+	next := ""
+	if offset+1 < len(f.Daily) {
+		next = f.Daily[offset+1].Day
+	}
+	sum, n := 0, 0
+	for _, slot := range f.Hourly {
+		if (slot.Day == day.Day && slot.Hour >= "22:00") || (slot.Day == next && slot.Hour < "04:00") {
+			sum += slot.Clouds
+			n++
+		}
+	}
+	if n < 3 {
+		return day.Clouds
+	}
+	return (sum + n/2) / n
+}
+
+// GetStargazing ... nights of the forecast days scored by cloud cover, moon illumination and the time the moon is up
+func GetStargazing(f Forecast) []StargazingNight {
+	nights := []StargazingNight{}
+	for i, day := range f.Daily {
+		n := StargazingNight{
+			Day:          day.Day,
+			Clouds:       nightClouds(f, i),
+			Illumination: math.Round(day.Moonphase.Illumination()*100) / 100,
+			MoonUp:       math.Round(moonUpFraction(day.Moonrise, day.Moonset)*100) / 100,
+		}
+		score := (1 - float64(n.Clouds)/100) * (1 - moonGlare*n.Illumination*n.MoonUp)
+		n.Score = int(math.Round(score * 100))
+		nights = append(nights, n)
+	}
+	return nights
+}
+
+// BestNight ... index of the night with the highest score, the earliest of equal ones, -1 without nights
+func BestNight(nights []StargazingNight) int {
+	best := -1
+	for i, n := range nights {
+		if best < 0 || n.Score > nights[best].Score {
+			best = i
+		}
+	}
+	return best
+}
+
+// Rating ... sehr gut from 80, gut from 60, mäßig from 40, otherwise schlecht
+func (n StargazingNight) Rating() string {
+	switch {
+	case n.Score >= 80:
+		return "sehr gut"
+	case n.Score >= 60:
+		return "gut"
+	case n.Score >= 40:
+		return "mäßig"
+	}
+	return "schlecht"
+}
+
+// PrintStargazing ... nights of the week for amateur astronomers, the best one is marked
+func PrintStargazing(f Forecast) {
+	fmt.Println()
+	printHeader("Sternenhimmel der nächsten Nächte (22 - 4 Uhr)", f)
+	nights := GetStargazing(f)
+	best := BestNight(nights)
+	fmt.Printf("%-10s  %7s  %6s  %9s  %6s  %s\n", "Nacht vom", "Wolken", "Mond", "Mond auf", "Punkte", "Bewertung")
+	for i, n := range nights {
+		mark := ""
+		if i == best && n.Score > 0 {
+			mark = " ← beste Nacht"
+		}
+		fmt.Printf("%-10s  %5d %%  %4.0f %%  %7.0f %%  %6d  %s%s\n",
+			n.Day, n.Clouds, n.Illumination*100, n.MoonUp*100, n.Score, n.Rating(), mark)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,48 @@
+package weather_test
+
+import (
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetStargazing(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{
+		Hourly: []weather.ForecastHourly{
+			{Day: "17.06.2022", Hour: "21:00", Clouds: 100},
+			{Day: "17.06.2022", Hour: "22:00", Clouds: 20},
+			{Day: "17.06.2022", Hour: "23:00", Clouds: 10},
+			{Day: "18.06.2022", Hour: "00:00", Clouds: 0},
+			{Day: "18.06.2022", Hour: "03:00", Clouds: 10},
+			{Day: "18.06.2022", Hour: "04:00", Clouds: 100},
+		},
+		Daily: []weather.ForecastDaily{
+			// full moon rising at dusk, up all night
+			{Day: "17.06.2022", Clouds: 80, Moonphase: 0.5, Moonrise: "21:30", Moonset: "05:10"},
+			// new moon
+			{Day: "18.06.2022", Clouds: 50, Moonphase: 0, Moonrise: "05:00", Moonset: "21:00"},
+			// waxing half moon setting at midnight
+			{Day: "19.06.2022", Clouds: 0, Moonphase: 0.25, Moonrise: "12:00", Moonset: "00:00"},
+		},
+	}
+	got := weather.GetStargazing(f)
+	want := []weather.StargazingNight{
+		{Day: "17.06.2022", Clouds: 10, Illumination: 1, MoonUp: 1, Score: 27},
+		{Day: "18.06.2022", Clouds: 50, Illumination: 0, MoonUp: 0, Score: 50},
+		{Day: "19.06.2022", Clouds: 0, Illumination: 0.5, MoonUp: 0.33, Score: 88},
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	if best := weather.BestNight(got); best != 2 {
+		t.Errorf("want the third night to be the best, got %d", best)
+	}
+	if rating := got[2].Rating(); rating != "sehr gut" {
+		t.Errorf("want sehr gut, got %s", rating)
+	}
+	if best := weather.BestNight(nil); best != -1 {
+		t.Errorf("want -1 without nights, got %d", best)
+	}
+}
@@ -0,0 +1,142 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	FunctionStatusbar = "statusbar"
+
+	// FormatStatusbar ... output format of a short line for status bars, refreshed by the bar or by --follow
+	FormatStatusbar = "statusbar"
+
+	// DefaultStatusbarTemplate ... line of the statusbar command unless --template is given
+	DefaultStatusbarTemplate = "%c %t"
+
+	// status bars whose colour codes --bar adds to the line
+	BarTmux     = "tmux"
+	BarPolybar  = "polybar"
+	BarI3blocks = "i3blocks"
+)
+
+var validBars = map[string]bool{
+	"":          true,
+	BarTmux:     true,
+	BarPolybar:  true,
+	BarI3blocks: true,
+}
+
+// barColors ... colours of the status bars for the SGR codes of the text output
+var barColors = map[string]string{
+	ansiBlue:       "#5f87ff",
+	ansiBoldBlue:   "#5f87ff",
+	ansiCyan:       "#00afaf",
+	ansiGreen:      "#5faf00",
+	ansiYellow:     "#d7af00",
+	ansiBoldYellow: "#ffaf00",
+	ansiRed:        "#d70000",
+	ansiBoldRed:    "#ff0000",
+}
+
+// statusbarSink ... prints the status line or the Waybar object of every refresh for --follow
+type statusbarSink struct {
+	w        io.Writer
+	format   string
+	bar      string
+	template string
+}
+
+// statusColor ... colour of the status line, by the most severe current alert or else by the temperature
+func statusColor(f Forecast, temperature float64) string {
+	alerts := f.CurrentAlerts()
+	if len(alerts) == 0 {
+		return temperatureColor(convertTemperature(temperature, f.Units, UnitsMetric))
+	}
+	severity := AlertUnknown
+	for _, a := range alerts {
+		if a.Severity.Rank() > severity.Rank() {
+			severity = a.Severity
+		}
+	}
+	if code := alertColor(severity); code != "" {
+		return code
+	}
+	return ansiYellow
+}
+
+// StatusLine ... the weather of the template as line for the status bar, led by ⚠ if there are alerts and
+// coloured with the codes of the bar, plain without bar; i3blocks takes full text, short text and colour
+// as three lines
+func StatusLine(bar, template string, c Conditions, f Forecast) string {
+	line := FormatOneline(template, c, f)
+	if len(f.CurrentAlerts()) > 0 {
+		line = "⚠ " + line
+	}
+	color := barColors[statusColor(f, c.Temperature)]
+	switch bar {
+	case BarTmux:
+		return fmt.Sprintf("#[fg=%s]%s#[default]", color, line)
+	case BarPolybar:
+		return fmt.Sprintf("%%{F%s}%s%%{F-}", color, line)
+	case BarI3blocks:
+		return fmt.Sprintf("%s\n%s\n%s", line, line, color)
+	}
+	return line
+}
+
+func (s *statusbarSink) Write(o Observation) error {
+	o.Forecast.Place = o.Location
+	if s.format == FormatWaybar {
+		return writeWaybar(s.w, GetWaybar(s.template, o.Conditions, o.Forecast))
+	}
+	_, err := fmt.Fprintln(s.w, StatusLine(s.bar, s.template, o.Conditions, o.Forecast))
+	return err
+}
+
+// statusbarFlags ... flags of the statusbar command, which prints --format statusbar by default
+func statusbarFlags(fs *flag.FlagSet, opts *Options) {
+	opts.Format = FormatStatusbar
+	opts.Template = DefaultStatusbarTemplate
+	templateFlag(fs, opts)
+	fs.StringVar(&opts.Bar, "bar", "", "colour codes of the status bar for --format statusbar: tmux, polybar or i3blocks")
+	fs.BoolVar(&opts.Follow, "follow", false, "keep running and print the line again on every refresh of --interval")
+}
+
+func validateStatusbar(fs *flag.FlagSet, opts *Options) error {
+	if !validBars[opts.Bar] {
+		return fmt.Errorf("invalid bar %q, want tmux, polybar or i3blocks", opts.Bar)
+	}
+	if opts.Follow && opts.Format == FormatJSON {
+		return errors.New("--follow prints text, statusbar or waybar lines, not json")
+	}
+	return nil
+}
+
+// runFollow ... prints the status line or the Waybar object right away and again on every refresh of the interval, failed refreshes
+// keep the last line
+func (env *cliEnv) runFollow(opts Options) error {
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return err
+	}
+	c.Exclude = opts.Exclude
+	f := Forecast{Place: placeName(c, coordinates)}
+	bar := ""
+	if opts.Format == FormatStatusbar {
+		bar = opts.Bar
+	}
+	w := &Watcher{
+		Provider:    env.provider(c, opts),
+		Coordinates: coordinates,
+		Location:    notifyLocation(opts, f),
+		Interval:    opts.Interval,
+		Sinks:       []Sink{&statusbarSink{w: os.Stdout, format: opts.Format, bar: bar, template: opts.Template}},
+	}
+	w.Run(context.Background())
+	return nil
+}
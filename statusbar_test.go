@@ -0,0 +1,56 @@
+package weather_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestStatusLine(t *testing.T) {
+	t.Parallel()
+	c := weather.Conditions{Temperature: 24.4, Condition: 800, Icon: "01d"}
+	f := weather.Forecast{Units: weather.UnitsMetric}
+	tests := map[string]string{
+		"":                  "☀ +24°C",
+		weather.BarTmux:     "#[fg=#d7af00]☀ +24°C#[default]",
+		weather.BarPolybar:  "%{F#d7af00}☀ +24°C%{F-}",
+		weather.BarI3blocks: "☀ +24°C\n☀ +24°C\n#d7af00",
+	}
+	for bar, want := range tests {
+		if got := weather.StatusLine(bar, weather.DefaultStatusbarTemplate, c, f); got != want {
+			t.Errorf("%q: want %q, got %q", bar, want, got)
+		}
+	}
+	f.Daily = []weather.ForecastDaily{{Alerts: []weather.Alert{{Name: "Sturm", Severity: weather.AlertExtreme}}}}
+	if got := weather.StatusLine(weather.BarTmux, "%t", c, f); got != "#[fg=#ff0000]⚠ +24°C#[default]" {
+		t.Errorf("want the line led by ⚠ in the colour of the alert, got %q", got)
+	}
+}
+
+func TestStatusbarFormat(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	out, err := runCLI(t, ts, "statusbar", "--bar", "polybar", "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); !strings.HasPrefix(got, "%{F#") || strings.Contains(got, "Bad Schnuffel") {
+		t.Errorf("want the short line with the colour codes of polybar, got %q", got)
+	}
+	for name, args := range map[string][]string{
+		"unknown bar":    {"--bar", "dwm"},
+		"follow as json": {"--format", "json", "--follow"},
+		"other command":  {"current", "--format", "statusbar"},
+		"oneline":        {"oneline", "--format", "waybar"},
+	} {
+		if args[0] != "current" && args[0] != "oneline" {
+			args = append([]string{"statusbar"}, args...)
+		}
+		if _, err := weather.ParseOptions(args[0], args[1:], weather.Config{}); err == nil {
+			t.Errorf("%s: want error, but got nil", name)
+		}
+	}
+}
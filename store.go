@@ -0,0 +1,283 @@
+package weather
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DriverSQLite ... database/sql driver name of the SQLite store, registered by the binary
+	DriverSQLite = "sqlite"
+	// DriverPostgres ... database/sql driver name of the PostgreSQL store, registered by the binary
+	DriverPostgres = "pgx"
+)
+
+type (
+	// Store ... persists observations for the history, implemented by SQLStore
+	Store interface {
+		Sink
+		// Observations are the stored observations of the location between from and to, oldest first
+		Observations(location string, from, to time.Time) ([]Observation, error)
+		Close() error
+	}
+
+	// SQLStore ... Store in a SQL database, the schema is migrated on open
+	SQLStore struct {
+		db      *sql.DB
+		dialect dialect
+	}
+
+	// StoreConfig ... store settings of the config file, the DSN of SQLite defaults to history.db next to the config file
+	StoreConfig struct {
+		Driver string `json:"driver"`
+		DSN    string `json:"dsn,omitempty"`
+	}
+
+	// dialect ... differences of the databases, like placeholders and the schema
+	dialect struct {
+		// migrations are applied in order, each one exactly once
+		migrations []string
+		// placeholder is the n-th query parameter, starting at 1
+		placeholder func(n int) string
+		// timestamp is the value of a time column
+		timestamp func(t time.Time) any
+	}
+)
+
+var _ Store = (*SQLStore)(nil)
+
+var dialects = map[string]dialect{
+	DriverSQLite: {
+		migrations: []string{
+			`CREATE TABLE observations (
+				id INTEGER PRIMARY KEY,
+				location TEXT NOT NULL,
+				time INTEGER NOT NULL,
+				temperature REAL NOT NULL,
+				feels_like REAL NOT NULL,
+				dew_point REAL NOT NULL,
+				pressure INTEGER NOT NULL,
+				humidity INTEGER NOT NULL,
+				wind_speed REAL NOT NULL,
+				wind_gust REAL NOT NULL,
+				wind_direction REAL NOT NULL,
+				uvi REAL NOT NULL,
+				summary TEXT NOT NULL,
+				conditions TEXT NOT NULL,
+				forecast TEXT NOT NULL
+			)`,
+			`CREATE INDEX observations_location_time ON observations (location, time)`,
+		},
+		placeholder: func(n int) string { return "?" },
+		timestamp:   func(t time.Time) any { return t.Unix() },
+	},
+	DriverPostgres: {
+		// without an id column, as the unique indexes of a TimescaleDB hypertable have to contain the time
+		migrations: []string{
+			`CREATE TABLE observations (
+				time TIMESTAMPTZ NOT NULL,
+				location TEXT NOT NULL,
+				temperature DOUBLE PRECISION NOT NULL,
+				feels_like DOUBLE PRECISION NOT NULL,
+				dew_point DOUBLE PRECISION NOT NULL,
+				pressure INTEGER NOT NULL,
+				humidity INTEGER NOT NULL,
+				wind_speed DOUBLE PRECISION NOT NULL,
+				wind_gust DOUBLE PRECISION NOT NULL,
+				wind_direction DOUBLE PRECISION NOT NULL,
+				uvi DOUBLE PRECISION NOT NULL,
+				summary TEXT NOT NULL,
+				conditions JSONB NOT NULL,
+				forecast JSONB NOT NULL
+			)`,
+			`CREATE INDEX observations_location_time ON observations (location, time DESC)`,
+			// partitioned by time if TimescaleDB is installed, a plain table otherwise
+			`DO $$
+			BEGIN
+				IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb') THEN
+					PERFORM create_hypertable('observations', 'time');
+				END IF;
+			END
+			$$`,
+		},
+		placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		timestamp:   func(t time.Time) any { return t.UTC() },
+	},
+}
+
+// OpenSQLStore ... store in the database, the driver has to be registered, e.g. by importing it in the binary
+func OpenSQLStore(driver, dsn string) (*SQLStore, error) {
+	d, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("unsupported store driver %q", driver)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	s := &SQLStore{db: db, dialect: d}
+	err = s.migrate()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating the store: %w", err)
+	}
+	return s, nil
+}
+
+// migrate ... applies the migrations missing in the database
+func (s *SQLStore) migrate() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL)`)
+	if err != nil {
+		return err
+	}
+	var version int
+	err = s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return err
+	}
+	for i := version; i < len(s.dialect.migrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(s.dialect.migrations[i])
+		if err == nil {
+			_, err = tx.Exec(`INSERT INTO schema_migrations (version) VALUES (`+s.dialect.placeholder(1)+`)`, i+1)
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// query ... replaces the ? of the query by the placeholders of the dialect
+func (s *SQLStore) query(q string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			b.WriteString(s.dialect.placeholder(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Write ... stores the observation, the conditions are also kept in columns for queries
+func (s *SQLStore) Write(o Observation) error {
+	conditions, err := json.Marshal(o.Conditions)
+	if err != nil {
+		return err
+	}
+	forecast, err := json.Marshal(o.Forecast)
+	if err != nil {
+		return err
+	}
+	c := o.Conditions
+	_, err = s.db.Exec(s.query(`INSERT INTO observations (location, time, temperature, feels_like, dew_point,
+		pressure, humidity, wind_speed, wind_gust, wind_direction, uvi, summary, conditions, forecast)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		o.Location, s.dialect.timestamp(o.Time), c.Temperature, c.FeelsLike, c.DewPoint, c.Pressure, c.Humidity,
+		float64(c.WindSpeed), float64(c.WindGust), float64(c.WindDirection), float64(c.UVI), c.Summary,
+		string(conditions), string(forecast))
+	return err
+}
+
+// Observations ... stored observations of the location between from and to, oldest first
+func (s *SQLStore) Observations(location string, from, to time.Time) ([]Observation, error) {
+	rows, err := s.db.Query(s.query(`SELECT time, conditions, forecast FROM observations
+		WHERE location = ? AND time >= ? AND time <= ? ORDER BY time`),
+		location, s.dialect.timestamp(from), s.dialect.timestamp(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	observations := []Observation{}
+	for rows.Next() {
+		var unix int64
+		var conditions, forecast string
+		err := rows.Scan(&unix, &conditions, &forecast)
+		if err != nil {
+			return nil, err
+		}
+		o := Observation{Location: location, Time: time.Unix(unix, 0)}
+		err = json.Unmarshal([]byte(conditions), &o.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(forecast), &o.Forecast)
+		if err != nil {
+			return nil, err
+		}
+		observations = append(observations, o)
+	}
+	return observations, rows.Err()
+}
+
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// store ... store of the config file, nil if none is configured
+func (env *cliEnv) store() (Store, error) {
+	cfg := env.cfg.Store
+	if cfg == nil {
+		return nil, nil
+	}
+	driver, dsn := cfg.Driver, cfg.DSN
+	if driver == "" {
+		driver = DriverSQLite
+	}
+	if dsn == "" && driver != DriverSQLite {
+		return nil, fmt.Errorf("store driver %s needs a dsn", driver)
+	}
+	if dsn == "" {
+		dsn = filepath.Join(filepath.Dir(env.cfgPath), "history.db")
+		err := os.MkdirAll(filepath.Dir(dsn), 0o755)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return OpenSQLStore(driver, dsn)
+}
+
+// record ... stores a fetched observation if a store is configured, failures only warn as the weather was fetched
+func (env *cliEnv) record(o Observation) {
+	s, err := env.store()
+	if err == nil && s != nil {
+		err = s.Write(o)
+		s.Close()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "store: %v\n", err)
+	}
+}
+
+// recent ... stored observations of the location since the time, oldest first, nil without a store,
+// failures are ignored as record already warns of a broken store
+func (env *cliEnv) recent(location string, since, now time.Time) []Observation {
+	s, err := env.store()
+	if err != nil || s == nil {
+		return nil
+	}
+	defer s.Close()
+	observations, err := s.Observations(location, since, now)
+	if err != nil {
+		return nil
+	}
+	return observations
+}
@@ -0,0 +1,191 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const FunctionSun = "sun"
+
+// elevations of the sun in degrees at sunrise and sunset, with the refraction at the horizon,
+// and bounding the blue and the golden hour
+const (
+	sunriseElevation = -0.833
+	blueHourStart    = -6.0
+	goldenHourStart  = -4.0
+	goldenHourEnd    = 6.0
+)
+
+type (
+	// TimeWindow ... period of a day like 04:32 - 05:39
+	TimeWindow struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+
+	// PhotoDay ... blue and golden hours of a day in the morning and the evening,
+	// nil where the sun doesn't pass the elevations, as in the summer of the far north
+	PhotoDay struct {
+		BlueMorning   *TimeWindow `json:"blue_morning,omitempty"`
+		GoldenMorning *TimeWindow `json:"golden_morning,omitempty"`
+		GoldenEvening *TimeWindow `json:"golden_evening,omitempty"`
+		BlueEvening   *TimeWindow `json:"blue_evening,omitempty"`
+	}
+)
+
+// sunWindow ... period the sun takes from one elevation to the other, rising or setting
+func sunWindow(date time.Time, coordinates Coordinates, from, to float64, rising bool) *TimeWindow {
+	start, ok := SunCrossing(date, coordinates, from, rising)
+	if !ok {
+		return nil
+	}
+	end, ok := SunCrossing(date, coordinates, to, rising)
+	if !ok {
+		return nil
+	}
+	return &TimeWindow{From: start.Round(time.Minute).Format("15:04"), To: end.Round(time.Minute).Format("15:04")}
+}
+
+// GetPhotoDay ... blue hour from -6° to -4°, golden hour from -4° to 6° elevation of the sun
+func GetPhotoDay(date time.Time, coordinates Coordinates) PhotoDay {
+	return PhotoDay{
+		BlueMorning:   sunWindow(date, coordinates, blueHourStart, goldenHourStart, true),
+		GoldenMorning: sunWindow(date, coordinates, goldenHourStart, goldenHourEnd, true),
+		GoldenEvening: sunWindow(date, coordinates, goldenHourEnd, goldenHourStart, false),
+		BlueEvening:   sunWindow(date, coordinates, goldenHourStart, blueHourStart, false),
+	}
+}
+
+// photoDay ... blue and golden hours of the forecast day, false without the coordinates of the forecast
+func (f Forecast) photoDay(offset int) (PhotoDay, bool) {
+	if f.Coordinates == nil {
+		return PhotoDay{}, false
+	}
+	date, err := time.ParseInLocation("02.01.2006", f.Daily[offset].Day, time.Local)
+	if err != nil {
+		return PhotoDay{}, false
+	}
+	return GetPhotoDay(date, *f.Coordinates), true
+}
+
+// String ... the windows of the day like "blaue Stunde 04:08 - 04:32, goldene Stunde 04:32 - 05:39, ..."
+func (p PhotoDay) String() string {
+	parts := []string{}
+	for _, w := range []struct {
+		name   string
+		window *TimeWindow
+	}{
+		{"blaue Stunde", p.BlueMorning},
+		{"goldene Stunde", p.GoldenMorning},
+		{"goldene Stunde", p.GoldenEvening},
+		{"blaue Stunde", p.BlueEvening},
+	} {
+		if w.window != nil {
+			parts = append(parts, fmt.Sprintf("%s %s - %s", w.name, w.window.From, w.window.To))
+		}
+	}
+	if len(parts) == 0 {
+		return "keine blaue oder goldene Stunde"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatDayLength ... day length like "16h 28min"
+func FormatDayLength(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh %02dmin", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// FormatDayLengthChange ... difference to the previous day like "3 min kürzer als gestern",
+// changes below a minute, which are common around the solstices, are given in seconds
+func FormatDayLengthChange(today, yesterday time.Duration) string {
+	diff := (today - yesterday).Round(time.Second)
+	comparison := "länger"
+	if diff < 0 {
+		comparison = "kürzer"
+		diff = -diff
+	}
+	switch {
+	case diff >= time.Minute:
+		return fmt.Sprintf("%d min %s als gestern", int(diff.Round(time.Minute).Minutes()), comparison)
+	case diff > 0:
+		return fmt.Sprintf("%d s %s als gestern", int(diff.Seconds()), comparison)
+	}
+	return "so lang wie gestern"
+}
+
+// FormatSunPosition ... position of the sun like "Azimut 215° (SW), Höhe 42.3°"
+func FormatSunPosition(p SunPosition) string {
+	return fmt.Sprintf("Azimut %.0f° (%s), Höhe %.1f°", p.Azimuth, Direction(p.Azimuth).Direction(), p.Elevation)
+}
+
+// localDayLength ... day length of the date computed from the coordinates, false at polar day and night
+func localDayLength(date time.Time, coordinates Coordinates) (time.Duration, bool) {
+	sunrise, okRise := SunCrossing(date, coordinates, sunriseElevation, true)
+	sunset, okSet := SunCrossing(date, coordinates, sunriseElevation, false)
+	return sunset.Sub(sunrise), okRise && okSet
+}
+
+// yesterdayLength ... day length of the day before the forecast day, false if it isn't known. Before the first day
+// of the forecast it takes the computed change of the day length, as the computed and the delivered day lengths
+// differ by some seconds
+func (f Forecast) yesterdayLength(offset int) (time.Duration, bool) {
+	if offset > 0 {
+		return f.Daily[offset-1].DayLength, true
+	}
+	if f.Coordinates == nil {
+		return 0, false
+	}
+	date, err := time.ParseInLocation("02.01.2006", f.Daily[offset].Day, time.Local)
+	if err != nil {
+		return 0, false
+	}
+	today, okToday := localDayLength(date, *f.Coordinates)
+	yesterday, okYesterday := localDayLength(date.AddDate(0, 0, -1), *f.Coordinates)
+	if !okToday || !okYesterday {
+		return 0, false
+	}
+	return f.Daily[offset].DayLength - today + yesterday, true
+}
+
+// FormatDayLengthOf ... day length of the forecast day with the change to the day before if it is known
+func (f Forecast) FormatDayLengthOf(offset int) string {
+	length := FormatDayLength(f.Daily[offset].DayLength)
+	if yesterday, ok := f.yesterdayLength(offset); ok {
+		length += ", " + FormatDayLengthChange(f.Daily[offset].DayLength, yesterday)
+	}
+	return length
+}
+
+// sunNow ... position of the sun now, nil without coordinates and for the days of --date, which may be far from now
+func sunNow(f Forecast, opts Options) *SunPosition {
+	if f.Coordinates == nil || !opts.Date.IsZero() {
+		return nil
+	}
+	p := GetSunPosition(time.Now(), *f.Coordinates)
+	return &p
+}
+
+// sunFlags ... flags of the sun command
+func sunFlags(fs *flag.FlagSet, opts *Options) {
+	fs.BoolVar(&opts.Photo, "photo", false, "add the blue and golden hours for photographers")
+}
+
+// PrintSun ... position of the sun if given, sunrise, sunset and day length for the coming days,
+// with photo also the blue and golden hours
+func PrintSun(f Forecast, position *SunPosition, photo bool) {
+	fmt.Println()
+	printHeader("Sonnenauf-/untergang, Tageslänge", f)
+	if position != nil {
+		fmt.Printf("Sonnenstand um %s: %s\n", position.Time.Format("15:04"), FormatSunPosition(*position))
+	}
+	for i, day := range f.Daily {
+		fmt.Printf("%s: %s - %s, %s\n", day.Day, day.Sunrise, day.Sunset, f.FormatDayLengthOf(i))
+		if p, ok := f.photoDay(i); ok && photo {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+	fmt.Println()
+}
@@ -0,0 +1,80 @@
+package weather_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestFormatDayLength(t *testing.T) {
+	t.Parallel()
+	want := "16h 28min"
+	got := weather.FormatDayLength(59308 * time.Second)
+	if want != got {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestFormatDayLengthChange(t *testing.T) {
+	t.Parallel()
+	day := 16 * time.Hour
+	tests := []struct {
+		today time.Duration
+		want  string
+	}{
+		{today: day + 2*time.Minute, want: "2 min länger als gestern"},
+		{today: day - 3*time.Minute, want: "3 min kürzer als gestern"},
+		{today: day + 10*time.Second, want: "10 s länger als gestern"},
+		{today: day, want: "so lang wie gestern"},
+	}
+	for _, tc := range tests {
+		got := weather.FormatDayLengthChange(tc.today, day)
+		if tc.want != got {
+			t.Errorf("want %s, got %s", tc.want, got)
+		}
+	}
+}
+
+func TestFormatDayLengthOf(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{Daily: []weather.ForecastDaily{
+		{Day: "01.10.2022", DayLength: 11*time.Hour + 40*time.Minute},
+		{Day: "02.10.2022", DayLength: 11*time.Hour + 36*time.Minute},
+	}}
+	if got, want := f.FormatDayLengthOf(0), "11h 40min"; got != want {
+		t.Errorf("want %q without coordinates, got %q", want, got)
+	}
+	if got, want := f.FormatDayLengthOf(1), "11h 36min, 4 min kürzer als gestern"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	f.Coordinates = &weather.Coordinates{Lat: 52.52, Lon: 13.405}
+	// Berlin loses almost 4 minutes a day in autumn
+	if got, want := f.FormatDayLengthOf(0), "11h 40min, 4 min kürzer als gestern"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestSunPositionOnlyNow(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	tests := map[string]struct {
+		args []string
+		want bool
+	}{
+		"offline": {[]string{"--offline"}, true},
+		"date":    {[]string{"--date", "2025-12-24"}, false},
+	}
+	for name, tt := range tests {
+		out, err := runCLI(t, ts, append(append([]string{"sun"}, tt.args...), "52.52,13.40")...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(string(out), "Sonnenstand"); got != tt.want {
+			t.Errorf("%s: want the position of the sun %t, got %s", name, tt.want, out)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package weather
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+)
+
+const (
+	// series of the SVG chart
+	SeriesTemp = "temp"
+	SeriesRain = "rain"
+	SeriesWind = "wind"
+)
+
+// chartSeries ... series of the SVG chart in the order they are drawn, all of them if none are asked for
+var chartSeries = []string{SeriesRain, SeriesTemp, SeriesWind}
+
+var chartWind = color.RGBA{R: 40, G: 150, B: 80, A: 255}
+
+// parseChartSeries ... series of a comma separated list like "temp,rain", all of them if the list is empty
+func parseChartSeries(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return chartSeries, nil
+	}
+	series := []string{}
+	for _, s := range strings.Split(list, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if !contains(chartSeries, s) {
+			return nil, fmt.Errorf("invalid series %q, want one of %s", s, strings.Join(chartSeries, ", "))
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// hexColor ... colour of the PNG chart as SVG colour, e.g. #d73228
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// WriteChartSVG ... chart of the hourly forecast as SVG of the size of the PNG chart, the rain chances as blue
+// bars on the right scale, the temperatures as red line on the left scale and the wind as green line from calm
+// to its highest speed named in the legend, series picks temp, rain and wind, all of them if empty
+func WriteChartSVG(w io.Writer, f Forecast, series []string) error {
+	if len(f.Hourly) == 0 {
+		return errors.New("no hourly forecast for the chart")
+	}
+	if len(series) == 0 {
+		series = chartSeries
+	}
+	plot := chartPlot
+	step := float64(plot.Dx()) / float64(len(f.Hourly))
+	center := func(i int) float64 {
+		return float64(plot.Min.X) + (float64(i)+0.5)*step
+	}
+	// scale ... y of the value between low and high
+	scale := func(v, low, high float64) float64 {
+		return float64(plot.Max.Y) - (v-low)/(high-low)*float64(plot.Dy())
+	}
+	min, max := temperatureRange(f.Hourly)
+	top := 0.0
+	for _, slot := range f.Hourly {
+		top = math.Max(top, displaySpeed(f, slot.WindSpeed))
+	}
+	// the wind scale ends at the next 10 km/h or mph above the highest speed
+	top = math.Max(10, math.Ceil(top/10)*10)
+
+	ew := &errWriter{w: w}
+	fmt.Fprintf(ew, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		ChartWidth, ChartHeight, ChartWidth, ChartHeight)
+	fmt.Fprintln(ew, `<rect width="100%" height="100%" fill="white"/>`)
+	if f.Place != "" {
+		fmt.Fprintf(ew, `<title>%s</title>`+"\n", html.EscapeString(f.Place))
+	}
+	for i := 0; i <= 4; i++ {
+		y := float64(plot.Max.Y) - float64(plot.Dy()*i)/4
+		fmt.Fprintf(ew, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="%s"/>`+"\n",
+			plot.Min.X, y, plot.Max.X, y, hexColor(chartGrid))
+		if contains(series, SeriesTemp) {
+			label := fmt.Sprintf("%.0f %s", min+(max-min)*float64(i)/4, f.TemperatureUnit())
+			fmt.Fprintf(ew, `<text x="%d" y="%.1f" text-anchor="end" fill="%s">%s</text>`+"\n",
+				plot.Min.X-4, y+4, hexColor(chartTemperature), html.EscapeString(label))
+		}
+		// the right scale takes the rain chances or, without rain, the wind
+		switch {
+		case contains(series, SeriesRain):
+			fmt.Fprintf(ew, `<text x="%d" y="%.1f" fill="%s">%d %%</text>`+"\n", plot.Max.X+4, y+4, hexColor(chartText), i*25)
+		case contains(series, SeriesWind):
+			fmt.Fprintf(ew, `<text x="%d" y="%.1f" fill="%s">%.0f %s</text>`+"\n",
+				plot.Max.X+4, y+4, hexColor(chartWind), top*float64(i)/4, speedUnit(f))
+		}
+	}
+
+	for i, slot := range f.Hourly {
+		x := float64(plot.Min.X) + float64(i)*step
+		hour := strings.SplitN(slot.Hour, ":", 2)[0]
+		if i > 0 && hour == "00" {
+			fmt.Fprintf(ew, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="%s"/>`+"\n",
+				x, plot.Min.Y, x, plot.Max.Y, hexColor(chartMidnight))
+		}
+		if hour == "00" || hour == "06" || hour == "12" || hour == "18" {
+			label := hour
+			if hour == "00" && len(slot.Day) >= 6 {
+				label = slot.Day[:6]
+			}
+			fmt.Fprintf(ew, `<text x="%.1f" y="%d" text-anchor="middle" fill="%s">%s</text>`+"\n",
+				x, plot.Max.Y+18, hexColor(chartText), html.EscapeString(label))
+		}
+	}
+
+	line := func(name string, c color.RGBA, y func(ForecastHourly) float64) {
+		points := []string{}
+		for i, slot := range f.Hourly {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", center(i), y(slot)))
+		}
+		fmt.Fprintf(ew, `<polyline class="%s" fill="none" stroke="%s" stroke-width="2" points="%s"/>`+"\n",
+			name, hexColor(c), strings.Join(points, " "))
+	}
+	legend := []string{}
+	for _, s := range chartSeries {
+		if !contains(series, s) {
+			continue
+		}
+		switch s {
+		case SeriesRain:
+			fmt.Fprintf(ew, `<g class="%s" fill="%s">`+"\n", s, hexColor(chartRain))
+			for i, slot := range f.Hourly {
+				if slot.RainChance <= 0 {
+					continue
+				}
+				y := scale(slot.RainChance, 0, 100)
+				fmt.Fprintf(ew, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f"/>`+"\n",
+					float64(plot.Min.X)+float64(i)*step+1, y, math.Max(step-2, 1), float64(plot.Max.Y)-y)
+			}
+			fmt.Fprintln(ew, `</g>`)
+			legend = append(legend, fmt.Sprintf(`<tspan fill="%s">Regen</tspan>`, hexColor(chartRain)))
+		case SeriesTemp:
+			line(s, chartTemperature, func(slot ForecastHourly) float64 { return scale(slot.Temperature, min, max) })
+			legend = append(legend, fmt.Sprintf(`<tspan fill="%s">Temperatur</tspan>`, hexColor(chartTemperature)))
+		case SeriesWind:
+			line(s, chartWind, func(slot ForecastHourly) float64 { return scale(displaySpeed(f, slot.WindSpeed), 0, top) })
+			legend = append(legend, fmt.Sprintf(`<tspan fill="%s">Wind 0 - %.0f %s</tspan>`, hexColor(chartWind), top, speedUnit(f)))
+		}
+	}
+	fmt.Fprintf(ew, `<text x="%d" y="%d">%s</text>`+"\n", plot.Min.X, plot.Min.Y-4, strings.Join(legend, " · "))
+	fmt.Fprintln(ew, `</svg>`)
+	return ew.err
+}
+
+// displaySpeed ... speed in km/h or mph as labelled by speedUnit
+func displaySpeed(f Forecast, s Speed) float64 {
+	if f.Units == UnitsImperial {
+		return float64(s)
+	}
+	return s.KmPerHour()
+}
+
+// handleChart ... SVG chart of the hourly forecast, the series parameter picks some of temp, rain and wind
+func (s *Server) handleChart(w http.ResponseWriter, r *http.Request) {
+	series, err := parseChartSeries(r.URL.Query().Get("series"))
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %v", ErrBadRequest, err))
+		return
+	}
+	_, forecast, err := s.get(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var b bytes.Buffer
+	err = WriteChartSVG(&b, forecast, series)
+	if err != nil {
+		// the provider answered, but without the hourly forecast the chart is drawn from
+		writeJSON(w, http.StatusNotFound, errorResponse{err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write(b.Bytes())
+}
@@ -0,0 +1,93 @@
+package weather
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// NotifyBriefing ... kind of the morning briefing
+	NotifyBriefing = "briefing"
+
+	// DefaultTelegramURL ... Bot API used without a configured one
+	DefaultTelegramURL = "https://api.telegram.org"
+)
+
+type (
+	// Telegram ... sends notifications to a chat through a Telegram bot
+	Telegram struct {
+		URL        string
+		Token      string
+		ChatID     string
+		HTTPClient *http.Client
+	}
+
+	// TelegramConfig ... Telegram settings of the config file
+	TelegramConfig struct {
+		Token  string `json:"token"`
+		ChatID string `json:"chat_id"`
+	}
+)
+
+// NewTelegram ... notifier for the chat via the bot with the token
+func NewTelegram(token, chatID string) *Telegram {
+	return &Telegram{
+		URL:        DefaultTelegramURL,
+		Token:      token,
+		ChatID:     chatID,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify ... sends title and message as one text message
+func (t *Telegram) Notify(n Notification) error {
+	body, err := json.Marshal(struct {
+		ChatID string `json:"chat_id"`
+		Text   string `json:"text"`
+	}{t.ChatID, n.Title + "\n" + n.Message})
+	if err != nil {
+		return err
+	}
+	resp, err := t.HTTPClient.Post(strings.TrimSuffix(t.URL, "/")+"/bot"+t.Token+"/sendMessage", "application/json", bytes.NewReader(body))
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// the URL contains the bot token
+		return fmt.Errorf("telegram: %w", urlErr.Err)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	return nil
+}
+
+// Briefing ... summary of the current conditions and today's forecast, e.g. sent in the morning
+func Briefing(location string, c Conditions, f Forecast) Notification {
+	unit := f.TemperatureUnit()
+	lines := []string{fmt.Sprintf("Aktuell %.1f %s, %s", c.Temperature, unit, c.Summary)}
+	if len(f.Daily) > 0 {
+		today := f.Daily[0]
+		lines = append(lines,
+			fmt.Sprintf("Heute %.0f bis %.0f %s, %s", today.Temp.Min, today.Temp.Max, unit, today.Description),
+			FormatDayTemperatures(today.Temp, unit),
+			fmt.Sprintf("Regenwahrscheinlichkeit %.0f %%", today.RainChance))
+	}
+	for _, a := range f.CurrentAlerts() {
+		lines = append(lines, "Warnung: "+a.Name)
+	}
+	return Notification{
+		Kind:     NotifyBriefing,
+		Location: location,
+		Title:    "Wetter für " + location,
+		Message:  strings.Join(lines, "\n"),
+	}
+}
@@ -0,0 +1,71 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTelegram(t *testing.T) {
+	t.Parallel()
+	var path string
+	var got map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer ts.Close()
+	tg := weather.NewTelegram("123:SECRET", "-10042")
+	tg.URL = ts.URL
+	err := tg.Notify(weather.Notification{Title: "Regen in Bonn", Message: "Ab 15:00 Uhr Regen (70 %)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/bot123:SECRET/sendMessage" {
+		t.Errorf("unexpected path %q", path)
+	}
+	want := map[string]string{"chat_id": "-10042", "text": "Regen in Bonn\nAb 15:00 Uhr Regen (70 %)"}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	tg.URL = "http://127.0.0.1:1"
+	err = tg.Notify(weather.Notification{})
+	if err == nil || strings.Contains(err.Error(), "SECRET") {
+		t.Errorf("want error without the bot token, got %v", err)
+	}
+}
+
+func TestBriefing(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{
+		Units: weather.UnitsMetric,
+		Daily: []weather.ForecastDaily{{
+			Description: "Sonne und Wolken",
+			Temp:        weather.DailyTempBenchmarks{Min: 12, Max: 24, Morning: 14, Day: 22, Evening: 20, Night: 15},
+			RainChance:  30,
+			Alerts:      []weather.Alert{{Name: "Hitze"}},
+		}},
+	}
+	got := weather.Briefing("Bonn", weather.Conditions{Temperature: 18.5, Summary: "Klar"}, f)
+	want := weather.Notification{
+		Kind:     weather.NotifyBriefing,
+		Location: "Bonn",
+		Title:    "Wetter für Bonn",
+		Message: strings.Join([]string{
+			"Aktuell 18.5 °C, Klar",
+			"Heute 12 bis 24 °C, Sonne und Wolken",
+			"morgens 14 °C, mittags 22 °C, abends 20 °C und nachts 15 °C",
+			"Regenwahrscheinlichkeit 30 %",
+			"Warnung: Hitze",
+		}, "\n"),
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
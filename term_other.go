@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd
+
+package weather
+
+import "os"
+
+// windowWidth ... the size of the terminal is unknown on this platform, so only COLUMNS sets the width
+func windowWidth(f *os.File) int {
+	return 0
+}
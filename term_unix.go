@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd
+
+package weather
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// windowWidth ... columns of the terminal by the TIOCGWINSZ ioctl, 0 if it fails
+func windowWidth(f *os.File) int {
+	var size struct {
+		rows, columns, xpixel, ypixel uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0
+	}
+	return int(size.columns)
+}
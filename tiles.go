@@ -0,0 +1,178 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"net/http"
+	"os"
+)
+
+const (
+	CommandMap = "map"
+
+	// layers of the weather maps 1.0
+	LayerClouds        = "clouds_new"
+	LayerPrecipitation = "precipitation_new"
+	LayerPressure      = "pressure_new"
+	LayerWind          = "wind_new"
+	LayerTemperature   = "temp_new"
+
+	// TileSize ... width and height of a map tile in pixels
+	TileSize = 256
+	// MaxZoom ... highest zoom level of the map tiles
+	MaxZoom = 18
+)
+
+// mapLayers ... layers of the map command by their short names
+var mapLayers = map[string]string{
+	"clouds":        LayerClouds,
+	"precipitation": LayerPrecipitation,
+	"pressure":      LayerPressure,
+	"wind":          LayerWind,
+	"temperature":   LayerTemperature,
+}
+
+// Tile ... map tile in the slippy map scheme of OpenStreetMap, X and Y count from the north west
+type Tile struct {
+	X, Y, Z int
+}
+
+// tilePosition ... position of the coordinates in tiles at the zoom level, the integer part is the tile
+func tilePosition(coordinates Coordinates, zoom int) (float64, float64) {
+	n := math.Exp2(float64(zoom))
+	lat := coordinates.Lat * math.Pi / 180
+	x := (coordinates.Lon + 180) / 360 * n
+	y := (1 - math.Log(math.Tan(lat)+1/math.Cos(lat))/math.Pi) / 2 * n
+	return x, y
+}
+
+// TileAt ... tile showing the coordinates at the zoom level
+func TileAt(coordinates Coordinates, zoom int) Tile {
+	x, y := tilePosition(coordinates, zoom)
+	return Tile{X: int(math.Floor(x)), Y: int(math.Floor(y)), Z: zoom}
+}
+
+// LookupLayer ... layer of a short name like precipitation, the layer names themselves are accepted as well
+func LookupLayer(name string) (string, bool) {
+	if layer, ok := mapLayers[name]; ok {
+		return layer, true
+	}
+	for _, layer := range mapLayers {
+		if layer == name {
+			return layer, true
+		}
+	}
+	return "", false
+}
+
+func (c *Client) FormatTileURL(layer string, t Tile) string {
+	return fmt.Sprintf("%s/map/%s/%d/%d/%d.png?appid=%s", c.TileURL, layer, t.Z, t.X, t.Y, c.APIKey)
+}
+
+// GetTile ... tile of the weather map layer, a transparent PNG
+func (c *Client) GetTile(layer string, t Tile) (image.Image, error) {
+	resp, err := c.HTTPClient.Get(c.FormatTileURL(layer, t))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	img, err := png.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid map tile: %w", err)
+	}
+	return img, nil
+}
+
+// GetMap ... static map of the layer around the coordinates, stitched from the tiles within radius
+// of the tile of the coordinates on a white background, the coordinates are marked with a red cross
+func (c *Client) GetMap(layer string, coordinates Coordinates, zoom, radius int) (*image.RGBA, error) {
+	if zoom < 0 || zoom > MaxZoom {
+		return nil, fmt.Errorf("invalid zoom %d, want between 0 and %d", zoom, MaxZoom)
+	}
+	center := TileAt(coordinates, zoom)
+	side := 2*radius + 1
+	img := image.NewRGBA(image.Rect(0, 0, side*TileSize, side*TileSize))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	tiles := 1 << zoom
+	for dy := -radius; dy <= radius; dy++ {
+		y := center.Y + dy
+		// there is nothing beyond the poles
+		if y < 0 || y >= tiles {
+			continue
+		}
+		for dx := -radius; dx <= radius; dx++ {
+			// east and west wrap around the date line
+			t := Tile{X: ((center.X+dx)%tiles + tiles) % tiles, Y: y, Z: zoom}
+			tile, err := c.GetTile(layer, t)
+			if err != nil {
+				return nil, err
+			}
+			at := image.Pt((dx+radius)*TileSize, (dy+radius)*TileSize)
+			draw.Draw(img, tile.Bounds().Sub(tile.Bounds().Min).Add(at), tile, tile.Bounds().Min, draw.Over)
+		}
+	}
+	x, y := tilePosition(coordinates, zoom)
+	markX := int((x - float64(center.X-radius)) * TileSize)
+	markY := int((y - float64(center.Y-radius)) * TileSize)
+	red := color.RGBA{R: 255, A: 255}
+	for d := -6; d <= 6; d++ {
+		img.Set(markX+d, markY, red)
+		img.Set(markX, markY+d, red)
+	}
+	return img, nil
+}
+
+// mapFlags ... flags of the map command
+func mapFlags(fs *flag.FlagSet, opts *Options) {
+	opts.Layer = "precipitation"
+	opts.Zoom = 8
+	opts.Output = "weather-map.png"
+	fs.StringVar(&opts.Layer, "layer", opts.Layer, "map layer: clouds, precipitation, pressure, wind or temperature")
+	fs.IntVar(&opts.Zoom, "zoom", opts.Zoom, fmt.Sprintf("zoom level of the map, 0 to %d", MaxZoom))
+	fs.StringVar(&opts.Output, "output", opts.Output, "PNG file the map is written to")
+}
+
+func validateMap(fs *flag.FlagSet, opts *Options) error {
+	if opts.Zoom < 0 || opts.Zoom > MaxZoom {
+		return fmt.Errorf("invalid zoom %d, want between 0 and %d", opts.Zoom, MaxZoom)
+	}
+	return nil
+}
+
+func runMap(env *cliEnv, opts Options) error {
+	layer, ok := LookupLayer(opts.Layer)
+	if !ok {
+		return fmt.Errorf("unknown layer %q, want clouds, precipitation, pressure, wind or temperature", opts.Layer)
+	}
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return err
+	}
+	img, err := c.GetMap(layer, coordinates, opts.Zoom, 1)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(opts.Output)
+	if err != nil {
+		return err
+	}
+	err = png.Encode(f, img)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Karte gespeichert in %s\n", opts.Output)
+	return nil
+}
@@ -0,0 +1,102 @@
+package weather_test
+
+import (
+	"image/color"
+	"image/png"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+func TestTileAt(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		coordinates weather.Coordinates
+		zoom        int
+		want        weather.Tile
+	}{
+		{weather.Coordinates{Lat: 50.7374, Lon: 7.0982}, 0, weather.Tile{X: 0, Y: 0, Z: 0}},
+		{weather.Coordinates{Lat: 50.7374, Lon: 7.0982}, 8, weather.Tile{X: 133, Y: 85, Z: 8}},
+		{weather.Coordinates{Lat: -33.9, Lon: 18.42}, 5, weather.Tile{X: 17, Y: 19, Z: 5}},
+	}
+	for _, tc := range tests {
+		got := weather.TileAt(tc.coordinates, tc.zoom)
+		if got != tc.want {
+			t.Errorf("%v at zoom %d: want %+v, got %+v", tc.coordinates, tc.zoom, tc.want, got)
+		}
+	}
+}
+
+func TestFormatTileURL(t *testing.T) {
+	t.Parallel()
+	c := weather.NewClient("dummyAPIKey")
+	want := "https://tile.openweathermap.org/map/precipitation_new/8/133/86.png?appid=dummyAPIKey"
+	got := c.FormatTileURL(weather.LayerPrecipitation, weather.Tile{X: 133, Y: 86, Z: 8})
+	if want != got {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestLookupLayer(t *testing.T) {
+	t.Parallel()
+	for name, want := range map[string]string{"clouds": weather.LayerClouds, "temp_new": weather.LayerTemperature} {
+		got, ok := weather.LookupLayer(name)
+		if !ok || got != want {
+			t.Errorf("%s: want %s, got %s", name, want, got)
+		}
+	}
+	if _, ok := weather.LookupLayer("radar"); ok {
+		t.Error("want unknown layer radar")
+	}
+}
+
+func TestGetMap(t *testing.T) {
+	t.Parallel()
+	c := weathertest.NewFakeClient(t)
+	img, err := c.GetMap(weather.LayerPrecipitation, weather.Coordinates{Lat: 50.7374, Lon: 7.0982}, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 768 || b.Dy() != 768 {
+		t.Fatalf("want 3x3 tiles of 768x768 pixels, got %v", b)
+	}
+	if got := img.RGBAAt(10, 10); got.B != 255 || got.R == 255 {
+		t.Errorf("want the blue tile over the white background, got %v", got)
+	}
+	// Bonn is at the bottom of its tile, about 5 % of its width from the west
+	if got := img.RGBAAt(256+12, 256+255); got != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("want the red mark at the location, got %v", got)
+	}
+	_, err = c.GetMap(weather.LayerPrecipitation, weather.Coordinates{}, 19, 1)
+	if err == nil {
+		t.Error("want error for zoom 19, but got nil")
+	}
+}
+
+func TestMapCommand(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	output := filepath.Join(t.TempDir(), "radar.png")
+	config := `{"base_url": "` + ts.URL + `", "tile_url": "` + ts.URL + `"}`
+	_, err := runCLIWithConfig(t, config, "map", "Bonn,DE", "--zoom", "6", "--output", output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	_, err = png.Decode(f)
+	if err != nil {
+		t.Errorf("want a PNG, got %v", err)
+	}
+	_, err = runCLIWithConfig(t, config, "map", "Bonn,DE", "--layer", "radar", "--output", output)
+	if err == nil {
+		t.Error("want error for an unknown layer, but got nil")
+	}
+}
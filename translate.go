@@ -0,0 +1,126 @@
+package weather
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+type (
+	// Translator ... translates texts into a language like de, the backend of TranslateAlerts
+	Translator interface {
+		Translate(text, lang string) (string, error)
+	}
+
+	// LibreTranslate ... translates with the API of LibreTranslate, self-hosted or a public instance
+	LibreTranslate struct {
+		URL string
+		// APIKey is required by some instances
+		APIKey     string
+		HTTPClient *http.Client
+	}
+
+	// TranslateConfig ... translation settings of the config file
+	TranslateConfig struct {
+		URL    string `json:"url"`
+		APIKey string `json:"api_key,omitempty"`
+	}
+)
+
+// NewLibreTranslate ... translator using the LibreTranslate instance at the URL
+func NewLibreTranslate(url string) *LibreTranslate {
+	return &LibreTranslate{
+		URL:        url,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Translate ... the text in the language, the language of the text is detected
+func (t *LibreTranslate) Translate(text, lang string) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"q":       text,
+		"source":  "auto",
+		"target":  lang,
+		"format":  "text",
+		"api_key": t.APIKey,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := t.HTTPClient.Post(t.URL+"/translate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var translation struct {
+		TranslatedText string
+	}
+	err = json.Unmarshal(data, &translation)
+	if err != nil {
+		return "", fmt.Errorf("invalid LibreTranslate response %s: %w", data, err)
+	}
+	return translation.TranslatedText, nil
+}
+
+// TranslateAlerts ... event and description of the alerts of the forecast translated into the language, every text
+// is only translated once. The original name stays in Event, which identifies the alert for notifications and
+// calendars. On failure the texts translated so far are kept
+func TranslateAlerts(f *Forecast, t Translator, lang string) error {
+	translated := map[string]string{}
+	translate := func(text string) (string, error) {
+		if text == "" {
+			return "", nil
+		}
+		if s, ok := translated[text]; ok {
+			return s, nil
+		}
+		s, err := t.Translate(text, lang)
+		if err != nil {
+			return text, err
+		}
+		translated[text] = s
+		return s, nil
+	}
+	for i := range f.Daily {
+		for j := range f.Daily[i].Alerts {
+			a := &f.Daily[i].Alerts[j]
+			name, err := translate(a.Name)
+			if err != nil {
+				return err
+			}
+			description, err := translate(a.Description)
+			if err != nil {
+				return err
+			}
+			if a.Event == "" {
+				a.Event = a.Name
+			}
+			a.Name, a.Description = name, description
+		}
+	}
+	return nil
+}
+
+// translateAlerts ... alerts of the forecast translated into the language by the translator of the config file,
+// failures only warn as the weather was fetched
+func (env *cliEnv) translateAlerts(f *Forecast, lang string) {
+	if env.cfg.Translate == nil {
+		return
+	}
+	t := NewLibreTranslate(env.cfg.Translate.URL)
+	t.APIKey = env.cfg.Translate.APIKey
+	if err := TranslateAlerts(f, t, lang); err != nil {
+		fmt.Fprintf(os.Stderr, "translate: %v\n", err)
+	}
+}
@@ -0,0 +1,95 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeTranslator ... translates by the dictionary, counting the calls, unknown texts fail
+type fakeTranslator struct {
+	dictionary map[string]string
+	calls      int
+}
+
+func (t *fakeTranslator) Translate(text, lang string) (string, error) {
+	t.calls++
+	s, ok := t.dictionary[text]
+	if !ok || lang != "de" {
+		return "", errors.New("unknown text")
+	}
+	return s, nil
+}
+
+// libreTranslateServer ... fake LibreTranslate instance answering with the text in upper case
+func libreTranslateServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		if r.URL.Path != "/translate" || json.NewDecoder(r.Body).Decode(&req) != nil || req["api_key"] != "secret" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"translatedText": strings.ToUpper(req["q"]) + " (" + req["target"] + ")"})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestTranslateAlerts(t *testing.T) {
+	t.Parallel()
+	heat := weather.Alert{Name: "Heat", Description: "Very hot"}
+	f := weather.Forecast{Daily: []weather.ForecastDaily{
+		{Alerts: []weather.Alert{heat}},
+		{Alerts: []weather.Alert{heat, {Name: "Storm"}}},
+	}}
+	tr := &fakeTranslator{dictionary: map[string]string{"Heat": "Hitze", "Very hot": "Sehr heiß", "Storm": "Sturm"}}
+	err := weather.TranslateAlerts(&f, tr, "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []weather.Alert{{Name: "Hitze", Description: "Sehr heiß", Event: "Heat"}, {Name: "Sturm", Event: "Storm"}}
+	if !cmp.Equal(want, f.Daily[1].Alerts) || !cmp.Equal(want[0], f.Daily[0].Alerts[0]) {
+		t.Errorf("unexpected translation %+v", f.Daily)
+	}
+	if tr.calls != 3 {
+		t.Errorf("want every text translated once, got %d calls", tr.calls)
+	}
+	f.Daily[0].Alerts = []weather.Alert{{Name: "Flood"}}
+	if err := weather.TranslateAlerts(&f, tr, "de"); err == nil || f.Daily[0].Alerts[0].Name != "Flood" {
+		t.Errorf("want an error and the text kept, got %v, %+v", err, f.Daily[0].Alerts)
+	}
+}
+
+func TestLibreTranslate(t *testing.T) {
+	t.Parallel()
+	tr := weather.NewLibreTranslate(libreTranslateServer(t).URL)
+	tr.APIKey = "secret"
+	got, err := tr.Translate("heat", "de")
+	if err != nil || got != "HEAT (de)" {
+		t.Errorf("want HEAT (de), got %q, %v", got, err)
+	}
+	tr.APIKey = ""
+	if _, err := tr.Translate("heat", "de"); err == nil {
+		t.Error("want an error without the API key")
+	}
+}
+
+func TestTranslateAlertsConfig(t *testing.T) {
+	heat := []map[string]any{{"start": 1655460000, "end": 1655496000, "name": "Heat", "description": "Very hot"}}
+	ts := alertServer(t, heat)
+	config := `{"base_url": "` + ts.URL + `", "translate": {"url": "` + libreTranslateServer(t).URL + `", "api_key": "secret"}}`
+	out, err := runCLIWithConfig(t, config, "alert", "Bonn,DE", "--lang", "fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "HEAT (fr) von") || !strings.Contains(string(out), "VERY HOT (fr)") {
+		t.Errorf("want the translated alert, got %s", out)
+	}
+}
@@ -0,0 +1,50 @@
+package weather
+
+import (
+	"fmt"
+)
+
+const FunctionUV = "uv"
+
+// UVIndex ... UV index as delivered by the API
+type UVIndex float64
+
+// Category ... risk category of the WHO
+func (u UVIndex) Category() string {
+	switch {
+	case u < 3:
+		return "niedrig"
+	case u < 6:
+		return "mäßig"
+	case u < 8:
+		return "hoch"
+	case u < 11:
+		return "sehr hoch"
+	}
+	return "extrem"
+}
+
+// Advice ... recommended protection for the risk category of the WHO
+func (u UVIndex) Advice() string {
+	switch {
+	case u < 3:
+		return "Kein Schutz erforderlich."
+	case u < 8:
+		return "Schutz erforderlich: Hut, T-Shirt, Sonnenbrille und Sonnencreme, mittags Schatten suchen."
+	}
+	return "Zusätzlicher Schutz erforderlich: mittags drinnen bleiben, sonst unbedingt Schatten suchen."
+}
+
+// PrintUV ... current UV index and daily maximum with the WHO risk categories
+func PrintUV(c Conditions, f Forecast) {
+	fmt.Println()
+	printHeader("UV-Index vom "+c.Timestamp, f)
+	fmt.Printf("Aktuell: %.1f (%s)\n", c.UVI, c.UVI.Category())
+	fmt.Println(c.UVI.Advice())
+	fmt.Println()
+	fmt.Println("Tageshöchstwerte ...")
+	for _, day := range f.Daily {
+		fmt.Printf("%s: %.1f (%s)\n", day.Day, day.UVI, day.UVI.Category())
+	}
+	fmt.Println()
+}
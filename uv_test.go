@@ -0,0 +1,32 @@
+package weather_test
+
+import (
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+func TestUVIndexCategory(t *testing.T) {
+	t.Parallel()
+	tests := map[weather.UVIndex]string{
+		0:    "niedrig",
+		2.9:  "niedrig",
+		3.75: "mäßig",
+		7.08: "hoch",
+		10:   "sehr hoch",
+		11.5: "extrem",
+	}
+	for uvi, want := range tests {
+		got := uvi.Category()
+		if want != got {
+			t.Errorf("%.2f: want %s, got %s", uvi, want, got)
+		}
+	}
+}
+
+func TestUVIndexAdvice(t *testing.T) {
+	t.Parallel()
+	if weather.UVIndex(1).Advice() == weather.UVIndex(9).Advice() {
+		t.Error("want different advice for low and very high UV index")
+	}
+}
@@ -0,0 +1,81 @@
+package weather
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+const (
+	CommandVersion = "version"
+
+	// ProviderOneCall ... name of the weather data source used by Client
+	ProviderOneCall = "OpenWeatherMap One Call 3.0"
+)
+
+// BuildInfo ... version information of the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// ldflags values handed over by the main package, they win over the module information
+var linkedBuildInfo BuildInfo
+
+// SetBuildInfo ... used by the main package to pass version data set via -ldflags, e.g. by goreleaser
+func SetBuildInfo(version, commit, date string) {
+	linkedBuildInfo = BuildInfo{
+		Version: version,
+		Commit:  commit,
+		Date:    date,
+	}
+}
+
+// GetBuildInfo ... version, commit and Go version, taken from the module information of the binary
+func GetBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   "(devel)",
+		GoVersion: runtime.Version(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if bi.Main.Version != "" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Commit = s.Value
+			case "vcs.time":
+				info.Date = s.Value
+			}
+		}
+	}
+	if linkedBuildInfo.Version != "" {
+		info.Version = linkedBuildInfo.Version
+	}
+	if linkedBuildInfo.Commit != "" {
+		info.Commit = linkedBuildInfo.Commit
+	}
+	if linkedBuildInfo.Date != "" {
+		info.Date = linkedBuildInfo.Date
+	}
+	return info
+}
+
+func runVersion(env *cliEnv, args []string) error {
+	info := GetBuildInfo()
+	c := NewClientFromConfig("", env.cfg)
+	fmt.Printf("%s %s\n", env.name, info.Version)
+	if info.Commit != "" {
+		fmt.Printf("Commit: %s\n", info.Commit)
+	}
+	if info.Date != "" {
+		fmt.Printf("Build: %s\n", info.Date)
+	}
+	fmt.Printf("Go: %s\n", info.GoVersion)
+	fmt.Printf("Provider: %s\n", c.ProviderName())
+	fmt.Printf("Basis-URL: %s\n", c.BaseURL)
+	return nil
+}
@@ -0,0 +1,23 @@
+package weather_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+func TestGetBuildInfo(t *testing.T) {
+	weather.SetBuildInfo("v1.2.3", "abc123", "2022-06-28")
+	defer weather.SetBuildInfo("", "", "")
+	got := weather.GetBuildInfo()
+	want := weather.BuildInfo{
+		Version:   "v1.2.3",
+		Commit:    "abc123",
+		Date:      "2022-06-28",
+		GoVersion: runtime.Version(),
+	}
+	if want != got {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
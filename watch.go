@@ -0,0 +1,201 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+const CommandWatch = "watch"
+
+type (
+	// Observation ... weather of a location as fetched by one refresh
+	Observation struct {
+		Location   string     `json:"location"`
+		Time       time.Time  `json:"time"`
+		Conditions Conditions `json:"conditions"`
+		Forecast   Forecast   `json:"forecast"`
+	}
+
+	// Sink ... receives every refresh of a Watcher, like the display, notifications or a store
+	Sink interface {
+		Write(o Observation) error
+	}
+
+	// Watcher ... refreshes the weather of one location every Interval and hands it to the sinks
+	Watcher struct {
+		Provider    Provider
+		Coordinates Coordinates
+		Location    string
+		Interval    time.Duration
+		Sinks       []Sink
+	}
+
+	// displaySink ... redraws the current conditions on a terminal, otherwise appends a line per refresh,
+	// w is the terminal's stdout as the conditions are printed there
+	displaySink struct {
+		w        io.Writer
+		terminal bool
+		// recent are the refreshes of the last hours, for the pressure trend
+		recent []Observation
+	}
+
+	// NotifySink ... notifies of new alerts, rain, temperature crossings and frost of every refresh
+	NotifySink struct {
+		Notifiers  []Notifier
+		State      NotifyState
+		RainWithin time.Duration
+		Thresholds []float64
+		// FrostThreshold is in °C, frost isn't notified without it
+		FrostThreshold *float64
+		// Path keeps the state across restarts if set
+		Path string
+	}
+)
+
+// Refresh ... fetches the weather once and writes it to all sinks, failing sinks don't stop the others
+func (w *Watcher) Refresh() error {
+	conditions, forecast, err := w.Provider.GetWeather(w.Coordinates)
+	if err != nil {
+		return err
+	}
+	o := Observation{
+		Location:   w.Location,
+		Time:       time.Now(),
+		Conditions: conditions,
+		Forecast:   forecast,
+	}
+	for _, sink := range w.Sinks {
+		err := sink.Write(o)
+		if err != nil {
+			log.Printf("%T: %v", sink, err)
+		}
+	}
+	return nil
+}
+
+// Run ... refreshes right away and then every interval until ctx is done, failed refreshes are logged
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		err := w.Refresh()
+		if err != nil {
+			log.Printf("refresh failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FormatObservation ... one log line of a refresh, e.g. "2022-06-17 12:00 Bonn: 21.5 °C, Klar, ..."
+func FormatObservation(o Observation) string {
+	c, f := o.Conditions, o.Forecast
+	line := fmt.Sprintf("%s %s: %.1f %s, %s, %d %%, %d hPa, Wind %s %s",
+		o.Time.Format("2006-01-02 15:04"), o.Location, c.Temperature, f.TemperatureUnit(), c.Summary,
+		c.Humidity, c.Pressure, f.FormatSpeed(c.WindSpeed), c.WindDirection.Direction())
+	if c.PressureTrend != nil {
+		line += " " + c.PressureTrend.Arrow()
+	}
+	if len(f.Hourly) > 0 {
+		line += fmt.Sprintf(", Regen %.0f %%", f.Hourly[0].RainChance)
+	}
+	if alerts := len(f.CurrentAlerts()); alerts > 0 {
+		line += fmt.Sprintf(", %d Warnungen", alerts)
+	}
+	return line
+}
+
+func (d *displaySink) Write(o Observation) error {
+	d.remember(o)
+	if trend, ok := GetPressureTrend(d.recent); ok {
+		o.Conditions.PressureTrend = &trend
+	}
+	if !d.terminal {
+		_, err := fmt.Fprintln(d.w, FormatObservation(o))
+		return err
+	}
+	// clear the screen and start at the top left
+	fmt.Fprint(d.w, "\033[H\033[2J")
+	o.Forecast.Place = o.Location
+	PrintCurrentConditions(o.Conditions, o.Forecast, false)
+	fmt.Fprintf(d.w, "Aktualisiert um %s\n", o.Time.Format("15:04"))
+	return nil
+}
+
+// remember ... adds the observation to the recent ones, dropping those too old for the pressure trend
+func (d *displaySink) remember(o Observation) {
+	d.recent = append(d.recent, o)
+	for len(d.recent) > 0 && o.Time.Sub(d.recent[0].Time) > pressureTrendPeriod+pressureTrendTolerance {
+		d.recent = d.recent[1:]
+	}
+}
+
+// Write ... sends the notifications of the observation, the state is saved after a successful delivery
+func (n *NotifySink) Write(o Observation) error {
+	notifications := n.State.Check(o.Location, o.Forecast, n.RainWithin, o.Time)
+	notifications = append(notifications, n.State.CheckTemperature(o.Location, o.Conditions, o.Forecast.TemperatureUnit(), n.Thresholds)...)
+	if n.FrostThreshold != nil {
+		notifications = append(notifications, n.State.CheckFrost(o.Location, o.Forecast, *n.FrostThreshold)...)
+	}
+	err := send(n.Notifiers, notifications)
+	if err != nil {
+		return err
+	}
+	if n.Path == "" {
+		return nil
+	}
+	return SaveNotifyState(n.Path, n.State)
+}
+
+func runWatch(env *cliEnv, opts Options) error {
+	c, coordinates, err := env.resolve(opts)
+	if err != nil {
+		return err
+	}
+	c.Exclude = opts.Exclude
+	f := Forecast{Place: placeName(c, coordinates)}
+	w := &Watcher{
+		Provider:    env.provider(c, opts),
+		Coordinates: coordinates,
+		Location:    notifyLocation(opts, f),
+		Interval:    opts.Interval,
+		Sinks:       []Sink{&displaySink{w: os.Stdout, terminal: isTerminal(os.Stdout)}},
+	}
+	// without a notifier the watcher only displays the weather
+	if notifiers := env.configuredNotifiers(opts); len(notifiers) > 0 {
+		path := NotifyStatePath(env.cfgPath)
+		state, err := LoadNotifyState(path)
+		if err != nil {
+			return err
+		}
+		frost := env.cfg.FrostLimit()
+		w.Sinks = append(w.Sinks, &NotifySink{
+			Notifiers:      notifiers,
+			State:          state,
+			RainWithin:     opts.RainWithin,
+			Thresholds:     env.cfg.TemperatureThresholds,
+			FrostThreshold: &frost,
+			Path:           path,
+		})
+	}
+	if env.cfg.InfluxDB != nil {
+		w.Sinks = append(w.Sinks, NewInflux(*env.cfg.InfluxDB))
+	}
+	s, err := env.store()
+	if err != nil {
+		return err
+	}
+	if s != nil {
+		defer s.Close()
+		w.Sinks = append(w.Sinks, s)
+	}
+	w.Run(context.Background())
+	return nil
+}
@@ -0,0 +1,115 @@
+package weather_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+)
+
+// recordingSink ... collects the observations, cancel is called after the wanted number
+type recordingSink struct {
+	mu           sync.Mutex
+	observations []weather.Observation
+	want         int
+	cancel       func()
+}
+
+func (s *recordingSink) Write(o weather.Observation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observations = append(s.observations, o)
+	if len(s.observations) == s.want {
+		s.cancel()
+	}
+	return nil
+}
+
+func TestWatcherRun(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sink := &recordingSink{want: 3, cancel: cancel}
+	w := &weather.Watcher{
+		Provider: newMock(),
+		Location: "Bonn",
+		Interval: time.Millisecond,
+		Sinks:    []weather.Sink{sink, sink},
+	}
+	w.Run(ctx)
+	if ctx.Err() == context.DeadlineExceeded {
+		t.Fatal("watcher didn't refresh in time")
+	}
+	got := sink.observations[0]
+	if got.Location != "Bonn" || got.Conditions.Temperature != 21.5 || got.Time.IsZero() {
+		t.Errorf("unexpected observation %+v", got)
+	}
+}
+
+func TestWatcherRefreshError(t *testing.T) {
+	t.Parallel()
+	sink := &recordingSink{}
+	w := &weather.Watcher{
+		Provider: &weathertest.Mock{Err: errors.New("unavailable")},
+		Sinks:    []weather.Sink{sink},
+	}
+	err := w.Refresh()
+	if err == nil {
+		t.Error("want error, but got nil")
+	}
+	if len(sink.observations) != 0 {
+		t.Errorf("want no observation for a failed refresh, got %d", len(sink.observations))
+	}
+}
+
+func TestFormatObservation(t *testing.T) {
+	t.Parallel()
+	o := weather.Observation{
+		Location:   "Bonn",
+		Time:       time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC),
+		Conditions: weather.Conditions{Temperature: 21.5, Summary: "Klar", Humidity: 40, Pressure: 1015, WindSpeed: 3.2, WindDirection: 230},
+		Forecast: weather.Forecast{
+			Units:  weather.UnitsMetric,
+			Hourly: []weather.ForecastHourly{{RainChance: 30}},
+			Daily:  []weather.ForecastDaily{{Alerts: []weather.Alert{{Name: "Hitze"}}}},
+		},
+	}
+	want := "2022-06-17 12:00 Bonn: 21.5 °C, Klar, 40 %, 1015 hPa, Wind 12 km/h SW, Regen 30 %, 1 Warnungen"
+	got := weather.FormatObservation(o)
+	if want != got {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestNotifySink(t *testing.T) {
+	t.Parallel()
+	var got []weather.Notification
+	n := notifierFunc(func(note weather.Notification) error {
+		got = append(got, note)
+		return nil
+	})
+	sink := &weather.NotifySink{Notifiers: []weather.Notifier{n}, RainWithin: time.Hour}
+	o := weather.Observation{Location: "Bonn", Time: time.Now(), Forecast: weather.Forecast{
+		Daily: []weather.ForecastDaily{{Alerts: []weather.Alert{{Name: "Hitze"}}}},
+	}}
+	for i := 0; i < 2; i++ {
+		err := sink.Write(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(got) != 1 || got[0].Alert.Name != "Hitze" {
+		t.Errorf("want one alert notification, got %+v", got)
+	}
+}
+
+// notifierFunc ... function as weather.Notifier
+type notifierFunc func(n weather.Notification) error
+
+func (f notifierFunc) Notify(n weather.Notification) error {
+	return f(n)
+}
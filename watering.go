@@ -0,0 +1,104 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+const (
+	FunctionGarden = "garden"
+
+	// wateringPeriod ... how far back the rain still moistens the soil
+	wateringPeriod = 3 * 24 * time.Hour
+	// soilReserve ... water in mm the soil keeps for the plants, more rain runs off or seeps away
+	soilReserve = 15.0
+)
+
+// WateringDay ... water balance of a forecast day, Reserve is the water left in the soil in mm at the end of the day,
+// Amount the water in l/m² to give if Water is set
+type WateringDay struct {
+	Day     string  `json:"day"`
+	ET0     float64 `json:"et0"`
+	Rain    float64 `json:"rain"`
+	Reserve float64 `json:"reserve"`
+	Water   bool    `json:"water"`
+	Amount  float64 `json:"amount,omitempty"`
+}
+
+// GetWatering ... water balance of the forecast days, starting with the rain of the last days in mm as reserve
+// of the soil, up to 15 mm. A day whose evapotranspiration empties the reserve needs watering, which fills it up
+// again, nil without the coordinates of the forecast
+func GetWatering(f Forecast, recentRain float64) []WateringDay {
+	days := []WateringDay{}
+	reserve := math.Min(recentRain, soilReserve)
+	for i, daily := range f.Daily {
+		et0, ok := f.ET0(i)
+		if !ok {
+			return nil
+		}
+		d := WateringDay{Day: daily.Day, ET0: math.Round(et0*10) / 10, Rain: daily.Rain + daily.Snow}
+		reserve = math.Min(reserve+d.Rain-et0, soilReserve)
+		if reserve < 0 {
+			d.Water, d.Amount = true, math.Ceil(-reserve)
+			reserve = 0
+		}
+		d.Reserve = math.Round(reserve*10) / 10
+		days = append(days, d)
+	}
+	return days
+}
+
+// Advice ... gießen with the amount or nicht gießen
+func (d WateringDay) Advice() string {
+	if d.Water {
+		return fmt.Sprintf("gießen, etwa %.0f l/m²", d.Amount)
+	}
+	return "nicht gießen"
+}
+
+// PrintWatering ... whether the garden needs watering on the days of the forecast
+func PrintWatering(f Forecast, recentRain float64) {
+	fmt.Println()
+	printHeader("Gießen oder nicht gießen?", f)
+	fmt.Printf("Niederschlag der letzten 3 Tage: %.1f mm\n", recentRain)
+	fmt.Printf("%-10s  %7s  %7s  %7s  %s\n", "Tag", "ET0", "Regen", "Vorrat", "Empfehlung")
+	for _, d := range GetWatering(f, recentRain) {
+		fmt.Printf("%-10s  %4.1f mm  %4.1f mm  %4.1f mm  %s\n", d.Day, d.ET0, d.Rain, d.Reserve, d.Advice())
+	}
+	fmt.Println()
+}
+
+func runGarden(env *cliEnv, opts Options) error {
+	_, forecast, err := env.fetch(opts)
+	if err != nil {
+		return err
+	}
+	if forecast.Coordinates == nil {
+		return fmt.Errorf("%s needs the coordinates of the location", FunctionGarden)
+	}
+	if opts.Days < len(forecast.Daily) {
+		forecast.Daily = forecast.Daily[:opts.Days]
+	}
+	now := time.Now()
+	recent := env.recent(notifyLocation(opts, forecast), now.Add(-wateringPeriod), now)
+	rain := RecentRain(recent, now.Format("02.01.2006"))
+	if opts.Format == FormatJSON {
+		type wateringJSON struct {
+			WateringDay
+			Advice string `json:"advice"`
+		}
+		days := []wateringJSON{}
+		for _, d := range GetWatering(forecast, rain) {
+			days = append(days, wateringJSON{d, d.Advice()})
+		}
+		return printJSON(os.Stdout, struct {
+			Place      string         `json:"place,omitempty"`
+			RecentRain float64        `json:"recent_rain"`
+			Daily      []wateringJSON `json:"daily"`
+		}{forecast.Place, rain, days})
+	}
+	PrintWatering(forecast, rain)
+	return nil
+}
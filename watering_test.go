@@ -0,0 +1,35 @@
+package weather_test
+
+import (
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+func TestGetWatering(t *testing.T) {
+	t.Parallel()
+	day := func(name string, rain float64) weather.ForecastDaily {
+		return weather.ForecastDaily{Day: name, Temp: weather.DailyTempBenchmarks{Min: 16, Max: 30}, Humidity: 40, WindSpeed: 3, Rain: rain}
+	}
+	f := weather.Forecast{
+		Units:       weather.UnitsMetric,
+		Coordinates: &weather.Coordinates{Lat: 50.7, Lon: 7.1},
+		Daily:       []weather.ForecastDaily{day("20.07.2022", 0), day("21.07.2022", 0), day("22.07.2022", 25)},
+	}
+	got := weather.GetWatering(f, 8)
+	if len(got) != 3 {
+		t.Fatalf("want 3 days, got %+v", got)
+	}
+	if got[0].Water || got[0].Advice() != "nicht gießen" {
+		t.Errorf("want the rain of the last days to last the first day, got %+v", got[0])
+	}
+	if !got[1].Water || got[1].Amount < 1 || got[1].Reserve != 0 {
+		t.Errorf("want watering on the second hot day, got %+v", got[1])
+	}
+	if got[2].Water || got[2].Reserve <= 0 || got[2].Reserve > 15 {
+		t.Errorf("want the rain to fill the reserve, got %+v", got[2])
+	}
+	if got := weather.GetWatering(weather.Forecast{Daily: f.Daily}, 0); got != nil {
+		t.Errorf("want nothing without coordinates, got %+v", got)
+	}
+}
@@ -0,0 +1,75 @@
+package weather
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// FormatWaybar ... output format of the JSON object of a custom module of Waybar with "return-type": "json"
+const FormatWaybar = "waybar"
+
+// WaybarOutput ... text of the bar, tooltip and CSS classes of a custom module of Waybar
+type WaybarOutput struct {
+	Text    string   `json:"text"`
+	Tooltip string   `json:"tooltip"`
+	Class   []string `json:"class"`
+}
+
+// pangoEscaper ... Waybar takes text and tooltip as Pango markup
+var pangoEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// conditionClass ... CSS class of the weather condition like rain, empty without condition
+func conditionClass(id ConditionID) string {
+	switch {
+	case id.IsThunderstorm():
+		return "thunderstorm"
+	case id.IsSnow():
+		return "snow"
+	case id.IsRain():
+		return "rain"
+	case id.IsAtmosphere():
+		return "fog"
+	case id == 800:
+		return "clear"
+	case id > 800:
+		return "clouds"
+	}
+	return ""
+}
+
+// GetWaybar ... the line of the template as text, the current conditions and the week as tooltip, classed by the
+// condition, frost from 0 °C down, hot from 30 °C and alert while alerts are in force
+func GetWaybar(template string, c Conditions, f Forecast) WaybarOutput {
+	// the tooltip isn't a terminal, so it gets the compact layout without colours
+	r := TextRenderer{Width: CompactWidth / 2}
+	var current, week bytes.Buffer
+	r.RenderCurrent(&current, c, f)
+	r.RenderWeek(&week, f)
+	// the blocks are separated by one blank line, without the blank lines the renderers surround them with
+	tooltip := strings.Trim(current.String(), "\n") + "\n\n" + strings.Trim(week.String(), "\n")
+	o := WaybarOutput{
+		Text:    pangoEscaper.Replace(FormatOneline(template, c, f)),
+		Tooltip: pangoEscaper.Replace(tooltip),
+		Class:   []string{},
+	}
+	if class := conditionClass(c.Condition); class != "" {
+		o.Class = append(o.Class, class)
+	}
+	switch celsius := convertTemperature(c.Temperature, f.Units, UnitsMetric); {
+	case celsius <= 0:
+		o.Class = append(o.Class, "frost")
+	case celsius >= 30:
+		o.Class = append(o.Class, "hot")
+	}
+	if len(f.CurrentAlerts()) > 0 {
+		o.Class = append(o.Class, "alert")
+	}
+	return o
+}
+
+// writeWaybar ... the object in one line, as Waybar reads a line per update
+func writeWaybar(w io.Writer, o WaybarOutput) error {
+	return json.NewEncoder(w).Encode(o)
+}
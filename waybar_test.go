@@ -0,0 +1,54 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetWaybar(t *testing.T) {
+	t.Parallel()
+	c := weather.Conditions{Temperature: -2, Condition: 601, Summary: "Schnee"}
+	f := weather.Forecast{Place: "Bonn & Beuel", Units: weather.UnitsMetric, Daily: []weather.ForecastDaily{
+		{Day: "17.12.2022", Description: "Schnee", Alerts: []weather.Alert{{Name: "Glätte"}}},
+	}}
+	got := weather.GetWaybar("%l %t", c, f)
+	if got.Text != "Bonn &amp; Beuel -2°C" {
+		t.Errorf("want the line escaped for Pango, got %q", got.Text)
+	}
+	if !strings.Contains(got.Tooltip, "17.12.2022") || !strings.Contains(got.Tooltip, "Bonn &amp; Beuel") {
+		t.Errorf("want the conditions and the week in the tooltip, got %q", got.Tooltip)
+	}
+	if strings.Contains(got.Tooltip, "\n\n\n") || strings.HasPrefix(got.Tooltip, "\n") || strings.HasSuffix(got.Tooltip, "\n") {
+		t.Errorf("want the blocks separated by one blank line, got %q", got.Tooltip)
+	}
+	want := []string{"snow", "frost", "alert"}
+	if !cmp.Equal(want, got.Class) {
+		t.Error(cmp.Diff(want, got.Class))
+	}
+}
+
+func TestWaybarFormat(t *testing.T) {
+	ts := httptest.NewServer(weathertest.Handler())
+	defer ts.Close()
+	out, err := runCLI(t, ts, "statusbar", "--format", "waybar", "Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(out)), "\n"); len(lines) != 1 {
+		t.Fatalf("want one line per update, got %q", out)
+	}
+	var got weather.WaybarOutput
+	err = json.Unmarshal(out, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Text == "" || got.Tooltip == "" {
+		t.Errorf("want text and tooltip, got %+v", got)
+	}
+}
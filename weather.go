@@ -1,106 +1,259 @@
 package weather
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
 type (
+	// Provider ... operations used by callers, implemented by Client and mockable in tests
+	Provider interface {
+		Get(location string) (Conditions, Forecast, error)
+		GetCoordinates(location string) (Coordinates, error)
+		GetWeather(coordinates Coordinates) (Conditions, Forecast, error)
+	}
+
 	Client struct {
-		APIKey     string
-		BaseURL    string
-		HTTPClient *http.Client
+		APIKey  string
+		BaseURL string
+		// TileURL and IconURL are the base URLs of the weather map tiles and icons
+		TileURL string
+		IconURL string
+		// PollenURL is the base URL of the pollen forecast of Open-Meteo
+		PollenURL string
+		// MeteoAlarmURL and DWDURL are the base URLs of the warnings of MeteoAlarm and the DWD
+		MeteoAlarmURL string
+		DWDURL        string
+		HTTPClient    *http.Client
+		GeoLimit      int
+		Units         string
+		Lang          string
+		// API selects the endpoints of GetWeather, APIAuto if empty
+		API string
+		// Exclude are the blocks left out of the One Call response, e.g. ExcludeMinutely, the free endpoints ignore it
+		Exclude []string
+		// Concurrency limits the requests of GetWeatherMany, DefaultConcurrency if 0
+		Concurrency int
+		// denied is set by APIAuto once One Call 3.0 rejected the key, it's accessed atomically
+		denied int32
 	}
 
 	Coordinates struct {
-		Lon float64
-		Lat float64
+		Lon float64 `json:"lon"`
+		Lat float64 `json:"lat"`
 	}
 
 	Conditions struct {
-		Timestamp     string
-		Sunrise       string
-		Sunset        string
-		Summary       string
-		Temperature   float64
-		FeelsLike     float64
-		DewPoint      float64
-		Pressure      int
-		Humidity      int
-		WindSpeed     Speed
-		WindGust      Speed
-		WindDirection Direction
+		Timestamp     string    `json:"timestamp"`
+		Sunrise       string    `json:"sunrise"`
+		Sunset        string    `json:"sunset"`
+		Summary       string    `json:"summary"`
+		Temperature   float64   `json:"temperature"`
+		FeelsLike     float64   `json:"feels_like"`
+		DewPoint      float64   `json:"dew_point"`
+		Pressure      int       `json:"pressure"`
+		Humidity      int       `json:"humidity"`
+		WindSpeed     Speed     `json:"wind_speed"`
+		WindGust      Speed     `json:"wind_gust"`
+		WindDirection Direction `json:"wind_direction"`
+		UVI           UVIndex   `json:"uvi"`
+		// Visibility is in metres, Clouds is the cloud cover in percent
+		Visibility int `json:"visibility"`
+		Clouds     int `json:"clouds"`
+		// Rain and Snow are the precipitation of the last hour in mm
+		Rain float64 `json:"rain_1h"`
+		Snow float64 `json:"snow_1h"`
+		// Icon is the code of the weather icon like 10d, see IconURL
+		Icon string `json:"icon,omitempty"`
+		// Condition is the weather condition code, e.g. 500 for light rain
+		Condition ConditionID `json:"condition_id,omitempty"`
+		// PressureTrend is only known from earlier observations, like those of the store
+		PressureTrend *PressureTrend `json:"pressure_trend,omitempty"`
 	}
 
 	ForecastHourly struct {
-		Day         string
-		Hour        string
-		Temperature float64
-		RainChance  float64
+		// Time is the start of the hour, Day and Hour are formatted for display
+		Time          time.Time   `json:"time"`
+		Day           string      `json:"day"`
+		Hour          string      `json:"hour"`
+		Temperature   float64     `json:"temperature"`
+		FeelsLike     float64     `json:"feels_like"`
+		RainChance    float64     `json:"rain_chance"`
+		WindSpeed     Speed       `json:"wind_speed"`
+		WindGust      Speed       `json:"wind_gust"`
+		WindDirection Direction   `json:"wind_direction"`
+		Humidity      int         `json:"humidity"`
+		Pressure      int         `json:"pressure"`
+		Clouds        int         `json:"clouds"`
+		Description   string      `json:"description"`
+		Icon          string      `json:"icon,omitempty"`
+		Condition     ConditionID `json:"condition_id,omitempty"`
+		// UVI is only available from the One Call API
+		UVI UVIndex `json:"uvi,omitempty"`
+		// Rain and Snow are the expected precipitation of the hour in mm
+		Rain float64 `json:"rain"`
+		Snow float64 `json:"snow"`
 	}
 
 	ForecastDaily struct {
-		Day       string
-		Moonrise  string
-		Moonset   string
-		Moonphase Phase
-		Temp      DailyTempBenchmarks
-		Alerts    []Alert
+		Day         string      `json:"day"`
+		Description string      `json:"description"`
+		Icon        string      `json:"icon,omitempty"`
+		Condition   ConditionID `json:"condition_id,omitempty"`
+		// Summary is the description of the whole day in a sentence, only available in English
+		Summary string `json:"summary,omitempty"`
+		Sunrise string `json:"sunrise"`
+		Sunset  string `json:"sunset"`
+		// DayLength is encoded as day_length_seconds, see MarshalJSON
+		DayLength     time.Duration       `json:"-"`
+		Moonrise      string              `json:"moonrise"`
+		Moonset       string              `json:"moonset"`
+		Moonphase     Phase               `json:"moonphase"`
+		Temp          DailyTempBenchmarks `json:"temp"`
+		RainChance    float64             `json:"rain_chance"`
+		WindSpeed     Speed               `json:"wind_speed"`
+		WindDirection Direction           `json:"wind_direction"`
+		WindGust      Speed               `json:"wind_gust"`
+		Humidity      int                 `json:"humidity"`
+		Clouds        int                 `json:"clouds"`
+		UVI           UVIndex             `json:"uvi"`
+		// Rain and Snow are the expected precipitation of the day in mm
+		Rain   float64 `json:"rain"`
+		Snow   float64 `json:"snow"`
+		Alerts []Alert `json:"alerts"`
+		// FireDanger is set by the CLI, which knows the rain of the days before
+		FireDanger *FireDanger `json:"fire_danger,omitempty"`
+		// Frost is set by the CLI with the threshold of the config file if the night before the day gets that cold
+		Frost *Frost `json:"frost,omitempty"`
 	}
 
 	DailyTempBenchmarks struct {
-		Max     float64
-		Min     float64
-		Morning float64
-		Day     float64
-		Evening float64
-		Night   float64
+		Max     float64 `json:"max"`
+		Min     float64 `json:"min"`
+		Morning float64 `json:"morning"`
+		Day     float64 `json:"day"`
+		Evening float64 `json:"evening"`
+		Night   float64 `json:"night"`
 	}
 
 	Alert struct {
-		Start       string
-		End         string
-		Name        string
-		Description string
+		Start       string `json:"start"`
+		End         string `json:"end"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		// Event is the name given by the sender if TranslateAlerts replaced Name, it identifies the alert instead
+		Event string `json:"event,omitempty"`
+		// Sender is the agency issuing the alert, Tags its kinds of weather, e.g. Wind or Flood
+		Sender string   `json:"sender,omitempty"`
+		Tags   []string `json:"tags,omitempty"`
+		// Severity, Urgency, Certainty and Category are those of the Common Alerting Protocol, see ClassifyAlert
+		Severity AlertSeverity `json:"severity,omitempty"`
+		// Level is the warning level in the terms of the sender, e.g. Unwetter of the DWD
+		Level     string `json:"level,omitempty"`
+		Urgency   string `json:"urgency,omitempty"`
+		Certainty string `json:"certainty,omitempty"`
+		Category  string `json:"category,omitempty"`
 	}
 
 	Forecast struct {
-		Hourly []ForecastHourly
-		Daily  []ForecastDaily
+		Place string `json:"place,omitempty"`
+		Units string `json:"units,omitempty"`
+		// Coordinates are those the forecast was fetched for, set by the Client
+		Coordinates *Coordinates `json:"coordinates,omitempty"`
+		// Minutely is the precipitation of the next hour, not available everywhere
+		Minutely []ForecastMinutely `json:"minutely"`
+		Hourly   []ForecastHourly   `json:"hourly"`
+		Daily    []ForecastDaily    `json:"daily"`
+	}
+
+	Place struct {
+		Name    string `json:"name"`
+		State   string `json:"state,omitempty"`
+		Country string `json:"country"`
+	}
+
+	// CurrentResponse ... conditions of one point in time, of the OneCall and the timemachine API
+	CurrentResponse struct {
+		Weather []struct {
+			ID          ConditionID
+			Description string
+			Icon        string
+		}
+		DT         int64
+		Sunrise    int64
+		Sunset     int64
+		Temp       float64
+		Feels_Like float64
+		Dew_Point  float64
+		Pressure   int
+		Humidity   int
+		Wind_Speed Speed
+		Wind_Gust  Speed
+		Wind_Deg   Direction
+		UVI        UVIndex
+		Visibility int
+		Clouds     int
+		Rain       Precipitation
+		Snow       Precipitation
 	}
 
 	WeatherResponse struct {
-		Current struct {
-			Weather []struct {
-				Description string
-			}
+		Current  CurrentResponse
+		Minutely []struct {
+			DT            int64
+			Precipitation float64
+		}
+		Hourly []struct {
 			DT         int64
-			Sunrise    int64
-			Sunset     int64
 			Temp       float64
 			Feels_Like float64
-			Dew_Point  float64
-			Pressure   int
-			Humidity   int
+			PoP        float64
 			Wind_Speed Speed
 			Wind_Gust  Speed
 			Wind_Deg   Direction
-		}
-		Hourly []struct {
-			DT   int64
-			Temp float64
-			PoP  float64
+			Humidity   int
+			Pressure   int
+			Clouds     int
+			UVI        UVIndex
+			Weather    []struct {
+				ID          ConditionID
+				Description string
+				Icon        string
+			}
+			Rain Precipitation
+			Snow Precipitation
 		}
 		Daily []struct {
 			DT         int64
+			Sunrise    int64
+			Sunset     int64
 			Moonrise   int64
 			Moonset    int64
 			Moon_Phase Phase
+			Summary    string
+			Weather    []struct {
+				ID          ConditionID
+				Description string
+				Icon        string
+			}
+			PoP        float64
+			Wind_Speed Speed
+			Wind_Deg   Direction
+			Wind_Gust  Speed
+			Humidity   int
+			Clouds     int
+			UVI        UVIndex
+			Rain       float64
+			Snow       float64
 			Temp       struct {
 				Max   float64
 				Min   float64
@@ -116,21 +269,63 @@ type (
 				Description string
 			}
 		}
+		// Alerts of One Call 3.0 are given for the whole response, not per day
+		Alerts []struct {
+			Sender_Name string
+			Event       string
+			Start       int64
+			End         int64
+			Description string
+			Tags        []string
+		}
 	}
 
 	GeoResponse []struct {
-		Lon float64
-		Lat float64
+		Name    string
+		State   string
+		Country string
+		Lon     float64
+		Lat     float64
+	}
+
+	ZipResponse struct {
+		Zip     string
+		Name    string
+		Country string
+		Lon     float64
+		Lat     float64
 	}
 
 	Speed float64
 
 	Direction float64
 
+	// Precipitation ... volume of rain or snow in mm as given by the API
+	Precipitation struct {
+		OneHour float64 `json:"1h"`
+	}
+
 	Phase float64
 )
 
 const (
+	// units of measurement supported by the API
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+	UnitsStandard = "standard"
+
+	// kinds of precipitation
+	PrecipitationRain  = "Regen"
+	PrecipitationSnow  = "Schnee"
+	PrecipitationSleet = "Schneeregen"
+
+	// blocks of the One Call response, which can be excluded
+	ExcludeCurrent  = "current"
+	ExcludeMinutely = "minutely"
+	ExcludeHourly   = "hourly"
+	ExcludeDaily    = "daily"
+	ExcludeAlerts   = "alerts"
+
 	// limits for wind directions
 	N   = 0.0   // N ... Norden
 	NNO = 22.5  // NNO ... NordNordOsten
@@ -149,152 +344,132 @@ const (
 	NW  = 315.0 // NW ... NordWesten
 	NNW = 337.5 // NNW ... NordNordWesten
 
-	// function arguments for CLI
-	FunctionCurrent       = "current"
-	FunctionToday         = "today"
-	FunctionTomorrow      = "tomorrow"
-	FunctionAfterTomorrow = "aftertomorrow"
-	FunctionMoon          = "moon"
-	FunctionRain          = "rain"
-	FunctionAlert         = "alert"
 )
 
-var validFunction = map[string]bool{
-	FunctionCurrent:       true,
-	FunctionToday:         true,
-	FunctionTomorrow:      true,
-	FunctionAfterTomorrow: true,
-	FunctionMoon:          true,
-	FunctionRain:          true,
-	FunctionAlert:         true,
-}
-
-func RunCLI() {
-	key := os.Getenv("OPENWEATHERMAP_API_KEY")
-	if key == "" {
-		fmt.Fprintln(os.Stderr, "Please set the env variable OPENWEATHERMAP_API_KEY")
-		os.Exit(1)
-	}
-
-	if len(os.Args) < 3 || !validFunction[os.Args[1]] {
-		fmt.Fprintf(os.Stderr, "Usage: %s FUNCTION LOCATION\n\nExample: %[1]s current London,UK\n", os.Args[0])
-		os.Exit(1)
-	}
-
-	location := GetLocation(os.Args)
-	function := os.Args[1]
-	c := NewClient(key)
-	coordinates, err := c.GetCoordinates(location)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
-	conditions, forecast, err := c.GetWeather(coordinates)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
-	switch function {
-	case FunctionCurrent:
-		PrintCurrentConditions(conditions, forecast)
-	case FunctionToday:
-		PrintForecast(forecast, 0)
-	case FunctionTomorrow:
-		PrintForecast(forecast, 1)
-	case FunctionAfterTomorrow:
-		PrintForecast(forecast, 2)
-	case FunctionMoon:
-		PrintMoon(forecast)
-	case FunctionRain:
-		PrintRain(forecast)
-	case FunctionAlert:
-		PrintAlerts(forecast)
-	}
-	/*
-		fmt.Println("Hours")
-		for _, slot := range forecast.Hourly {
-			fmt.Println(slot.Timestamp)
-		}
-	*/
+func Get(location, key string) (Conditions, Forecast, error) {
+	return NewClient(key).Get(location)
 }
 
-func GetLocation(args []string) string {
-	return strings.Join(args[2:], "+")
+// GetAt ... fetches the weather for known coordinates without any geocoding
+func GetAt(coordinates Coordinates, key string) (Conditions, Forecast, error) {
+	return NewClient(key).GetWeather(coordinates)
 }
 
-func GetFunction(args []string) string {
-	return strings.Join(args[1:2], "")
+// ParseCoordinates ... accepts locations given as "lat,lon", e.g. 52.52,13.40
+func ParseCoordinates(location string) (Coordinates, bool) {
+	parts := strings.Split(location, ",")
+	if len(parts) != 2 {
+		return Coordinates{}, false
+	}
+	// NaN fails every comparison, so it is rejected before the range checks
+	lat, err := strconv.ParseFloat(strings.Trim(parts[0], " +"), 64)
+	if err != nil || math.IsNaN(lat) || math.IsInf(lat, 0) || lat < -90 || lat > 90 {
+		return Coordinates{}, false
+	}
+	lon, err := strconv.ParseFloat(strings.Trim(parts[1], " +"), 64)
+	if err != nil || math.IsNaN(lon) || math.IsInf(lon, 0) || lon < -180 || lon > 180 {
+		return Coordinates{}, false
+	}
+	return Coordinates{Lat: lat, Lon: lon}, true
 }
 
-func Get(location, key string) (Conditions, Forecast, error) {
-	c := NewClient(key)
-	coordinates, err := c.GetCoordinates(location)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
-	conditions, forecast, err := c.GetWeather(coordinates)
-	if err != nil {
-		return Conditions{}, Forecast{}, err
-	}
-	return conditions, forecast, nil
+var validExclude = map[string]bool{
+	ExcludeCurrent:  true,
+	ExcludeMinutely: true,
+	ExcludeHourly:   true,
+	ExcludeDaily:    true,
+	ExcludeAlerts:   true,
 }
 
 func NewClient(apiKey string) *Client {
 	return &Client{
-		APIKey:  apiKey,
-		BaseURL: "https://api.openweathermap.org",
+		APIKey:        apiKey,
+		BaseURL:       "https://api.openweathermap.org",
+		TileURL:       "https://tile.openweathermap.org",
+		IconURL:       DefaultIconURL,
+		PollenURL:     DefaultPollenURL,
+		MeteoAlarmURL: DefaultMeteoAlarmURL,
+		DWDURL:        DefaultDWDURL,
 		HTTPClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		GeoLimit: DefaultGeoLimit,
+		Units:    UnitsMetric,
+		Lang:     "de",
 	}
 }
 
 func ParseWeatherResponse(data []byte) (Conditions, Forecast, error) {
+	return ParseWeatherResponseExcluding(data, nil)
+}
+
+// ParseWeatherResponseExcluding ... ParseWeatherResponse of a response without the excluded blocks,
+// their parts of the weather stay empty
+func ParseWeatherResponseExcluding(data []byte, exclude []string) (Conditions, Forecast, error) {
+	excluded := map[string]bool{}
+	for _, block := range exclude {
+		excluded[block] = true
+	}
 	var resp WeatherResponse
 	err := json.Unmarshal(data, &resp)
 	if err != nil {
 		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: %w", data, err)
 	}
-	if len(resp.Current.Weather) < 1 {
+	if !excluded[ExcludeCurrent] && len(resp.Current.Weather) < 1 {
 		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: want at least one Weather element", data)
 	}
-	if len(resp.Hourly) < 12 {
+	if !excluded[ExcludeHourly] && len(resp.Hourly) < 12 {
 		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: want at least some Hourly elements", data)
 	}
-	if len(resp.Daily) < 3 {
+	if !excluded[ExcludeDaily] && len(resp.Daily) < 3 {
 		return Conditions{}, Forecast{}, fmt.Errorf("invalid API response %s: want at least Daily elements till after tomorrow", data)
 	}
-	conditions := Conditions{
-		Timestamp:     time.Unix(resp.Current.DT, 0).Format("02.01.2006 15:04 MST"),
-		Sunrise:       time.Unix(resp.Current.Sunrise, 0).Format("15:04"),
-		Sunset:        time.Unix(resp.Current.Sunset, 0).Format("15:04"),
-		Summary:       resp.Current.Weather[0].Description,
-		Temperature:   resp.Current.Temp,
-		FeelsLike:     resp.Current.Feels_Like,
-		DewPoint:      resp.Current.Dew_Point,
-		Pressure:      resp.Current.Pressure,
-		Humidity:      resp.Current.Humidity,
-		WindSpeed:     resp.Current.Wind_Speed,
-		WindGust:      resp.Current.Wind_Gust,
-		WindDirection: resp.Current.Wind_Deg,
+	conditions := Conditions{}
+	if !excluded[ExcludeCurrent] {
+		conditions = resp.Current.conditions()
 	}
 	forecast := Forecast{
-		Hourly: []ForecastHourly{},
-		Daily:  []ForecastDaily{},
+		Minutely: []ForecastMinutely{},
+		Hourly:   []ForecastHourly{},
+		Daily:    []ForecastDaily{},
+	}
+	for _, slot := range resp.Minutely {
+		forecast.Minutely = append(forecast.Minutely, ForecastMinutely{
+			Time:          time.Unix(slot.DT, 0),
+			Precipitation: slot.Precipitation,
+		})
 	}
 	for _, slot := range resp.Hourly {
 		s := ForecastHourly{
-			Day:         time.Unix(slot.DT, 0).Format("02.01.2006"),
-			Hour:        time.Unix(slot.DT, 0).Format("15:04"),
-			Temperature: slot.Temp,
-			RainChance:  slot.PoP * 100,
+			Time:          time.Unix(slot.DT, 0),
+			Day:           time.Unix(slot.DT, 0).Format("02.01.2006"),
+			Hour:          time.Unix(slot.DT, 0).Format("15:04"),
+			Temperature:   slot.Temp,
+			FeelsLike:     slot.Feels_Like,
+			RainChance:    slot.PoP * 100,
+			WindSpeed:     slot.Wind_Speed,
+			WindGust:      slot.Wind_Gust,
+			WindDirection: slot.Wind_Deg,
+			Humidity:      slot.Humidity,
+			Pressure:      slot.Pressure,
+			Clouds:        slot.Clouds,
+			UVI:           slot.UVI,
+			Rain:          slot.Rain.OneHour,
+			Snow:          slot.Snow.OneHour,
+		}
+		if len(slot.Weather) > 0 {
+			s.Description = slot.Weather[0].Description
+			s.Icon = slot.Weather[0].Icon
+			s.Condition = slot.Weather[0].ID
 		}
 		forecast.Hourly = append(forecast.Hourly, s)
 	}
 	for _, slot := range resp.Daily {
 		s := ForecastDaily{
 			Day:       time.Unix(slot.DT, 0).Format("02.01.2006"),
+			Sunrise:   time.Unix(slot.Sunrise, 0).Format("15:04"),
+			Sunset:    time.Unix(slot.Sunset, 0).Format("15:04"),
+			DayLength: time.Duration(slot.Sunset-slot.Sunrise) * time.Second,
 			Moonrise:  time.Unix(slot.Moonrise, 0).Format("15:04"),
 			Moonset:   time.Unix(slot.Moonset, 0).Format("15:04"),
 			Moonphase: slot.Moon_Phase,
@@ -306,7 +481,22 @@ func ParseWeatherResponse(data []byte) (Conditions, Forecast, error) {
 				Evening: slot.Temp.Eve,
 				Night:   slot.Temp.Night,
 			},
-			Alerts: []Alert{},
+			RainChance:    slot.PoP * 100,
+			WindSpeed:     slot.Wind_Speed,
+			WindDirection: slot.Wind_Deg,
+			WindGust:      slot.Wind_Gust,
+			Humidity:      slot.Humidity,
+			Clouds:        slot.Clouds,
+			UVI:           slot.UVI,
+			Rain:          slot.Rain,
+			Snow:          slot.Snow,
+			Summary:       slot.Summary,
+			Alerts:        []Alert{},
+		}
+		if len(slot.Weather) > 0 {
+			s.Description = slot.Weather[0].Description
+			s.Icon = slot.Weather[0].Icon
+			s.Condition = slot.Weather[0].ID
 		}
 		for _, a := range slot.Alerts {
 			alert := Alert{
@@ -315,7 +505,24 @@ func ParseWeatherResponse(data []byte) (Conditions, Forecast, error) {
 				Name:        a.Name,
 				Description: a.Description,
 			}
-			s.Alerts = append(s.Alerts, alert)
+			s.Alerts = append(s.Alerts, ClassifyAlert(alert))
+		}
+		// an alert belongs to every day it is in force on
+		y, m, d := time.Unix(slot.DT, 0).Date()
+		dayStart := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		for _, a := range resp.Alerts {
+			if a.Start >= dayEnd.Unix() || a.End <= dayStart.Unix() {
+				continue
+			}
+			s.Alerts = append(s.Alerts, ClassifyAlert(Alert{
+				Start:       time.Unix(a.Start, 0).Format("02.01.2006, 15:04"),
+				End:         time.Unix(a.End, 0).Format("02.01.2006, 15:04"),
+				Name:        a.Event,
+				Description: a.Description,
+				Sender:      a.Sender_Name,
+				Tags:        a.Tags,
+			}))
 		}
 		forecast.Daily = append(forecast.Daily, s)
 	}
@@ -338,183 +545,237 @@ func ParseGeoResponse(data []byte) (Coordinates, error) {
 	return coordinates, nil
 }
 
+// printHeader ... title of every output, followed by the resolved place if known
+func printHeader(title string, f Forecast) {
+	writeHeader(os.Stdout, title, f)
+}
+
 // PrintCurrentConditions ... output of the current weather conditions, perfect if you can't look out of your window
-func PrintCurrentConditions(c Conditions, f Forecast) {
-	fmt.Println()
-	fmt.Println("Aktuelles Wetter vom " + c.Timestamp)
-	fmt.Println("-----------------------------------------------------")
-	fmt.Printf("Sonne: %s / %s\n", c.Sunrise, c.Sunset)
-	fmt.Printf("Mond: %s / %s, %s\n", f.Daily[0].Moonrise, f.Daily[0].Moonset, f.Daily[0].Moonphase.Description())
-	fmt.Printf("Beschreibung: %s\n", c.Summary)
-	fmt.Printf("Temperatur: %.1f °C, gefühlt %.1f °C\n", c.Temperature, c.FeelsLike)
-	fmt.Printf("Taupunkt: %.1f °C\n", c.DewPoint)
-	fmt.Printf("Luftdruck: %d hPa\n", c.Pressure)
-	fmt.Printf("Luftfeuchtigkeit: %d %%\n", c.Humidity)
-	fmt.Printf("Wind: %.0f km/h aus %s, in Böen %.0f km/h\n", c.WindSpeed.KmPerHour(), c.WindDirection.Direction(), c.WindGust.KmPerHour())
-	fmt.Println()
-	if len(f.Daily[0].Alerts) > 0 {
-		for _, a := range f.Daily[0].Alerts {
-			fmt.Printf("%s von %s - %s\n", a.Name, a.Start, a.End)
-			fmt.Println(a.Description)
-			fmt.Println()
-		}
-	}
+// with icons the moon phase is led by its glyph
+func PrintCurrentConditions(c Conditions, f Forecast, icons bool) {
+	TextRenderer{Icons: icons}.RenderCurrent(os.Stdout, c, f)
 }
 
-// PrintForecast ... output of forecast for today, tomorrow or the day after tomorrow
+// PrintForecast ... output of the forecast of one day, offset 0 is today
 func PrintForecast(f Forecast, offset int) error {
-	if offset < 0 || offset > 2 {
-		return fmt.Errorf("offset %d is out of range, should be 0, 1 or 2", offset)
+	return TextRenderer{}.RenderForecast(os.Stdout, f, offset)
+}
+
+// FormatPrecipitation ... volume of rain and snow, e.g. "0.5 mm Regen, 1.2 mm Schnee", empty parts are skipped
+func FormatPrecipitation(rain, snow float64) string {
+	parts := []string{}
+	if rain > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f mm Regen", rain))
 	}
-	fmt.Println()
-	fmt.Printf("Vorhersage für %s\n", f.Daily[offset].Day)
-	fmt.Println("-----------------------------------------------------")
-	fmt.Println("Temperaturen ...")
-	fmt.Printf("... zwischen %.0f °C und %.0f °C\n",
-		f.Daily[offset].Temp.Min,
-		f.Daily[offset].Temp.Max)
-	fmt.Printf("... morgens %.0f °C, mittags %.0f °C, abends %.0f °C und nachts %.0f °C.\n",
-		f.Daily[offset].Temp.Morning,
-		f.Daily[offset].Temp.Day,
-		f.Daily[offset].Temp.Evening,
-		f.Daily[offset].Temp.Night)
-	fmt.Println()
-	fmt.Println(GetRainyPeriods(f, offset))
-	fmt.Println()
-	if len(f.Daily[offset].Alerts) > 0 {
-		for _, a := range f.Daily[0].Alerts {
-			fmt.Printf("%s von %s - %s\n", a.Name, a.Start, a.End)
-			fmt.Println(a.Description)
-			fmt.Println()
-		}
+	if snow > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f mm Schnee", snow))
 	}
-	return nil
+	if len(parts) == 0 {
+		return "kein Niederschlag"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatDayTemperatures ... temperatures in the course of the day, e.g. "morgens 16 °C, mittags 28 °C, ..."
+func FormatDayTemperatures(t DailyTempBenchmarks, unit string) string {
+	return fmt.Sprintf("morgens %.0f %s, mittags %.0f %s, abends %.0f %s und nachts %.0f %s",
+		t.Morning, unit, t.Day, unit, t.Evening, unit, t.Night, unit)
 }
 
-// PrintMoon ... output of moonrise and moonset for next days, including the moon phases
-func PrintMoon(f Forecast) {
+// PrintMoon ... output of moonrise and moonset for next days, including the moon phases, led by their glyphs
+// if icons is set
+func PrintMoon(f Forecast, icons bool) {
 	fmt.Println()
-	fmt.Println("Mondauf-/untergang, Mondphase")
-	fmt.Println("-----------------------------------------------------")
+	printHeader("Mondauf-/untergang, Mondphase", f)
 	lastDescription := ""
 	for _, day := range f.Daily {
 		currentDescritption := day.Moonphase.Description()
+		// the moon phases computed without the API have no moonrise and moonset
+		times := day.Moonrise + " - " + day.Moonset + ", "
+		if day.Moonrise == "--:--" && day.Moonset == "--:--" {
+			times = ""
+		}
 		if lastDescription != currentDescritption {
-			fmt.Printf("%s: %s - %s, %s\n", day.Day, day.Moonrise, day.Moonset, day.Moonphase.Description())
+			fmt.Printf("%s: %s%s, %s\n", day.Day, times, day.Moonphase.describe(icons), day.Moonphase.formatLight())
 		} else {
-			fmt.Printf("%s: %s - %s\n", day.Day, day.Moonrise, day.Moonset)
+			fmt.Printf("%s: %s%s\n", day.Day, times, day.Moonphase.FormatMoon())
 		}
 		lastDescription = currentDescritption
 	}
 	fmt.Println()
+	after := f.nextMoonsAfter(time.Now())
+	fmt.Printf("Nächster Vollmond: %s Uhr\n", NextMoonPhase(after, true).Format("02.01.2006, 15:04"))
+	fmt.Printf("Nächster Neumond: %s Uhr\n", NextMoonPhase(after, false).Format("02.01.2006, 15:04"))
+	fmt.Println()
 }
 
-// PrintRain ... perception of rain and snow for today and next days, including ascii graph
+// PrintRain ... perception of rain and snow for today and next days, with a bar chart of the hourly rain chances
 func PrintRain(f Forecast) {
+	if len(f.Daily) == 0 {
+		return
+	}
 	fmt.Println()
-	fmt.Printf("Niederschlag vom %s - %s\n", f.Daily[0].Day, f.Daily[2].Day)
-	fmt.Println("-----------------------------------------------------")
-	fmt.Printf("%s: %s\n", f.Daily[0].Day, GetRainyPeriods(f, 0))
-	fmt.Printf("%s: %s\n", f.Daily[1].Day, GetRainyPeriods(f, 1))
-	fmt.Printf("%s: %s\n", f.Daily[2].Day, GetRainyPeriods(f, 2))
+	last := 0
+	for last+1 < len(f.Daily) && hasHourly(f, f.Daily[last+1].Day) {
+		last++
+	}
+	printHeader(fmt.Sprintf("Niederschlag vom %s - %s", f.Daily[0].Day, f.Daily[last].Day), f)
+	for offset := 0; offset <= last; offset++ {
+		day := f.Daily[offset]
+		fmt.Printf("%s: %s", day.Day, GetRainyPeriods(f, offset))
+		if day.Rain > 0 || day.Snow > 0 {
+			fmt.Printf(" Insgesamt %s.", FormatPrecipitation(day.Rain, day.Snow))
+		}
+		fmt.Println()
+		if g, err := GetGraph(f, "rain", offset); err == nil {
+			TextRenderer{}.writeRainChart(os.Stdout, g)
+		}
+	}
 	fmt.Println()
 }
 
 // PrintAlerts ... alerts for today and the next days
 func PrintAlerts(f Forecast) {
-	fmt.Println()
-	fmt.Printf("Warnungen vom %s - %s\n", f.Daily[0].Day, f.Daily[2].Day)
-	fmt.Println("-----------------------------------------------------")
-	switch true {
-	case len(f.Daily[0].Alerts) > 0:
-		for _, a := range f.Daily[0].Alerts {
-			fmt.Printf("%s von %s - %s\n", a.Name, a.Start, a.End)
-			fmt.Println(a.Description)
-			fmt.Println()
-		}
-	case len(f.Daily[1].Alerts) > 0:
-		for _, a := range f.Daily[1].Alerts {
-			fmt.Printf("%s von %s - %s\n", a.Name, a.Start, a.End)
-			fmt.Println(a.Description)
-			fmt.Println()
-		}
-	case len(f.Daily[2].Alerts) > 0:
-		for _, a := range f.Daily[2].Alerts {
-			fmt.Printf("%s von %s - %s\n", a.Name, a.Start, a.End)
-			fmt.Println(a.Description)
-			fmt.Println()
+	TextRenderer{}.RenderAlerts(os.Stdout, f)
+}
+
+// hasHourly ... true if the hourly forecast covers the given day, which is not the case after 48 hours
+func hasHourly(f Forecast, day string) bool {
+	for _, slot := range f.Hourly {
+		if slot.Day == day {
+			return true
 		}
-	default:
-		fmt.Println("Es liegen keine Warnungen vor.")
 	}
-	fmt.Println()
+	return false
+}
+
+// PrintWeek ... compact table with the outlook for all available days
+func PrintWeek(f Forecast) {
+	TextRenderer{}.RenderWeek(os.Stdout, f)
+}
+
+// PrintHourly ... table with the forecast for the next hours, at most 48 hours are available
+func PrintHourly(f Forecast, hours int) {
+	TextRenderer{}.RenderHourly(os.Stdout, f, hours)
 }
 
-// GetGraphData ... delivers data collections for temperatures, wind speeds etc.
+// GetGraphData ... delivers data collections for temperatures, wind speeds etc.,
+// key is one of Temp, FeelsLike, Rain, Wind, Gust, Humidity, Pressure and Clouds
 func GetGraphData(f Forecast, key string, offset int) []float64 {
 	reference := f.Daily[offset].Day
 	values := []float64{}
 	for _, slot := range f.Hourly {
-		if slot.Day == reference {
-			if key == "Temp" {
-				values = append(values, slot.Temperature)
-			}
+		if slot.Day != reference {
+			continue
+		}
+		switch key {
+		case "Temp":
+			values = append(values, slot.Temperature)
+		case "FeelsLike":
+			values = append(values, slot.FeelsLike)
+		case "Rain":
+			values = append(values, slot.RainChance)
+		case "Wind":
+			values = append(values, float64(slot.WindSpeed))
+		case "Gust":
+			values = append(values, float64(slot.WindGust))
+		case "Humidity":
+			values = append(values, float64(slot.Humidity))
+		case "Pressure":
+			values = append(values, float64(slot.Pressure))
+		case "Clouds":
+			values = append(values, float64(slot.Clouds))
 		}
 	}
 	return values
 }
 
-// GetRainyPeriods ... filter for rainy periods
+// GetRainyPeriods ... filter for rainy periods, with the expected amount if the forecast has one,
+// periods of snow or sleet are named as such
 func GetRainyPeriods(f Forecast, offset int) string {
+	type period struct {
+		from, to string
+		amount   float64
+		kind     string
+	}
 	reference := f.Daily[offset].Day
-	values := []string{}
-	itsRaining := ""
-	previousSlot := ""
+	periods := []period{}
+	raining := false
 	for _, slot := range f.Hourly {
 		if slot.Day != reference {
 			continue
 		}
-		if slot.RainChance > 0 {
-			if itsRaining == "" {
-				itsRaining = slot.Hour
-			}
-			previousSlot = slot.Hour
-		} else {
-			if previousSlot != "" {
-				if itsRaining != previousSlot {
-					// period of more than 1 hour
-					itsRaining = "von " + itsRaining + " - " + previousSlot
-				} else {
-					// short period of 1 hour only
-					itsRaining = "um " + itsRaining
-				}
-				values = append(values, itsRaining)
-				itsRaining = ""
-				previousSlot = ""
-			}
+		if slot.RainChance <= 0 {
+			raining = false
+			continue
 		}
-	}
-	// process hanging periods till midnight
-	if itsRaining != "" {
-		if itsRaining != previousSlot {
-			// period of more than 1 hour
-			itsRaining = "von " + itsRaining + " - " + previousSlot
-		} else {
-			// short period of 1 hour only
-			itsRaining = "um " + itsRaining
+		kind := slot.PrecipitationKind(f.Units)
+		if !raining {
+			periods = append(periods, period{from: slot.Hour, kind: kind})
+			raining = true
 		}
-
-		if itsRaining == "von 00:00 - 23:00" {
-			itsRaining = "den ganzen Tag über"
+		p := &periods[len(periods)-1]
+		p.to = slot.Hour
+		p.amount += slot.Rain + slot.Snow
+		if p.kind != kind {
+			// rain turning into snow or the other way round
+			p.kind = PrecipitationSleet
 		}
-		values = append(values, itsRaining)
+	}
+	if len(periods) == 0 {
+		return "Es regnet nicht."
 	}
 
-	result := "Es regnet nicht."
-	if len(values) > 0 {
-		result = "Es regnet " + strings.Join(values, ", ") + "."
+	same := true
+	for _, p := range periods {
+		same = same && p.kind == periods[0].kind
+	}
+	values := []string{}
+	for _, p := range periods {
+		value := "um " + p.from
+		switch {
+		case p.from == "00:00" && p.to == "23:00":
+			value = "den ganzen Tag über"
+		case p.from != p.to:
+			value = "von " + p.from + " - " + p.to
+		}
+		if p.amount > 0 {
+			value += fmt.Sprintf(" (%.1f mm)", p.amount)
+		}
+		if !same {
+			value = p.kind + " " + value
+		}
+		values = append(values, value)
 	}
-	return result
+	switch {
+	case same && periods[0].kind == PrecipitationRain:
+		return "Es regnet " + strings.Join(values, ", ") + "."
+	case same && periods[0].kind == PrecipitationSnow:
+		return "Es schneit " + strings.Join(values, ", ") + "."
+	case same:
+		return "Schneeregen " + strings.Join(values, ", ") + "."
+	}
+	return "Niederschlag: " + strings.Join(values, ", ") + "."
+}
+
+// PrecipitationKind ... Regen, Schnee or Schneeregen, by the forecast amounts or else by the temperature
+func (h ForecastHourly) PrecipitationKind(units string) string {
+	switch {
+	case h.Rain > 0 && h.Snow > 0:
+		return PrecipitationSleet
+	case h.Snow > 0:
+		return PrecipitationSnow
+	case h.Rain > 0:
+		return PrecipitationRain
+	}
+	celsius := convertTemperature(h.Temperature, units, UnitsMetric)
+	switch {
+	case celsius <= 0:
+		return PrecipitationSnow
+	case celsius <= 2:
+		return PrecipitationSleet
+	}
+	return PrecipitationRain
 }
 
 // GetTimestamp ... wrapper for time conversion and format
@@ -522,21 +783,132 @@ func GetTimestamp(sec int64, format string) string {
 	return time.Unix(sec, 0).Format(format)
 }
 
+// ParseZipResponse ... coordinates of a postal code lookup
+func ParseZipResponse(data []byte) (Coordinates, error) {
+	var resp ZipResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("invalid API response %s: %w", data, err)
+	}
+	if resp.Lat == 0 && resp.Lon == 0 {
+		return Coordinates{}, fmt.Errorf("invalid API response %s: want coordinates for postal code", data)
+	}
+	coordinates := Coordinates{
+		Lat: resp.Lat,
+		Lon: resp.Lon,
+	}
+	return coordinates, nil
+}
+
+// ParseReverseGeoResponse ... name of the place found at the requested coordinates
+func ParseReverseGeoResponse(data []byte) (Place, error) {
+	var resp GeoResponse
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return Place{}, fmt.Errorf("invalid API response %s: %w", data, err)
+	}
+	if len(resp) < 1 {
+		return Place{}, fmt.Errorf("invalid API response %s: want at least one place", data)
+	}
+	place := Place{
+		Name:    resp[0].Name,
+		State:   resp[0].State,
+		Country: resp[0].Country,
+	}
+	return place, nil
+}
+
+// conditions ... the response as Conditions, there has to be at least one Weather element
+func (r CurrentResponse) conditions() Conditions {
+	return Conditions{
+		Timestamp:     time.Unix(r.DT, 0).Format("02.01.2006 15:04 MST"),
+		Sunrise:       time.Unix(r.Sunrise, 0).Format("15:04"),
+		Sunset:        time.Unix(r.Sunset, 0).Format("15:04"),
+		Summary:       r.Weather[0].Description,
+		Temperature:   r.Temp,
+		FeelsLike:     r.Feels_Like,
+		DewPoint:      r.Dew_Point,
+		Pressure:      r.Pressure,
+		Humidity:      r.Humidity,
+		WindSpeed:     r.Wind_Speed,
+		WindGust:      r.Wind_Gust,
+		WindDirection: r.Wind_Deg,
+		UVI:           r.UVI,
+		Visibility:    r.Visibility,
+		Clouds:        r.Clouds,
+		Rain:          r.Rain.OneHour,
+		Snow:          r.Snow.OneHour,
+		Icon:          r.Weather[0].Icon,
+		Condition:     r.Weather[0].ID,
+	}
+}
+
+// excludes ... true if the block is left out of the One Call response
+func (c *Client) excludes(block string) bool {
+	for _, b := range c.Exclude {
+		if b == block {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Client) FormatWeatherURL(coordinates Coordinates) string {
-	return fmt.Sprintf("%s/data/3.0/onecall?lat=%g&lon=%g&units=metric&lang=de&appid=%s", c.BaseURL, coordinates.Lat, coordinates.Lon, c.APIKey)
+	URL := fmt.Sprintf("%s/data/3.0/onecall?lat=%g&lon=%g&units=%s&lang=%s&appid=%s", c.BaseURL, coordinates.Lat, coordinates.Lon, c.Units, c.Lang, c.APIKey)
+	if len(c.Exclude) > 0 {
+		URL += "&exclude=" + strings.Join(c.Exclude, ",")
+	}
+	return URL
 }
 
 func (c *Client) FormatGeoURL(location string) string {
 	return fmt.Sprintf("%s/geo/1.0/direct?q=%s&limit=1&appid=%s", c.BaseURL, location, c.APIKey)
 }
 
+func (c *Client) FormatZipURL(zip string) string {
+	return fmt.Sprintf("%s/geo/1.0/zip?zip=%s&appid=%s", c.BaseURL, zip, c.APIKey)
+}
+
+func (c *Client) FormatReverseGeoURL(coordinates Coordinates) string {
+	return fmt.Sprintf("%s/geo/1.0/reverse?lat=%g&lon=%g&limit=1&appid=%s", c.BaseURL, coordinates.Lat, coordinates.Lon, c.APIKey)
+}
+
+// GetWeather ... weather of One Call 3.0 or the free endpoints, depending on API
 func (c *Client) GetWeather(coordinates Coordinates) (Conditions, Forecast, error) {
-	URL := c.FormatWeatherURL(coordinates)
-	resp, err := c.HTTPClient.Get(URL)
+	return c.getWeather(context.Background(), coordinates)
+}
+
+// getWeather ... GetWeather with the requests bound to ctx
+func (c *Client) getWeather(ctx context.Context, coordinates Coordinates) (Conditions, Forecast, error) {
+	for _, block := range c.Exclude {
+		if !validExclude[block] {
+			return Conditions{}, Forecast{}, fmt.Errorf("invalid exclude %q, want current, minutely, hourly, daily or alerts", block)
+		}
+	}
+	switch c.API {
+	case "", APIAuto, APIOneCall:
+	case APIFree:
+		return c.getWeatherFree(ctx, coordinates)
+	default:
+		return Conditions{}, Forecast{}, fmt.Errorf("unknown api %q, want auto, onecall or free", c.API)
+	}
+	if c.oneCallDenied() {
+		return c.getWeatherFree(ctx, coordinates)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.FormatWeatherURL(coordinates), nil)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return Conditions{}, Forecast{}, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized && c.API != APIOneCall {
+		// the key isn't subscribed to One Call 3.0, from now on only the free endpoints are asked
+		atomic.StoreInt32(&c.denied, 1)
+		return c.getWeatherFree(ctx, coordinates)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return Conditions{}, Forecast{}, fmt.Errorf("unexptected response status %q", resp.Status)
 	}
@@ -544,13 +916,32 @@ func (c *Client) GetWeather(coordinates Coordinates) (Conditions, Forecast, erro
 	if err != nil {
 		return Conditions{}, Forecast{}, err
 	}
-	conditions, forecast, err := ParseWeatherResponse(data)
+	conditions, forecast, err := ParseWeatherResponseExcluding(data, c.Exclude)
 	if err != nil {
 		return Conditions{}, Forecast{}, err
 	}
+	forecast.Units = c.Units
+	forecast.Coordinates = &coordinates
 	return conditions, forecast, nil
 }
 
+// Get ... resolves the location and fetches its weather in one go
+func (c *Client) Get(location string) (Conditions, Forecast, error) {
+	coordinates, err := c.Locate(location)
+	if err != nil {
+		return Conditions{}, Forecast{}, err
+	}
+	return c.GetWeather(coordinates)
+}
+
+// Locate ... uses coordinates given as "lat,lon" directly, everything else is geocoded
+func (c *Client) Locate(location string) (Coordinates, error) {
+	if coordinates, ok := ParseCoordinates(location); ok {
+		return coordinates, nil
+	}
+	return c.GetCoordinates(location)
+}
+
 func (c *Client) GetCoordinates(location string) (Coordinates, error) {
 	URL := c.FormatGeoURL(location)
 	resp, err := c.HTTPClient.Get(URL)
@@ -572,6 +963,111 @@ func (c *Client) GetCoordinates(location string) (Coordinates, error) {
 	return coordinates, nil
 }
 
+// GetCoordinatesByZip ... geocoding of a postal code with country code, e.g. 10115,DE
+func (c *Client) GetCoordinatesByZip(zip string) (Coordinates, error) {
+	URL := c.FormatZipURL(zip)
+	resp, err := c.HTTPClient.Get(URL)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Coordinates{}, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	coordinates, err := ParseZipResponse(data)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	return coordinates, nil
+}
+
+// ReverseGeocode ... resolves coordinates into the name of a place
+func (c *Client) ReverseGeocode(coordinates Coordinates) (Place, error) {
+	URL := c.FormatReverseGeoURL(coordinates)
+	resp, err := c.HTTPClient.Get(URL)
+	if err != nil {
+		return Place{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Place{}, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Place{}, err
+	}
+	place, err := ParseReverseGeoResponse(data)
+	if err != nil {
+		return Place{}, err
+	}
+	return place, nil
+}
+
+// String ... human readable place like "Berlin, Berlin, DE", empty parts are skipped
+func (p Place) String() string {
+	parts := []string{}
+	for _, part := range []string{p.Name, p.State, p.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// dailyJSON ... ForecastDaily with the day length in seconds instead of nanoseconds
+type dailyJSON struct {
+	forecastDaily
+	DayLengthSeconds int64 `json:"day_length_seconds"`
+}
+
+type forecastDaily ForecastDaily
+
+func (d ForecastDaily) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dailyJSON{forecastDaily(d), int64(d.DayLength / time.Second)})
+}
+
+func (d *ForecastDaily) UnmarshalJSON(data []byte) error {
+	var v dailyJSON
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	*d = ForecastDaily(v.forecastDaily)
+	d.DayLength = time.Duration(v.DayLengthSeconds) * time.Second
+	return nil
+}
+
+// TemperatureUnit ... symbol matching the units the forecast was requested with
+func (f Forecast) TemperatureUnit() string {
+	switch f.Units {
+	case UnitsImperial:
+		return "°F"
+	case UnitsStandard:
+		return "K"
+	}
+	return "°C"
+}
+
+// CurrentAlerts ... alerts of the first forecast day, each once, never nil
+func (f Forecast) CurrentAlerts() []Alert {
+	if len(f.Daily) == 0 {
+		return []Alert{}
+	}
+	return MergeAlerts(f.Daily[0].Alerts)
+}
+
+// FormatSpeed ... wind speed in km/h, or in mph for imperial units
+func (f Forecast) FormatSpeed(s Speed) string {
+	if f.Units == UnitsImperial {
+		return fmt.Sprintf("%.0f mph", float64(s))
+	}
+	return fmt.Sprintf("%.0f km/h", s.KmPerHour())
+}
+
 // KmPerHour ... helper method for speed output
 func (s Speed) KmPerHour() float64 {
 	return float64(s) * 3.6
@@ -1,17 +1,34 @@
 package weather_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
 	"github.com/google/go-cmp/cmp"
 )
 
+// TestMain ... runs the tests in the time zone of the fixtures, which expect the wall times of Bonn
+func TestMain(m *testing.M) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	time.Local = loc
+	os.Exit(m.Run())
+}
+
 func TestConditionsFromParseWeatherResponse(t *testing.T) {
 	t.Parallel()
 	data, err := os.ReadFile("testdata/weather_30.json")
@@ -31,6 +48,12 @@ func TestConditionsFromParseWeatherResponse(t *testing.T) {
 		WindSpeed:     2.3,
 		WindGust:      3.32,
 		WindDirection: 233,
+		UVI:           3.75,
+		Visibility:    10000,
+		Clouds:        85,
+		Rain:          0.12,
+		Icon:          "10d",
+		Condition:     500,
 	}
 	got, _, err := weather.ParseWeatherResponse(data)
 	if err != nil {
@@ -48,10 +71,17 @@ func TestDailyForecastFromParseWeatherResponse(t *testing.T) {
 		t.Fatal(err)
 	}
 	want := weather.ForecastDaily{
-		Day:       "17.06.2022",
-		Moonrise:  "00:24",
-		Moonset:   "08:14",
-		Moonphase: 0.62,
+		Day:         "17.06.2022",
+		Description: "Bedeckt",
+		Icon:        "04d",
+		Condition:   804,
+		Summary:     "Expect a day of partly cloudy with clear spells",
+		Sunrise:     "05:18",
+		Sunset:      "21:46",
+		DayLength:   59308 * time.Second,
+		Moonrise:    "00:24",
+		Moonset:     "08:14",
+		Moonphase:   0.62,
 		Temp: weather.DailyTempBenchmarks{
 			Max:     31.38,
 			Min:     13.58,
@@ -60,7 +90,13 @@ func TestDailyForecastFromParseWeatherResponse(t *testing.T) {
 			Evening: 30.18,
 			Night:   20.39,
 		},
-		Alerts: []weather.Alert{},
+		WindSpeed:     2.8,
+		WindDirection: 244,
+		WindGust:      4.5,
+		Humidity:      31,
+		Clouds:        96,
+		UVI:           7.08,
+		Alerts:        []weather.Alert{},
 	}
 	_, fc, err := weather.ParseWeatherResponse(data)
 	if err != nil {
@@ -142,6 +178,53 @@ func TestFormatWeatherURL(t *testing.T) {
 	}
 }
 
+func TestFormatWeatherURLExclude(t *testing.T) {
+	t.Parallel()
+	c := weather.NewClient("dummyAPIKey")
+	c.Exclude = []string{weather.ExcludeMinutely, weather.ExcludeAlerts}
+	want := "https://api.openweathermap.org/data/3.0/onecall?lat=1&lon=2&units=metric&lang=de&appid=dummyAPIKey&exclude=minutely,alerts"
+	got := c.FormatWeatherURL(weather.Coordinates{Lat: 1, Lon: 2})
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseWeatherResponseExcluding(t *testing.T) {
+	t.Parallel()
+	var resp map[string]any
+	err := json.Unmarshal(weathertest.WeatherResponse, &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(resp, "current")
+	delete(resp, "hourly")
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = weather.ParseWeatherResponse(data)
+	if err == nil {
+		t.Error("want error for missing blocks, but got nil")
+	}
+	conditions, fc, err := weather.ParseWeatherResponseExcluding(data, []string{weather.ExcludeCurrent, weather.ExcludeHourly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conditions != (weather.Conditions{}) || len(fc.Hourly) != 0 || len(fc.Daily) != 8 {
+		t.Errorf("want only the daily forecast, got %+v with %d hours and %d days", conditions, len(fc.Hourly), len(fc.Daily))
+	}
+}
+
+func TestGetWeatherInvalidExclude(t *testing.T) {
+	t.Parallel()
+	c := weather.NewClient("dummyAPIKey")
+	c.Exclude = []string{"weekly"}
+	_, _, err := c.GetWeather(weather.Coordinates{Lat: 1, Lon: 2})
+	if err == nil {
+		t.Error("want error for invalid exclude, but got nil")
+	}
+}
+
 func TestFormatGeoURL(t *testing.T) {
 	t.Parallel()
 	c := weather.NewClient("dummyAPIKey")
@@ -153,6 +236,15 @@ func TestFormatGeoURL(t *testing.T) {
 	}
 }
 
+func TestJoinLocation(t *testing.T) {
+	t.Parallel()
+	want := "What+a+long+Place"
+	got := weather.JoinLocation([]string{"What", "a", "long", "Place"})
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
 func TestLocationWithSpace(t *testing.T) {
 	t.Parallel()
 	params := []string{"HIDDEN", "HIDDEN", "What", "a", "long", "Place"}
@@ -222,6 +314,12 @@ func TestConditionsFromGetWeather(t *testing.T) {
 		WindSpeed:     2.3,
 		WindGust:      3.32,
 		WindDirection: 233,
+		UVI:           3.75,
+		Visibility:    10000,
+		Clouds:        85,
+		Rain:          0.12,
+		Icon:          "10d",
+		Condition:     500,
 	}
 	coordinates := weather.Coordinates{Lat: 1.0, Lon: 2.0}
 	got, _, err := c.GetWeather(coordinates)
@@ -249,9 +347,21 @@ func TestForecastHourlyFromGetWeather(t *testing.T) {
 	c.BaseURL = ts.URL
 	c.HTTPClient = ts.Client()
 	want := weather.ForecastHourly{
-		Day:         "17.06.2022",
-		Hour:        "17:00",
-		Temperature: 31.38,
+		Time:          time.Unix(1655478000, 0),
+		Day:           "17.06.2022",
+		Hour:          "17:00",
+		Temperature:   31.38,
+		FeelsLike:     29.86,
+		WindSpeed:     2.3,
+		WindGust:      3.32,
+		WindDirection: 233,
+		Humidity:      27,
+		Pressure:      1021,
+		Clouds:        85,
+		Description:   "Bedeckt",
+		Icon:          "04d",
+		Condition:     804,
+		UVI:           3.75,
 	}
 	coordinates := weather.Coordinates{Lat: 1.0, Lon: 2.0}
 	_, fc, err := c.GetWeather(coordinates)
@@ -280,10 +390,17 @@ func TestForecastDailyFromGetWeather(t *testing.T) {
 	c.BaseURL = ts.URL
 	c.HTTPClient = ts.Client()
 	want := weather.ForecastDaily{
-		Day:       "17.06.2022",
-		Moonrise:  "00:24",
-		Moonset:   "08:14",
-		Moonphase: 0.62,
+		Day:         "17.06.2022",
+		Description: "Bedeckt",
+		Icon:        "04d",
+		Condition:   804,
+		Summary:     "Expect a day of partly cloudy with clear spells",
+		Sunrise:     "05:18",
+		Sunset:      "21:46",
+		DayLength:   59308 * time.Second,
+		Moonrise:    "00:24",
+		Moonset:     "08:14",
+		Moonphase:   0.62,
 		Temp: weather.DailyTempBenchmarks{
 			Max:     31.38,
 			Min:     13.58,
@@ -292,7 +409,13 @@ func TestForecastDailyFromGetWeather(t *testing.T) {
 			Evening: 30.18,
 			Night:   20.39,
 		},
-		Alerts: []weather.Alert{},
+		WindSpeed:     2.8,
+		WindDirection: 244,
+		WindGust:      4.5,
+		Humidity:      31,
+		Clouds:        96,
+		UVI:           7.08,
+		Alerts:        []weather.Alert{},
 	}
 	coordinates := weather.Coordinates{Lat: 1.0, Lon: 2.0}
 	_, fc, err := c.GetWeather(coordinates)
@@ -314,6 +437,26 @@ func TestPrintForcastWithWrongOffset(t *testing.T) {
 	}
 }
 
+func TestPrintForecastLastDay(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/weather_30.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fc, err := weather.ParseWeatherResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = weather.PrintForecast(fc, len(fc.Daily)-1)
+	if err != nil {
+		t.Errorf("want forecast of the last of %d days, got %v", len(fc.Daily), err)
+	}
+	err = weather.PrintForecast(fc, len(fc.Daily))
+	if err == nil {
+		t.Error("want error for the day after the forecast, but got nil")
+	}
+}
+
 func TestGetCoordinates(t *testing.T) {
 	t.Parallel()
 	ts := httptest.NewTLSServer(
@@ -371,3 +514,359 @@ func TestDirection(t *testing.T) {
 		t.Error(cmp.Diff(want, got))
 	}
 }
+
+func TestLocationMissing(t *testing.T) {
+	t.Parallel()
+	params := []string{"HIDDEN", "current"}
+	want := ""
+	got := weather.GetLocation(params)
+	if want != got {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestFormatDayTemperatures(t *testing.T) {
+	t.Parallel()
+	temp := weather.DailyTempBenchmarks{Morning: 16.2, Day: 28.4, Evening: 24.6, Night: 18.1}
+	want := "morgens 16 °C, mittags 28 °C, abends 25 °C und nachts 18 °C"
+	got := weather.FormatDayTemperatures(temp, "°C")
+	if want != got {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestForecastDailyJSONDayLengthInSeconds(t *testing.T) {
+	t.Parallel()
+	day := weather.ForecastDaily{Day: "Fr, 17.06.", DayLength: 59308 * time.Second, Alerts: []weather.Alert{}}
+	data, err := json.Marshal(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"day_length_seconds":59308`) {
+		t.Errorf("want day length in seconds, got %s", data)
+	}
+	var got weather.ForecastDaily
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(day, got) {
+		t.Error(cmp.Diff(day, got))
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input string
+		want  weather.Coordinates
+		ok    bool
+	}{
+		{input: "52.52,13.40", want: weather.Coordinates{Lat: 52.52, Lon: 13.40}, ok: true},
+		{input: "52.52,+13.40", want: weather.Coordinates{Lat: 52.52, Lon: 13.40}, ok: true},
+		{input: "-33.9,18.42", want: weather.Coordinates{Lat: -33.9, Lon: 18.42}, ok: true},
+		{input: "London,UK", ok: false},
+		{input: "95.0,13.40", ok: false},
+		{input: "52.52", ok: false},
+		{input: "NaN,NaN", ok: false},
+		{input: "52.52,NaN", ok: false},
+		{input: "Inf,13.40", ok: false},
+		{input: "52.52,-Inf", ok: false},
+	}
+	for _, tc := range tests {
+		got, ok := weather.ParseCoordinates(tc.input)
+		if ok != tc.ok {
+			t.Errorf("%s: want ok %v, got %v", tc.input, tc.ok, ok)
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Error(cmp.Diff(tc.want, got))
+		}
+	}
+}
+
+func TestLocateWithCoordinatesSkipsGeocoding(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s", r.URL)
+		}))
+	defer ts.Close()
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.HTTPClient = ts.Client()
+	want := weather.Coordinates{Lat: 52.52, Lon: 13.4}
+	got, err := c.Locate("52.52,13.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseZipResponse(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/geo_zip.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := weather.Coordinates{
+		Lat: 52.5323,
+		Lon: 13.3846,
+	}
+	got, err := weather.ParseZipResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestParseZipResponseEmpty(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/geo_zip_invalid.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = weather.ParseZipResponse(data)
+	if err == nil {
+		t.Fatal("want error parsing invalid response, but got nil")
+	}
+}
+
+func TestFormatZipURL(t *testing.T) {
+	t.Parallel()
+	c := weather.NewClient("dummyAPIKey")
+	want := "https://api.openweathermap.org/geo/1.0/zip?zip=10115,DE&appid=dummyAPIKey"
+	got := c.FormatZipURL("10115,DE")
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestGetCoordinatesByZip(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f, err := os.Open("testdata/geo_zip.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			io.Copy(w, f)
+		}))
+	defer ts.Close()
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.HTTPClient = ts.Client()
+	want := weather.Coordinates{
+		Lat: 52.5323,
+		Lon: 13.3846,
+	}
+	got, err := c.GetCoordinatesByZip("10115,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestReverseGeocode(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f, err := os.Open("testdata/geo_service.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			io.Copy(w, f)
+		}))
+	defer ts.Close()
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.HTTPClient = ts.Client()
+	want := weather.Place{
+		Name:    "Bad Schnuffel",
+		State:   "North Rhine-Westphalia",
+		Country: "DE",
+	}
+	got, err := c.ReverseGeocode(weather.Coordinates{Lat: 55.123456, Lon: 3.7654321})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestFormatReverseGeoURL(t *testing.T) {
+	t.Parallel()
+	c := weather.NewClient("dummyAPIKey")
+	coordinates := weather.Coordinates{
+		Lat: 55.123456,
+		Lon: 3.7654321,
+	}
+	want := "https://api.openweathermap.org/geo/1.0/reverse?lat=55.123456&lon=3.7654321&limit=1&appid=dummyAPIKey"
+	got := c.FormatReverseGeoURL(coordinates)
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestPlaceString(t *testing.T) {
+	t.Parallel()
+	want := "Berlin, DE"
+	got := weather.Place{Name: "Berlin", Country: "DE"}.String()
+	if want != got {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestWeekFromParseWeatherResponse(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/weather_30.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fc, err := weather.ParseWeatherResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Daily) != 8 {
+		t.Fatalf("want 8 days, got %d", len(fc.Daily))
+	}
+	got := fc.Daily[2]
+	if got.Description != "Leichter Regen" {
+		t.Errorf("want Leichter Regen, got %s", got.Description)
+	}
+	if math.Round(got.RainChance) != 56 {
+		t.Errorf("want rain chance of 56 %%, got %.2f", got.RainChance)
+	}
+}
+
+func TestFormatPrecipitation(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		rain, snow float64
+		want       string
+	}{
+		"none": {0, 0, "kein Niederschlag"},
+		"rain": {0.12, 0, "0.1 mm Regen"},
+		"both": {0.5, 1.25, "0.5 mm Regen, 1.2 mm Schnee"},
+	}
+	for name, tc := range tests {
+		got := weather.FormatPrecipitation(tc.rain, tc.snow)
+		if tc.want != got {
+			t.Errorf("%s: want %q, got %q", name, tc.want, got)
+		}
+	}
+}
+
+func TestGetGraphData(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{
+		Hourly: []weather.ForecastHourly{
+			{Day: "17.06.2022", Temperature: 20, Humidity: 60, Pressure: 1012, WindSpeed: 3},
+			{Day: "17.06.2022", Temperature: 22, Humidity: 55, Pressure: 1013, WindSpeed: 4},
+			{Day: "18.06.2022", Temperature: 18, Humidity: 70, Pressure: 1010, WindSpeed: 6},
+		},
+		Daily: []weather.ForecastDaily{{Day: "17.06.2022"}, {Day: "18.06.2022"}},
+	}
+	tests := map[string]struct {
+		key    string
+		offset int
+		want   []float64
+	}{
+		"temperature": {"Temp", 0, []float64{20, 22}},
+		"humidity":    {"Humidity", 0, []float64{60, 55}},
+		"pressure":    {"Pressure", 1, []float64{1010}},
+		"wind":        {"Wind", 1, []float64{6}},
+		"unknown":     {"Moon", 0, []float64{}},
+	}
+	for name, tc := range tests {
+		got := weather.GetGraphData(f, tc.key, tc.offset)
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("%s: %s", name, cmp.Diff(tc.want, got))
+		}
+	}
+}
+
+func TestGetRainyPeriods(t *testing.T) {
+	t.Parallel()
+	slot := func(hour string, chance, temperature, rain, snow float64) weather.ForecastHourly {
+		return weather.ForecastHourly{Day: "17.06.2022", Hour: hour, RainChance: chance, Temperature: temperature, Rain: rain, Snow: snow}
+	}
+	tests := map[string]struct {
+		hourly []weather.ForecastHourly
+		units  string
+		want   string
+	}{
+		"dry": {
+			hourly: []weather.ForecastHourly{slot("12:00", 0, 15, 0, 0)},
+			want:   "Es regnet nicht.",
+		},
+		"chance only": {
+			hourly: []weather.ForecastHourly{slot("12:00", 40, 15, 0, 0), slot("13:00", 0, 15, 0, 0)},
+			want:   "Es regnet um 12:00.",
+		},
+		"amounts": {
+			hourly: []weather.ForecastHourly{
+				slot("12:00", 60, 15, 1.5, 0), slot("13:00", 80, 14, 2.7, 0), slot("14:00", 0, 14, 0, 0),
+				slot("20:00", 30, 12, 0.4, 0),
+			},
+			want: "Es regnet von 12:00 - 13:00 (4.2 mm), um 20:00 (0.4 mm).",
+		},
+		"snow": {
+			hourly: []weather.ForecastHourly{slot("06:00", 70, 1, 0, 1.2), slot("07:00", 70, -2, 0, 0)},
+			want:   "Es schneit von 06:00 - 07:00 (1.2 mm).",
+		},
+		"snow by temperature in fahrenheit": {
+			hourly: []weather.ForecastHourly{slot("06:00", 70, 30, 0, 0)},
+			units:  weather.UnitsImperial,
+			want:   "Es schneit um 06:00.",
+		},
+		"sleet": {
+			hourly: []weather.ForecastHourly{slot("06:00", 70, 1.5, 0, 0)},
+			want:   "Schneeregen um 06:00.",
+		},
+		"mixed": {
+			hourly: []weather.ForecastHourly{
+				slot("06:00", 70, 1, 0.2, 0.8), slot("07:00", 0, 3, 0, 0),
+				slot("15:00", 50, 6, 0.5, 0), slot("16:00", 50, 3, 0, 0),
+			},
+			want: "Niederschlag: Schneeregen um 06:00 (1.0 mm), Regen von 15:00 - 16:00 (0.5 mm).",
+		},
+	}
+	for name, tc := range tests {
+		f := weather.Forecast{Units: tc.units, Hourly: tc.hourly, Daily: []weather.ForecastDaily{{Day: "17.06.2022"}}}
+		got := weather.GetRainyPeriods(f, 0)
+		if tc.want != got {
+			t.Errorf("%s: want %q, got %q", name, tc.want, got)
+		}
+	}
+}
+
+func TestPrecipitationFromParseWeatherResponse(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/weather_30.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, f, err := weather.ParseWeatherResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Daily[2].Rain != 1.49 || f.Daily[0].Rain != 0 {
+		t.Errorf("want daily rain of 0 and 1.49 mm, got %g and %g", f.Daily[0].Rain, f.Daily[2].Rain)
+	}
+	total := 0.0
+	for _, slot := range f.Hourly {
+		total += slot.Rain
+	}
+	if total == 0 {
+		t.Error("want hourly rain amounts, got none")
+	}
+}
@@ -0,0 +1,65 @@
+package weathertest
+
+import (
+	"sync"
+
+	"github.com/cntzr/weather"
+)
+
+// Mock ... in-memory weather.Provider returning the configured data, Err is returned by every call if set
+//
+// The fields may be set before the mock is in use, later changes go through Set.
+type Mock struct {
+	Coordinates weather.Coordinates
+	Conditions  weather.Conditions
+	Forecast    weather.Forecast
+	Err         error
+
+	mu        sync.Mutex
+	locations []string
+}
+
+var _ weather.Provider = (*Mock)(nil)
+
+func (m *Mock) Get(location string) (weather.Conditions, weather.Forecast, error) {
+	coordinates, err := m.GetCoordinates(location)
+	if err != nil {
+		return weather.Conditions{}, weather.Forecast{}, err
+	}
+	return m.GetWeather(coordinates)
+}
+
+// GetCoordinates ... records the requested location, so tests can check what was asked for
+func (m *Mock) GetCoordinates(location string) (weather.Coordinates, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.locations = append(m.locations, location)
+	if m.Err != nil {
+		return weather.Coordinates{}, m.Err
+	}
+	return m.Coordinates, nil
+}
+
+// Locations ... locations requested so far, in order
+func (m *Mock) Locations() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.locations...)
+}
+
+// Set ... replaces the returned weather while the mock is in use
+func (m *Mock) Set(conditions weather.Conditions, forecast weather.Forecast) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Conditions = conditions
+	m.Forecast = forecast
+}
+
+func (m *Mock) GetWeather(coordinates weather.Coordinates) (weather.Conditions, weather.Forecast, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return weather.Conditions{}, weather.Forecast{}, m.Err
+	}
+	return m.Conditions, m.Forecast, nil
+}
@@ -0,0 +1,126 @@
+// Package weathertest provides a fake OpenWeatherMap server and client
+// helpers for applications embedding the weather package in their tests.
+package weathertest
+
+import (
+	"bytes"
+	_ "embed"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+var (
+	//go:embed testdata/geo_service.json
+	GeoResponse []byte
+
+	//go:embed testdata/geo_zip.json
+	ZipResponse []byte
+
+	//go:embed testdata/air_pollution.json
+	AirPollutionResponse []byte
+
+	//go:embed testdata/weather_30.json
+	WeatherResponse []byte
+
+	//go:embed testdata/timemachine.json
+	TimemachineResponse []byte
+
+	//go:embed testdata/pollen.json
+	PollenResponse []byte
+
+	//go:embed testdata/meteoalarm.json
+	MeteoAlarmResponse []byte
+
+	//go:embed testdata/dwd.json
+	DWDResponse []byte
+
+	//go:embed testdata/openmeteo.json
+	OpenMeteoResponse []byte
+
+	//go:embed testdata/openmeteo_geo.json
+	OpenMeteoGeoResponse []byte
+
+	// TileResponse ... map tile of 256x256 pixels, half transparent blue like rain
+	TileResponse = tile(256, color.NRGBA{B: 255, A: 128})
+
+	// IconResponse ... weather icon of 100x100 pixels in grey
+	IconResponse = tile(100, color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+)
+
+// Handler ... serves the canned geo, zip, reverse geo, onecall, timemachine, air pollution, pollen, MeteoAlarm,
+// DWD, Open-Meteo, map tile and icon responses, everything else is answered with 404
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/geo/1.0/direct", serve(GeoResponse))
+	mux.HandleFunc("/geo/1.0/zip", serve(ZipResponse))
+	mux.HandleFunc("/geo/1.0/reverse", serve(GeoResponse))
+	mux.HandleFunc("/data/3.0/onecall", serve(WeatherResponse))
+	mux.HandleFunc("/data/3.0/onecall/timemachine", serve(TimemachineResponse))
+	mux.HandleFunc("/data/2.5/air_pollution", serve(AirPollutionResponse))
+	mux.HandleFunc("/v1/air-quality", serve(PollenResponse))
+	mux.HandleFunc("/api/v1/warnings/feeds-germany", serve(MeteoAlarmResponse))
+	mux.HandleFunc("/DWD/warnungen/warnapp/json/warnings.json", serve(DWDResponse))
+	mux.HandleFunc("/v1/forecast", serve(OpenMeteoResponse))
+	mux.HandleFunc("/v1/search", serve(OpenMeteoGeoResponse))
+	mux.HandleFunc("/map/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(TileResponse)
+	})
+	mux.HandleFunc("/img/wn/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(IconResponse)
+	})
+	return mux
+}
+
+// NewServer ... starts a TLS test server with the canned responses, the caller has to close it
+func NewServer() *httptest.Server {
+	return httptest.NewTLSServer(Handler())
+}
+
+// NewClient ... wires a weather client to the given test server
+func NewClient(ts *httptest.Server) *weather.Client {
+	c := weather.NewClient("dummyAPIKey")
+	c.BaseURL = ts.URL
+	c.TileURL = ts.URL
+	c.IconURL = ts.URL + "/img/wn"
+	c.PollenURL = ts.URL
+	c.MeteoAlarmURL = ts.URL
+	c.DWDURL = ts.URL
+	c.HTTPClient = ts.Client()
+	return c
+}
+
+// NewFakeClient ... starts a test server, which is closed at the end of the test, and returns a client using it
+func NewFakeClient(t testing.TB) *weather.Client {
+	t.Helper()
+	ts := NewServer()
+	t.Cleanup(ts.Close)
+	return NewClient(ts)
+}
+
+func serve(data []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
+
+// tile ... square PNG of the size filled with c
+func tile(size int, c color.Color) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
@@ -0,0 +1,69 @@
+package weathertest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cntzr/weather"
+	"github.com/cntzr/weather/weathertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewFakeClientGetCoordinates(t *testing.T) {
+	t.Parallel()
+	c := weathertest.NewFakeClient(t)
+	want := weather.Coordinates{
+		Lat: 55.123456,
+		Lon: 3.7654321,
+	}
+	got, err := c.GetCoordinates("Paris,FR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestNewFakeClientGetWeather(t *testing.T) {
+	t.Parallel()
+	c := weathertest.NewFakeClient(t)
+	conditions, forecast, err := c.GetWeather(weather.Coordinates{Lat: 1.0, Lon: 2.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Leichter Regen"
+	if conditions.Summary != want {
+		t.Errorf("want %s, got %s", want, conditions.Summary)
+	}
+	if len(forecast.Daily) != 8 {
+		t.Errorf("want 8 daily entries, got %d", len(forecast.Daily))
+	}
+}
+
+func TestMockGet(t *testing.T) {
+	t.Parallel()
+	m := &weathertest.Mock{
+		Conditions: weather.Conditions{Summary: "Sonnig"},
+	}
+	var p weather.Provider = m
+	got, _, err := p.Get("Bonn,DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Summary != "Sonnig" {
+		t.Errorf("want Sonnig, got %s", got.Summary)
+	}
+	if !cmp.Equal([]string{"Bonn,DE"}, m.Locations()) {
+		t.Error(cmp.Diff([]string{"Bonn,DE"}, m.Locations()))
+	}
+}
+
+func TestMockError(t *testing.T) {
+	t.Parallel()
+	m := &weathertest.Mock{Err: errors.New("boom")}
+	_, _, err := m.Get("Bonn,DE")
+	if err == nil {
+		t.Fatal("want error from mock, but got nil")
+	}
+}
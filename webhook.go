@@ -0,0 +1,78 @@
+package weather
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	// Webhook ... posts notifications as JSON to a URL, retrying failed deliveries
+	Webhook struct {
+		URL string
+		// Headers are sent with every request, e.g. for authentication
+		Headers map[string]string
+		// Retries is the number of attempts after the first one, the wait doubles from Backoff on
+		Retries    int
+		Backoff    time.Duration
+		HTTPClient *http.Client
+	}
+
+	// WebhookConfig ... webhook settings of the config file
+	WebhookConfig struct {
+		URL     string            `json:"url"`
+		Headers map[string]string `json:"headers,omitempty"`
+		Retries int               `json:"retries,omitempty"`
+	}
+)
+
+// NewWebhook ... webhook for the URL with three retries
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		URL:        url,
+		Retries:    3,
+		Backoff:    time.Second,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify ... posts the notification, server errors and failed connections are retried, client errors are not
+func (w *Webhook) Notify(n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	wait := w.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(body)
+		if err == nil || !retry || attempt >= w.Retries {
+			return err
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// post ... one delivery attempt, retry tells whether a failure is worth another one
+func (w *Webhook) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := w.HTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("unexptected response status %q", resp.Status)
+	}
+	return false, nil
+}
@@ -0,0 +1,72 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cntzr/weather"
+	"github.com/google/go-cmp/cmp"
+)
+
+// webhookServer ... answers with the statuses in order, then with 200, and records the received notifications
+type webhookServer struct {
+	mu       sync.Mutex
+	statuses []int
+	received []weather.Notification
+	headers  []string
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n weather.Notification
+	json.NewDecoder(r.Body).Decode(&n)
+	s.received = append(s.received, n)
+	s.headers = append(s.headers, r.Header.Get("X-Token"))
+	if len(s.statuses) > 0 {
+		w.WriteHeader(s.statuses[0])
+		s.statuses = s.statuses[1:]
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		statuses []int
+		attempts int
+		wantErr  bool
+	}{
+		"delivered":       {nil, 1, false},
+		"retried":         {[]int{http.StatusBadGateway, http.StatusTooManyRequests}, 3, false},
+		"retries used up": {[]int{500, 500, 500}, 3, true},
+		"client error":    {[]int{http.StatusBadRequest}, 1, true},
+	}
+	for name, tc := range tests {
+		s := &webhookServer{statuses: tc.statuses}
+		ts := httptest.NewServer(s)
+		w := weather.NewWebhook(ts.URL)
+		w.Retries = 2
+		w.Backoff = time.Millisecond
+		w.Headers = map[string]string{"X-Token": "secret"}
+		n := weather.Notification{Kind: weather.NotifyAlert, Location: "Bonn", Alert: &weather.Alert{Name: "Hitze"}}
+		err := w.Notify(n)
+		ts.Close()
+		if tc.wantErr != (err != nil) {
+			t.Errorf("%s: want error %v, got %v", name, tc.wantErr, err)
+		}
+		if len(s.received) != tc.attempts {
+			t.Errorf("%s: want %d attempts, got %d", name, tc.attempts, len(s.received))
+			continue
+		}
+		if !cmp.Equal(n, s.received[0]) {
+			t.Errorf("%s: %s", name, cmp.Diff(n, s.received[0]))
+		}
+		if s.headers[0] != "secret" {
+			t.Errorf("%s: want configured header, got %q", name, s.headers[0])
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package weather
+
+import (
+	"fmt"
+)
+
+const FunctionWind = "wind"
+
+// beaufortLimits ... upper limits of the Beaufort scale in m/s
+var beaufortLimits = []float64{0.2, 1.5, 3.3, 5.4, 7.9, 10.7, 13.8, 17.1, 20.7, 24.4, 28.4, 32.6}
+
+// Beaufort ... wind force on the Beaufort scale, the speed has to be in m/s
+func (s Speed) Beaufort() int {
+	for force, limit := range beaufortLimits {
+		if float64(s) <= limit {
+			return force
+		}
+	}
+	return 12
+}
+
+// GetWindiestHour ... hour of the day with the strongest gusts, or wind speed if gusts are missing
+func GetWindiestHour(f Forecast, offset int) (ForecastHourly, bool) {
+	if offset < 0 || offset >= len(f.Daily) {
+		return ForecastHourly{}, false
+	}
+	reference := f.Daily[offset].Day
+	windiest := ForecastHourly{}
+	found := false
+	for _, slot := range f.Hourly {
+		if slot.Day != reference {
+			continue
+		}
+		if !found || strongest(slot) > strongest(windiest) {
+			windiest = slot
+			found = true
+		}
+	}
+	return windiest, found
+}
+
+func strongest(slot ForecastHourly) Speed {
+	if slot.WindGust > slot.WindSpeed {
+		return slot.WindGust
+	}
+	return slot.WindSpeed
+}
+
+// PrintWind ... current wind, windiest hour of today and hourly wind outlook, for cyclists and sailors
+func PrintWind(c Conditions, f Forecast, hours int) {
+	if hours > len(f.Hourly) {
+		hours = len(f.Hourly)
+	}
+	fmt.Println()
+	printHeader("Wind vom "+c.Timestamp, f)
+	fmt.Printf("Aktuell: %s aus %s, in Böen %s (%d Bft)\n",
+		f.FormatSpeed(c.WindSpeed),
+		c.WindDirection.Direction(),
+		f.FormatSpeed(c.WindGust),
+		beaufort(f, c.WindSpeed))
+	if windiest, ok := GetWindiestHour(f, 0); ok {
+		fmt.Printf("Am windigsten heute um %s: %s aus %s, in Böen %s\n",
+			windiest.Hour,
+			f.FormatSpeed(windiest.WindSpeed),
+			windiest.WindDirection.Direction(),
+			f.FormatSpeed(windiest.WindGust))
+	}
+	fmt.Println()
+	fmt.Printf("%-10s  %-5s  %-9s  %-4s  %-9s  %s\n", "Tag", "Zeit", "Wind", "aus", "Böen", "Bft")
+	for _, slot := range f.Hourly[:hours] {
+		fmt.Printf("%-10s  %-5s  %-9s  %-4s  %-9s  %d\n",
+			slot.Day,
+			slot.Hour,
+			f.FormatSpeed(slot.WindSpeed),
+			slot.WindDirection.Direction(),
+			f.FormatSpeed(slot.WindGust),
+			beaufort(f, slot.WindSpeed))
+	}
+	fmt.Println()
+}
+
+// beaufort ... Beaufort force independent of the units, imperial speeds are in mph
+func beaufort(f Forecast, s Speed) int {
+	return metersPerSecond(s, f.Units).Beaufort()
+}
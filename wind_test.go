@@ -0,0 +1,50 @@
+package weather_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+func TestBeaufort(t *testing.T) {
+	t.Parallel()
+	tests := map[weather.Speed]int{
+		0.1:  0,
+		2.3:  2,
+		10.0: 5,
+		25.0: 10,
+		40.0: 12,
+	}
+	for speed, want := range tests {
+		got := speed.Beaufort()
+		if want != got {
+			t.Errorf("%.1f m/s: want %d Bft, got %d", speed, want, got)
+		}
+	}
+}
+
+func TestGetWindiestHour(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/weather_30.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fc, err := weather.ParseWeatherResponse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := weather.GetWindiestHour(fc, 0)
+	if !ok {
+		t.Fatal("want windiest hour for today")
+	}
+	for _, slot := range fc.Hourly {
+		if slot.Day == got.Day && slot.WindGust > got.WindGust {
+			t.Errorf("found stronger gusts at %s than at %s", slot.Hour, got.Hour)
+		}
+	}
+	_, ok = weather.GetWindiestHour(fc, 9)
+	if ok {
+		t.Error("want no windiest hour for offset out of range")
+	}
+}
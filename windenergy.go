@@ -0,0 +1,113 @@
+package weather
+
+import (
+	"flag"
+	"fmt"
+	"math"
+)
+
+const (
+	// HubHeight ... typical hub height of a small wind turbine in m, the API delivers the wind 10 m above ground
+	HubHeight = 20
+	// windShear ... exponent of the wind profile power law over open land
+	windShear = 1.0 / 7
+
+	// power curve of a typical small turbine in m/s: it starts at cut-in, reaches its rated power at rated
+	// and shuts down at cut-out to protect itself
+	cutInSpeed  = 3
+	ratedSpeed  = 11
+	cutOutSpeed = 25
+)
+
+// WindEnergyDay ... wind power outlook of one day from the hourly forecast, speeds at hub height in m/s
+type WindEnergyDay struct {
+	Day       string `json:"day"`
+	MeanSpeed Speed  `json:"mean_speed"`
+	MaxSpeed  Speed  `json:"max_speed"`
+	// CapacityFactor is the mean power relative to the rated power, between 0 and 1
+	CapacityFactor float64 `json:"capacity_factor"`
+	// Hours are the hours the turbine runs, Observed all hours of the day in the forecast
+	Hours    int `json:"hours"`
+	Observed int `json:"observed"`
+}
+
+// HubHeightSpeed ... wind speed at the height in m from the speed 10 m above ground
+func HubHeightSpeed(s Speed, height float64) Speed {
+	return Speed(float64(s) * math.Pow(height/10, windShear))
+}
+
+// RelativePower ... power of the turbine at the wind speed at hub height relative to its rated power,
+// growing with the cube of the speed between cut-in and rated speed
+func RelativePower(s Speed) float64 {
+	v := float64(s)
+	switch {
+	case v < cutInSpeed || v > cutOutSpeed:
+		return 0
+	case v >= ratedSpeed:
+		return 1
+	}
+	return (math.Pow(v, 3) - math.Pow(cutInSpeed, 3)) / (math.Pow(ratedSpeed, 3) - math.Pow(cutInSpeed, 3))
+}
+
+// GetWindEnergy ... wind power outlook of the days of the hourly forecast
+func GetWindEnergy(f Forecast) []WindEnergyDay {
+	days := []WindEnergyDay{}
+	for _, slot := range f.Hourly {
+		if n := len(days); n == 0 || days[n-1].Day != slot.Day {
+			days = append(days, WindEnergyDay{Day: slot.Day})
+		}
+		d := &days[len(days)-1]
+		v := HubHeightSpeed(metersPerSecond(slot.WindSpeed, f.Units), HubHeight)
+		power := RelativePower(v)
+		// summed up here, divided below
+		d.MeanSpeed += v
+		d.CapacityFactor += power
+		if v > d.MaxSpeed {
+			d.MaxSpeed = v
+		}
+		if power > 0 {
+			d.Hours++
+		}
+		d.Observed++
+	}
+	for i := range days {
+		days[i].MeanSpeed /= Speed(days[i].Observed)
+		days[i].CapacityFactor /= float64(days[i].Observed)
+	}
+	return days
+}
+
+// Rating ... how much the turbine yields: schwach below 10 %, mäßig below 25 %, gut below 40 %, otherwise sehr gut
+func (d WindEnergyDay) Rating() string {
+	switch {
+	case d.CapacityFactor < 0.1:
+		return "schwach"
+	case d.CapacityFactor < 0.25:
+		return "mäßig"
+	case d.CapacityFactor < 0.4:
+		return "gut"
+	}
+	return "sehr gut"
+}
+
+// windEnergyFlags ... flags of the wind command
+func windEnergyFlags(fs *flag.FlagSet, opts *Options) {
+	fs.BoolVar(&opts.Energy, "energy", false, fmt.Sprintf("show the wind power outlook of a small turbine with a hub height of %d m", HubHeight))
+}
+
+// PrintWindEnergy ... wind power outlook per day for owners of small turbines
+func PrintWindEnergy(f Forecast) {
+	fmt.Println()
+	printHeader(fmt.Sprintf("Windenergie in %d m Nabenhöhe", HubHeight), f)
+	fmt.Printf("%-10s  %-8s  %-8s  %-9s  %-9s  %s\n", "Tag", "Mittel", "Max", "Leistung", "Laufzeit", "Ertrag")
+	for _, d := range GetWindEnergy(f) {
+		fmt.Printf("%-10s  %-8s  %-8s  %7.0f %%  %-9s  %s\n",
+			d.Day,
+			fmt.Sprintf("%.1f m/s", d.MeanSpeed),
+			fmt.Sprintf("%.1f m/s", d.MaxSpeed),
+			d.CapacityFactor*100,
+			fmt.Sprintf("%d/%d h", d.Hours, d.Observed),
+			d.Rating())
+	}
+	fmt.Println()
+}
@@ -0,0 +1,62 @@
+package weather_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cntzr/weather"
+)
+
+func TestRelativePower(t *testing.T) {
+	t.Parallel()
+	tests := map[weather.Speed]float64{
+		2:  0,
+		3:  0,
+		7:  (343.0 - 27) / (1331 - 27),
+		11: 1,
+		20: 1,
+		26: 0,
+	}
+	for speed, want := range tests {
+		if got := weather.RelativePower(speed); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%.0f m/s: want %.3f, got %.3f", speed, want, got)
+		}
+	}
+}
+
+func TestHubHeightSpeed(t *testing.T) {
+	t.Parallel()
+	if got := weather.HubHeightSpeed(5, 10); got != 5 {
+		t.Errorf("want the same speed at 10 m, got %.2f", got)
+	}
+	if got := weather.HubHeightSpeed(5, 20); math.Abs(float64(got)-5.52) > 0.01 {
+		t.Errorf("want 5.52 m/s at 20 m, got %.2f", got)
+	}
+}
+
+func TestGetWindEnergy(t *testing.T) {
+	t.Parallel()
+	f := weather.Forecast{
+		Units: weather.UnitsImperial,
+		Hourly: []weather.ForecastHourly{
+			{Day: "17.06.2022", WindSpeed: 2},
+			{Day: "17.06.2022", WindSpeed: 4},
+			{Day: "18.06.2022", WindSpeed: 30},
+			{Day: "18.06.2022", WindSpeed: 30},
+		},
+	}
+	got := weather.GetWindEnergy(f)
+	if len(got) != 2 {
+		t.Fatalf("want 2 days, got %+v", got)
+	}
+	if got[0].Hours != 0 || got[0].CapacityFactor != 0 || got[0].Rating() != "schwach" {
+		t.Errorf("want no power of a calm day, got %+v", got[0])
+	}
+	// 30 mph are 13.4 m/s 10 m above ground, above the rated speed
+	if got[1].Hours != 2 || got[1].Observed != 2 || got[1].CapacityFactor != 1 || got[1].Rating() != "sehr gut" {
+		t.Errorf("want full power of a windy day, got %+v", got[1])
+	}
+	if math.Abs(float64(got[1].MeanSpeed)-14.8) > 0.1 {
+		t.Errorf("want a mean of 14.8 m/s at hub height, got %.2f", got[1].MeanSpeed)
+	}
+}